@@ -18,20 +18,19 @@ package client
 
 import (
 	"fmt"
-	"strings"
 
 	"go.uber.org/zap"
 
 	"github.com/mrhaoxx/go-mc/chat"
 	"github.com/mrhaoxx/go-mc/data/packetid"
-	"github.com/mrhaoxx/go-mc/data/registryid"
-	"github.com/mrhaoxx/go-mc/level"
 	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/recipe"
 	"github.com/mrhaoxx/go-mc/net"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 	"github.com/mrhaoxx/go-mc/net/queue"
 	"github.com/mrhaoxx/go-mc/server"
 	"github.com/mrhaoxx/go-mc/world"
+	"github.com/mrhaoxx/go-mc/world/container"
 )
 
 type Client struct {
@@ -39,20 +38,28 @@ type Client struct {
 	conn     *net.Conn
 	player   *world.Player
 	world    *world.World
+	recipes  *recipe.Manager
 	queue    server.PacketQueue
 	handlers []PacketHandler
+	// containerCounter is the id of the last opened window.
+	containerCounter int32
+	// editingSign is the position of the sign whose editor is opened, or nil.
+	editingSign *[3]int32
 	// pointer to the Player.Input
 	*world.Inputs
 }
 
 type PacketHandler func(p pk.Packet, c *Client) error
 
-func New(log *zap.Logger, conn *net.Conn, player *world.Player, world *world.World) *Client {
+func New(log *zap.Logger, conn *net.Conn, player *world.Player, world *world.World, recipes *recipe.Manager) *Client {
+	player.InventoryMenu = container.NewInventoryMenu(&player.Inventory, recipes)
+	player.ContainerMenu = player.InventoryMenu
 	return &Client{
 		log:      log,
 		conn:     conn,
 		player:   player,
 		world:    world,
+		recipes:  recipes,
 		queue:    queue.NewChannelQueue[pk.Packet](256),
 		handlers: defaultHandlers[:],
 		Inputs:   &player.Inputs,
@@ -118,15 +125,12 @@ func (c *Client) AddHandler(id packetid.ServerboundPacketID, handler PacketHandl
 }
 func (c *Client) GetPlayer() *world.Player { return c.player }
 
-// itemIDToBlockState converts item ID to block state ID
-// For most blocks, the item ID matches the block state ID
-func itemIDToBlockState(itemID int32) block.Block {
-	// id to string
-	var name = registryid.Item[itemID]
-	// name to blockid
-	var blockID = block.FromID[name]
-	return blockID
-}
+// World returns the world the player is in.
+func (c *Client) World() *world.World { return c.world }
+
+// SetWorld changes the world the packets of the client are handled in.
+// It must be called from a packet handler, since the handlers use the world without locking.
+func (c *Client) SetWorld(w *world.World) { c.world = w }
 
 var defaultHandlers = [packetid.ServerboundPacketIDGuard]PacketHandler{
 	packetid.ServerboundAcceptTeleportation:  clientAcceptTeleportation,
@@ -136,134 +140,8 @@ var defaultHandlers = [packetid.ServerboundPacketIDGuard]PacketHandler{
 	packetid.ServerboundMovePlayerRot:        clientMovePlayerRot,
 	packetid.ServerboundMovePlayerStatusOnly: clientMovePlayerStatusOnly,
 	packetid.ServerboundMoveVehicle:          clientMoveVehicle,
-	packetid.ServerboundChatCommand: func(p pk.Packet, c *Client) error {
-		var command pk.String
-		if err := p.Scan(&command); err != nil {
-			return err
-		}
-		fmt.Println("command", command)
-
-		var splits = strings.Split(string(command), " ")
-		if len(splits) < 1 {
-			c.SendSystemChat(chat.Message{
-				Text: "Commands Separated by Space",
-			}, false)
-			return nil
-		}
-		var cmd = splits[0]
-		var args = splits[1:]
-		switch cmd {
-		case "ping":
-			c.SendSystemChat(chat.Message{
-				Text: "Pong!",
-			}, false)
-		case "tp":
-			if len(args) != 3 {
-				c.SendSystemChat(chat.Message{
-					Text: "Usage: /tp <x> <y> <z>",
-				}, false)
-				return nil
-			}
-			var x, y, z int
-			fmt.Sscanf(args[0], "%d", &x)
-			fmt.Sscanf(args[1], "%d", &y)
-			fmt.Sscanf(args[2], "%d", &z)
-			c.SendSystemChat(chat.Message{
-				Text: "Teleporting to " + args[0] + " " + args[1] + " " + args[2],
-			}, false)
-			c.player.Position[0] = float64(x)
-			c.player.Position[1] = float64(y)
-			c.player.Position[2] = float64(z)
-			c.SendPlayerPosition(c.player.Position, c.player.Rotation)
-			return nil
-
-		case "setblock":
-
-			if len(args) != 4 {
-				c.SendSystemChat(chat.Message{
-					Text: "Usage: /setblock <x> <y> <z> <block>",
-				}, false)
-				return nil
-			}
-
-			var x, y, z int
-			fmt.Sscanf(args[0], "%d", &x)
-			fmt.Sscanf(args[1], "%d", &y)
-			fmt.Sscanf(args[2], "%d", &z)
-			var block int
-			fmt.Sscanf(args[3], "%d", &block)
-			c.SendSystemChat(chat.Message{
-				Text: "Setting block to " + args[3] + " at " + args[0] + " " + args[1] + " " + args[2],
-			}, false)
-			c.world.GetChunk([2]int32{int32(x / 16), int32(z / 16)}).SetBlock(x, y, z, level.BlocksState(block))
-			return nil
-
-		case "fill":
-			if len(args) != 7 {
-				c.SendSystemChat(chat.Message{
-					Text: "Usage: /fill <x1> <y1> <z1> <x2> <y2> <z2> <block>",
-				}, false)
-				return nil
-			}
-
-			var x1, y1, z1, x2, y2, z2 int
-			fmt.Sscanf(args[0], "%d", &x1)
-			fmt.Sscanf(args[1], "%d", &y1)
-			fmt.Sscanf(args[2], "%d", &z1)
-			fmt.Sscanf(args[3], "%d", &x2)
-			fmt.Sscanf(args[4], "%d", &y2)
-			fmt.Sscanf(args[5], "%d", &z2)
-			var block int
-			fmt.Sscanf(args[6], "%d", &block)
-
-			// Ensure coordinates are in the right order (min to max)
-			if x1 > x2 {
-				x1, x2 = x2, x1
-			}
-			if y1 > y2 {
-				y1, y2 = y2, y1
-			}
-			if z1 > z2 {
-				z1, z2 = z2, z1
-			}
-
-			// Calculate the volume
-			volume := (x2 - x1 + 1) * (y2 - y1 + 1) * (z2 - z1 + 1)
-
-			c.SendSystemChat(chat.Message{
-				Text: fmt.Sprintf("Filling %d blocks from (%d,%d,%d) to (%d,%d,%d) with block %s", volume, x1, y1, z1, x2, y2, z2, args[6]),
-			}, false)
-
-			// Fill the region
-			for x := x1; x <= x2; x++ {
-				for y := y1; y <= y2; y++ {
-					for z := z1; z <= z2; z++ {
-						var ck = c.world.GetChunk([2]int32{int32(x / 16), int32(z / 16)})
-						if ck == nil {
-							c.SendSystemChat(chat.Message{
-								Text: fmt.Sprintf("Chunk not found at (%d,%d)", x, z),
-							}, false)
-							continue
-						}
-
-						ck.SetBlock(x, y, z, level.BlocksState(block))
-					}
-				}
-			}
-
-			c.SendSystemChat(chat.Message{
-				Text: fmt.Sprintf("Successfully filled %d blocks", volume),
-			}, false)
-			return nil
-
-		default:
-			c.SendSystemChat(chat.Message{
-				Text: "Unknown command: " + cmd,
-			}, false)
-		}
-		return nil
-
-	},
+	packetid.ServerboundPlayerAbilities:      clientPlayerAbilities,
+	packetid.ServerboundPlayerCommand:        clientPlayerCommand,
 	packetid.ServerboundChunkBatchReceived: func(p pk.Packet, c *Client) error {
 		var chunkBatch pk.Float
 		if err := p.Scan(&chunkBatch); err != nil {
@@ -300,6 +178,10 @@ var defaultHandlers = [packetid.ServerboundPacketIDGuard]PacketHandler{
 			}
 		}
 
+		c.Inputs.Lock()
+		c.Inputs.Sneaking = playerInput&0x20 != 0
+		c.Inputs.Unlock()
+
 		fmt.Println("Client: Player input", playerInputString)
 		c.log.Info("Client: Player input", zap.String("input", playerInputString), zap.String("name", c.player.Name))
 		return nil
@@ -318,13 +200,18 @@ var defaultHandlers = [packetid.ServerboundPacketIDGuard]PacketHandler{
 		c.world.BroadcastSwing(c.player, anim)
 		return nil
 	},
-	packetid.ServerboundUseItemOn:           clientUseItemOn,
-	packetid.ServerboundPlayerAction:        clientPlayerAction,
-	packetid.ServerboundSetCarriedItem:      clientSetCarriedItem,
-	packetid.ServerboundSetCreativeModeSlot: clientSetCreativeModeSlot,
+	packetid.ServerboundUseItemOn:            clientUseItemOn,
+	packetid.ServerboundPlayerAction:         clientPlayerAction,
+	packetid.ServerboundSetCarriedItem:       clientSetCarriedItem,
+	packetid.ServerboundSetCreativeModeSlot:  clientSetCreativeModeSlot,
+	packetid.ServerboundContainerClick:       clientContainerClick,
+	packetid.ServerboundContainerClose:       clientContainerClose,
+	packetid.ServerboundContainerButtonClick: clientContainerButtonClick,
+	packetid.ServerboundPlaceRecipe:          clientPlaceRecipe,
+	packetid.ServerboundSignUpdate:           clientSignUpdate,
 }
 
-// clientUseItemOn handles right-click block placement.
+// clientUseItemOn handles right-click on blocks, which uses the block or places the held block item.
 func clientUseItemOn(p pk.Packet, c *Client) error {
 	var (
 		hand       pk.VarInt
@@ -343,42 +230,56 @@ func clientUseItemOn(p pk.Packet, c *Client) error {
 	}
 
 	fmt.Println("Client: UseItemOn", hand, pos, face, fx, fy, fz, inside, seq)
-	// Compute placement position based on clicked face
-	x, y, z := pos.X, pos.Y, pos.Z
-	switch int(face) {
-	case 0: // down
-		y--
-	case 1: // up
-		y++
-	case 2: // north (-z)
-		z--
-	case 3: // south (+z)
-		z++
-	case 4: // west (-x)
-		x--
-	case 5: // east (+x)
-		x++
+	defer c.ackBlockSequence(int32(seq))
+	clicked := [3]int{pos.X, pos.Y, pos.Z}
+	secondary := c.secondaryUse()
+	if state, ok := c.world.GetBlock(pos.X, pos.Y, pos.Z); ok && !secondary {
+		if _, ok := block.StateList[state].(block.CraftingTable); ok {
+			if c.world.InReach(c, clicked) {
+				m := container.NewCraftingMenu(&c.player.Inventory, c.recipes)
+				m.Block = &clicked
+				c.OpenMenu(m, chat.TranslateMsg("container.crafting"))
+			}
+			return nil
+		}
 	}
-	ck := c.world.GetChunk([2]int32{int32(x >> 4), int32(z >> 4)})
-	if ck == nil {
-		c.log.Debug("UseItemOn in not-loaded chunk", zap.Int("x", x), zap.Int("y", y), zap.Int("z", z))
+	if !secondary && c.useSign([3]int32{int32(pos.X), int32(pos.Y), int32(pos.Z)}) {
 		return nil
 	}
-
-	// Get the item from the player's carried slot
-	if c.player.CarriedSlot >= 0 && c.player.CarriedSlot < 9 {
-		itemStack := c.player.Inventory[c.player.CarriedSlot]
-		if itemStack != nil {
-			// Convert item ID to block state ID
-			// For wool blocks, item ID = block state ID
-			blockStateID := itemIDToBlockState(itemStack.ItemID)
-			ck.SetBlock(x, y, z, level.BlocksState(block.ToStateID[blockStateID]))
-			ck.UpdateToViewers()
-		}
+	if face < 0 || face > 5 {
+		return nil
 	}
+	cursor := [3]float64{float64(fx), float64(fy), float64(fz)}
+	c.placeBlock(int32(hand), clicked, block.Direction(face), cursor)
 	return nil
 }
 
+// secondaryUse reports whether the player is sneaking with an item in a hand,
+// which places the item instead of using the clicked block, like vanilla.
+func (c *Client) secondaryUse() bool {
+	c.Inputs.Lock()
+	sneaking := c.Inputs.Sneaking
+	c.Inputs.Unlock()
+	if !sneaking {
+		return false
+	}
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	held := !c.player.Inventory[container.OffhandSlot].IsEmpty()
+	if slot := c.player.CarriedSlot; slot >= 0 && slot < container.HotbarSize {
+		held = held || !c.player.Inventory[slot].IsEmpty()
+	}
+	return held
+}
+
+// ackBlockSequence records the sequence number of a block interaction,
+// the world acknowledges it after sending the resulting block changes.
+func (c *Client) ackBlockSequence(seq int32) {
+	c.Inputs.Lock()
+	c.Inputs.BlockSequence = seq
+	c.Inputs.Unlock()
+}
+
 // clientPlayerAction handles actions like block breaking.
 func clientPlayerAction(p pk.Packet, c *Client) error {
 	var (
@@ -394,15 +295,10 @@ func clientPlayerAction(p pk.Packet, c *Client) error {
 		}
 	}
 	fmt.Println("Client: Player action", status, pos, face, seq)
-	// Only handle finish-destroy to actually remove the block.
-	if int(status) == 2 || int(status) == 0 { // Stop/Finish digging
-		x, y, z := pos.X, pos.Y, pos.Z
-		ck := c.world.GetChunk([2]int32{int32(x >> 4), int32(z >> 4)})
-		if ck == nil {
-			return nil
-		}
-		ck.SetBlock(x, y, z, block.ToStateID[block.Air{}])
-		ck.UpdateToViewers()
+	defer c.ackBlockSequence(int32(seq))
+	switch status {
+	case 0, 1, 2: // Start, abort and finish digging
+		c.digBlock(world.DigAction(status), [3]int{pos.X, pos.Y, pos.Z})
 	}
 	return nil
 }
@@ -428,65 +324,3 @@ func clientSetCarriedItem(p pk.Packet, c *Client) error {
 	c.log.Info("Client: SetCarriedItem", zap.Int32("slot", s), zap.String("name", c.player.Name))
 	return nil
 }
-
-// clientSetCreativeModeSlot handles creative inventory edits.
-// Slot numbering follows vanilla (hotbar 36..44).
-func clientSetCreativeModeSlot(p pk.Packet, c *Client) error {
-	var slot pk.Short
-	var count pk.VarInt
-
-	if err := p.Scan(&slot, &count); err != nil {
-		return err
-	}
-
-	idx := int32(slot)
-	if count > 0 {
-		var itemID pk.VarInt = 1
-		var nAdd pk.VarInt
-		var nDel pk.VarInt
-		if err := p.Scan(&slot, &count, &itemID, &nAdd, &nDel); err != nil {
-			return err
-		}
-
-		// Map slot index to inventory index
-		var invIdx int32
-		switch {
-		case idx >= 36 && idx <= 44: // Hotbar
-			invIdx = idx - 36
-		case idx >= 9 && idx <= 35: // Main inventory
-			invIdx = idx
-		default:
-			invIdx = idx
-		}
-
-		if invIdx >= 0 && invIdx < int32(len(c.player.Inventory)) {
-			c.player.Inventory[invIdx] = &world.ItemStack{
-				ItemID: int32(itemID),
-				Count:  byte(count),
-			}
-		}
-
-		// Update carried slot if player edited a hotbar index
-		if idx >= 36 && idx <= 44 {
-			c.player.CarriedSlot = idx - 36
-		}
-		c.log.Info("Client: SetCreativeModeSlot", zap.Int32("slot", idx), zap.Int32("itemID", int32(itemID)), zap.Int("nAdd", int(nAdd)), zap.Int("nDel", int(nDel)), zap.Int("count", int(count)), zap.String("name", c.player.Name))
-	} else {
-		// Empty slot
-		var invIdx int32
-		switch {
-		case idx >= 36 && idx <= 44:
-			invIdx = idx - 36
-		case idx >= 9 && idx <= 35:
-			invIdx = idx
-		default:
-			invIdx = idx
-		}
-
-		if invIdx >= 0 && invIdx < int32(len(c.player.Inventory)) {
-			c.player.Inventory[invIdx] = nil
-		}
-		c.log.Info("Client: SetCreativeModeSlot (empty)", zap.Int32("slot", idx), zap.String("name", c.player.Name))
-	}
-	return nil
-}
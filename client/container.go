@@ -0,0 +1,152 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/world/container"
+)
+
+var _ container.Viewer = (*Client)(nil)
+
+// InitInventoryMenu sends the inventory to the client, which is kept in sync from then on.
+// It's called after the login and the respawns.
+func (c *Client) InitInventoryMenu() {
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	c.player.InventoryMenu.SetViewer(c)
+}
+
+// OpenMenu shows the window to the player with the title, closing the open one.
+// The ID of the menu is assigned here.
+func (c *Client) OpenMenu(m *container.Menu, title chat.Message) {
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	if c.player.ContainerMenu != c.player.InventoryMenu {
+		c.SendContainerClose(c.player.ContainerMenu.ID)
+		c.doCloseMenu()
+	}
+	// Like vanilla, the ids of the windows go from 1 to 100.
+	c.containerCounter = c.containerCounter%100 + 1
+	m.ID = c.containerCounter
+	c.SendOpenScreen(m.ID, m.Type, title)
+	m.SetViewer(c)
+	c.player.ContainerMenu = m
+}
+
+// CloseMenu closes the window open on the client, if it's not the inventory.
+func (c *Client) CloseMenu() {
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	if c.player.ContainerMenu != c.player.InventoryMenu {
+		c.SendContainerClose(c.player.ContainerMenu.ID)
+	}
+	c.doCloseMenu()
+}
+
+// doCloseMenu puts back the items the player is holding and returns to the inventory menu.
+// The ContainerLock must be held.
+func (c *Client) doCloseMenu() {
+	c.player.ContainerMenu.Removed(c.player)
+	c.player.ContainerMenu = c.player.InventoryMenu
+	c.player.InventoryMenu.BroadcastChanges()
+}
+
+func clientContainerClick(p pk.Packet, c *Client) error {
+	var (
+		windowID, stateID pk.VarInt
+		slot              pk.Short
+		button            pk.Byte
+		mode              pk.VarInt
+		changed           []container.ChangedSlot
+		carried           item.ItemStack
+	)
+	if err := p.Scan(&windowID, &stateID, &slot, &button, &mode, pk.Array(&changed), &carried); err != nil {
+		return err
+	}
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	m := c.player.ContainerMenu
+	if m.ID != int32(windowID) {
+		return nil
+	}
+	if c.player.Gamemode == 3 { // spectators can't touch the items
+		m.SendAllDataToRemote()
+		return nil
+	}
+	if !m.IsValidSlotIndex(int(slot)) {
+		c.log.Debug("Click on invalid slot", zap.Int16("slot", int16(slot)), zap.String("name", c.player.Name))
+		return nil
+	}
+	m.Click(c.player, int32(stateID), int(slot), int(button), container.ClickType(mode), changed, carried)
+	return nil
+}
+
+func clientContainerClose(p pk.Packet, c *Client) error {
+	var windowID pk.VarInt
+	if err := p.Scan(&windowID); err != nil {
+		return err
+	}
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	c.doCloseMenu()
+	return nil
+}
+
+func clientContainerButtonClick(p pk.Packet, c *Client) error {
+	var windowID, button pk.VarInt
+	if err := p.Scan(&windowID, &button); err != nil {
+		return err
+	}
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	m := c.player.ContainerMenu
+	if m.ID == int32(windowID) && m.ButtonClick != nil && m.ButtonClick(int32(button)) {
+		m.BroadcastChanges()
+	}
+	return nil
+}
+
+// clientSetCreativeModeSlot handles creative inventory edits.
+// Slot numbering follows the inventory menu (hotbar 36..44), -1 drops the item.
+func clientSetCreativeModeSlot(p pk.Packet, c *Client) error {
+	var slot pk.Short
+	var stack item.ItemStack
+	if err := p.Scan(&slot, &stack); err != nil {
+		return err
+	}
+	if !c.player.HasInfiniteMaterials() {
+		return nil
+	}
+	validItem := stack.IsEmpty() || stack.Count <= stack.MaxStackSize()
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	switch m := c.player.InventoryMenu; {
+	case slot >= 1 && slot <= container.InventoryOffhandSlot && validItem:
+		m.Slots[slot].Set(stack)
+		m.SetRemoteSlot(int(slot), stack)
+		m.BroadcastChanges()
+	case slot < 0 && validItem && !stack.IsEmpty():
+		c.player.Drop(stack)
+	}
+	c.log.Debug("Client: SetCreativeModeSlot", zap.Int16("slot", int16(slot)), zap.Int32("itemID", int32(stack.ItemID)), zap.Int32("count", stack.Count), zap.String("name", c.player.Name))
+	return nil
+}
@@ -0,0 +1,62 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/world"
+	"github.com/mrhaoxx/go-mc/world/container"
+)
+
+var canBreakType, _ = component.TypeID(&component.CanBreak{})
+
+// digBlock starts, aborts or finishes breaking the block at the position.
+func (c *Client) digBlock(action world.DigAction, pos [3]int) {
+	// In adventure mode, only the blocks allowed by the item can be broken.
+	if action != world.DigAbort && c.player.Gamemode == 2 && !c.canBreak(pos) {
+		if state, ok := c.world.GetBlock(pos[0], pos[1], pos[2]); ok {
+			c.ViewBlockUpdate([3]int32{int32(pos[0]), int32(pos[1]), int32(pos[2])}, state)
+		}
+		return
+	}
+	c.world.DigBlock(c, pos, action)
+}
+
+// canBreak reports whether the CanBreak component of the item held allows breaking the block.
+func (c *Client) canBreak(pos [3]int) bool {
+	if c.player.CarriedSlot < 0 || c.player.CarriedSlot >= container.HotbarSize {
+		return false
+	}
+	c.player.ContainerLock.Lock()
+	stack := c.player.Inventory[c.player.CarriedSlot]
+	c.player.ContainerLock.Unlock()
+	comp, ok := stack.Component(canBreakType)
+	if !ok {
+		return false
+	}
+	state, loaded := c.world.GetBlock(pos[0], pos[1], pos[2])
+	if !loaded {
+		return false
+	}
+	for _, p := range comp.(*component.CanBreak).Predicates {
+		if block.MatchPredicate(p, block.StateID(state)) {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 
 	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/world/container"
 	"go.uber.org/zap"
 )
 
@@ -38,52 +39,59 @@ func clientAcceptTeleportation(p pk.Packet, c *Client) error {
 	return nil
 }
 
+// onGroundFlag is the bit of the movement flags set when the player stands on the ground,
+// the other one being set when it walks into a wall.
+const onGroundFlag = 0x01
+
 func clientMovePlayerPos(p pk.Packet, c *Client) error {
 	var X, FeetY, Z pk.Double
-	var OnGround pk.Boolean
-	if err := p.Scan(&X, &FeetY, &Z, &OnGround); err != nil {
+	var Flags pk.UnsignedByte
+	if err := p.Scan(&X, &FeetY, &Z, &Flags); err != nil {
 		return err
 	}
 	c.Inputs.Lock()
 	c.Inputs.Position = [3]float64{float64(X), float64(FeetY), float64(Z)}
+	c.Inputs.OnGround = Flags&onGroundFlag != 0
 	c.Inputs.Unlock()
-	// fmt.Println("Client: Move player pos", X, FeetY, Z, OnGround)
+	// fmt.Println("Client: Move player pos", X, FeetY, Z, Flags)
 	return nil
 }
 
 func clientMovePlayerPosRot(p pk.Packet, c *Client) error {
 	var X, FeetY, Z pk.Double
 	var Yaw, Pitch pk.Float
-	var OnGround pk.Boolean
-	if err := p.Scan(&X, &FeetY, &Z, &Yaw, &Pitch, &OnGround); err != nil {
+	var Flags pk.UnsignedByte
+	if err := p.Scan(&X, &FeetY, &Z, &Yaw, &Pitch, &Flags); err != nil {
 		return err
 	}
 	c.Inputs.Lock()
 	c.Inputs.Position = [3]float64{float64(X), float64(FeetY), float64(Z)}
 	c.Inputs.Rotation = [2]float32{float32(Yaw), float32(Pitch)}
+	c.Inputs.OnGround = Flags&onGroundFlag != 0
 	c.Inputs.Unlock()
 	return nil
 }
 
 func clientMovePlayerRot(p pk.Packet, c *Client) error {
 	var Yaw, Pitch pk.Float
-	var OnGround pk.Boolean
-	if err := p.Scan(&Yaw, &Pitch, &OnGround); err != nil {
+	var Flags pk.UnsignedByte
+	if err := p.Scan(&Yaw, &Pitch, &Flags); err != nil {
 		return err
 	}
 	c.Inputs.Lock()
 	c.Inputs.Rotation = [2]float32{float32(Yaw), float32(Pitch)}
+	c.Inputs.OnGround = Flags&onGroundFlag != 0
 	c.Inputs.Unlock()
 	return nil
 }
 
 func clientMovePlayerStatusOnly(p pk.Packet, c *Client) error {
-	var OnGround pk.UnsignedByte
-	if err := p.Scan(&OnGround); err != nil {
+	var Flags pk.UnsignedByte
+	if err := p.Scan(&Flags); err != nil {
 		return err
 	}
 	c.Inputs.Lock()
-	c.Inputs.OnGround = OnGround != 0
+	c.Inputs.OnGround = Flags&onGroundFlag != 0
 	c.Inputs.Unlock()
 	return nil
 }
@@ -91,3 +99,49 @@ func clientMovePlayerStatusOnly(p pk.Packet, c *Client) error {
 func clientMoveVehicle(_ pk.Packet, _ *Client) error {
 	return nil
 }
+
+// flyingFlag is the bit of the abilities set when the player flies.
+const flyingFlag = 0x02
+
+// clientPlayerAbilities records whether the player flies, which the world checks it may.
+func clientPlayerAbilities(p pk.Packet, c *Client) error {
+	var Flags pk.Byte
+	if err := p.Scan(&Flags); err != nil {
+		return err
+	}
+	c.Inputs.Lock()
+	c.Inputs.Flying = Flags&flyingFlag != 0
+	c.Inputs.Unlock()
+	return nil
+}
+
+// The actions of ServerboundPlayerCommand used by the movement checks.
+const (
+	commandStartSprinting  = 1
+	commandStopSprinting   = 2
+	commandStartFallFlying = 6
+	chestSlot              = container.ArmorSlot + 2
+)
+
+// clientPlayerCommand records the sprinting and the gliding of the player.
+// The player only starts gliding wearing an elytra, and stops when it lands.
+func clientPlayerCommand(p pk.Packet, c *Client) error {
+	var EntityID, Action, JumpBoost pk.VarInt
+	if err := p.Scan(&EntityID, &Action, &JumpBoost); err != nil {
+		return err
+	}
+	switch Action {
+	case commandStartSprinting, commandStopSprinting:
+		c.Inputs.Lock()
+		c.Inputs.Sprinting = Action == commandStartSprinting
+		c.Inputs.Unlock()
+	case commandStartFallFlying:
+		c.player.ContainerLock.Lock()
+		glider := c.player.Inventory[chestSlot].IsGlider()
+		c.player.ContainerLock.Unlock()
+		c.Inputs.Lock()
+		c.Inputs.FallFlying = glider && !bool(c.Inputs.OnGround)
+		c.Inputs.Unlock()
+	}
+	return nil
+}
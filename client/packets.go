@@ -28,12 +28,19 @@ import (
 
 	"github.com/mrhaoxx/go-mc/chat"
 	"github.com/mrhaoxx/go-mc/chat/sign"
+	"github.com/mrhaoxx/go-mc/data/inventory"
 	"github.com/mrhaoxx/go-mc/data/packetid"
 	"github.com/mrhaoxx/go-mc/data/registryid"
 	"github.com/mrhaoxx/go-mc/hpcworld"
 	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/recipe"
+	"github.com/mrhaoxx/go-mc/nbt"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/server/command"
 	"github.com/mrhaoxx/go-mc/world"
+	"github.com/mrhaoxx/go-mc/world/entity"
 )
 
 func (c *Client) SendPacket(id packetid.ClientboundPacketID, fields ...pk.FieldEncoder) {
@@ -80,36 +87,55 @@ func (c *Client) SendDisconnect(reason chat.Message) {
 // pk.Boolean(false),         // Is Debug
 // pk.Boolean(false),         // Is Flat
 // pk.Boolean(false),         // Has Last Death Location
-func (c *Client) SendLogin(w *world.World, p *world.Player) {
+func (c *Client) SendLogin(w *world.World, p *world.Player, dimensions []string) {
 	zap.L().Info("SendLogin", zap.Int32("eid", p.EntityID), zap.Int32("viewDistance", p.ViewDistance))
-	hashedSeed := w.HashedSeed()
+	names := make([]pk.Identifier, len(dimensions))
+	for i, name := range dimensions {
+		names[i] = pk.Identifier(name)
+	}
 	c.SendPacket(
 		packetid.ClientboundLogin,
 		pk.Int(p.EntityID),
-		pk.Boolean(false), // Is Hardcore
-		pk.Array([]pk.Identifier{
-			pk.Identifier(w.Name()),
-		}), // Dimension Name
-		pk.VarInt(20),                        // Max players (ignored by client)
-		pk.VarInt(p.ViewDistance),            // View Distance
-		pk.VarInt(p.ViewDistance),            // Simulation Distance
-		pk.Boolean(false),                    // Reduced Debug Info
-		pk.Boolean(true),                     // Enable respawn screen
-		pk.Boolean(false),                    // Do Limit Crafting
-		pk.VarInt(0),                         // World Info Dimension Type
-		pk.Identifier("minecraft:overworld"), // World Info Dimension Name
-		pk.Long(binary.BigEndian.Uint64(hashedSeed[:8])), // World Info Hashed Seed
-		pk.Byte(1),        // World Info Gamemode
-		pk.Byte(0),        // World Info Previous Gamemode
-		pk.Boolean(false), // World Info Is Debug
-		pk.Boolean(false), // World Info Is Flat
-		pk.Boolean(false), // World Info Has Last Death Location
-		pk.VarInt(40),
-		pk.VarInt(40),
-		pk.Boolean(true),
+		pk.Boolean(false),                 // Is Hardcore
+		pk.Array(names),                   // Dimension Names
+		pk.VarInt(20),                     // Max players (ignored by client)
+		pk.VarInt(p.ViewDistance),         // View Distance
+		pk.VarInt(p.ViewDistance),         // Simulation Distance
+		pk.Boolean(false),                 // Reduced Debug Info
+		pk.Boolean(!w.ImmediateRespawn()), // Enable respawn screen
+		pk.Boolean(false),                 // Do Limit Crafting
+		spawnInfo(w, p),
+		pk.Boolean(true), // Enforces Secure Chat
 	)
 }
 
+// SendRespawn sends ClientboundRespawn, making the client leave its world for the dimension of w.
+// The client drops its chunks and entities, so they are sent again by w.
+func (c *Client) SendRespawn(w *world.World, p *world.Player) {
+	c.SendPacket(
+		packetid.ClientboundRespawn,
+		spawnInfo(w, p),
+		pk.Byte(0x03), // Data Kept: attributes and metadata
+	)
+}
+
+// spawnInfo is the world info shared by ClientboundLogin and ClientboundRespawn.
+func spawnInfo(w *world.World, p *world.Player) pk.Tuple {
+	hashedSeed := w.HashedSeed()
+	return pk.Tuple{
+		pk.VarInt(w.DimensionType()),                     // Dimension Type
+		pk.Identifier(w.Name()),                          // Dimension Name
+		pk.Long(binary.BigEndian.Uint64(hashedSeed[:8])), // Hashed Seed
+		pk.Byte(p.Gamemode),                              // Gamemode
+		pk.Byte(0),                                       // Previous Gamemode
+		pk.Boolean(false),                                // Is Debug
+		pk.Boolean(false),                                // Is Flat
+		pk.Boolean(false),                                // Has Last Death Location
+		pk.VarInt(40),                                    // Portal Cooldown
+		pk.VarInt(40),                                    // Sea Level
+	}
+}
+
 func (c *Client) SendGameEvent(event pk.UnsignedByte, value pk.Float) {
 	c.SendPacket(
 		packetid.ClientboundGameEvent,
@@ -208,6 +234,87 @@ func (c *Client) SendForgetLevelChunk(pos level.ChunkPos) {
 	c.SendPacket(packetid.ClientboundForgetLevelChunk, pos)
 }
 
+func (c *Client) SendBlockUpdate(pos [3]int32, state level.BlocksState) {
+	c.SendPacket(
+		packetid.ClientboundBlockUpdate,
+		pk.Position{X: int(pos[0]), Y: int(pos[1]), Z: int(pos[2])},
+		pk.VarInt(state),
+	)
+}
+
+// SendSectionBlocksUpdate sends multiple changed blocks in one chunk section.
+// Each block is packed as state<<12 | x<<8 | z<<4 | y, where x, y, z are relative to the section.
+func (c *Client) SendSectionBlocksUpdate(section [3]int32, changes []world.BlockChange) {
+	sectionPos := int64(section[0]&0x3FFFFF)<<42 | int64(section[2]&0x3FFFFF)<<20 | int64(section[1]&0xFFFFF)
+	blocks := make([]pk.VarLong, len(changes))
+	for i, v := range changes {
+		x, y, z := int64(v.Pos[0]&15), int64(v.Pos[1]&15), int64(v.Pos[2]&15)
+		blocks[i] = pk.VarLong(int64(v.State)<<12 | x<<8 | z<<4 | y)
+	}
+	c.SendPacket(
+		packetid.ClientboundSectionBlocksUpdate,
+		pk.Long(sectionPos),
+		pk.Array(blocks),
+	)
+}
+
+// SendLightUpdate sends the sky and block light of the chunk sections in the mask.
+// The light sections start from the one below the world, so section i is the bit i+1.
+func (c *Client) SendLightUpdate(pos level.ChunkPos, chunk *hpcworld.Chunk, sections uint32) {
+	mask := pk.BitSet{int64(sections) << 1}
+	var skyLight, blockLight []pk.ByteArray
+	for i := range chunk.Sections {
+		if sections&(1<<i) == 0 {
+			continue
+		}
+		s := &chunk.Sections[i]
+		skyLight = append(skyLight, lightArray(&s.SkyLight))
+		blockLight = append(blockLight, lightArray(&s.BlockLight))
+	}
+	c.SendPacket(
+		packetid.ClientboundLightUpdate,
+		pk.VarInt(pos[0]),
+		pk.VarInt(pos[1]),
+		mask,        // Sky Light Mask
+		mask,        // Block Light Mask
+		pk.BitSet{}, // Empty Sky Light Mask
+		pk.BitSet{}, // Empty Block Light Mask
+		pk.Array(skyLight),
+		pk.Array(blockLight),
+	)
+}
+
+func lightArray(l *[2048]int8) pk.ByteArray {
+	b := make(pk.ByteArray, len(l))
+	for i, v := range l {
+		b[i] = byte(v)
+	}
+	return b
+}
+
+func (c *Client) SendBlockEntityData(pos [3]int32, typ block.EntityType, data nbt.RawMessage) {
+	c.SendPacket(
+		packetid.ClientboundBlockEntityData,
+		pk.Position{X: int(pos[0]), Y: int(pos[1]), Z: int(pos[2])},
+		pk.VarInt(typ),
+		pk.NBT(data),
+	)
+}
+
+// SendOpenSignEditor opens the editor of the sign on the side facing the player or the other one.
+func (c *Client) SendOpenSignEditor(pos [3]int32, front bool) {
+	c.SendPacket(
+		packetid.ClientboundOpenSignEditor,
+		pk.Position{X: int(pos[0]), Y: int(pos[1]), Z: int(pos[2])},
+		pk.Boolean(front),
+	)
+}
+
+// SendBlockChangedAck acknowledges the block interactions up to the sequence number.
+func (c *Client) SendBlockChangedAck(sequence int32) {
+	c.SendPacket(packetid.ClientboundBlockChangedAck, pk.VarInt(sequence))
+}
+
 func (c *Client) SendAddPlayer(p *world.Player) {
 	// Spawn the player entity for viewers.
 	// Use AddEntity with entity type set to "minecraft:player".
@@ -333,14 +440,57 @@ func (c *Client) SendSetDefaultSpawnPosition(xyz [3]int32, angle float32) {
 	return
 }
 
-func (c *Client) SendSetPlayerInventorySlot(slot int32, stack *world.ItemStack) {
-	localStack := &ItemStack{
-		ItemID: stack.ItemID,
-		Count:  stack.Count,
+func (c *Client) SendSetPlayerInventorySlot(slot int32, stack item.ItemStack) {
+	c.SendPacket(packetid.ClientboundSetPlayerInventory, pk.VarInt(slot), stack)
+}
+
+func (c *Client) SendOpenScreen(windowID int32, typ inventory.InventoryID, title chat.Message) {
+	c.SendPacket(packetid.ClientboundOpenScreen, pk.VarInt(windowID), pk.VarInt(typ), title)
+}
+
+func (c *Client) SendContainerClose(windowID int32) {
+	c.SendPacket(packetid.ClientboundContainerClose, pk.VarInt(windowID))
+}
+
+func (c *Client) SendContainerSetContent(windowID, stateID int32, items []item.ItemStack, carried item.ItemStack) {
+	c.SendPacket(packetid.ClientboundContainerSetContent, pk.VarInt(windowID), pk.VarInt(stateID), pk.Array(items), carried)
+}
+
+func (c *Client) SendContainerSetSlot(windowID, stateID int32, slot int16, stack item.ItemStack) {
+	c.SendPacket(packetid.ClientboundContainerSetSlot, pk.VarInt(windowID), pk.VarInt(stateID), pk.Short(slot), stack)
+}
+
+func (c *Client) SendContainerSetData(windowID int32, property, value int16) {
+	c.SendPacket(packetid.ClientboundContainerSetData, pk.VarInt(windowID), pk.Short(property), pk.Short(value))
+}
+
+func (c *Client) SendSetCursorItem(stack item.ItemStack) {
+	c.SendPacket(packetid.ClientboundSetCursorItem, stack)
+}
+
+// SendUpdateRecipes sends the content of recipe.Manager.UpdateRecipes.
+func (c *Client) SendUpdateRecipes(recipes pk.FieldEncoder) {
+	c.SendPacket(packetid.ClientboundUpdateRecipes, recipes)
+}
+
+// SendRecipeBookSettings sends whether the recipe books of the crafting table, the furnace,
+// the blast furnace and the smoker are open and filtering the craftable recipes, in this order.
+func (c *Client) SendRecipeBookSettings(open, filtering [4]bool) {
+	var settings pk.Tuple
+	for i := range open {
+		settings = append(settings, pk.Boolean(open[i]), pk.Boolean(filtering[i]))
 	}
-	fields := []pk.FieldEncoder{pk.VarInt(slot)}
-	fields = append(fields, localStack.encodeFields()...)
-	c.SendPacket(packetid.ClientboundSetPlayerInventory, fields...)
+	c.SendPacket(packetid.ClientboundRecipeBookSettings, settings)
+}
+
+// SendRecipeBookAdd unlocks the recipes in the recipe book, replacing the unlocked ones if replace is set.
+func (c *Client) SendRecipeBookAdd(entries []recipe.BookEntry, replace bool) {
+	c.SendPacket(packetid.ClientboundRecipeBookAdd, pk.Array(entries), pk.Boolean(replace))
+}
+
+// SendPlaceGhostRecipe shows the recipe in the crafting grid of the window, when the player misses the ingredients.
+func (c *Client) SendPlaceGhostRecipe(windowID int32, display pk.FieldEncoder) {
+	c.SendPacket(packetid.ClientboundPlaceGhostRecipe, pk.VarInt(windowID), display)
 }
 
 func (c *Client) SendRemoveEntities(entityIDs []int32) {
@@ -391,10 +541,27 @@ func (c *Client) SendSetChunkCacheCenter(chunkPos [2]int32) {
 	)
 }
 
-func (c *Client) ViewChunkLoad(pos level.ChunkPos, chunk *hpcworld.Chunk) {
-	c.SendLevelChunkWithLight(pos, hpcworld.LevelChunkFromHPC(chunk))
+func (c *Client) ViewChunkLoad(pos level.ChunkPos, chunk *hpcworld.Chunk, blockEntities []level.BlockEntity) {
+	lc := hpcworld.LevelChunkFromHPC(chunk)
+	lc.BlockEntity = blockEntities
+	c.SendLevelChunkWithLight(pos, lc)
+}
+func (c *Client) ViewChunkUnload(pos level.ChunkPos) { c.SendForgetLevelChunk(pos) }
+func (c *Client) ViewBlockUpdate(pos [3]int32, state level.BlocksState) {
+	c.SendBlockUpdate(pos, state)
+}
+
+func (c *Client) ViewSectionBlocksUpdate(section [3]int32, changes []world.BlockChange) {
+	c.SendSectionBlocksUpdate(section, changes)
+}
+
+func (c *Client) ViewLightUpdate(pos level.ChunkPos, chunk *hpcworld.Chunk, sections uint32) {
+	c.SendLightUpdate(pos, chunk, sections)
+}
+
+func (c *Client) ViewBlockEntityData(pos [3]int32, typ block.EntityType, data nbt.RawMessage) {
+	c.SendBlockEntityData(pos, typ, data)
 }
-func (c *Client) ViewChunkUnload(pos level.ChunkPos)   { c.SendForgetLevelChunk(pos) }
 func (c *Client) ViewAddPlayer(p *world.Player)        { c.SendAddPlayer(p) }
 func (c *Client) ViewRemoveEntities(entityIDs []int32) { c.SendRemoveEntities(entityIDs) }
 func (c *Client) ViewMoveEntityPos(id int32, delta [3]int16, onGround bool) {
@@ -458,6 +625,33 @@ func (c *Client) SendAnimate(eid int32, animation byte) {
 	)
 }
 
+// ViewSetEntityData sends the metadata of an entity.
+func (c *Client) ViewSetEntityData(id int32, metadata entity.MetadataSet) {
+	c.SendPacket(packetid.ClientboundSetEntityData, pk.VarInt(id), metadata)
+}
+
+// ViewBlockDestruction shows the cracks of a block being broken by an entity.
+func (c *Client) ViewBlockDestruction(id int32, pos [3]int32, stage int8) {
+	c.SendPacket(
+		packetid.ClientboundBlockDestruction,
+		pk.VarInt(id),
+		pk.Position{X: int(pos[0]), Y: int(pos[1]), Z: int(pos[2])},
+		pk.Byte(stage),
+	)
+}
+
+// ViewDamageEvent shows the entity hurt, which plays its hurt animation and sound.
+func (c *Client) ViewDamageEvent(id int32, damageType int32, attackerID int32) {
+	c.SendPacket(
+		packetid.ClientboundDamageEvent,
+		pk.VarInt(id),
+		pk.VarInt(damageType),
+		pk.VarInt(attackerID+1), // Source Cause ID, 0 if none
+		pk.VarInt(attackerID+1), // Source Direct ID
+		pk.Boolean(false),       // Has Source Position
+	)
+}
+
 // ViewAddEntity spawns a generic entity for the viewer by registry name.
 func (c *Client) ViewAddEntity(e *world.Entity, typeName string) {
 	// Resolve entity type ID
@@ -491,3 +685,59 @@ func (c *Client) ViewAddEntity(e *world.Entity, typeName string) {
 		pk.Short(0),
 	)
 }
+
+// SendCommands sends the command graph, which is usually a command.Graph or the visible part of it.
+func (c *Client) SendCommands(commands pk.FieldEncoder) {
+	c.SendPacket(packetid.ClientboundCommands, commands)
+}
+
+// SendEntityEvent emits ClientboundEntityEvent with the entity status.
+func (c *Client) SendEntityEvent(eid int32, status byte) {
+	c.SendPacket(
+		packetid.ClientboundEntityEvent,
+		pk.Int(eid),
+		pk.Byte(status),
+	)
+}
+
+// SendSetHealth sends the health, the food level and the saturation of the player.
+func (c *Client) SendSetHealth(health float32, food int32, saturation float32) {
+	c.SendPacket(
+		packetid.ClientboundSetHealth,
+		pk.Float(health),
+		pk.VarInt(food),
+		pk.Float(saturation),
+	)
+}
+
+// SendSetExperience sends the experience of the player, its progress toward the next level from 0 to 1.
+func (c *Client) SendSetExperience(progress float32, level, total int32) {
+	c.SendPacket(
+		packetid.ClientboundSetExperience,
+		pk.Float(progress),
+		pk.VarInt(level),
+		pk.VarInt(total),
+	)
+}
+
+// SendPlayerCombatKill tells the client its player died with the message,
+// which shows the respawn screen unless it was disabled at login.
+func (c *Client) SendPlayerCombatKill(id int32, message chat.Message) {
+	c.SendPacket(
+		packetid.ClientboundPlayerCombatKill,
+		pk.VarInt(id),
+		message,
+	)
+}
+
+// SendCommandSuggestions answers the ServerboundCommandSuggestion of the id,
+// the suggestions replace the length characters from the start of the text.
+func (c *Client) SendCommandSuggestions(id int32, start, length int, suggestions []command.Suggestion) {
+	c.SendPacket(
+		packetid.ClientboundCommandSuggestions,
+		pk.VarInt(id),
+		pk.VarInt(start),
+		pk.VarInt(length),
+		pk.Array(suggestions),
+	)
+}
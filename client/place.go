@@ -0,0 +1,99 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/world/container"
+)
+
+var canPlaceOnType, _ = component.TypeID(&component.CanPlaceOn{})
+
+// placeBlock places the block item held in the hand, 0 for the main hand and 1 for the off hand,
+// by clicking on the face of a block. The item is used up unless the player is in creative mode.
+func (c *Client) placeBlock(hand int32, clicked [3]int, face block.Direction, cursor [3]float64) {
+	if c.player.Gamemode == 3 {
+		return
+	}
+	slot := container.OffhandSlot
+	if hand == 0 {
+		if c.player.CarriedSlot < 0 || c.player.CarriedSlot >= container.HotbarSize {
+			return
+		}
+		slot = int(c.player.CarriedSlot)
+	}
+	c.player.ContainerLock.Lock()
+	stack := c.player.Inventory[slot]
+	c.player.ContainerLock.Unlock()
+	if stack.IsEmpty() {
+		return
+	}
+	// In adventure mode, blocks are only placed on the blocks allowed by the item.
+	if !c.player.MayBuild() && !c.canPlaceOn(stack, clicked) {
+		return
+	}
+
+	c.Inputs.Lock()
+	rot := c.Inputs.Rotation
+	c.Inputs.Unlock()
+	placed, ok := c.world.PlaceBlock(c, block.PlaceContext{
+		Item:    stack.ItemID.Name(),
+		Clicked: clicked,
+		Face:    face,
+		Cursor:  cursor,
+		Yaw:     rot[0],
+		Pitch:   rot[1],
+	})
+	if !ok {
+		return
+	}
+	if !c.player.HasInfiniteMaterials() {
+		c.player.ContainerLock.Lock()
+		if s := &c.player.Inventory[slot]; s.ItemID == stack.ItemID && s.Count > 0 {
+			s.Count--
+		}
+		c.player.ContainerMenu.BroadcastChanges()
+		c.player.ContainerLock.Unlock()
+	}
+	// The text of a new sign is written right after it's placed.
+	pos := placed[0].Pos
+	if e, ok := c.world.BlockEntity(pos[0], pos[1], pos[2]); ok {
+		if _, ok := signOf(e); ok && c.player.MayBuild() {
+			c.openSignEditor([3]int32{int32(pos[0]), int32(pos[1]), int32(pos[2])}, true)
+		}
+	}
+}
+
+// canPlaceOn reports whether the CanPlaceOn component of the stack allows placing blocks on the clicked block.
+func (c *Client) canPlaceOn(stack item.ItemStack, clicked [3]int) bool {
+	comp, ok := stack.Component(canPlaceOnType)
+	if !ok {
+		return false
+	}
+	state, loaded := c.world.GetBlock(clicked[0], clicked[1], clicked[2])
+	if !loaded {
+		return false
+	}
+	for _, p := range comp.(*component.CanPlaceOn).Predicates {
+		if block.MatchPredicate(p, block.StateID(state)) {
+			return true
+		}
+	}
+	return false
+}
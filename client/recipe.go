@@ -0,0 +1,60 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"github.com/mrhaoxx/go-mc/level/recipe"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// InitRecipeBook sends the recipes and unlocks all of them in the recipe book.
+// It's called once after the login.
+func (c *Client) InitRecipeBook() {
+	c.SendUpdateRecipes(c.recipes.UpdateRecipes())
+	c.SendRecipeBookSettings([4]bool{}, [4]bool{})
+	c.SendRecipeBookAdd(c.recipes.BookEntries(), true)
+}
+
+// clientPlaceRecipe fills the crafting grid with the ingredients of the recipe clicked in the recipe book.
+func clientPlaceRecipe(p pk.Packet, c *Client) error {
+	var (
+		windowID, recipeID pk.VarInt
+		useMaxItems        pk.Boolean
+	)
+	if err := p.Scan(&windowID, &recipeID, &useMaxItems); err != nil {
+		return err
+	}
+	h, ok := c.recipes.Get(int32(recipeID))
+	if !ok {
+		return nil
+	}
+	r, ok := h.Recipe.(recipe.CraftingRecipe)
+	if !ok {
+		return nil
+	}
+	c.player.ContainerLock.Lock()
+	defer c.player.ContainerLock.Unlock()
+	m := c.player.ContainerMenu
+	if m.ID != int32(windowID) || m.Crafting == nil || c.player.Gamemode == 3 {
+		return nil
+	}
+	if !m.PlaceRecipe(c.player, r, bool(useMaxItems)) {
+		c.SendPlaceGhostRecipe(m.ID, h.Display())
+	}
+	m.BroadcastChanges()
+	return nil
+}
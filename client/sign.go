@@ -0,0 +1,126 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/level/block"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/world"
+)
+
+// signEditRange is the distance from the eyes a sign can be edited within,
+// which is the block interaction range plus the margin vanilla allows.
+const signEditRange = 4.5 + 4
+
+// maxSignLineLength is the number of UTF-16 characters of each line sent by the sign editor, like vanilla.
+const maxSignLineLength = 384
+
+// signOf returns the sign data of a sign or hanging sign entity.
+func signOf(e block.Entity) (sign block.SignEntity, ok bool) {
+	switch e := e.(type) {
+	case block.SignEntity:
+		return e, true
+	case block.HangingSignEntity:
+		return block.SignEntity(e), true
+	}
+	return sign, false
+}
+
+// useSign opens the editor of the sign at pos on the side the player looks at,
+// unless the sign is waxed or the player can't build.
+// It reports whether there is a sign, which takes the click instead of the held item.
+func (c *Client) useSign(pos [3]int32) bool {
+	e, ok := c.world.BlockEntity(int(pos[0]), int(pos[1]), int(pos[2]))
+	if !ok {
+		return false
+	}
+	sign, ok := signOf(e)
+	if !ok || sign.IsWaxed || !c.player.MayBuild() {
+		return ok
+	}
+	state, _ := c.world.GetBlock(int(pos[0]), int(pos[1]), int(pos[2]))
+	eye := c.player.Position
+	eye[1] += world.PlayerEyeHeight
+	c.openSignEditor(pos, world.FacingFrontText(block.StateList[state], pos, eye))
+	return true
+}
+
+// openSignEditor opens the editor of the sign, and the player is allowed to change the text of the side.
+func (c *Client) openSignEditor(pos [3]int32, front bool) {
+	c.editingSign = &pos
+	c.SendOpenSignEditor(pos, front)
+}
+
+// clientSignUpdate changes the text of the sign the player was editing.
+func clientSignUpdate(p pk.Packet, c *Client) error {
+	var (
+		pos   pk.Position
+		front pk.Boolean
+		lines [4]pk.String
+	)
+	if err := p.Scan(&pos, &front, &lines[0], &lines[1], &lines[2], &lines[3]); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		// Like vanilla, the client sending longer lines is disconnected.
+		if n := len(utf16.Encode([]rune(string(line)))); n > maxSignLineLength {
+			return fmt.Errorf("sign line of %d characters exceeds %d", n, maxSignLineLength)
+		}
+	}
+	at := [3]int32{int32(pos.X), int32(pos.Y), int32(pos.Z)}
+	if c.editingSign == nil || *c.editingSign != at {
+		return nil
+	}
+	c.editingSign = nil
+	eye := c.player.Position
+	eye[1] += world.PlayerEyeHeight
+	var d2 float64
+	for i := range eye {
+		d := eye[i] - (float64(at[i]) + 0.5)
+		d2 += d * d
+	}
+	if d2 > signEditRange*signEditRange {
+		return nil
+	}
+	e, ok := c.world.BlockEntity(pos.X, pos.Y, pos.Z)
+	if !ok {
+		return nil
+	}
+	sign, ok := signOf(e)
+	if !ok || sign.IsWaxed {
+		return nil
+	}
+	text := &sign.FrontText
+	if !front {
+		text = &sign.BackText
+	}
+	for i, line := range lines {
+		// Like vanilla, the formatting codes are removed.
+		stripped, _ := chat.TransCtrlSeq(string(line), false)
+		text.Messages[i] = block.Text(chat.Text(stripped))
+	}
+	if _, ok := e.(block.HangingSignEntity); ok {
+		c.world.SetBlockEntity(pos.X, pos.Y, pos.Z, block.HangingSignEntity(sign))
+	} else {
+		c.world.SetBlockEntity(pos.X, pos.Y, pos.Z, sign)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/mrhaoxx/go-mc/data/entity"
+)
+
+type class struct {
+	Name     string      `json:"name"`
+	Parent   string      `json:"parent"`
+	Fields   [][2]string `json:"fields"`
+	Entities []string    `json:"entities"`
+}
+
+// The classes are transcribed from the defineSynchedData methods of the entities of vanilla,
+// the fields in the order of their EntityDataAccessor, named without the DATA_ prefix and the _ID suffix.
+// The classes defining no field are left out, the types of entities are listed in their closest ancestor defining some.
+//
+//go:embed classes.json
+var classesJson []byte
+
+//go:embed template.go.tmpl
+var tempSource string
+
+type tempData struct {
+	Classes []class
+	ClassOf map[string]string
+}
+
+var temp = template.Must(template.
+	New("class_template").
+	Funcs(template.FuncMap{
+		"Generator": func() string { return "data/entity/generator/generate.go" },
+	}).
+	Parse(tempSource),
+)
+
+func main() {
+	var classes []class
+	if err := json.Unmarshal(classesJson, &classes); err != nil {
+		log.Fatal(err)
+	}
+
+	defined := make(map[string]bool)
+	classOf := make(map[string]string)
+	for _, c := range classes {
+		if c.Parent != "" && !defined[c.Parent] {
+			log.Fatalf("the parent %s of %s isn't defined before it", c.Parent, c.Name)
+		}
+		defined[c.Name] = true
+		for _, name := range c.Entities {
+			classOf[name] = c.Name
+		}
+	}
+	for _, e := range entity.ByID {
+		if _, ok := classOf[e.Name]; !ok {
+			log.Fatalf("the entity %s has no class", e.Name)
+		}
+	}
+
+	var buff bytes.Buffer
+	if err := temp.Execute(&buff, tempData{Classes: classes, ClassOf: classOf}); err != nil {
+		log.Fatal(err)
+	}
+	formattedSource, err := format.Source(buff.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("..", "class.go"), formattedSource, 0o666); err != nil {
+		log.Fatal(err)
+	}
+}
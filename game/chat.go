@@ -27,6 +27,7 @@ import (
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 	"github.com/mrhaoxx/go-mc/registry"
 	"github.com/mrhaoxx/go-mc/server"
+	"github.com/mrhaoxx/go-mc/world"
 )
 
 const MsgExpiresTime = time.Minute * 5
@@ -43,6 +44,11 @@ func (g *globalChat) broadcastSystemChat(msg chat.Message, overlay bool) {
 	})
 }
 
+// broadcastDeath tells everyone the death message of the player.
+func (g *globalChat) broadcastDeath(_ world.Client, _ *world.Player, msg chat.Message) {
+	g.broadcastSystemChat(msg, false)
+}
+
 func (g *globalChat) Handle(p pk.Packet, c *client.Client) error {
 	var (
 		message       pk.String
@@ -0,0 +1,294 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package game
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/client"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/server/command"
+	"github.com/mrhaoxx/go-mc/world"
+)
+
+// maxFillVolume is the maximum number of blocks changed by one /fill, same as vanilla.
+const maxFillVolume = 32768
+
+// commandSource is a player executing a command.
+type commandSource struct {
+	*client.Client
+	game *Game
+}
+
+func (s commandSource) PermissionLevel() int { return s.GetPlayer().PermissionLevel }
+
+func (s commandSource) OnlinePlayers() []string { return s.game.playerList.names() }
+
+// Target returns the block at the crosshair of the player within the reach.
+func (s commandSource) Target() (pos [3]float64, block [3]int, ok bool) {
+	p := s.GetPlayer()
+	eye := p.Position
+	eye[1] += world.PlayerEyeHeight
+	reach := survivalReach
+	if p.Gamemode == 1 {
+		reach = creativeReach
+	}
+	return s.World().Raycast(eye, world.LookVector(p.Rotation), reach)
+}
+
+// The block interaction ranges of players.
+const (
+	survivalReach = 4.5
+	creativeReach = 5
+)
+
+// sourceClient returns the client executing the command,
+// or an error if the command isn't run by a player, like the commands registered by other code may be.
+func sourceClient(ctx context.Context) (*client.Client, error) {
+	s, ok := command.SourceFrom(ctx).(commandSource)
+	if !ok {
+		return nil, commandError(chat.TranslateMsg("permissions.requires.player"))
+	}
+	return s.Client, nil
+}
+
+// commandError is an error shown to the player as the message.
+type commandError chat.Message
+
+func (e commandError) Error() string { return chat.Message(e).ClearString() }
+
+// Commands returns the command graph of the game.
+// More commands can be registered before any player joins.
+func (g *Game) Commands() *command.Graph {
+	return g.commands
+}
+
+func (g *Game) registerCommands() {
+	c := g.commands
+	c.AppendLiteral(c.Literal("ping").
+		HandleFunc(g.pingCommand),
+	).AppendLiteral(c.Literal("tp").Requires(2).
+		AppendArgument(c.Argument("location", command.Vec3Parser{}).Suggests(command.SuggestVec3).
+			HandleFunc(g.tpCommand)).
+		Unhandle(),
+	).AppendLiteral(c.Literal("setblock").Requires(2).
+		AppendArgument(c.Argument("pos", command.BlockPosParser{}).Suggests(command.SuggestBlockPos).
+			AppendArgument(c.Argument("block", command.BlockStateParser{}).Suggests(command.SuggestBlocks).
+				HandleFunc(g.setblockCommand)).
+			Unhandle()).
+		Unhandle(),
+	).AppendLiteral(c.Literal("fill").Requires(2).
+		AppendArgument(c.Argument("from", command.BlockPosParser{}).Suggests(command.SuggestBlockPos).
+			AppendArgument(c.Argument("to", command.BlockPosParser{}).Suggests(command.SuggestBlockPos).
+				AppendArgument(c.Argument("block", command.BlockStateParser{}).Suggests(command.SuggestBlocks).
+					HandleFunc(g.fillCommand)).
+				Unhandle()).
+			Unhandle()).
+		Unhandle(),
+	).AppendLiteral(c.Literal("dimension").Requires(2).
+		AppendArgument(c.Argument("dimension", command.DimensionParser{}).
+			AppendArgument(c.Argument("location", command.Vec3Parser{}).Suggests(command.SuggestVec3).
+				HandleFunc(g.dimensionCommand)).
+			HandleFunc(g.dimensionCommand)).
+		Unhandle(),
+	).AppendLiteral(c.Literal("gamerule").Requires(2).
+		AppendLiteral(c.Literal("randomTickSpeed").
+			AppendArgument(c.Argument("value", command.IntegerParser{Min: math.MinInt32, Max: math.MaxInt32}).
+				HandleFunc(g.randomTickSpeedCommand)).
+			HandleFunc(g.randomTickSpeedCommand)).
+		Unhandle(),
+	)
+}
+
+// handleCommand executes the ServerboundChatCommand from the player.
+func (g *Game) handleCommand(p pk.Packet, c *client.Client) error {
+	var cmd pk.String
+	if err := p.Scan(&cmd); err != nil {
+		return err
+	}
+	g.log.Info("Player issued command", zap.String("name", c.GetPlayer().Name), zap.String("command", string(cmd)))
+
+	ctx := command.WithSource(context.TODO(), commandSource{c, g})
+	err := g.commands.Execute(ctx, string(cmd))
+	var perr command.ParseErr
+	var cerr commandError
+	switch {
+	case err == nil:
+	case errors.As(err, &perr):
+		c.SendSystemChat(chat.Text(perr.Err).SetColor(chat.Red), false)
+		c.SendSystemChat(parseErrContext(string(cmd), perr.Pos), false)
+	case errors.As(err, &cerr):
+		c.SendSystemChat(chat.Message(cerr).SetColor(chat.Red), false)
+	default:
+		g.log.Error("Command failed", zap.String("command", string(cmd)), zap.Error(err))
+		c.SendSystemChat(chat.TranslateMsg("command.failed").SetColor(chat.Red), false)
+	}
+	return nil
+}
+
+// handleSuggestion answers the ServerboundCommandSuggestion sent when the player is typing a command.
+func (g *Game) handleSuggestion(p pk.Packet, c *client.Client) error {
+	var (
+		id   pk.VarInt
+		text pk.String
+	)
+	if err := p.Scan(&id, &text); err != nil {
+		return err
+	}
+	cmd := strings.TrimPrefix(string(text), "/")
+	ctx := command.WithSource(context.TODO(), commandSource{c, g})
+	start, suggestions := g.commands.Suggest(ctx, cmd)
+	// the range is in the text including the slash
+	offset := len(text) - len(cmd)
+	c.SendCommandSuggestions(int32(id), offset+start, len(cmd)-start, suggestions)
+	return nil
+}
+
+// parseErrContext shows where the command failed to parse, like vanilla does.
+func parseErrContext(cmd string, pos int) chat.Message {
+	pos = min(pos, len(cmd))
+	before := cmd[:pos]
+	if len(before) > 10 {
+		before = "..." + before[len(before)-10:]
+	}
+	msg := chat.Text(before).SetColor(chat.Gray)
+	if pos < len(cmd) {
+		after := chat.Text(cmd[pos:]).SetColor(chat.Red)
+		after.UnderLined = true
+		msg = msg.Append(after)
+	}
+	here := chat.TranslateMsg("command.context.here").SetColor(chat.Red)
+	here.Italic = true
+	return msg.Append(here)
+}
+
+func (g *Game) pingCommand(ctx context.Context, _ []command.ParsedData) error {
+	c, err := sourceClient(ctx)
+	if err != nil {
+		return err
+	}
+	c.SendSystemChat(chat.Text("Pong!"), false)
+	return nil
+}
+
+func (g *Game) tpCommand(ctx context.Context, args []command.ParsedData) error {
+	c, err := sourceClient(ctx)
+	if err != nil {
+		return err
+	}
+	p := c.GetPlayer()
+	pos := args[2].(command.Coordinates).Resolve(p.Position, p.Rotation)
+	c.World().Teleport(c, pos)
+	c.SendSystemChat(chat.TranslateMsg("commands.teleport.success.location.single",
+		chat.Text(p.Name),
+		chat.Text(strconv.FormatFloat(pos[0], 'f', -1, 64)),
+		chat.Text(strconv.FormatFloat(pos[1], 'f', -1, 64)),
+		chat.Text(strconv.FormatFloat(pos[2], 'f', -1, 64)),
+	), false)
+	return nil
+}
+
+func (g *Game) setblockCommand(ctx context.Context, args []command.ParsedData) error {
+	c, err := sourceClient(ctx)
+	if err != nil {
+		return err
+	}
+	pos := blockPos(c, args[2])
+	state := args[3].(command.BlockStateData).State
+	if c.World().IsOutsideBuildHeight(pos[1]) {
+		return commandError(chat.TranslateMsg("argument.pos.outofworld"))
+	}
+	if !c.World().SetBlock(pos[0], pos[1], pos[2], state) {
+		return commandError(chat.TranslateMsg("argument.pos.unloaded"))
+	}
+	c.SendSystemChat(chat.TranslateMsg("commands.setblock.success",
+		chat.Text(strconv.Itoa(pos[0])), chat.Text(strconv.Itoa(pos[1])), chat.Text(strconv.Itoa(pos[2])),
+	), false)
+	return nil
+}
+
+func (g *Game) fillCommand(ctx context.Context, args []command.ParsedData) error {
+	c, err := sourceClient(ctx)
+	if err != nil {
+		return err
+	}
+	from, to := blockPos(c, args[2]), blockPos(c, args[3])
+	state := args[4].(command.BlockStateData).State
+	for i := range from {
+		if from[i] > to[i] {
+			from[i], to[i] = to[i], from[i]
+		}
+	}
+	// the volume of the coordinates far apart doesn't fit in an int
+	volume := 1.0
+	for i := range from {
+		volume *= float64(to[i]-from[i]) + 1
+	}
+	if volume > maxFillVolume {
+		return commandError(chat.TranslateMsg("commands.fill.toobig",
+			chat.Text(strconv.Itoa(maxFillVolume)), chat.Text(strconv.FormatFloat(volume, 'f', -1, 64))))
+	}
+	var count int
+	for x := from[0]; x <= to[0]; x++ {
+		for y := from[1]; y <= to[1]; y++ {
+			for z := from[2]; z <= to[2]; z++ {
+				if c.World().SetBlock(x, y, z, state) {
+					count++
+				}
+			}
+		}
+	}
+	if count == 0 {
+		return commandError(chat.TranslateMsg("argument.pos.unloaded"))
+	}
+	c.SendSystemChat(chat.TranslateMsg("commands.fill.success", chat.Text(strconv.Itoa(count))), false)
+	return nil
+}
+
+// blockPos resolves the block position argument from the player.
+func blockPos(c *client.Client, arg command.ParsedData) [3]int {
+	p := c.GetPlayer()
+	return arg.(command.Coordinates).BlockPos(p.Position, p.Rotation)
+}
+
+// randomTickSpeedCommand queries or sets the randomTickSpeed gamerule, which applies to all dimensions.
+func (g *Game) randomTickSpeedCommand(ctx context.Context, args []command.ParsedData) error {
+	c, err := sourceClient(ctx)
+	if err != nil {
+		return err
+	}
+	if len(args) < 4 {
+		speed := g.overworld.RandomTickSpeed()
+		c.SendSystemChat(chat.TranslateMsg("commands.gamerule.query",
+			chat.Text("randomTickSpeed"), chat.Text(strconv.Itoa(speed))), false)
+		return nil
+	}
+	speed := int(args[3].(int32))
+	for _, w := range g.worlds {
+		w.SetRandomTickSpeed(speed)
+	}
+	c.SendSystemChat(chat.TranslateMsg("commands.gamerule.set",
+		chat.Text("randomTickSpeed"), chat.Text(strconv.Itoa(speed))), false)
+	return nil
+}
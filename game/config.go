@@ -17,25 +17,65 @@
 package game
 
 import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
 	"time"
+	"unicode/utf16"
 
 	"golang.org/x/time/rate"
 )
 
 type Config struct {
-	MaxPlayers                  int    `toml:"max-players"`
-	ViewDistance                int32  `toml:"view-distance"`
-	ListenAddress               string `toml:"listen-address"`
-	MessageOfTheDay             string `toml:"motd"`
-	NetworkCompressionThreshold int    `toml:"network-compression-threshold"`
-	OnlineMode                  bool   `toml:"online-mode"`
-	LevelName                   string `toml:"level-name"`
-	EnforceSecureProfile        bool   `toml:"enforce-secure-profile"`
+	MaxPlayers                  int      `toml:"max-players"`
+	ViewDistance                int32    `toml:"view-distance"`
+	ListenAddress               string   `toml:"listen-address"`
+	MessageOfTheDay             string   `toml:"motd"`
+	NetworkCompressionThreshold int      `toml:"network-compression-threshold"`
+	OnlineMode                  bool     `toml:"online-mode"`
+	LevelName                   string   `toml:"level-name"`
+	EnforceSecureProfile        bool     `toml:"enforce-secure-profile"`
+	LevelSeed                   string   `toml:"level-seed"`
+	LevelType                   string   `toml:"level-type"`
+	GeneratorSettings           string   `toml:"generator-settings"`
+	Ops                         []string `toml:"ops"`
+	OpPermissionLevel           int      `toml:"op-permission-level"`
+	MovementViolationKick       int      `toml:"movement-violation-kick"`
+	ImmediateRespawn            bool     `toml:"immediate-respawn"`
 
 	ChunkLoadingLimiter       Limiter `toml:"chunk-loading-limiter"`
 	PlayerChunkLoadingLimiter Limiter `toml:"player-chunk-loading-limiter"`
 }
 
+// Seed parses LevelSeed like vanilla does.
+// Numbers are used directly, other strings are hashed with Java's String.hashCode.
+// An empty seed is chosen at random, which levelSeed saves with the new level.
+func (c *Config) Seed() int64 {
+	if c.LevelSeed == "" {
+		return rand.Int64()
+	}
+	if seed, err := strconv.ParseInt(c.LevelSeed, 10, 64); err == nil {
+		return seed
+	}
+	var h int32
+	for _, v := range utf16.Encode([]rune(c.LevelSeed)) {
+		h = 31*h + int32(v)
+	}
+	return int64(h)
+}
+
+// PermissionLevel returns the permission level of the player,
+// players listed in Ops have OpPermissionLevel, or 4 if it isn't set.
+func (c *Config) PermissionLevel(name string) int {
+	if !slices.Contains(c.Ops, name) {
+		return 0
+	}
+	if c.OpPermissionLevel == 0 {
+		return 4
+	}
+	return c.OpPermissionLevel
+}
+
 type Limiter struct {
 	Every duration `toml:"every"`
 	N     int
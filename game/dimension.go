@@ -0,0 +1,176 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package game
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/client"
+	"github.com/mrhaoxx/go-mc/level/loot"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/server/command"
+	"github.com/mrhaoxx/go-mc/world"
+	"github.com/mrhaoxx/go-mc/world/generator"
+)
+
+const (
+	overworld = "minecraft:overworld"
+	theNether = "minecraft:the_nether"
+	theEnd    = "minecraft:the_end"
+)
+
+// dimensions are the worlds hosted by the game, in the order sent to the client.
+// Each one is stored in a directory of the level like vanilla.
+// Only the overworld is generated by the level-type config,
+// the nether and the end are superflat since there are no generators for them yet.
+var dimensions = []struct {
+	name   string
+	dir    string
+	layers string
+	spawn  [3]int32
+}{
+	{name: overworld, dir: ".", spawn: [3]int32{0, 100, 0}},
+	{name: theNether, dir: "DIM-1", layers: "minecraft:bedrock,127*minecraft:netherrack;minecraft:nether_wastes", spawn: [3]int32{0, 64, 0}},
+	{name: theEnd, dir: "DIM1", layers: "113*minecraft:end_stone;minecraft:the_end", spawn: [3]int32{100, 49, 0}},
+}
+
+// createWorlds loads the worlds of all dimensions, keyed by the dimension name.
+// They are generated with the seed, the blocks broken in them drop the items of the loot tables,
+// and onDeath is called when players die in them.
+func createWorlds(logger *zap.Logger, path string, config *Config, seed int64, lootTables *loot.Tables,
+	onDeath func(world.Client, *world.Player, chat.Message),
+) (map[string]*world.World, error) {
+	worlds := make(map[string]*world.World, len(dimensions))
+	for _, dim := range dimensions {
+		var gen world.Generator
+		var err error
+		if dim.layers == "" {
+			gen, err = createGenerator(config)
+		} else {
+			gen, err = generator.ParseFlat(dim.layers)
+		}
+		if err != nil {
+			return nil, err
+		}
+		worlds[dim.name] = world.New(
+			logger.Named(dim.name),
+			world.NewProvider(filepath.Join(path, dim.dir, "region"), config.ChunkLoadingLimiter.Limiter()),
+			gen,
+			world.Config{
+				Dimension:    dim.name,
+				ViewDistance: config.ViewDistance,
+				// SpawnAngle:    lv.Data.SpawnAngle,
+				SpawnPosition:    dim.spawn,
+				Seed:             seed,
+				LootTables:       lootTables,
+				OnViolation:      reportViolations(logger.Named("movement"), config.MovementViolationKick),
+				OnDeath:          onDeath,
+				ImmediateRespawn: config.ImmediateRespawn,
+			},
+		)
+	}
+	return worlds, nil
+}
+
+// dimensionNames returns the names of the dimensions, which the client uses to complete dimension arguments.
+func dimensionNames() []string {
+	names := make([]string, len(dimensions))
+	for i, dim := range dimensions {
+		names[i] = dim.name
+	}
+	return names
+}
+
+// changeDimension moves the player of the client from its world to the position in another one.
+// It must be called from a packet handler of the client, like the commands.
+func (g *Game) changeDimension(c *client.Client, to *world.World, pos world.Position) {
+	p := c.GetPlayer()
+	c.CloseMenu()
+	c.World().RemovePlayer(c, p)
+	g.enterWorld(c, to, pos)
+}
+
+// enterWorld makes the client of the player removed from its world respawn in another one at the position.
+func (g *Game) enterWorld(c *client.Client, to *world.World, pos world.Position) {
+	p := c.GetPlayer()
+	c.SetWorld(to)
+	c.SendRespawn(to, p)
+	c.SendGameEvent(pk.UnsignedByte(13), pk.Float(0))
+	// the client creates a new player when it respawns, which has lost the permission level
+	c.SendEntityEvent(p.EntityID, byte(24+p.PermissionLevel))
+	to.SpawnPlayer(c, p, pos, g.config.PlayerChunkLoadingLimiter.Limiter())
+	c.InitInventoryMenu()
+}
+
+func (g *Game) dimensionCommand(ctx context.Context, args []command.ParsedData) error {
+	c, err := sourceClient(ctx)
+	if err != nil {
+		return err
+	}
+	p := c.GetPlayer()
+	name := args[2].(string)
+	w, ok := g.worlds[name]
+	if !ok {
+		return commandError(chat.TranslateMsg("argument.dimension.invalid", chat.Text(name)))
+	}
+	var pos world.Position
+	if len(args) > 3 {
+		pos = args[3].(command.Coordinates).Resolve(p.Position, p.Rotation)
+	} else {
+		spawn, _ := w.SpawnPositionAndAngle()
+		pos = world.Position{float64(spawn[0]) + 0.5, float64(spawn[1]), float64(spawn[2]) + 0.5}
+	}
+	if w == c.World() {
+		w.Teleport(c, pos)
+	} else {
+		g.changeDimension(c, w, pos)
+	}
+	c.SendSystemChat(chat.TranslateMsg("commands.teleport.success.location.single",
+		chat.Text(p.Name),
+		chat.Text(strconv.FormatFloat(pos[0], 'f', -1, 64)),
+		chat.Text(strconv.FormatFloat(pos[1], 'f', -1, 64)),
+		chat.Text(strconv.FormatFloat(pos[2], 'f', -1, 64)),
+	), false)
+	return nil
+}
+
+// handleClientTickEnd moves the player of the client through the portal it stood in during the tick,
+// between the overworld and the nether where the distances are 8 times shorter,
+// or from the end to the overworld spawn and from the other dimensions to the end platform.
+func (g *Game) handleClientTickEnd(_ pk.Packet, c *client.Client) error {
+	from := c.World()
+	portal, pos := from.TakePortal(c)
+	switch {
+	case portal == world.NetherPortal && from.Name() == overworld:
+		to := g.worlds[theNether]
+		g.changeDimension(c, to, to.NetherPortalExit(world.Position{pos[0] / 8, pos[1], pos[2] / 8}))
+	case portal == world.NetherPortal && from.Name() == theNether:
+		g.changeDimension(c, g.overworld, g.overworld.NetherPortalExit(world.Position{pos[0] * 8, pos[1], pos[2] * 8}))
+	case portal == world.EndPortal && from.Name() == theEnd:
+		spawn, _ := g.overworld.SpawnPositionAndAngle()
+		g.changeDimension(c, g.overworld, world.Position{float64(spawn[0]) + 0.5, float64(spawn[1]), float64(spawn[2]) + 0.5})
+	case portal == world.EndPortal:
+		to := g.worlds[theEnd]
+		g.changeDimension(c, to, to.EndPlatform())
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package game
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -29,10 +30,13 @@ import (
 	"github.com/mrhaoxx/go-mc/chat"
 	"github.com/mrhaoxx/go-mc/client"
 	"github.com/mrhaoxx/go-mc/data/packetid"
+	"github.com/mrhaoxx/go-mc/level/recipe"
 	"github.com/mrhaoxx/go-mc/net"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 	"github.com/mrhaoxx/go-mc/server"
+	"github.com/mrhaoxx/go-mc/server/command"
 	"github.com/mrhaoxx/go-mc/world"
+	"github.com/mrhaoxx/go-mc/world/generator"
 	"github.com/mrhaoxx/go-mc/yggdrasil/user"
 )
 
@@ -43,20 +47,17 @@ type Game struct {
 	serverInfo *server.PingInfo
 
 	playerProvider world.PlayerProvider
-	overworld      *world.World
+	// worlds are the worlds of the dimensions keyed by name, players join the overworld.
+	worlds    map[string]*world.World
+	overworld *world.World
 
 	globalChat globalChat
+	commands   *command.Graph
+	recipes    *recipe.Manager
 	*playerList
 }
 
 func NewGame(log *zap.Logger, config Config, pingList *server.PlayerList, serverInfo *server.PingInfo) *Game {
-	// providers
-	overworld, err := createWorld(log, filepath.Join(".", config.LevelName), &config)
-	if err != nil {
-		log.Fatal("cannot load overworld", zap.Error(err))
-	}
-	playerProvider := world.NewPlayerProvider(filepath.Join(".", config.LevelName, "playerdata"))
-
 	// keepalive
 	keepAlive := server.NewKeepAlive()
 	pl := playerList{pingList: pingList, keepAlive: keepAlive}
@@ -71,6 +72,18 @@ func NewGame(log *zap.Logger, config Config, pingList *server.PlayerList, server
 		chatTypeCodec: &world.NetworkCodec.ChatType,
 	}
 
+	packs := dataPacks(log, filepath.Join(".", config.LevelName))
+	seed, err := levelSeed(filepath.Join(".", config.LevelName), &config)
+	if err != nil {
+		log.Fatal("cannot load the level seed", zap.Error(err))
+	}
+	// providers
+	worlds, err := createWorlds(log, filepath.Join(".", config.LevelName), &config, seed, loadLootTables(log, packs), g.broadcastDeath)
+	if err != nil {
+		log.Fatal("cannot load worlds", zap.Error(err))
+	}
+	playerProvider := world.NewPlayerProvider(filepath.Join(".", config.LevelName, "playerdata"))
+
 	// go func() {
 	// 	for range time.Tick(time.Second * 5) {
 	// 		pl.pingList.Range(func(c server.PlayerListClient, _ server.PlayerSample) {
@@ -79,43 +92,53 @@ func NewGame(log *zap.Logger, config Config, pingList *server.PlayerList, server
 	// 	}
 	// }()
 
-	return &Game{
+	game := &Game{
 		log: log.Named("game"),
 
 		config:     config,
 		serverInfo: serverInfo,
 
 		playerProvider: playerProvider,
-		overworld:      overworld,
+		worlds:         worlds,
+		overworld:      worlds[overworld],
 
 		globalChat: g,
+		commands:   command.NewGraph(),
+		recipes:    loadRecipes(log, packs),
 		playerList: &pl,
 	}
+	game.registerCommands()
+	return game
 }
 
-func createWorld(logger *zap.Logger, path string, config *Config) (*world.World, error) {
-	// f, err := os.Open(filepath.Join(path, "level.dat"))
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// r, err := gzip.NewReader(f)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// lv, err := save.ReadLevel(r)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	overworld := world.New(
-		logger.Named("overworld"),
-		// world.NewProvider(filepath.Join(path, "region"), config.ChunkLoadingLimiter.Limiter()),
-		world.Config{
-			ViewDistance: config.ViewDistance,
-			// SpawnAngle:    lv.Data.SpawnAngle,
-			SpawnPosition: [3]int32{0, 100, 0},
-		},
-	)
-	return overworld, nil
+// Close stops the worlds and saves their chunks and players, to be called when the server shuts down.
+func (g *Game) Close() {
+	for name, w := range g.worlds {
+		if err := w.Close(); err != nil {
+			g.log.Error("Save world error", zap.String("dimension", name), zap.Error(err))
+		}
+		if err := w.SavePlayers(g.playerProvider.PutPlayer); err != nil {
+			g.log.Error("Save player data error", zap.String("dimension", name), zap.Error(err))
+		}
+	}
+}
+
+// createGenerator selects the world generator by the level-type config.
+func createGenerator(config *Config) (world.Generator, error) {
+	switch config.LevelType {
+	case "", "minecraft:normal", "normal":
+		return generator.NewNoise(), nil
+	case "minecraft:flat", "flat":
+		settings := config.GeneratorSettings
+		if settings == "" {
+			settings = generator.DefaultFlatLayers
+		}
+		return generator.ParseFlat(settings)
+	case "minecraft:void", "void":
+		return generator.Void{}, nil
+	default:
+		return nil, fmt.Errorf("unknown level type: %q", config.LevelType)
+	}
 }
 
 // AcceptPlayer will be called in an independent goroutine when new player login
@@ -139,6 +162,10 @@ func (g *Game) AcceptPlayer(name string, id uuid.UUID, profilePubKey *user.Publi
 			PubKey:         profilePubKey,
 			Properties:     properties,
 			Gamemode:       1,
+			Health:         20,
+			FoodLevel:      20,
+			Saturation:     5,
+			Air:            300,
 			ChunkPos:       [3]int32{48 >> 4, 64 >> 4, 35 >> 4},
 			EntitiesInView: make(map[int32]*world.Entity),
 			ViewDistance:   10,
@@ -147,14 +174,23 @@ func (g *Game) AcceptPlayer(name string, id uuid.UUID, profilePubKey *user.Publi
 		logger.Error("Read player data error", zap.Error(err))
 		return
 	}
-	c := client.New(logger, conn, p, g.overworld)
+	p.PermissionLevel = g.config.PermissionLevel(name)
+	// the player logs in to the world it was saved in
+	w, ok := g.worlds[p.Dimension]
+	if !ok {
+		w = g.overworld
+	}
+	c := client.New(logger, conn, p, w, g.recipes)
 
 	logger.Info("Player join", zap.Int32("eid", p.EntityID))
 	defer logger.Info("Player left")
 
-	c.SendLogin(g.overworld, p)
+	c.SendLogin(w, p, dimensionNames())
 
 	c.SendGameEvent(pk.UnsignedByte(13), pk.Float(0))
+	// entity events 24 to 28 set the op permission level 0 to 4 of the player
+	c.SendEntityEvent(p.EntityID, byte(24+p.PermissionLevel))
+	c.SendCommands(g.commands.Visible(p.PermissionLevel))
 	// c.SendServerData(g.serverInfo.Description(), g.serverInfo.FavIcon(), g.config.EnforceSecureProfile)
 
 	// joinMsg := chat.TranslateMsg("multiplayer.player.joined", chat.Text(p.Name))
@@ -162,14 +198,25 @@ func (g *Game) AcceptPlayer(name string, id uuid.UUID, profilePubKey *user.Publi
 	g.globalChat.broadcastSystemChat(chat.Text("Player joined"+p.Name), false)
 	defer g.globalChat.broadcastSystemChat(chat.Text("Player left"+p.Name), false)
 	c.AddHandler(packetid.ServerboundChat, g.globalChat.Handle)
+	c.AddHandler(packetid.ServerboundChatCommand, g.handleCommand)
+	c.AddHandler(packetid.ServerboundCommandSuggestion, g.handleSuggestion)
+	c.AddHandler(packetid.ServerboundClientCommand, g.handleClientCommand)
+	c.AddHandler(packetid.ServerboundClientTickEnd, g.handleClientTickEnd)
 
 	g.playerList.addPlayer(c, p)
 	defer g.playerList.removePlayer(c)
 
-	c.SendPlayerPosition(p.Position, p.Rotation)
-	g.overworld.AddPlayer(c, p, g.config.PlayerChunkLoadingLimiter.Limiter())
-	defer g.overworld.RemovePlayer(c, p)
+	c.InitInventoryMenu()
+	w.AddPlayer(c, p, g.config.PlayerChunkLoadingLimiter.Limiter())
+	// the player may have changed dimension
+	defer func() {
+		c.World().RemovePlayer(c, p)
+		if err := g.playerProvider.PutPlayer(p); err != nil {
+			logger.Error("Save player data error", zap.Error(err))
+		}
+	}()
 	c.SendPacket(packetid.ClientboundUpdateTags, pk.Array(defaultTags))
+	c.InitRecipeBook()
 	c.SendSetDefaultSpawnPosition(g.overworld.SpawnPositionAndAngle())
 
 	c.Start()
@@ -0,0 +1,72 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package game
+
+import (
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mrhaoxx/go-mc/nbt"
+)
+
+// levelData is the part of the level.dat the game keeps, the other fields vanilla saves in it are left to their defaults.
+type levelData struct {
+	Data struct {
+		LevelName        string
+		WorldGenSettings struct {
+			Seed int64 `nbt:"seed"`
+		}
+	}
+}
+
+// levelSeed returns the seed of the level in the directory, which is kept in its level.dat like vanilla.
+// A level without a level.dat is new, the seed of the config is saved in one so that the level keeps it after restarts.
+func levelSeed(dir string, config *Config) (int64, error) {
+	var level levelData
+	f, err := os.Open(filepath.Join(dir, "level.dat"))
+	if err == nil {
+		defer f.Close()
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		_, err = nbt.NewDecoder(r).Decode(&level)
+		return level.Data.WorldGenSettings.Seed, err
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+	level.Data.LevelName = config.LevelName
+	level.Data.WorldGenSettings.Seed = config.Seed()
+	return level.Data.WorldGenSettings.Seed, saveLevel(dir, &level)
+}
+
+// saveLevel writes the level.dat of the level in the directory.
+func saveLevel(dir string, level *levelData) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, "level.dat"))
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(f)
+	err = nbt.NewEncoder(w).Encode(level, "")
+	return errors.Join(err, w.Close(), f.Close())
+}
@@ -0,0 +1,47 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package game
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/world"
+)
+
+// reportViolations returns the hook logging the illegal moves of the players,
+// which kicks them after kickAfter violations of a kind, or never if it's 0.
+func reportViolations(logger *zap.Logger, kickAfter int) func(c world.Client, p *world.Player, v world.Violation) {
+	return func(c world.Client, p *world.Player, v world.Violation) {
+		count := p.Violations[v]
+		logger.Warn("Illegal move",
+			zap.String("name", p.Name),
+			zap.Stringer("violation", v),
+			zap.Int("count", count),
+			zap.Float64s("position", p.Position[:]),
+		)
+		if kickAfter <= 0 || count < kickAfter {
+			return
+		}
+		reason := "multiplayer.disconnect.invalid_player_movement"
+		if v == world.ViolationFlight {
+			reason = "multiplayer.disconnect.flying"
+		}
+		logger.Info("Kick player", zap.String("name", p.Name), zap.Stringer("violation", v))
+		c.SendDisconnect(chat.TranslateMsg(reason))
+	}
+}
@@ -72,6 +72,15 @@ func (pl *playerList) removePlayer(c *client.Client) {
 	})
 }
 
+// names returns the names of the online players.
+func (pl *playerList) names() []string {
+	names := make([]string, 0, pl.pingList.Len())
+	pl.pingList.Range(func(_ server.PlayerListClient, p server.PlayerSample) {
+		names = append(names, p.Name)
+	})
+	return names
+}
+
 func keepAliveHandler(k *server.KeepAlive) client.PacketHandler {
 	return func(p pk.Packet, c *client.Client) error {
 		var req pk.Long
@@ -0,0 +1,74 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package game
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/mrhaoxx/go-mc/level/loot"
+	"github.com/mrhaoxx/go-mc/level/recipe"
+)
+
+// loadRecipes loads the built-in recipes and the ones of the data packs.
+// All the vanilla recipes are loaded if the vanilla data pack, found in the server jar, is put in the datapacks directory.
+func loadRecipes(log *zap.Logger, packs []fs.FS) *recipe.Manager {
+	recipes, err := recipe.Load(append([]fs.FS{recipe.Builtin}, packs...)...)
+	if recipes == nil {
+		log.Fatal("cannot load recipes", zap.Error(err))
+	}
+	if err != nil {
+		log.Warn("some recipes cannot be loaded", zap.Error(err))
+	}
+	log.Info("Recipes loaded", zap.Int("count", len(recipes.Recipes())))
+	return recipes
+}
+
+// dataPacks opens the data packs of the level, which are the directories in the datapacks directory like vanilla.
+func dataPacks(log *zap.Logger, path string) []fs.FS {
+	var packs []fs.FS
+	dir := filepath.Join(path, "datapacks")
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Error("cannot list data packs", zap.Error(err))
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			packs = append(packs, os.DirFS(filepath.Join(dir, e.Name())))
+		}
+	}
+	return packs
+}
+
+// loadLootTables loads the built-in loot tables and the ones of the data packs,
+// which decide the items dropped by the blocks broken.
+// The blocks without a loot table drop their own item.
+func loadLootTables(log *zap.Logger, packs []fs.FS) *loot.Tables {
+	tables, err := loot.Load(append([]fs.FS{loot.Builtin}, packs...)...)
+	if tables == nil {
+		log.Fatal("cannot load loot tables", zap.Error(err))
+	}
+	if err != nil {
+		log.Warn("some loot tables cannot be loaded", zap.Error(err))
+	}
+	log.Info("Loot tables loaded", zap.Int("count", tables.Len()))
+	return tables
+}
@@ -0,0 +1,53 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package game
+
+import (
+	"github.com/mrhaoxx/go-mc/client"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/world"
+)
+
+// The actions of ServerboundClientCommand.
+const (
+	performRespawn = iota
+	requestStats
+)
+
+// handleClientCommand respawns the dead player when it clicks respawn on the respawn screen.
+// The statistics aren't kept, so their requests are ignored.
+func (g *Game) handleClientCommand(p pk.Packet, c *client.Client) error {
+	var action pk.VarInt
+	if err := p.Scan(&action); err != nil {
+		return err
+	}
+	if action == performRespawn && c.World().IsDead(c) {
+		g.respawn(c)
+	}
+	return nil
+}
+
+// respawn moves the dead player of the client to the spawn of the overworld, with its health restored.
+func (g *Game) respawn(c *client.Client) {
+	p := c.GetPlayer()
+	c.CloseMenu()
+	c.World().RemovePlayer(c, p)
+	p.Respawn()
+	spawn, angle := g.overworld.SpawnPositionAndAngle()
+	p.Rotation = world.Rotation{angle, 0}
+	g.enterWorld(c, g.overworld, world.Position{float64(spawn[0]) + 0.5, float64(spawn[1]), float64(spawn[2]) + 0.5})
+}
@@ -3,17 +3,17 @@ module github.com/mrhaoxx/go-mc
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/google/uuid v1.3.0
 	github.com/iancoleman/strcase v0.2.0
+	go.uber.org/zap v1.27.0
 	golang.org/x/exp v0.0.0-20230321023759-10a507213a29
+	golang.org/x/time v0.13.0
 )
 
 require (
-	github.com/BurntSushi/toml v1.5.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
-	go.uber.org/zap v1.27.0 // indirect
 	golang.org/x/mod v0.28.0 // indirect
 	golang.org/x/sync v0.17.0 // indirect
-	golang.org/x/time v0.13.0 // indirect
 	golang.org/x/tools v0.37.0 // indirect
 )
@@ -1,11 +1,7 @@
 package hpcworld
 
 /*
-// #cgo LDFLAGS: -Wl,--unresolved-symbols=ignore-all
-#cgo LDFLAGS: -Wl,-undefined,dynamic_lookup
 #include "bridge.h"
-// Expose load_chunk prototype for cgo
-Chunk* load_chunk(int32_t x, int32_t z);
 */
 import "C"
 import (
@@ -15,20 +11,6 @@ import (
 	"github.com/mrhaoxx/go-mc/level/block"
 )
 
-// cgo: expose load_chunk
-/*
-Chunk* load_chunk(int32_t x, int32_t z);
-*/
-
-// LoadChunk wraps the C load_chunk function and returns a Go pointer to Chunk
-func LoadChunk(x, z int32) *Chunk {
-	cChunk := C.load_chunk(C.int32_t(x), C.int32_t(z))
-	if cChunk == nil {
-		return nil
-	}
-	return ToGoChunk(cChunk)
-}
-
 type Section struct {
 	Blockcount int16
 
@@ -50,10 +32,38 @@ func (s *Section) SetBlock(i int, v int32) {
 	s.BlocksState[i] = v
 }
 
+// Light values are stored as nibbles, two per byte, the lower nibble first.
+func getNibble(arr *[2048]int8, i int) int {
+	return int(uint8(arr[i>>1])>>(uint(i&1)*4)) & 0xF
+}
+
+func setNibble(arr *[2048]int8, i int, v int) {
+	shift := uint(i&1) * 4
+	b := uint8(arr[i>>1])&^(0xF<<shift) | uint8(v&0xF)<<shift
+	arr[i>>1] = int8(b)
+}
+
+func (s *Section) GetSkyLight(i int) int      { return getNibble(&s.SkyLight, i) }
+func (s *Section) SetSkyLight(i int, v int)   { setNibble(&s.SkyLight, i, v) }
+func (s *Section) GetBlockLight(i int) int    { return getNibble(&s.BlockLight, i) }
+func (s *Section) SetBlockLight(i int, v int) { setNibble(&s.BlockLight, i, v) }
+
 type Chunk struct {
 	Sections [24]Section
 }
 
+// BlockAt returns the block at the chunk relative coordinates, y counted from the bottom of the chunk.
+func (c *Chunk) BlockAt(x, y, z int) level.BlocksState {
+	return level.BlocksState(c.Sections[y>>4].BlocksState[(y&15)<<8|z<<4|x])
+}
+
+// HeightMaps computes the heightmaps of the chunk.
+func (c *Chunk) HeightMaps() level.HeightMaps {
+	h := level.NewHeightMaps(len(c.Sections) * 16)
+	h.Compute(len(c.Sections)*16, c.BlockAt)
+	return h
+}
+
 func init() {
 	var s Section
 	if unsafe.Sizeof(s) != uintptr(C.Section_size) {
@@ -90,10 +100,16 @@ func init() {
 	}
 }
 
-func NewChunk() *C.Chunk                      { return (*C.Chunk)(C.malloc(C.Chunk_size)) }
+// Alloc allocates a zeroed chunk in C memory. Release it with Chunk.Free.
+func Alloc() *Chunk { return ToGoChunk(NewChunk()) }
+
+func NewChunk() *C.Chunk                      { return (*C.Chunk)(C.calloc(1, C.Chunk_size)) }
 func FreeChunk(ch *C.Chunk)                   { C.free(unsafe.Pointer(ch)) }
 func SectionAt(ch *C.Chunk, i int) *C.Section { return &ch.sections[i] }
 
+// Free releases the C memory of the chunk. The chunk must not be used after that.
+func (c *Chunk) Free() { FreeChunk(ToCChunk(c)) }
+
 // ToGoChunk converts a C Chunk pointer to a Go Chunk pointer for testing
 func ToGoChunk(ch *C.Chunk) *Chunk {
 	return (*Chunk)(unsafe.Pointer(ch))
@@ -153,12 +169,20 @@ func LevelChunkFromHPC(ch *Chunk) *level.Chunk {
 		}
 	}
 
+	lc.HeightMaps.Compute(secs*16, goCh.BlockAt)
+
 	// Status: no HPC notion; default to empty
 	lc.Status = level.StatusEmpty
 
 	return lc
 }
 
+// ChunkFromLevel works like HPCChunkFromLevel but returns the Go view of the chunk.
+// The memory is still C-allocated.
+func ChunkFromLevel(lc *level.Chunk) *Chunk {
+	return ToGoChunk(HPCChunkFromLevel(lc))
+}
+
 // HPCChunkFromLevel converts a level.Chunk into an HPC C-backed Chunk.
 // It allocates a new C.Chunk and fills sections with states, biomes, and light data.
 func HPCChunkFromLevel(lc *level.Chunk) *C.Chunk {
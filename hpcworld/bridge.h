@@ -37,5 +37,3 @@ enum {
   Chunk_align = _Alignof(Chunk),
   Chunk_off_sections = offsetof(Chunk, sections),
 };
-
-Chunk* load_chunk(int32_t x, int32_t z);
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	_ "embed"
 	"fmt"
 	"math/bits"
+	"reflect"
 
 	"github.com/mrhaoxx/go-mc/nbt"
 )
@@ -23,6 +24,8 @@ var blockStates []byte
 var (
 	ToStateID map[Block]StateID
 	StateList []Block
+	// defaultStates is the state returned by DefaultState for each block.
+	defaultStates map[string]StateID
 )
 
 // BitsPerBlock indicates how many bits are needed to represent all possible
@@ -54,6 +57,15 @@ func (s *State) Block() (Block, error) {
 	return block, nil
 }
 
+// DefaultState returns the state of the block given by its id only.
+// The states data doesn't record the vanilla default states,
+// so this is the state with the zero value of every property the block allows it for,
+// and the value of the first state of the block for the others.
+func DefaultState(id string) (StateID, bool) {
+	s, ok := defaultStates[id]
+	return s, ok
+}
+
 type UnknownBlockErr struct {
 	Name string
 }
@@ -75,6 +87,7 @@ func init() {
 	}
 	ToStateID = make(map[Block]StateID, len(states))
 	StateList = make([]Block, 0, len(states))
+	firstState := make(map[string]StateID, len(FromID))
 	for _, state := range states {
 		block, err := state.Block()
 		if err != nil {
@@ -83,8 +96,43 @@ func init() {
 		if _, ok := ToStateID[block]; ok {
 			panic(fmt.Errorf("state %#v already exists", block))
 		}
+		if _, ok := firstState[state.Name]; !ok {
+			firstState[state.Name] = StateID(len(StateList))
+		}
 		ToStateID[block] = StateID(len(StateList))
 		StateList = append(StateList, block)
 	}
 	BitsPerBlock = bits.Len(uint(len(StateList)))
+	initDefaultStates(firstState)
+}
+
+func initDefaultStates(firstState map[string]StateID) {
+	// zeroAllowed records the properties of each block having the zero value in some state.
+	zeroAllowed := make(map[string][]bool, len(firstState))
+	for _, b := range StateList {
+		v := reflect.ValueOf(b)
+		allowed, ok := zeroAllowed[b.ID()]
+		if !ok {
+			allowed = make([]bool, v.NumField())
+			zeroAllowed[b.ID()] = allowed
+		}
+		for i := range allowed {
+			allowed[i] = allowed[i] || v.Field(i).IsZero()
+		}
+	}
+	defaultStates = make(map[string]StateID, len(firstState))
+	for id, first := range firstState {
+		firstValue := reflect.ValueOf(StateList[first])
+		v := reflect.New(firstValue.Type()).Elem()
+		for i, allowed := range zeroAllowed[id] {
+			if !allowed {
+				v.Field(i).Set(firstValue.Field(i))
+			}
+		}
+		if s, ok := ToStateID[v.Interface().(Block)]; ok {
+			defaultStates[id] = s
+		} else {
+			defaultStates[id] = first
+		}
+	}
 }
@@ -8,14 +8,10 @@ type Entity interface {
 
 type (
 	FurnaceEntity               struct{}
-	ChestEntity                 struct{}
-	TrappedChestEntity          struct{}
 	EnderChestEntity            struct{}
 	JukeboxEntity               struct{}
 	DispenserEntity             struct{}
 	DropperEntity               struct{}
-	SignEntity                  struct{}
-	HangingSignEntity           struct{}
 	MobSpawnerEntity            struct{}
 	CreakingHeartEntity         struct{}
 	PistonEntity                struct{}
@@ -23,11 +19,9 @@ type (
 	EnchantingTableEntity       struct{}
 	EndPortalEntity             struct{}
 	BeaconEntity                struct{}
-	SkullEntity                 struct{}
 	DaylightDetectorEntity      struct{}
 	HopperEntity                struct{}
 	ComparatorEntity            struct{}
-	BannerEntity                struct{}
 	StructureBlockEntity        struct{}
 	EndGatewayEntity            struct{}
 	CommandBlockEntity          struct{}
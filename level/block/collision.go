@@ -0,0 +1,196 @@
+package block
+
+import "strings"
+
+// Box is an axis-aligned box inside a block, in blocks from its lower corner.
+// The boxes of fences and walls reach 1.5 blocks high.
+type Box struct {
+	Min, Max [3]float64
+}
+
+// Offset returns the box moved by the position of its block.
+func (b Box) Offset(x, y, z int) Box {
+	d := [3]float64{float64(x), float64(y), float64(z)}
+	for i := range d {
+		b.Min[i] += d[i]
+		b.Max[i] += d[i]
+	}
+	return b
+}
+
+var collisions [][]Box
+
+// fullCube is the collision of the full blocks and of the partial blocks whose shape isn't known.
+var fullCube = []Box{pixels(0, 0, 0, 16, 16, 16)}
+
+// CollisionBoxes returns the boxes the entities collide with in the block state.
+// The shapes of the slabs, stairs, fences, walls, panes, doors, trapdoors, fence gates,
+// snow layers, carpets and beds are modeled; the other partial blocks are full cubes.
+func CollisionBoxes(s StateID) []Box { return collisions[s] }
+
+// pixels returns the box between the corners in sixteenths of a block, like vanilla's Block.box.
+func pixels(x0, y0, z0, x1, y1, z1 float64) Box {
+	return Box{Min: [3]float64{x0 / 16, y0 / 16, z0 / 16}, Max: [3]float64{x1 / 16, y1 / 16, z1 / 16}}
+}
+
+// side returns the box against the horizontal side of the block, thick pixels wide, from y0 to y1.
+func side(d Direction, thick, y0, y1 float64) Box {
+	switch d {
+	case North:
+		return pixels(0, y0, 0, 16, y1, thick)
+	case South:
+		return pixels(0, y0, 16-thick, 16, y1, 16)
+	case West:
+		return pixels(0, y0, 0, thick, y1, 16)
+	default:
+		return pixels(16-thick, y0, 0, 16, y1, 16)
+	}
+}
+
+// intersect returns the common part of two boxes.
+func intersect(a, b Box) Box {
+	for i := range a.Min {
+		a.Min[i] = max(a.Min[i], b.Min[i])
+		a.Max[i] = min(a.Max[i], b.Max[i])
+	}
+	return a
+}
+
+// post returns the center post and the arms to the connected sides,
+// the post being 2*node pixels wide and the arms 2*arm pixels wide, like vanilla's CrossCollisionBlock.
+func post(b Block, node, arm, height float64, withPost bool) []Box {
+	var boxes []Box
+	if withPost {
+		boxes = append(boxes, pixels(8-node, 0, 8-node, 8+node, height, 8+node))
+	}
+	for d, name := range horizontalFields {
+		wall, _ := fieldOf[WallSide](b, name)
+		if boolField(b, name) || wall != WallSideNone {
+			boxes = append(boxes, pixels(8-arm, 0, 8-arm, 8+arm, height, 8+arm).grow(d))
+		}
+	}
+	return boxes
+}
+
+// grow extends the box to the side of the block in the direction.
+func (b Box) grow(d Direction) Box {
+	switch d {
+	case North:
+		b.Min[2] = 0
+	case South:
+		b.Max[2] = 1
+	case West:
+		b.Min[0] = 0
+	case East:
+		b.Max[0] = 1
+	}
+	return b
+}
+
+// stairs returns the bottom or top slab of the stairs and the step on it, like vanilla's StairBlock.
+func stairs(b Block) []Box {
+	facing, _ := fieldOf[Direction](b, "Facing")
+	half, _ := fieldOf[Half](b, "Half")
+	shape, _ := fieldOf[StairsShape](b, "Shape")
+	slab, y0, y1 := pixels(0, 0, 0, 16, 8, 16), 8.0, 16.0
+	if half == Top {
+		slab, y0, y1 = pixels(0, 8, 0, 16, 16, 16), 0, 8
+	}
+	back := side(facing, 8, y0, y1)
+	switch shape {
+	case StairsShapeOuterLeft:
+		return []Box{slab, intersect(back, side(facing.CounterClockwise(), 8, y0, y1))}
+	case StairsShapeOuterRight:
+		return []Box{slab, intersect(back, side(facing.Clockwise(), 8, y0, y1))}
+	case StairsShapeInnerLeft:
+		return []Box{slab, back, side(facing.CounterClockwise(), 8, y0, y1)}
+	case StairsShapeInnerRight:
+		return []Box{slab, back, side(facing.Clockwise(), 8, y0, y1)}
+	default:
+		return []Box{slab, back}
+	}
+}
+
+// collisionOf returns the collision boxes of a partial block.
+func collisionOf(b Block) []Box {
+	id := b.ID()
+	facing, _ := fieldOf[Direction](b, "Facing")
+	switch {
+	case strings.HasSuffix(id, "_slab"):
+		if t, _ := fieldOf[SlabType](b, "Type"); t == SlabTypeTop {
+			return []Box{pixels(0, 8, 0, 16, 16, 16)}
+		}
+		return []Box{pixels(0, 0, 0, 16, 8, 16)}
+	case strings.HasSuffix(id, "_stairs"):
+		return stairs(b)
+	case id == "minecraft:snow":
+		if layers := intField(b, "Layers", 1); layers > 1 {
+			return []Box{pixels(0, 0, 0, 16, float64(layers-1)*2, 16)}
+		}
+		return nil
+	case strings.HasSuffix(id, "_carpet"):
+		return []Box{pixels(0, 0, 0, 16, 1, 16)}
+	case id == "minecraft:farmland", id == "minecraft:dirt_path":
+		return []Box{pixels(0, 0, 0, 16, 15, 16)}
+	case strings.HasSuffix(id, "_bed"):
+		return []Box{pixels(0, 0, 0, 16, 9, 16)}
+	case strings.HasSuffix(id, "_fence_gate"):
+		if boolField(b, "Open") {
+			return nil
+		}
+		if facing.Axis() == X {
+			return []Box{pixels(6, 0, 0, 10, 24, 16)}
+		}
+		return []Box{pixels(0, 0, 6, 16, 24, 10)}
+	case strings.HasSuffix(id, "_fence"):
+		return post(b, 2, 2, 24, true)
+	case isWall(b):
+		return post(b, 4, 3, 24, boolField(b, "Up"))
+	case isPane(b):
+		return post(b, 1, 1, 16, true)
+	case strings.HasSuffix(id, "_trapdoor"):
+		if boolField(b, "Open") {
+			return []Box{side(facing.Opposite(), 3, 0, 16)}
+		}
+		if half, _ := fieldOf[Half](b, "Half"); half == Top {
+			return []Box{pixels(0, 13, 0, 16, 16, 16)}
+		}
+		return []Box{pixels(0, 0, 0, 16, 3, 16)}
+	case strings.HasSuffix(id, "_door"):
+		if !boolField(b, "Open") {
+			return []Box{side(facing.Opposite(), 3, 0, 16)}
+		}
+		if hinge, _ := fieldOf[DoorHingeSide](b, "Hinge"); hinge == DoorHingeSideRight {
+			return []Box{side(facing.Clockwise(), 3, 0, 16)}
+		}
+		return []Box{side(facing.CounterClockwise(), 3, 0, 16)}
+	default:
+		return fullCube
+	}
+}
+
+func initCollisions() {
+	collisions = make([][]Box, len(StateList))
+	for i, b := range StateList {
+		switch shapes[i] {
+		case ShapeFull:
+			collisions[i] = fullCube
+		case ShapePartial:
+			collisions[i] = collisionOf(b)
+		}
+	}
+}
+
+// slipperiness are the blocks entities slide on, the others having vanilla's default of 0.6.
+var slipperiness = map[string]float64{
+	"minecraft:ice": 0.98, "minecraft:packed_ice": 0.98, "minecraft:frosted_ice": 0.98,
+	"minecraft:blue_ice": 0.989, "minecraft:slime_block": 0.8,
+}
+
+// Slipperiness returns the part of the horizontal velocity the entities standing on the block keep every tick.
+func Slipperiness(s StateID) float64 {
+	if f, ok := slipperiness[StateList[s].ID()]; ok {
+		return f
+	}
+	return 0.6
+}
@@ -0,0 +1,54 @@
+package block
+
+import "testing"
+
+func TestCollisionBoxes(t *testing.T) {
+	// top returns the highest point of the boxes above the center of the block, or -1 if none covers it.
+	top := func(boxes []Box, x, z float64) float64 {
+		y := -1.0
+		for _, b := range boxes {
+			if b.Min[0] <= x && x <= b.Max[0] && b.Min[2] <= z && z <= b.Max[2] {
+				y = max(y, b.Max[1])
+			}
+		}
+		return y
+	}
+	for _, tt := range []struct {
+		b    Block
+		x, z float64
+		want float64
+	}{
+		{Stone{}, 0.5, 0.5, 1},
+		{Air{}, 0.5, 0.5, -1},
+		{ShortGrass{}, 0.5, 0.5, -1},
+		{OakSlab{Type: SlabTypeBottom}, 0.5, 0.5, 0.5},
+		{OakSlab{Type: SlabTypeTop}, 0.5, 0.5, 1},
+		{OakSlab{Type: SlabTypeDouble}, 0.5, 0.5, 1},
+		{OakStairs{Facing: North, Half: Bottom, Shape: StairsShapeStraight}, 0.5, 0.25, 1},
+		{OakStairs{Facing: North, Half: Bottom, Shape: StairsShapeStraight}, 0.5, 0.75, 0.5},
+		{OakStairs{Facing: North, Half: Bottom, Shape: StairsShapeOuterLeft}, 0.75, 0.25, 0.5},
+		{OakStairs{Facing: North, Half: Bottom, Shape: StairsShapeOuterLeft}, 0.25, 0.25, 1},
+		{OakStairs{Facing: North, Half: Bottom, Shape: StairsShapeInnerRight}, 0.75, 0.75, 1},
+		{Snow{Layers: 1}, 0.5, 0.5, -1},
+		{Snow{Layers: 5}, 0.5, 0.5, 0.5},
+		{WhiteCarpet{}, 0.5, 0.5, 1.0 / 16},
+		{OakFence{}, 0.5, 0.5, 1.5},
+		{OakFence{}, 0.5, 0.1, -1},
+		{OakFence{North: true}, 0.5, 0.1, 1.5},
+		{CobblestoneWall{Up: false}, 0.5, 0.5, -1},
+		{CobblestoneWall{East: WallSideLow}, 0.9, 0.5, 1.5},
+		{OakFenceGate{Facing: North}, 0.5, 0.5, 1.5},
+		{OakFenceGate{Facing: North, Open: true}, 0.5, 0.5, -1},
+		{OakTrapdoor{Facing: North, Half: Bottom}, 0.5, 0.5, 3.0 / 16},
+		{OakTrapdoor{Facing: North, Open: true}, 0.5, 0.5, -1},
+		{OakTrapdoor{Facing: North, Open: true}, 0.5, 0.95, 1},
+		{OakDoor{Facing: East}, 0.1, 0.5, 1},
+		{OakDoor{Facing: East}, 0.9, 0.5, -1},
+		{OakDoor{Facing: East, Open: true, Hinge: DoorHingeSideLeft}, 0.5, 0.1, 1},
+		{Chest{Facing: North}, 0.5, 0.5, 1},
+	} {
+		if got := top(CollisionBoxes(ToStateID[tt.b]), tt.x, tt.z); got != tt.want {
+			t.Errorf("top of %#v at (%v, %v) = %v, want %v", tt.b, tt.x, tt.z, got, tt.want)
+		}
+	}
+}
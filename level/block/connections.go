@@ -0,0 +1,201 @@
+package block
+
+import "strings"
+
+// horizontalFields are the names of the properties of the connections to the horizontal neighbours.
+var horizontalFields = map[Direction]string{North: "North", South: "South", West: "West", East: "East"}
+
+// connectionExceptions are full blocks that fences, walls and panes don't connect to.
+var connectionExceptions = names(
+	"barrier", "carved_pumpkin", "jack_o_lantern", "melon", "pumpkin", "shulker_box",
+)
+
+// neighbors returns the blocks next to pos, nil for the ones not loaded.
+func neighbors(pos [3]int, get Getter) (n [6]Block) {
+	for d := Down; d <= East; d++ {
+		p := d.Relative(pos)
+		if s, ok := get(p[0], p[1], p[2]); ok {
+			n[d] = StateList[s]
+		}
+	}
+	return
+}
+
+// solidSide reports whether fences, walls and panes connect to the side of the block.
+func solidSide(b Block) bool {
+	if b == nil || ShapeOf(ToStateID[b]) != ShapeFull {
+		return false
+	}
+	id := b.ID()
+	return !connectionExceptions[id] && !strings.HasSuffix(id, "_leaves") && !strings.HasSuffix(id, "shulker_box")
+}
+
+// gateConnects reports whether the block is a fence gate in line with the side in the direction.
+func gateConnects(b Block, dir Direction) bool {
+	if b == nil || !strings.HasSuffix(b.ID(), "_fence_gate") {
+		return false
+	}
+	facing, _ := fieldOf[Direction](b, "Facing")
+	return facing.Axis() == dir.Clockwise().Axis()
+}
+
+func isPane(b Block) bool {
+	return b != nil && (strings.HasSuffix(b.ID(), "_pane") || b.ID() == "minecraft:iron_bars")
+}
+
+func isWall(b Block) bool {
+	return b != nil && strings.HasSuffix(b.ID(), "_wall") && hasType[WallSide](b, "North")
+}
+
+// fenceConnections connects the fence to the fences of the same material, fence gates and solid blocks.
+func fenceConnections(b Block, pos [3]int, get Getter) Block {
+	nether := b.ID() == "minecraft:nether_brick_fence"
+	n := neighbors(pos, get)
+	for d, name := range horizontalFields {
+		o := n[d]
+		sameFence := o != nil && strings.HasSuffix(o.ID(), "_fence") &&
+			(o.ID() == "minecraft:nether_brick_fence") == nether
+		b, _ = withField(b, name, Boolean(sameFence || gateConnects(o, d) || solidSide(o)))
+	}
+	return b
+}
+
+// paneConnections connects the glass pane or iron bars to the other ones, walls and solid blocks.
+func paneConnections(b Block, pos [3]int, get Getter) Block {
+	n := neighbors(pos, get)
+	for d, name := range horizontalFields {
+		o := n[d]
+		b, _ = withField(b, name, Boolean(isPane(o) || isWall(o) || solidSide(o)))
+	}
+	return b
+}
+
+// wallPostNames are the blocks on top of walls which raise their post.
+var wallPostNames = names("torch", "soul_torch", "redstone_torch", "lantern", "soul_lantern")
+
+// wallConnections connects the wall to the other walls, panes, fence gates and solid blocks.
+// A side is tall under a full block, and the post is raised unless the wall is straight.
+func wallConnections(b Block, pos [3]int, get Getter) Block {
+	n := neighbors(pos, get)
+	above := n[Up]
+	tall := above != nil && ShapeOf(ToStateID[above]) == ShapeFull
+	connected := make(map[Direction]bool, 4)
+	for d, name := range horizontalFields {
+		o := n[d]
+		connected[d] = isWall(o) || isPane(o) || gateConnects(o, d) || solidSide(o)
+		side := WallSideNone
+		if connected[d] {
+			side = pick(tall, WallSideTall, WallSideLow)
+		}
+		b, _ = withField(b, name, side)
+	}
+	straight := connected[North] == connected[South] && connected[West] == connected[East] &&
+		connected[North] != connected[East]
+	raised := above != nil && (wallPostNames[above.ID()] || isWall(above) && boolField(above, "Up") ||
+		strings.HasSuffix(above.ID(), "_sign") || strings.HasSuffix(above.ID(), "_banner"))
+	b, _ = withField(b, "Up", Boolean(raised || !straight))
+	return b
+}
+
+// stairsShape turns the stairs into a corner with the stairs behind or in front of it, like vanilla's getStairsShape.
+func stairsShape(b Block, pos [3]int, get Getter) Block {
+	facing, _ := fieldOf[Direction](b, "Facing")
+	half, _ := fieldOf[Half](b, "Half")
+	stairsAt := func(d Direction) (facing Direction, ok bool) {
+		p := d.Relative(pos)
+		s, loaded := get(p[0], p[1], p[2])
+		if !loaded || !strings.HasSuffix(StateList[s].ID(), "_stairs") {
+			return 0, false
+		}
+		h, _ := fieldOf[Half](StateList[s], "Half")
+		facing, _ = fieldOf[Direction](StateList[s], "Facing")
+		return facing, h == half
+	}
+	// canTakeShape reports whether the stairs on the side don't continue these stairs in a straight line.
+	canTakeShape := func(d Direction) bool {
+		f, same := stairsAt(d)
+		return !same || f != facing
+	}
+	shape := StairsShapeStraight
+	if behind, ok := stairsAt(facing); ok && behind.Axis() != facing.Axis() && canTakeShape(behind.Opposite()) {
+		shape = pick(behind == facing.CounterClockwise(), StairsShapeOuterLeft, StairsShapeOuterRight)
+	} else if front, ok := stairsAt(facing.Opposite()); ok && front.Axis() != facing.Axis() && canTakeShape(front) {
+		shape = pick(front == facing.CounterClockwise(), StairsShapeInnerLeft, StairsShapeInnerRight)
+	}
+	b, _ = withField(b, "Shape", shape)
+	return b
+}
+
+// signalSourceNames are the blocks emitting redstone power redstone wire connects to.
+var signalSourceNames = names(
+	"redstone_torch", "redstone_wall_torch", "lever", "redstone_block", "daylight_detector",
+	"target", "trapped_chest", "tripwire_hook", "detector_rail", "lectern", "lightning_rod",
+	"sculk_sensor", "calibrated_sculk_sensor",
+)
+
+// wireConnectsTo reports whether the redstone wire connects to the block in the direction.
+func wireConnectsTo(b Block, dir Direction) bool {
+	if b == nil {
+		return false
+	}
+	id := b.ID()
+	switch {
+	case isWire(b):
+		return true
+	case id == "minecraft:repeater":
+		facing, _ := fieldOf[Direction](b, "Facing")
+		return facing.Axis() == dir.Axis()
+	case id == "minecraft:observer":
+		facing, _ := fieldOf[Direction](b, "Facing")
+		return facing == dir
+	}
+	return signalSourceNames[id] || id == "minecraft:comparator" ||
+		strings.HasSuffix(id, "_button") || strings.HasSuffix(id, "_pressure_plate")
+}
+
+func isWire(b Block) bool {
+	return b != nil && b.ID() == "minecraft:redstone_wire"
+}
+
+// wireConnections connects the redstone wire to the wires and the redstone components around,
+// climbing up and down full blocks. Like vanilla, a wire without connections is a cross,
+// and a wire connected on a single side is a line.
+func wireConnections(b Block, pos [3]int, get Getter) Block {
+	at := func(p [3]int) Block {
+		if s, ok := get(p[0], p[1], p[2]); ok {
+			return StateList[s]
+		}
+		return nil
+	}
+	conductor := func(b Block) bool { return b != nil && ShapeOf(ToStateID[b]) == ShapeFull }
+	aboveConductor := conductor(at(Up.Relative(pos)))
+	dot := true
+	sides := make(map[Direction]RedstoneSide, 4)
+	for d, name := range horizontalFields {
+		side, _ := fieldOf[RedstoneSide](b, name)
+		dot = dot && side == RedstoneSideNone
+		np := d.Relative(pos)
+		n := at(np)
+		switch {
+		case !aboveConductor && conductor(n) && isWire(at(Up.Relative(np))):
+			sides[d] = RedstoneSideUp
+		case wireConnectsTo(n, d), !conductor(n) && isWire(at(Down.Relative(np))):
+			sides[d] = RedstoneSideSide
+		default:
+			sides[d] = RedstoneSideNone
+		}
+	}
+	none := func(d Direction) bool { return sides[d] == RedstoneSideNone }
+	if !(dot && none(North) && none(South) && none(West) && none(East)) {
+		noZ, noX := none(North) && none(South), none(West) && none(East)
+		for d := range sides {
+			if none(d) && pick(d.Axis() == X, noZ, noX) {
+				sides[d] = RedstoneSideSide
+			}
+		}
+	}
+	for d, name := range horizontalFields {
+		b, _ = withField(b, name, sides[d])
+	}
+	return b
+}
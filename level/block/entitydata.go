@@ -0,0 +1,235 @@
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/nbt"
+	"github.com/mrhaoxx/go-mc/save"
+)
+
+// The block entities with typed data. Their fields are decoded from the NBT of the save files.
+type (
+	ChestEntity struct {
+		Items         []save.Item    `nbt:"Items,omitempty"`
+		CustomName    *Text          `nbt:"CustomName,omitempty"`
+		Lock          nbt.RawMessage `nbt:"lock,omitempty"`
+		LootTable     string         `nbt:"LootTable,omitempty"`
+		LootTableSeed int64          `nbt:"LootTableSeed,omitempty"`
+	}
+	TrappedChestEntity ChestEntity
+	SignEntity         struct {
+		FrontText SignText `nbt:"front_text"`
+		BackText  SignText `nbt:"back_text"`
+		IsWaxed   bool     `nbt:"is_waxed"`
+	}
+	HangingSignEntity SignEntity
+	BannerEntity      struct {
+		Patterns   []BannerLayer `nbt:"patterns,omitempty"`
+		CustomName *Text         `nbt:"CustomName,omitempty"`
+	}
+	SkullEntity struct {
+		Profile        *Profile `nbt:"profile,omitempty"`
+		NoteBlockSound string   `nbt:"note_block_sound,omitempty"`
+		CustomName     *Text    `nbt:"custom_name,omitempty"`
+	}
+)
+
+// SignText is the text on a side of a sign.
+type SignText struct {
+	Messages [4]Text `nbt:"messages"`
+	// Color is the name of the dye color of the text.
+	Color          string `nbt:"color"`
+	HasGlowingText bool   `nbt:"has_glowing_text"`
+}
+
+// NewSignText returns the text of a new sign, which is empty and black.
+func NewSignText() SignText {
+	return SignText{Color: "black"}
+}
+
+// BannerLayer is a pattern of a banner in a dye color.
+type BannerLayer struct {
+	Pattern string `nbt:"pattern"`
+	Color   string `nbt:"color"`
+}
+
+// Profile is the game profile of a player head.
+// It's read from either a compound or the player name.
+type Profile struct {
+	Name string `nbt:"name,omitempty"`
+	// ID is the UUID of the player as four ints.
+	ID         []int32           `nbt:"id,omitempty"`
+	Properties []ProfileProperty `nbt:"properties,omitempty"`
+}
+
+// ProfileProperty is a property of a game profile, like the skin textures.
+type ProfileProperty struct {
+	Name      string `nbt:"name"`
+	Value     string `nbt:"value"`
+	Signature string `nbt:"signature,omitempty"`
+}
+
+func (p *Profile) UnmarshalNBT(tagType byte, r nbt.DecoderReader) error {
+	decoder := nbt.NewDecoder(io.MultiReader(bytes.NewReader([]byte{tagType}), r))
+	decoder.NetworkFormat(true) // TagType directly followed the body
+	switch tagType {
+	case nbt.TagString:
+		*p = Profile{}
+		_, err := decoder.Decode(&p.Name)
+		return err
+	case nbt.TagCompound:
+		type profile Profile // without the UnmarshalNBT method
+		_, err := decoder.Decode((*profile)(p))
+		return err
+	default:
+		return errors.New("profile should be a string or a compound")
+	}
+}
+
+// Text is a text component, stored as a JSON string in the block entity data.
+type Text chat.Message
+
+func (t Text) MarshalText() ([]byte, error) {
+	return json.Marshal(chat.Message(t))
+}
+
+func (t *Text) UnmarshalText(text []byte) error {
+	return json.Unmarshal(text, (*chat.Message)(t))
+}
+
+// RawEntity is the block entity of a type without typed data.
+// Its data is kept as loaded, so it's saved unchanged.
+type RawEntity struct {
+	Type EntityType
+	Data nbt.RawMessage
+}
+
+func (r RawEntity) ID() string                    { return EntityList[r.Type].ID() }
+func (r RawEntity) IsValidBlock(block Block) bool { return EntityList[r.Type].IsValidBlock(block) }
+func (r RawEntity) TagType() byte                 { return r.Data.Type }
+func (r RawEntity) MarshalNBT(w io.Writer) error  { return r.Data.MarshalNBT(w) }
+
+// TypeOf returns the type of the block entity.
+func TypeOf(e Entity) EntityType {
+	if r, ok := e.(RawEntity); ok {
+		return r.Type
+	}
+	return EntityTypes[e.ID()]
+}
+
+// blockEntityTypes maps the block ids to the type of their block entity.
+var blockEntityTypes map[string]EntityType
+
+func init() {
+	blockEntityTypes = make(map[string]EntityType)
+	for id, b := range FromID {
+		for i, e := range EntityList {
+			if e.IsValidBlock(b) {
+				blockEntityTypes[id] = EntityType(i)
+				break
+			}
+		}
+	}
+}
+
+// NewEntity returns the block entity created with the block, or false if the block doesn't have one.
+func NewEntity(b Block) (Entity, bool) {
+	typ, ok := blockEntityTypes[b.ID()]
+	if !ok {
+		return nil, false
+	}
+	switch e := EntityList[typ].(type) {
+	case SignEntity:
+		return SignEntity{FrontText: NewSignText(), BackText: NewSignText()}, true
+	case HangingSignEntity:
+		return HangingSignEntity{FrontText: NewSignText(), BackText: NewSignText()}, true
+	default:
+		if reflect.TypeOf(e).NumField() == 0 {
+			return RawEntity{Type: typ, Data: nbt.RawMessage{Type: nbt.TagCompound, Data: []byte{nbt.TagEnd}}}, true
+		}
+		return e, true
+	}
+}
+
+// UnmarshalEntity decodes the block entity in the form of the save files,
+// and returns it with its world coordinates.
+func UnmarshalEntity(data nbt.RawMessage) (e Entity, pos [3]int32, err error) {
+	var header struct {
+		ID string `nbt:"id"`
+		X  int32  `nbt:"x"`
+		Y  int32  `nbt:"y"`
+		Z  int32  `nbt:"z"`
+	}
+	if err := data.Unmarshal(&header); err != nil {
+		return nil, pos, err
+	}
+	pos = [3]int32{header.X, header.Y, header.Z}
+	typ, ok := EntityTypes[header.ID]
+	if !ok {
+		return nil, pos, fmt.Errorf("unknown block entity id: %s", header.ID)
+	}
+	t := reflect.TypeOf(EntityList[typ])
+	if t.NumField() == 0 {
+		return RawEntity{Type: typ, Data: data}, pos, nil
+	}
+	v := reflect.New(t)
+	if err := data.Unmarshal(v.Interface()); err != nil {
+		return nil, pos, fmt.Errorf("decode block entity %s: %w", header.ID, err)
+	}
+	return v.Elem().Interface().(Entity), pos, nil
+}
+
+// MarshalEntity encodes the block entity at the world coordinates into the form of the save files.
+func MarshalEntity(e Entity, pos [3]int32) (nbt.RawMessage, error) {
+	m, err := entityFields(e)
+	if err != nil {
+		return nbt.RawMessage{}, err
+	}
+	fields := make(map[string]any, len(m)+4)
+	for k, v := range m {
+		fields[k] = v
+	}
+	fields["id"] = e.ID()
+	fields["x"], fields["y"], fields["z"] = pos[0], pos[1], pos[2]
+	return encodeCompound(fields)
+}
+
+// EntityData encodes the data of the block entity sent to the clients,
+// which is the saved data without the id and the coordinates.
+func EntityData(e Entity) (nbt.RawMessage, error) {
+	m, err := entityFields(e)
+	if err != nil {
+		return nbt.RawMessage{}, err
+	}
+	delete(m, "id")
+	delete(m, "x")
+	delete(m, "y")
+	delete(m, "z")
+	return encodeCompound(m)
+}
+
+// entityFields encodes the block entity and splits the compound into its fields.
+func entityFields(e Entity) (m map[string]nbt.RawMessage, err error) {
+	data, err := nbt.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	err = nbt.Unmarshal(data, &m)
+	return
+}
+
+func encodeCompound[T any](fields map[string]T) (nbt.RawMessage, error) {
+	data, err := nbt.Marshal(fields)
+	if err != nil {
+		return nbt.RawMessage{}, err
+	}
+	var m nbt.RawMessage
+	err = nbt.Unmarshal(data, &m)
+	return m, err
+}
@@ -0,0 +1,145 @@
+package block
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/nbt"
+)
+
+func rawNBT(t *testing.T, snbt string) (m nbt.RawMessage) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := nbt.NewEncoder(&buf).Encode(nbt.StringifiedMessage(snbt), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := nbt.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestUnmarshalEntity(t *testing.T) {
+	for _, tt := range []struct {
+		snbt string
+		pos  [3]int32
+		want Entity
+	}{
+		{
+			`{id:"minecraft:sign",x:1,y:-2,z:3,is_waxed:1b,` +
+				`front_text:{messages:['"hi"','""','""','""'],color:"red",has_glowing_text:0b},` +
+				`back_text:{messages:['""','""','""','""'],color:"black",has_glowing_text:1b}}`,
+			[3]int32{1, -2, 3},
+			SignEntity{
+				FrontText: SignText{Messages: [4]Text{Text(chat.Text("hi")), {}, {}, {}}, Color: "red"},
+				BackText:  SignText{Color: "black", HasGlowingText: true},
+				IsWaxed:   true,
+			},
+		},
+		{
+			`{id:"minecraft:chest",x:0,y:64,z:0,Items:[{Slot:3b,id:"minecraft:stone",count:5}]}`,
+			[3]int32{0, 64, 0},
+			nil, // checked below
+		},
+		{
+			`{id:"minecraft:banner",x:0,y:0,z:0,patterns:[{pattern:"minecraft:stripe_bottom",color:"blue"}]}`,
+			[3]int32{0, 0, 0},
+			BannerEntity{Patterns: []BannerLayer{{Pattern: "minecraft:stripe_bottom", Color: "blue"}}},
+		},
+		{
+			`{id:"minecraft:skull",x:0,y:0,z:0,profile:"Notch"}`,
+			[3]int32{0, 0, 0},
+			SkullEntity{Profile: &Profile{Name: "Notch"}},
+		},
+		{
+			`{id:"minecraft:skull",x:0,y:0,z:0,profile:{name:"Notch",id:[I;1,2,3,4]}}`,
+			[3]int32{0, 0, 0},
+			SkullEntity{Profile: &Profile{Name: "Notch", ID: []int32{1, 2, 3, 4}}},
+		},
+	} {
+		e, pos, err := UnmarshalEntity(rawNBT(t, tt.snbt))
+		if err != nil {
+			t.Errorf("UnmarshalEntity(%s): %v", tt.snbt, err)
+			continue
+		}
+		if pos != tt.pos {
+			t.Errorf("UnmarshalEntity(%s): pos = %v, want %v", tt.snbt, pos, tt.pos)
+		}
+		if chest, ok := e.(ChestEntity); ok {
+			if len(chest.Items) != 1 || chest.Items[0].Slot != 3 || chest.Items[0].Count != 5 {
+				t.Errorf("UnmarshalEntity(%s) = %+v", tt.snbt, e)
+			}
+			continue
+		}
+		// The texts are compared in their encoded form, since the decoded messages have nil and empty slices.
+		if !reflect.DeepEqual(fieldsOf(t, e), fieldsOf(t, tt.want)) {
+			t.Errorf("UnmarshalEntity(%s) = %+v, want %+v", tt.snbt, e, tt.want)
+		}
+	}
+}
+
+func TestMarshalEntity(t *testing.T) {
+	furnace := `{id:"minecraft:furnace",x:5,y:6,z:7,BurnTime:20s}`
+	for _, snbt := range []string{
+		furnace,
+		`{id:"minecraft:sign",x:1,y:2,z:3,is_waxed:0b,` +
+			`front_text:{messages:['"a"','"b"','""','""'],color:"black",has_glowing_text:0b},` +
+			`back_text:{messages:['""','""','""','""'],color:"black",has_glowing_text:0b}}`,
+	} {
+		e, pos, err := UnmarshalEntity(rawNBT(t, snbt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := MarshalEntity(e, pos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, pos2, err := UnmarshalEntity(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pos2 != pos || !reflect.DeepEqual(fieldsOf(t, again), fieldsOf(t, e)) {
+			t.Errorf("%s is saved as %s", snbt, data)
+		}
+	}
+
+	e, _, _ := UnmarshalEntity(rawNBT(t, furnace))
+	if _, ok := e.(RawEntity); !ok {
+		t.Fatalf("furnace is decoded as %T, want RawEntity", e)
+	}
+	if fields := fieldsOf(t, e); fields["BurnTime"] == nil || len(fields) != 1 {
+		t.Errorf("the data sent to the client is %v", fields)
+	}
+}
+
+func TestNewEntity(t *testing.T) {
+	if _, ok := NewEntity(Stone{}); ok {
+		t.Error("stone has a block entity")
+	}
+	e, ok := NewEntity(OakWallSign{})
+	if sign, isSign := e.(SignEntity); !ok || !isSign || sign.FrontText.Color != "black" {
+		t.Errorf("the block entity of oak wall sign is %#v", e)
+	}
+	if e, ok := NewEntity(OakHangingSign{}); !ok || TypeOf(e) != EntityTypes["minecraft:hanging_sign"] {
+		t.Errorf("the block entity of oak hanging sign is %#v", e)
+	}
+	if e, ok := NewEntity(Furnace{}); !ok || TypeOf(e) != EntityTypes["minecraft:furnace"] {
+		t.Errorf("the block entity of furnace is %#v", e)
+	}
+}
+
+// fieldsOf decodes the data of the block entity sent to the client into maps,
+// since the order of the fields in the encoded compounds is random.
+func fieldsOf(t *testing.T, e Entity) (fields map[string]any) {
+	t.Helper()
+	data, err := EntityData(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Unmarshal(&fields); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
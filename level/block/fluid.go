@@ -0,0 +1,406 @@
+package block
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// FluidType is the kind of fluid in a block.
+type FluidType byte
+
+const (
+	FluidEmpty FluidType = iota
+	FluidWater
+	FluidLava
+)
+
+// Fluid is the fluid in a block state, like vanilla's FluidState.
+type Fluid struct {
+	Type FluidType
+	// Amount is from 1 to 8, which is the amount of sources and falling fluids.
+	Amount  int
+	Source  bool
+	Falling bool
+}
+
+// FluidOf returns the fluid in the block state.
+// Waterlogged blocks and the blocks always in water contain a water source.
+func FluidOf(s StateID) Fluid {
+	switch b := StateList[s].(type) {
+	case Water:
+		return fluidOfLevel(FluidWater, int(b.Level))
+	case Lava:
+		return fluidOfLevel(FluidLava, int(b.Level))
+	}
+	if IsWaterlogged(s) {
+		return Fluid{Type: FluidWater, Amount: 8, Source: true}
+	}
+	return Fluid{}
+}
+
+// fluidOfLevel returns the fluid of a liquid block of the level property.
+// Level 0 is a source, levels 1 to 7 are flowing fluids decreasing in amount, and the higher ones are falling.
+func fluidOfLevel(t FluidType, level int) Fluid {
+	switch {
+	case level == 0:
+		return Fluid{Type: t, Amount: 8, Source: true}
+	case level >= 8:
+		return Fluid{Type: t, Amount: 8, Falling: true}
+	}
+	return Fluid{Type: t, Amount: 8 - level}
+}
+
+// IsEmpty reports whether there is no fluid.
+func (f Fluid) IsEmpty() bool { return f.Type == FluidEmpty }
+
+// ID returns the id of the fluid in the minecraft:fluid registry,
+// which is also the id of its scheduled ticks.
+func (f Fluid) ID() string {
+	switch {
+	case f.Type == FluidWater && f.Source:
+		return "minecraft:water"
+	case f.Type == FluidWater:
+		return "minecraft:flowing_water"
+	case f.Type == FluidLava && f.Source:
+		return "minecraft:lava"
+	case f.Type == FluidLava:
+		return "minecraft:flowing_lava"
+	}
+	return "minecraft:empty"
+}
+
+// State returns the liquid block of the fluid, or air if it's empty.
+func (f Fluid) State() StateID {
+	level := 0
+	if !f.Source {
+		level = 8 - min(f.Amount, 8) + pick(f.Falling, 8, 0)
+	}
+	switch f.Type {
+	case FluidWater:
+		return ToStateID[Water{Level: Integer(level)}]
+	case FluidLava:
+		return ToStateID[Lava{Level: Integer(level)}]
+	}
+	return ToStateID[Air{}]
+}
+
+// The properties of the fluids, like vanilla's WaterFluid and LavaFluid.
+// Lava flows faster and further in ultrawarm dimensions.
+func (t FluidType) tickDelay(l Level) int {
+	if t == FluidLava && !l.Ultrawarm() {
+		return 30
+	}
+	return pick(t == FluidLava, 10, 5)
+}
+
+func (t FluidType) dropOff(l Level) int {
+	return pick(t == FluidLava && !l.Ultrawarm(), 2, 1)
+}
+
+func (t FluidType) slopeFindDistance(l Level) int {
+	return pick(t == FluidLava && !l.Ultrawarm(), 2, 4)
+}
+
+// convertsToSource reports whether two sources make a third one, true only for water like the vanilla gamerules.
+func (t FluidType) convertsToSource() bool { return t == FluidWater }
+
+// noFluidNames are the blocks without collision which fluids don't flow into.
+var noFluidNames = names("ladder", "sugar_cane", "bubble_column", "nether_portal", "end_portal", "end_gateway", "structure_void")
+
+// The fluid properties of the block states, indexed by StateID.
+var (
+	// fluidContainers are the blocks which keep the fluids flowing into them, like waterloggable blocks.
+	fluidContainers []bool
+	// waterloggable are the containers taking water sources.
+	waterloggable []bool
+	// holdsAnyFluid are the blocks fluids may flow into.
+	holdsAnyFluid []bool
+)
+
+// initFluids is called after the shapes are known.
+func initFluids() {
+	fluidContainers = make([]bool, len(StateList))
+	waterloggable = make([]bool, len(StateList))
+	holdsAnyFluid = make([]bool, len(StateList))
+	for i, b := range StateList {
+		id := b.ID()
+		switch b.(type) {
+		case Kelp, KelpPlant, Seagrass, TallSeagrass:
+			fluidContainers[i] = true
+		default:
+			waterloggable[i] = hasType[Boolean](b, "Waterlogged") && !boolField(b, "Waterlogged")
+			fluidContainers[i] = hasType[Boolean](b, "Waterlogged")
+		}
+		holdsAnyFluid[i] = fluidContainers[i] || ShapeOf(StateID(i)) == ShapeEmpty &&
+			!noFluidNames[id] && !strings.HasSuffix(id, "_door") && !strings.HasSuffix(id, "_sign")
+	}
+}
+
+// canHoldSpecificFluid reports whether the fluid may flow into the block.
+// Waterloggable blocks only take water sources.
+func canHoldSpecificFluid(s StateID, f Fluid) bool {
+	if !fluidContainers[s] {
+		return true
+	}
+	return waterloggable[s] && f.Type == FluidWater && f.Source
+}
+
+func canHoldFluid(s StateID, f Fluid) bool { return holdsAnyFluid[s] && canHoldSpecificFluid(s, f) }
+
+// canPassThroughWall reports whether a fluid flows from the block to the next one in the direction,
+// which it doesn't through full faces.
+func canPassThroughWall(dir Direction, s, next StateID) bool {
+	return !stateFaceFull(s, dir) && !stateFaceFull(next, dir.Opposite())
+}
+
+// canBeReplacedWith reports whether the fluid in a block is replaced by one of the type flowing in the direction.
+func (f Fluid) canBeReplacedWith(t FluidType, dir Direction) bool {
+	switch f.Type {
+	case FluidWater:
+		return dir == Down && t != FluidWater
+	case FluidLava:
+		// At least 4/9 high, like vanilla.
+		return f.Amount >= 4 && t == FluidWater
+	}
+	return true
+}
+
+// flowing is the fluid flowing with the amount.
+func (t FluidType) flowing(amount int, falling bool) Fluid {
+	return Fluid{Type: t, Amount: amount, Falling: falling}
+}
+
+// flow is a fluid of the type flowing in a level, like vanilla's FlowingFluid.
+type flow struct {
+	l Level
+	t FluidType
+}
+
+// at returns the block at the position, and false if it isn't loaded.
+func (fl flow) at(pos [3]int) (StateID, bool) {
+	return fl.l.GetBlock(pos[0], pos[1], pos[2])
+}
+
+func (fl flow) isSource(f Fluid) bool { return f.Type == fl.t && f.Source }
+
+// canMaybePassThrough reports whether the fluid may flow from the block into the next one in the direction.
+func (fl flow) canMaybePassThrough(s StateID, dir Direction, next StateID) bool {
+	return !fl.isSource(FluidOf(next)) && holdsAnyFluid[next] && canPassThroughWall(dir, s, next)
+}
+
+// newLiquid returns the fluid the block s at pos gets from the ones around it.
+func (fl flow) newLiquid(pos [3]int, s StateID) Fluid {
+	maxAmount, sources := 0, 0
+	for _, d := range horizontalOrder {
+		n, ok := fl.at(d.Relative(pos))
+		if !ok {
+			continue
+		}
+		if f := FluidOf(n); f.Type == fl.t && canPassThroughWall(d, s, n) {
+			if f.Source {
+				sources++
+			}
+			maxAmount = max(maxAmount, f.Amount)
+		}
+	}
+	if fl.t.convertsToSource() && sources >= 2 {
+		below, ok := fl.at(Down.Relative(pos))
+		if ok && (ShapeOf(below) != ShapeEmpty || fl.isSource(FluidOf(below))) {
+			return Fluid{Type: fl.t, Amount: 8, Source: true}
+		}
+	}
+	if above, ok := fl.at(Up.Relative(pos)); ok && FluidOf(above).Type == fl.t && canPassThroughWall(Up, s, above) {
+		return fl.t.flowing(8, true)
+	}
+	if amount := maxAmount - fl.t.dropOff(fl.l); amount > 0 {
+		return fl.t.flowing(amount, false)
+	}
+	return Fluid{}
+}
+
+// isWaterHole reports whether the fluid in the block s at pos can fall into the block below.
+func (fl flow) isWaterHole(pos [3]int, s StateID) bool {
+	below, ok := fl.at(Down.Relative(pos))
+	if !ok || !canPassThroughWall(Down, s, below) {
+		return false
+	}
+	return FluidOf(below).Type == fl.t || canHoldFluid(below, fl.t.flowing(1, false))
+}
+
+// slopeDistance returns the distance from pos to the nearest hole the fluid falls into,
+// not going back in the direction from, or 1000 if there's none within the slope find distance.
+func (fl flow) slopeDistance(pos [3]int, distance int, from Direction, s StateID) int {
+	best := 1000
+	for _, d := range horizontalOrder {
+		if d == from {
+			continue
+		}
+		np := d.Relative(pos)
+		n, ok := fl.at(np)
+		if !ok || !fl.canMaybePassThrough(s, d, n) || !canHoldSpecificFluid(n, fl.t.flowing(1, false)) {
+			continue
+		}
+		if fl.isWaterHole(np, n) {
+			return distance
+		}
+		if distance < fl.t.slopeFindDistance(fl.l) {
+			best = min(best, fl.slopeDistance(np, distance+1, d.Opposite(), n))
+		}
+	}
+	return best
+}
+
+// spreads returns the fluids flowing to the sides of the block s at pos,
+// only towards the nearest holes if there are any.
+func (fl flow) spreads(pos [3]int, s StateID) map[Direction]Fluid {
+	minDistance := 1000
+	spreads := make(map[Direction]Fluid, 4)
+	for _, d := range horizontalOrder {
+		np := d.Relative(pos)
+		n, ok := fl.at(np)
+		if !ok || !fl.canMaybePassThrough(s, d, n) {
+			continue
+		}
+		f := fl.newLiquid(np, n)
+		if !canHoldSpecificFluid(n, f) {
+			continue
+		}
+		distance := 0
+		if !fl.isWaterHole(np, n) {
+			distance = fl.slopeDistance(np, 1, d.Opposite(), n)
+		}
+		if distance < minDistance {
+			clear(spreads)
+		}
+		if distance <= minDistance {
+			if FluidOf(n).canBeReplacedWith(f.Type, d) {
+				spreads[d] = f
+			}
+			minDistance = distance
+		}
+	}
+	return spreads
+}
+
+// spreadTo lets the fluid flow into the block s at pos from the direction.
+// Lava falling on water turns it into stone, and water sources waterlog the blocks.
+func (fl flow) spreadTo(pos [3]int, s StateID, dir Direction, f Fluid) {
+	if fl.t == FluidLava && dir == Down && FluidOf(s).Type == FluidWater {
+		if _, liquid := StateList[s].(Water); liquid {
+			fl.l.SetBlock(pos[0], pos[1], pos[2], ToStateID[Stone{}])
+		}
+		return
+	}
+	if fluidContainers[s] {
+		if canHoldSpecificFluid(s, f) {
+			b, _ := withField(StateList[s], "Waterlogged", Boolean(true))
+			fl.l.SetBlock(pos[0], pos[1], pos[2], ToStateID[b])
+		}
+		return
+	}
+	fl.l.SetBlock(pos[0], pos[1], pos[2], f.State())
+}
+
+// spreadToSides lets the fluid f in the block s at pos flow to the sides.
+func (fl flow) spreadToSides(pos [3]int, s StateID, f Fluid) {
+	amount := f.Amount - fl.t.dropOff(fl.l)
+	if f.Falling {
+		amount = 7
+	}
+	if amount <= 0 {
+		return
+	}
+	spreads := fl.spreads(pos, s)
+	for _, d := range horizontalOrder {
+		if nf, ok := spreads[d]; ok {
+			np := d.Relative(pos)
+			if n, ok := fl.at(np); ok {
+				fl.spreadTo(np, n, d, nf)
+			}
+		}
+	}
+}
+
+// sourceNeighbors returns the number of sources of the fluid next to pos.
+func (fl flow) sourceNeighbors(pos [3]int) (n int) {
+	for _, d := range horizontalOrder {
+		if s, ok := fl.at(d.Relative(pos)); ok && fl.isSource(FluidOf(s)) {
+			n++
+		}
+	}
+	return
+}
+
+// spread lets the fluid f in the block s at pos fall, or flow to the sides if it can't.
+func (fl flow) spread(pos [3]int, s StateID, f Fluid) {
+	belowPos := Down.Relative(pos)
+	if below, ok := fl.at(belowPos); ok && fl.canMaybePassThrough(s, Down, below) {
+		nf := fl.newLiquid(belowPos, below)
+		if FluidOf(below).canBeReplacedWith(nf.Type, Down) && canHoldSpecificFluid(below, nf) {
+			fl.spreadTo(belowPos, below, Down, nf)
+			if fl.sourceNeighbors(pos) >= 3 {
+				fl.spreadToSides(pos, s, f)
+			}
+			return
+		}
+	}
+	if f.Source || !fl.isWaterHole(pos, s) {
+		fl.spreadToSides(pos, s, f)
+	}
+}
+
+// spreadDelay returns the delay of the next tick of the fluid changed from f to nf.
+// Lava rising in a flow slows down at random, like vanilla.
+func (fl flow) spreadDelay(f, nf Fluid, r *rand.Rand) int {
+	delay := fl.t.tickDelay(fl.l)
+	if fl.t == FluidLava && !f.Falling && !nf.IsEmpty() && !nf.Falling && nf.Amount > f.Amount && r.Intn(4) != 0 {
+		delay *= 4
+	}
+	return delay
+}
+
+// horizontalOrder is the order vanilla goes round the horizontal directions in.
+var horizontalOrder = [4]Direction{North, East, South, West}
+
+// fluidTick updates the amount of the flowing fluid at pos from the fluids around, then lets it spread.
+func fluidTick(l Level, pos [3]int, s StateID, r *rand.Rand) {
+	f := FluidOf(s)
+	fl := flow{l: l, t: f.Type}
+	if !f.Source {
+		nf := fl.newLiquid(pos, s)
+		if nf != f {
+			delay := fl.spreadDelay(f, nf, r)
+			s, f = nf.State(), nf
+			l.SetBlock(pos[0], pos[1], pos[2], s)
+			if !nf.IsEmpty() {
+				l.ScheduleFluidTick(pos[0], pos[1], pos[2], nf.ID(), delay, TickPriorityNormal)
+			}
+		}
+	}
+	if !f.IsEmpty() {
+		fl.spread(pos, s, f)
+	}
+}
+
+// fluidChanged schedules the tick of the fluid in the block at pos after it or a neighbour changed.
+// Lava next to water turns into obsidian if it's a source, or cobblestone otherwise.
+func fluidChanged(l Level, pos [3]int, s StateID) {
+	f := FluidOf(s)
+	if f.IsEmpty() {
+		return
+	}
+	if f.Type == FluidLava {
+		for _, d := range [...]Direction{Up, North, South, West, East} {
+			p := d.Relative(pos)
+			if n, ok := l.GetBlock(p[0], p[1], p[2]); ok && FluidOf(n).Type == FluidWater {
+				l.SetBlock(pos[0], pos[1], pos[2], ToStateID[pick[Block](f.Source, Obsidian{}, Cobblestone{})])
+				return
+			}
+		}
+	}
+	l.ScheduleFluidTick(pos[0], pos[1], pos[2], f.ID(), f.Type.tickDelay(l), TickPriorityNormal)
+}
+
+func init() {
+	RegisterFluidTickHandler(fluidTick, "minecraft:water", "minecraft:flowing_water", "minecraft:lava", "minecraft:flowing_lava")
+}
@@ -0,0 +1,150 @@
+package block
+
+import "testing"
+
+// flowLevel is a testLevel which updates the neighbours of the blocks set, like the world does.
+type flowLevel struct{ testLevel }
+
+func newFlowLevel(w testWorld) flowLevel {
+	return flowLevel{testLevel{w, make(map[[3]int]string)}}
+}
+
+func (l flowLevel) SetBlock(x, y, z int, s StateID) {
+	pos := [3]int{x, y, z}
+	l.testWorld[pos] = StateList[s]
+	NeighborChanged(l, pos, s)
+	for d := Down; d <= East; d++ {
+		p := d.Relative(pos)
+		if n, ok := l.GetBlock(p[0], p[1], p[2]); ok {
+			NeighborChanged(l, p, n)
+		}
+	}
+}
+
+// run places the blocks and runs the scheduled fluid ticks until the fluids stop flowing.
+func (l flowLevel) run(t *testing.T, blocks map[[3]int]Block) {
+	for pos, b := range blocks {
+		l.SetBlock(pos[0], pos[1], pos[2], ToStateID[b])
+	}
+	for i := 0; len(l.ticks) > 0; i++ {
+		if i == 100 {
+			t.Fatalf("the fluids are still flowing: %v", l.ticks)
+		}
+		ticks := make(map[[3]int]string, len(l.ticks))
+		for pos, id := range l.ticks {
+			ticks[pos] = id
+		}
+		clear(l.ticks)
+		for pos, id := range ticks {
+			s, _ := l.GetBlock(pos[0], pos[1], pos[2])
+			FluidTick(l, pos, id, s, nil)
+		}
+	}
+}
+
+func TestFluidTick(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		world  testWorld
+		blocks map[[3]int]Block
+		want   map[[3]int]Block
+	}{
+		{
+			"water spreads 7 blocks on the floor",
+			testWorld{},
+			map[[3]int]Block{{0, 64, 0}: Water{}},
+			map[[3]int]Block{
+				{0, 64, 0}: Water{}, {1, 64, 0}: Water{Level: 1}, {0, 64, -3}: Water{Level: 3},
+				{7, 64, 0}: Water{Level: 7}, {8, 64, 0}: Air{}, {4, 64, 4}: Air{}, {0, 65, 0}: Air{},
+			},
+		},
+		{
+			"lava spreads 3 blocks",
+			testWorld{},
+			map[[3]int]Block{{0, 64, 0}: Lava{}},
+			map[[3]int]Block{{0, 64, 0}: Lava{}, {1, 64, 0}: Lava{Level: 2}, {3, 64, 0}: Lava{Level: 6}, {4, 64, 0}: Air{}},
+		},
+		{
+			"water falls down, spreading at the top and the bottom",
+			testWorld{},
+			map[[3]int]Block{{0, 68, 0}: Water{}},
+			map[[3]int]Block{
+				{0, 66, 0}: Water{Level: 8}, {1, 68, 0}: Water{Level: 1}, {1, 66, 0}: Water{Level: 8}, {2, 66, 0}: Air{},
+				{1, 64, 0}: Water{Level: 8}, {2, 64, 0}: Water{Level: 1}, {3, 64, 0}: Water{Level: 2},
+			},
+		},
+		{
+			"water flows towards the nearest hole",
+			testWorld{{2, 63, 0}: Air{}},
+			map[[3]int]Block{{0, 64, 0}: Water{}},
+			map[[3]int]Block{{1, 64, 0}: Water{Level: 1}, {-1, 64, 0}: Air{}, {0, 64, 1}: Air{}, {2, 63, 0}: Water{Level: 8}},
+		},
+		{
+			"two water sources make a third one",
+			testWorld{},
+			map[[3]int]Block{{0, 64, 0}: Water{}, {2, 64, 0}: Water{}},
+			map[[3]int]Block{{1, 64, 0}: Water{}, {1, 64, 1}: Water{Level: 1}},
+		},
+		{
+			"lava source next to water turns into obsidian",
+			testWorld{{0, 64, 0}: Lava{}},
+			map[[3]int]Block{{0, 64, 1}: Water{}},
+			map[[3]int]Block{{0, 64, 0}: Obsidian{}},
+		},
+		{
+			"lava falling on water turns it into stone",
+			testWorld{{0, 64, 0}: Water{}, {1, 64, 0}: Stone{}, {-1, 64, 0}: Stone{}, {0, 64, 1}: Stone{}, {0, 64, -1}: Stone{}},
+			map[[3]int]Block{{0, 66, 0}: Lava{}},
+			map[[3]int]Block{{0, 65, 0}: Lava{Level: 8}, {0, 64, 0}: Stone{}},
+		},
+		{
+			"water flows out of a waterlogged block but doesn't waterlog others",
+			testWorld{{1, 64, 0}: OakStairs{Facing: East, Half: Bottom}},
+			map[[3]int]Block{{0, 64, 0}: OakSlab{Type: SlabTypeBottom, Waterlogged: true}},
+			map[[3]int]Block{{-1, 64, 0}: Water{Level: 1}, {1, 64, 0}: OakStairs{Facing: East, Half: Bottom}},
+		},
+		{
+			"water doesn't flow through full blocks",
+			testWorld{{1, 64, 0}: Stone{}},
+			map[[3]int]Block{{0, 64, 0}: Water{}},
+			map[[3]int]Block{{1, 64, 0}: Stone{}, {2, 64, 0}: Water{Level: 4}},
+		},
+	} {
+		l := newFlowLevel(tt.world)
+		l.run(t, tt.blocks)
+		for pos, want := range tt.want {
+			s, _ := l.GetBlock(pos[0], pos[1], pos[2])
+			if got := StateList[s]; got != want {
+				t.Errorf("%s: the block at %v is %#v, want %#v", tt.name, pos, got, want)
+			}
+		}
+	}
+}
+
+func TestFluidOf(t *testing.T) {
+	for b, want := range map[Block]Fluid{
+		Water{}:         {Type: FluidWater, Amount: 8, Source: true},
+		Water{Level: 3}: {Type: FluidWater, Amount: 5},
+		Lava{Level: 9}:  {Type: FluidLava, Amount: 8, Falling: true},
+		OakStairs{Facing: North, Waterlogged: true}: {Type: FluidWater, Amount: 8, Source: true},
+		Kelp{}:                   {Type: FluidWater, Amount: 8, Source: true},
+		OakStairs{Facing: North}: {},
+	} {
+		if got := FluidOf(ToStateID[b]); got != want {
+			t.Errorf("FluidOf(%#v) = %+v, want %+v", b, got, want)
+		}
+	}
+	for _, b := range []Block{Water{}, Water{Level: 3}, Lava{Level: 8}} {
+		if got := FluidOf(ToStateID[b]).State(); got != ToStateID[b] {
+			t.Errorf("the state of the fluid of %#v is %#v", b, StateList[got])
+		}
+	}
+}
+
+func TestFluidChanged_cobblestone(t *testing.T) {
+	l := testLevel{testWorld{{0, 64, 0}: Lava{Level: 2}, {1, 64, 0}: Water{}}, make(map[[3]int]string)}
+	NeighborChanged(l, [3]int{0, 64, 0}, ToStateID[Lava{Level: 2}])
+	if got := l.testWorld[[3]int{0, 64, 0}]; got != (Cobblestone{}) {
+		t.Errorf("flowing lava next to water turns into %#v", got)
+	}
+}
@@ -0,0 +1,125 @@
+package block
+
+import "strings"
+
+var (
+	emission []uint8
+	opacity  []uint8
+)
+
+// LightEmission returns the block light level emitted by the state.
+func LightEmission(s StateID) int { return int(emission[s]) }
+
+// LightOpacity returns how much light is reduced when passing through
+// the state, in addition to the 1 level lost for every block travelled.
+// Opaque full blocks return 15, water, leaves and other see-through full
+// blocks return 1, and everything else 0.
+func LightOpacity(s StateID) int { return int(opacity[s]) }
+
+// constEmission lists blocks that always emit the same light level.
+var constEmission = map[string]uint8{
+	"glowstone": 15, "sea_lantern": 15, "jack_o_lantern": 15, "lantern": 15,
+	"ochre_froglight": 15, "verdant_froglight": 15, "pearlescent_froglight": 15,
+	"shroomlight": 15, "beacon": 15, "conduit": 15, "fire": 15, "lava": 15,
+	"lava_cauldron": 15, "end_portal": 15, "end_gateway": 15,
+	"torch": 14, "wall_torch": 14, "end_rod": 14,
+	"nether_portal": 11,
+	"soul_torch":    10, "soul_wall_torch": 10, "soul_lantern": 10, "soul_fire": 10,
+	"crying_obsidian":  10,
+	"enchanting_table": 7, "ender_chest": 7,
+	"glow_lichen": 7, "sculk_catalyst": 6,
+	"amethyst_cluster": 5, "large_amethyst_bud": 4, "medium_amethyst_bud": 2,
+	"small_amethyst_bud": 1, "magma_block": 3,
+	"brewing_stand": 1, "brown_mushroom": 1, "dragon_egg": 1, "end_portal_frame": 1,
+	"sculk_sensor": 1, "calibrated_sculk_sensor": 1,
+}
+
+func emissionOf(b Block) uint8 {
+	switch b := b.(type) {
+	case Furnace, Smoker, BlastFurnace:
+		if boolField(b, "Lit") {
+			return 13
+		}
+	case RedstoneLamp, CopperBulb, WaxedCopperBulb, Campfire:
+		if boolField(b, "Lit") {
+			return 15
+		}
+	case ExposedCopperBulb, WaxedExposedCopperBulb:
+		if boolField(b, "Lit") {
+			return 12
+		}
+	case WeatheredCopperBulb, WaxedWeatheredCopperBulb:
+		if boolField(b, "Lit") {
+			return 8
+		}
+	case OxidizedCopperBulb, WaxedOxidizedCopperBulb:
+		if boolField(b, "Lit") {
+			return 4
+		}
+	case SoulCampfire:
+		if b.Lit {
+			return 10
+		}
+	case RedstoneTorch, RedstoneWallTorch:
+		if boolField(b, "Lit") {
+			return 7
+		}
+	case RedstoneOre, DeepslateRedstoneOre:
+		if boolField(b, "Lit") {
+			return 9
+		}
+	case SeaPickle:
+		if b.Waterlogged {
+			return uint8(3 + 3*(b.Pickles-1))
+		}
+	case RespawnAnchor:
+		return [...]uint8{0, 3, 7, 11, 15}[b.Charges]
+	case Light:
+		return uint8(b.Level)
+	case CaveVines:
+		if b.Berries {
+			return 14
+		}
+	case CaveVinesPlant:
+		if b.Berries {
+			return 14
+		}
+	default:
+		id := strings.TrimPrefix(b.ID(), "minecraft:")
+		if n, ok := constEmission[id]; ok {
+			return n
+		}
+		if strings.HasSuffix(id, "candle") && boolField(b, "Lit") {
+			return uint8(3 * intField(b, "Candles", 1))
+		}
+		if strings.HasSuffix(id, "candle_cake") && boolField(b, "Lit") {
+			return 3
+		}
+	}
+	return 0
+}
+
+func opacityOf(s StateID) uint8 {
+	switch {
+	case shapes[s] == ShapeFull && !transparent[s]:
+		return 15
+	case shapes[s] == ShapeFull, waterlogged[s]:
+		if strings.HasSuffix(StateList[s].ID(), "glass") {
+			return 0
+		}
+		return 1
+	case StateList[s].ID() == "minecraft:cobweb":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func initLight() {
+	emission = make([]uint8, len(StateList))
+	opacity = make([]uint8, len(StateList))
+	for i, b := range StateList {
+		emission[i] = emissionOf(b)
+		opacity[i] = opacityOf(StateID(i))
+	}
+}
@@ -0,0 +1,397 @@
+package block
+
+import (
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+)
+
+// Tool tiers, the lowest tier of pickaxe that drops a block.
+const (
+	tierAny = iota
+	tierStone
+	tierIron
+	tierDiamond
+)
+
+// mining is how a block is mined, like the block properties and the mining tags of vanilla.
+type mining struct {
+	hardness float32
+	// tool is the kind of tool mining the block faster, like "pickaxe", or empty.
+	tool string
+	// requiresTool is set for the blocks which drop nothing unless mined by a correct tool.
+	requiresTool bool
+	tier         int
+	// known is unset for the blocks whose hardness isn't known.
+	known bool
+}
+
+// miningOf is the mining of the blocks keyed by the block id.
+var miningOf = make(map[string]mining)
+
+// Name tables used by hardnessOf and miningFor. The hardnesses are the destroy times of vanilla.
+var (
+	hardnessNames = map[float32]map[string]bool{
+		-1: names("bedrock", "barrier", "light", "command_block", "chain_command_block", "repeating_command_block",
+			"structure_block", "jigsaw", "end_portal", "end_gateway", "end_portal_frame", "nether_portal", "moving_piston"),
+		100: names("water", "lava"),
+		0: names("air", "cave_air", "void_air", "fire", "soul_fire", "tnt", "redstone_wire", "repeater", "comparator",
+			"tripwire", "tripwire_hook", "flower_pot", "slime_block", "honey_block", "scaffolding", "frogspawn",
+			"structure_void", "end_rod", "lily_pad", "sugar_cane", "kelp", "kelp_plant", "seagrass", "tall_seagrass",
+			"sea_pickle", "nether_wart", "sweet_berry_bush", "bamboo_sapling", "dead_bush", "hanging_roots",
+			"spore_blossom", "small_dripleaf", "azalea", "flowering_azalea", "cave_vines", "cave_vines_plant",
+			"twisting_vines", "twisting_vines_plant", "weeping_vines", "weeping_vines_plant", "nether_sprouts",
+			"crimson_roots", "warped_roots", "crimson_fungus", "warped_fungus", "brown_mushroom", "red_mushroom",
+			"decorated_pot", "pale_hanging_moss", "resin_clump", "wall_torch", "mangrove_propagule",
+			"attached_pumpkin_stem", "attached_melon_stem", "bubble_column"),
+		0.1: names("snow", "moss_block", "pale_moss_block", "big_dripleaf", "big_dripleaf_stem", "candle"),
+		0.2: names("vine", "glow_lichen", "sculk_vein", "sculk", "cocoa", "daylight_detector", "snow_block",
+			"brown_mushroom_block", "red_mushroom_block", "mushroom_stem"),
+		0.25: names("powder_snow", "suspicious_sand", "suspicious_gravel"),
+		0.3:  names("glass", "tinted_glass", "glass_pane", "glowstone", "sea_lantern", "redstone_lamp", "bee_nest"),
+		0.4:  names("cactus", "ladder", "netherrack", "crimson_nylium", "warped_nylium", "chorus_plant", "chorus_flower"),
+		0.5: names("dirt", "coarse_dirt", "podzol", "sand", "red_sand", "ice", "packed_ice", "frosted_ice", "soul_sand",
+			"soul_soil", "magma_block", "lever", "hay_block", "dried_kelp_block", "cake", "mud", "target", "rooted_dirt",
+			"brewing_stand", "turtle_egg", "sniffer_egg"),
+		0.6: names("gravel", "grass_block", "mycelium", "clay", "farmland", "sponge", "wet_sponge", "composter",
+			"beehive", "honeycomb_block"),
+		0.65: names("dirt_path"),
+		0.7:  names("rail", "mangrove_roots", "muddy_mangrove_roots"),
+		0.75: names("calcite"),
+		0.8: names("sandstone", "chiseled_sandstone", "cut_sandstone", "red_sandstone", "chiseled_red_sandstone",
+			"cut_red_sandstone", "quartz_block", "chiseled_quartz_block", "quartz_pillar", "quartz_bricks", "note_block"),
+		1: names("pumpkin", "carved_pumpkin", "jack_o_lantern", "melon", "nether_wart_block", "warped_wart_block",
+			"shroomlight", "bamboo", "packed_mud"),
+		1.25: names("terracotta", "basalt", "polished_basalt", "smooth_basalt"),
+		1.5: names("stone", "granite", "diorite", "andesite", "polished_granite", "polished_diorite", "polished_andesite",
+			"stone_bricks", "mossy_stone_bricks", "cracked_stone_bricks", "chiseled_stone_bricks",
+			"prismarine", "prismarine_bricks", "dark_prismarine", "purpur_block", "purpur_pillar",
+			"blackstone", "polished_blackstone", "polished_blackstone_bricks", "chiseled_polished_blackstone",
+			"cracked_polished_blackstone_bricks", "gilded_blackstone", "dripstone_block", "pointed_dripstone",
+			"tuff", "polished_tuff", "tuff_bricks", "chiseled_tuff", "chiseled_tuff_bricks",
+			"bookshelf", "chiseled_bookshelf", "piston", "sticky_piston", "piston_head",
+			"amethyst_block", "budding_amethyst", "amethyst_cluster", "sculk_sensor", "calibrated_sculk_sensor",
+			"mud_bricks", "crafter", "resin_bricks", "chiseled_resin_bricks", "infested_deepslate"),
+		2: names("cobblestone", "mossy_cobblestone", "bricks", "nether_bricks", "red_nether_bricks",
+			"cracked_nether_bricks", "chiseled_nether_bricks", "smooth_stone", "smooth_sandstone",
+			"smooth_red_sandstone", "smooth_quartz", "cauldron", "water_cauldron", "lava_cauldron",
+			"powder_snow_cauldron", "jukebox", "campfire", "soul_campfire", "bone_block", "grindstone",
+			"shulker_box", "bamboo_block", "bamboo_mosaic", "stripped_bamboo_block"),
+		2.5: names("crafting_table", "chest", "trapped_chest", "barrel", "lectern", "loom", "cartography_table",
+			"fletching_table", "smithing_table"),
+		2.8: names("blue_ice"),
+		3: names("end_stone", "end_stone_bricks", "deepslate", "gold_block", "lapis_block", "hopper", "observer",
+			"beacon", "conduit", "dragon_egg", "lightning_rod", "sculk_catalyst", "sculk_shrieker"),
+		3.5: names("cobbled_deepslate", "polished_deepslate", "deepslate_bricks", "deepslate_tiles",
+			"cracked_deepslate_bricks", "cracked_deepslate_tiles", "chiseled_deepslate", "furnace", "blast_furnace",
+			"smoker", "dispenser", "dropper", "lodestone", "lantern", "soul_lantern", "stonecutter"),
+		4: names("cobweb"),
+		5: names("iron_block", "diamond_block", "emerald_block", "redstone_block", "coal_block", "raw_iron_block",
+			"raw_copper_block", "raw_gold_block", "iron_bars", "iron_door", "iron_trapdoor", "anvil", "chipped_anvil",
+			"damaged_anvil", "bell", "spawner", "chain", "enchanting_table"),
+		10:   names("heavy_core", "creaking_heart"),
+		22.5: names("ender_chest"),
+		30:   names("ancient_debris"),
+		50:   names("obsidian", "crying_obsidian", "respawn_anchor", "netherite_block", "vault", "trial_spawner"),
+		55:   names("reinforced_deepslate"),
+	}
+	// hardnessSuffixes are checked in order after the names.
+	hardnessSuffixes = []struct {
+		suffix   string
+		hardness float32
+	}{
+		{"_glazed_terracotta", 1.4}, {"_terracotta", 1.25}, {"_concrete_powder", 0.5}, {"_concrete", 1.8},
+		{"_stained_glass_pane", 0.3}, {"_stained_glass", 0.3}, {"_wool", 0.8}, {"_carpet", 0.1},
+		{"_candle_cake", 0.5}, {"_candle", 0.1}, {"_bed", 0.2}, {"_leaves", 0.2}, {"_banner", 1}, {"_sign", 1},
+		{"_head", 1}, {"_skull", 1}, {"_shulker_box", 2}, {"_log", 2}, {"_wood", 2}, {"_stem", 2}, {"_hyphae", 2},
+		{"_planks", 2}, {"_fence_gate", 2}, {"_fence", 2}, {"_button", 0.5}, {"_pressure_plate", 0.5},
+		{"_trapdoor", 3}, {"_door", 3}, {"_rail", 0.7}, {"_sapling", 0}, {"_tulip", 0}, {"_coral_block", 1.5},
+		{"_coral_wall_fan", 0}, {"_coral_fan", 0}, {"_coral", 0}, {"_torch", 0}, {"_froglight", 0.3},
+		{"_amethyst_bud", 1.5}, {"_ore", 3},
+	}
+	// materialSuffixes are the blocks made of another one, like stairs, with the hardness of the material.
+	materialSuffixes = []string{"_stairs", "_slab", "_wall"}
+
+	woodTypes = []string{
+		"oak_", "spruce_", "birch_", "jungle_", "acacia_", "dark_oak_", "mangrove_", "cherry_", "pale_oak_",
+		"bamboo_", "crimson_", "warped_",
+	}
+	axeNames = names("chest", "trapped_chest", "barrel", "crafting_table", "bookshelf", "chiseled_bookshelf",
+		"lectern", "loom", "composter", "jukebox", "note_block", "campfire", "soul_campfire", "pumpkin",
+		"carved_pumpkin", "jack_o_lantern", "melon", "beehive", "bee_nest", "cocoa", "ladder", "bamboo",
+		"brown_mushroom_block", "red_mushroom_block", "mushroom_stem", "daylight_detector", "cartography_table",
+		"fletching_table", "smithing_table", "creaking_heart", "vine", "glow_lichen", "mangrove_roots")
+	shovelNames = names("dirt", "grass_block", "coarse_dirt", "podzol", "mycelium", "rooted_dirt", "farmland",
+		"dirt_path", "sand", "red_sand", "gravel", "clay", "soul_sand", "soul_soil", "snow", "snow_block",
+		"powder_snow", "mud", "muddy_mangrove_roots", "suspicious_sand", "suspicious_gravel")
+	hoeNames = names("hay_block", "sponge", "wet_sponge", "target", "dried_kelp_block", "sculk", "sculk_vein",
+		"sculk_catalyst", "sculk_sensor", "calibrated_sculk_sensor", "sculk_shrieker", "moss_block", "moss_carpet",
+		"pale_moss_block", "pale_moss_carpet", "nether_wart_block", "warped_wart_block", "shroomlight")
+	// pickaxeNames are the blocks mined by pickaxes whose hardness is below the one of stone.
+	pickaxeNames = names("sandstone", "chiseled_sandstone", "cut_sandstone", "red_sandstone",
+		"chiseled_red_sandstone", "cut_red_sandstone", "quartz_block", "chiseled_quartz_block", "quartz_pillar",
+		"quartz_bricks", "ice", "packed_ice", "frosted_ice", "netherrack", "crimson_nylium", "warped_nylium",
+		"calcite", "magma_block", "brewing_stand", "stone_button", "polished_blackstone_button",
+		"stone_pressure_plate", "polished_blackstone_pressure_plate", "light_weighted_pressure_plate",
+		"heavy_weighted_pressure_plate", "rail", "powered_rail", "detector_rail", "activator_rail")
+	// notPickaxeNames are hard blocks which aren't mined faster by pickaxes.
+	notPickaxeNames = names("cobweb", "dragon_egg", "beacon", "bedrock", "barrier", "light", "command_block",
+		"chain_command_block", "repeating_command_block", "structure_block", "jigsaw", "end_portal_frame",
+		"reinforced_deepslate", "water", "lava")
+	// noToolNames are the blocks mined by pickaxes which drop without them.
+	noToolNames = names("ice", "packed_ice", "blue_ice", "frosted_ice", "piston", "sticky_piston", "piston_head",
+		"shulker_box", "conduit", "pointed_dripstone", "stone_button", "polished_blackstone_button",
+		"rail", "powered_rail", "detector_rail", "activator_rail")
+
+	needsDiamondNames = names("obsidian", "crying_obsidian", "netherite_block", "respawn_anchor", "ancient_debris")
+	needsIronNames    = names("diamond_ore", "deepslate_diamond_ore", "diamond_block", "emerald_ore",
+		"deepslate_emerald_ore", "emerald_block", "gold_ore", "deepslate_gold_ore", "gold_block", "raw_gold_block",
+		"redstone_ore", "deepslate_redstone_ore")
+	needsStoneNames = names("iron_ore", "deepslate_iron_ore", "iron_block", "raw_iron_block", "lapis_ore",
+		"deepslate_lapis_ore", "lapis_block", "raw_copper_block", "lightning_rod")
+
+	leavesSuffix = "_leaves"
+	// swordEfficientNames are the blocks swords mine a bit faster, besides the leaves and the plants.
+	swordEfficientNames = names("cocoa", "vine", "glow_lichen", "pumpkin", "carved_pumpkin", "jack_o_lantern",
+		"melon", "big_dripleaf", "big_dripleaf_stem", "small_dripleaf", "hanging_roots", "moss_carpet",
+		"pale_moss_carpet", "sweet_berry_bush", "kelp", "kelp_plant", "seagrass", "tall_seagrass", "dead_bush",
+		"cave_vines", "cave_vines_plant", "weeping_vines", "twisting_vines", "nether_sprouts", "crimson_roots",
+		"warped_roots", "crimson_fungus", "warped_fungus", "brown_mushroom", "red_mushroom", "spore_blossom")
+)
+
+func init() {
+	for id := range FromID {
+		miningOf[id] = miningFor(id)
+	}
+}
+
+// hardnessOf returns the hardness of the block of the id, false if it isn't known.
+func hardnessOf(id string) (float32, bool) {
+	for h, names := range hardnessNames {
+		if names[id] {
+			return h, true
+		}
+	}
+	name := strings.TrimPrefix(id, "minecraft:")
+	switch {
+	case plantNames[id], cropNames[id], torchNames[id], strings.HasPrefix(name, "potted_"):
+		return 0, true
+	case strings.HasPrefix(name, "deepslate_") && strings.HasSuffix(name, "_ore"):
+		return 4.5, true
+	case strings.HasPrefix(name, "infested_"):
+		return 0.75, true
+	case strings.Contains(name, "copper") && !strings.HasSuffix(name, "_ore"):
+		return 3, true
+	}
+	for _, s := range hardnessSuffixes {
+		if strings.HasSuffix(name, s.suffix) {
+			return s.hardness, true
+		}
+	}
+	if material, ok := materialOf(id); ok {
+		return hardnessOf(material)
+	}
+	return 0, false
+}
+
+// materialOf returns the block the stairs, slab or wall of the id is made of,
+// like minecraft:oak_planks for minecraft:oak_stairs.
+func materialOf(id string) (string, bool) {
+	for _, suffix := range materialSuffixes {
+		base, ok := strings.CutSuffix(id, suffix)
+		if !ok {
+			continue
+		}
+		for _, m := range []string{base, base + "s", base + "_planks", base + "_block"} {
+			if _, ok := FromID[m]; ok {
+				return m, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isWooden reports whether the block of the id is made of wood, like minecraft:oak_door.
+func isWooden(id string) bool {
+	name := strings.TrimPrefix(id, "minecraft:")
+	return hasAny(name, strings.HasPrefix, woodTypes) || strings.HasPrefix(name, "stripped_")
+}
+
+func miningFor(id string) mining {
+	if material, ok := materialOf(id); ok {
+		return miningFor(material)
+	}
+	m := mining{hardness: 1}
+	hardness, known := hardnessOf(id)
+	if known {
+		m.hardness, m.known = hardness, true
+	}
+	name := strings.TrimPrefix(id, "minecraft:")
+	switch {
+	case shovelNames[id], strings.HasSuffix(name, "_concrete_powder"):
+		m.tool = "shovel"
+		m.requiresTool = name == "snow" || name == "snow_block"
+	case hoeNames[id], strings.HasSuffix(name, leavesSuffix):
+		m.tool = "hoe"
+	case axeNames[id], strings.HasSuffix(name, "_sign"), strings.HasSuffix(name, "_banner"),
+		isWooden(id) && !strings.Contains(name, "nylium") && !strings.Contains(name, "roots") &&
+			!strings.Contains(name, "fungus") && !strings.HasSuffix(name, "_sapling"):
+		m.tool = "axe"
+	case pickaxeNames[id], known && hardness >= 1 && !notPickaxeNames[id] && !strings.HasSuffix(name, "_head") &&
+		!strings.HasSuffix(name, "_skull") && !strings.HasSuffix(name, "_wool") && !strings.HasPrefix(name, "infested_"):
+		m.tool = "pickaxe"
+		m.requiresTool = !noToolNames[id] && !strings.HasSuffix(name, "shulker_box")
+	case id == "minecraft:cobweb":
+		m.requiresTool = true
+	}
+	switch {
+	case needsDiamondNames[id]:
+		m.tier = tierDiamond
+	case needsIronNames[id]:
+		m.tier = tierIron
+	case needsStoneNames[id], m.tool == "pickaxe" && strings.Contains(name, "copper"):
+		m.tier = tierStone
+	}
+	return m
+}
+
+// Hardness returns the hardness of the block, its destroy time in vanilla.
+// It's -1 for the unbreakable blocks like bedrock.
+// The hardness of the blocks which aren't known is guessed from their shapes.
+func Hardness(s StateID) float32 {
+	m := miningOf[StateList[s].ID()]
+	if !m.known && ShapeOf(s) == ShapeEmpty {
+		return 0
+	}
+	return m.hardness
+}
+
+// RequiresCorrectTool reports whether the block drops nothing unless it's mined with a correct tool,
+// like stone needing a pickaxe.
+func RequiresCorrectTool(s StateID) bool {
+	return miningOf[StateList[s].ID()].requiresTool
+}
+
+// InMiningTag reports whether the block is in the vanilla block tag of the name, without the #.
+// Only the tags used by the tools are known, like minecraft:mineable/pickaxe or minecraft:needs_iron_tool.
+func InMiningTag(s StateID, tag string) bool {
+	b := StateList[s]
+	m := miningOf[b.ID()]
+	name := strings.TrimPrefix(b.ID(), "minecraft:")
+	switch tag {
+	case "minecraft:mineable/pickaxe", "minecraft:mineable/axe", "minecraft:mineable/shovel", "minecraft:mineable/hoe":
+		return m.tool == strings.TrimPrefix(tag, "minecraft:mineable/")
+	case "minecraft:needs_stone_tool":
+		return m.tier == tierStone
+	case "minecraft:needs_iron_tool":
+		return m.tier == tierIron
+	case "minecraft:needs_diamond_tool":
+		return m.tier == tierDiamond
+	case "minecraft:incorrect_for_wooden_tool", "minecraft:incorrect_for_gold_tool":
+		return m.tier >= tierStone
+	case "minecraft:incorrect_for_stone_tool":
+		return m.tier >= tierIron
+	case "minecraft:incorrect_for_iron_tool":
+		return m.tier >= tierDiamond
+	case "minecraft:leaves":
+		return strings.HasSuffix(name, leavesSuffix)
+	case "minecraft:wool":
+		return strings.HasSuffix(name, "_wool")
+	case "minecraft:sword_efficient":
+		return strings.HasSuffix(name, leavesSuffix) || swordEfficientNames[b.ID()] || plantNames[b.ID()] ||
+			cropNames[b.ID()] || strings.HasSuffix(name, "_sapling") || strings.HasSuffix(name, "_tulip")
+	}
+	return false
+}
+
+// toolRule returns the first rule of the tool matching the block for which has is set.
+func toolRule(t *component.Tool, s StateID, has func(r component.ToolRule) bool) (component.ToolRule, bool) {
+	for _, r := range t.Rules {
+		if !has(r) {
+			continue
+		}
+		if r.Blocks.Tag != "" && InMiningTag(s, string(r.Blocks.Tag)) ||
+			r.Blocks.Tag == "" && containsID(r.Blocks.IDs, registryIDs[StateList[s].ID()]) {
+			return r, true
+		}
+	}
+	return component.ToolRule{}, false
+}
+
+// ToolSpeed returns the speed the tool mines the block at, from the first of its rules setting it.
+func ToolSpeed(t *component.Tool, s StateID) float32 {
+	if r, ok := toolRule(t, s, func(r component.ToolRule) bool { return bool(r.Speed.Has) }); ok {
+		return float32(r.Speed.Val)
+	}
+	return float32(t.DefaultMiningSpeed)
+}
+
+// IsCorrectTool reports whether the tool gets the drops of the blocks requiring a correct tool,
+// from the first of its rules deciding it.
+func IsCorrectTool(t *component.Tool, s StateID) bool {
+	r, ok := toolRule(t, s, func(r component.ToolRule) bool { return bool(r.CorrectForDrop.Has) })
+	return ok && bool(r.CorrectForDrop.Val)
+}
+
+// Miner is what the mining speed of a player depends on besides the block.
+type Miner struct {
+	// Tool is how the held item mines blocks, nil if it isn't a tool.
+	Tool *component.Tool
+	// Efficiency is the level of the efficiency enchantment of the tool.
+	Efficiency int32
+	// Haste and MiningFatigue are the amplifiers of the effects plus one, 0 without them.
+	Haste, MiningFatigue int32
+	// Underwater is set when the eyes are in water without the aqua affinity enchantment.
+	Underwater bool
+	OnGround   bool
+}
+
+// HasCorrectTool reports whether the block drops its items when the miner breaks it.
+func (m Miner) HasCorrectTool(s StateID) bool {
+	return !RequiresCorrectTool(s) || m.Tool != nil && IsCorrectTool(m.Tool, s)
+}
+
+// DestroySpeed returns the speed the miner mines the block at, like vanilla's Player.getDestroySpeed.
+func (m Miner) DestroySpeed(s StateID) float32 {
+	speed := float32(1)
+	if m.Tool != nil {
+		speed = ToolSpeed(m.Tool, s)
+	}
+	if speed > 1 && m.Efficiency > 0 {
+		speed += float32(m.Efficiency*m.Efficiency + 1)
+	}
+	if m.Haste > 0 {
+		speed *= 1 + float32(m.Haste)*0.2
+	}
+	switch m.MiningFatigue {
+	case 0:
+	case 1:
+		speed *= 0.3
+	case 2:
+		speed *= 0.09
+	case 3:
+		speed *= 0.0027
+	default:
+		speed *= 0.00081
+	}
+	if m.Underwater {
+		speed *= 0.2
+	}
+	if !m.OnGround {
+		speed /= 5
+	}
+	return speed
+}
+
+// DestroyProgress returns the part of the block the miner breaks in a game tick,
+// like vanilla's BlockState.getDestroyProgress. A block with a progress of 1 or more breaks at once.
+func (m Miner) DestroyProgress(s StateID) float32 {
+	hardness := Hardness(s)
+	if hardness < 0 {
+		return 0
+	}
+	if hardness == 0 {
+		return 1
+	}
+	return m.DestroySpeed(s) / hardness / pick[float32](m.HasCorrectTool(s), 30, 100)
+}
@@ -0,0 +1,96 @@
+package block
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+func TestHardness(t *testing.T) {
+	for _, tt := range []struct {
+		b    Block
+		want float32
+	}{
+		{Stone{}, 1.5},
+		{Bedrock{}, -1},
+		{ShortGrass{}, 0},
+		{OakStairs{Facing: North}, 2},
+		{CobblestoneWall{}, 2},
+		{DeepslateDiamondOre{}, 4.5},
+		{WhiteWool{}, 0.8},
+		{Obsidian{}, 50},
+	} {
+		if got := Hardness(ToStateID[tt.b]); got != tt.want {
+			t.Errorf("Hardness(%s) = %v, want %v", tt.b.ID(), got, tt.want)
+		}
+	}
+}
+
+// pickaxe is the default tool of a vanilla iron pickaxe.
+var pickaxe = &component.Tool{
+	Rules: []component.ToolRule{
+		{
+			Blocks:         component.IDSet{Tag: "minecraft:incorrect_for_iron_tool"},
+			CorrectForDrop: pk.Option[pk.Boolean, *pk.Boolean]{Has: true, Val: false},
+		},
+		{
+			Blocks:         component.IDSet{Tag: "minecraft:mineable/pickaxe"},
+			Speed:          pk.Option[pk.Float, *pk.Float]{Has: true, Val: 6},
+			CorrectForDrop: pk.Option[pk.Boolean, *pk.Boolean]{Has: true, Val: true},
+		},
+	},
+	DefaultMiningSpeed: 1,
+}
+
+func TestMiner_HasCorrectTool(t *testing.T) {
+	hand, iron := Miner{}, Miner{Tool: pickaxe}
+	for _, tt := range []struct {
+		b          Block
+		hand, iron bool
+	}{
+		{Stone{}, false, true},
+		{DiamondOre{}, false, true},
+		{Obsidian{}, false, false},
+		{Dirt{}, true, true},
+		{OakLog{Axis: Y}, true, true},
+		{Ice{}, true, true},
+	} {
+		s := ToStateID[tt.b]
+		if got := hand.HasCorrectTool(s); got != tt.hand {
+			t.Errorf("hand HasCorrectTool(%s) = %v, want %v", tt.b.ID(), got, tt.hand)
+		}
+		if got := iron.HasCorrectTool(s); got != tt.iron {
+			t.Errorf("iron pickaxe HasCorrectTool(%s) = %v, want %v", tt.b.ID(), got, tt.iron)
+		}
+	}
+}
+
+func TestMiner_DestroyProgress(t *testing.T) {
+	stone := ToStateID[Stone{}]
+	for _, tt := range []struct {
+		name  string
+		m     Miner
+		s     StateID
+		ticks int
+	}{
+		{"stone by hand", Miner{OnGround: true}, stone, 150},
+		{"stone with an iron pickaxe", Miner{Tool: pickaxe, OnGround: true}, stone, 8},
+		{"stone while jumping", Miner{Tool: pickaxe}, stone, 38},
+		{"stone with efficiency and haste", Miner{Tool: pickaxe, Efficiency: 2, Haste: 2, OnGround: true}, stone, 3},
+		{"stone with mining fatigue", Miner{Tool: pickaxe, MiningFatigue: 1, OnGround: true}, stone, 25},
+		{"stone underwater", Miner{Tool: pickaxe, Underwater: true, OnGround: true}, stone, 38},
+		{"dirt with a pickaxe", Miner{Tool: pickaxe, OnGround: true}, ToStateID[Dirt{}], 15},
+		{"grass", Miner{}, ToStateID[ShortGrass{}], 1},
+	} {
+		progress := tt.m.DestroyProgress(tt.s)
+		ticks := int(math.Ceil(float64(1 / progress)))
+		if ticks != tt.ticks {
+			t.Errorf("%s: DestroyProgress() = %v, breaking in %d ticks, want %d", tt.name, progress, ticks, tt.ticks)
+		}
+	}
+	if p := (Miner{Tool: pickaxe, OnGround: true}).DestroyProgress(ToStateID[Bedrock{}]); p != 0 {
+		t.Errorf("bedrock DestroyProgress() = %v, want 0", p)
+	}
+}
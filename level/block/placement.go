@@ -0,0 +1,539 @@
+package block
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// PlaceContext is a player placing a block item by clicking on a block.
+// The placed block state is derived from it, like vanilla's BlockPlaceContext.
+type PlaceContext struct {
+	// Item is the id of the held item.
+	Item string
+	// Clicked is the block the player clicked on, and Face is the clicked face of it.
+	Clicked [3]int
+	Face    Direction
+	// Cursor is the clicked point relative to the clicked block, each from 0 to 1.
+	Cursor [3]float64
+	// Yaw and Pitch are the rotation of the player.
+	Yaw, Pitch float32
+	// Get returns the block state at the world coordinates,
+	// or false if the chunk isn't loaded or y is out of the world.
+	Get func(x, y, z int) (StateID, bool)
+}
+
+// Placed is a block set by placing a block item.
+type Placed struct {
+	Pos   [3]int
+	State StateID
+}
+
+// Place returns the blocks set by placing the block item of the context.
+// Two-block structures like doors, beds and tall plants return both parts, the clicked one first.
+// It reports false if the item doesn't place a block or the block can't be placed there.
+// Entities in the way aren't checked, which is done by the caller.
+func Place(ctx PlaceContext) ([]Placed, bool) {
+	id, ok := ItemBlock(ctx.Item)
+	if !ok {
+		return nil, false
+	}
+	p := placer{PlaceContext: ctx, pos: ctx.Clicked, replacingClicked: true}
+	s, ok := p.get(p.pos)
+	if !ok {
+		return nil, false
+	}
+	if !p.canBeReplaced(s, id) {
+		p.pos, p.replacingClicked = ctx.Face.Relative(ctx.Clicked), false
+		if s, ok = p.get(p.pos); !ok || !p.canBeReplaced(s, id) {
+			return nil, false
+		}
+	}
+	p.existing = StateList[s]
+
+	var b Block
+	if wall, ok := wallVariants[id]; ok {
+		b, ok = p.standingOrWall(id, wall)
+		if !ok {
+			return nil, false
+		}
+	} else if b, ok = p.stateFor(id); !ok || !p.canSurvive(b, p.pos) {
+		return nil, false
+	}
+	b = connect(b, p.pos, p.Get)
+	placed := []Placed{{Pos: p.pos, State: ToStateID[b]}}
+	if pos, other, ok := p.otherPart(b); ok {
+		placed = append(placed, Placed{Pos: pos, State: ToStateID[other]})
+	}
+	return placed, true
+}
+
+// itemBlocks are the items placing a block of a different name.
+var itemBlocks = map[string]string{
+	"minecraft:redstone":          "minecraft:redstone_wire",
+	"minecraft:string":            "minecraft:tripwire",
+	"minecraft:wheat_seeds":       "minecraft:wheat",
+	"minecraft:beetroot_seeds":    "minecraft:beetroots",
+	"minecraft:carrot":            "minecraft:carrots",
+	"minecraft:potato":            "minecraft:potatoes",
+	"minecraft:melon_seeds":       "minecraft:melon_stem",
+	"minecraft:pumpkin_seeds":     "minecraft:pumpkin_stem",
+	"minecraft:torchflower_seeds": "minecraft:torchflower_crop",
+	"minecraft:pitcher_pod":       "minecraft:pitcher_crop",
+	"minecraft:sweet_berries":     "minecraft:sweet_berry_bush",
+	"minecraft:glow_berries":      "minecraft:cave_vines",
+	"minecraft:cocoa_beans":       "minecraft:cocoa",
+}
+
+// notBlockItems are the items sharing the name of a block they don't place.
+var notBlockItems = names("air", "wheat")
+
+// ItemBlock returns the id of the block placed by the item, or false if it isn't a block item.
+func ItemBlock(item string) (string, bool) {
+	if id, ok := itemBlocks[item]; ok {
+		return id, true
+	}
+	if _, ok := FromID[item]; !ok || notBlockItems[item] {
+		return "", false
+	}
+	return item, true
+}
+
+// wallVariants maps the blocks placed on the floor, or the ceiling for hanging signs,
+// to the ones of the same item placed on walls. Like vanilla's StandingAndWallBlockItem.
+var wallVariants map[string]string
+
+func init() {
+	wallVariants = make(map[string]string)
+	for id := range FromID {
+		for _, suffix := range []string{"hanging_sign", "sign", "torch", "banner", "skull", "head", "fan"} {
+			if prefix, ok := strings.CutSuffix(id, suffix); ok {
+				if _, ok := FromID[prefix+"wall_"+suffix]; ok {
+					wallVariants[id] = prefix + "wall_" + suffix
+				}
+				break
+			}
+		}
+	}
+}
+
+// replaceableNames are the blocks replaced by placing any block in them.
+var replaceableNames = names(
+	"air", "cave_air", "void_air", "water", "lava", "bubble_column",
+	"short_grass", "fern", "dead_bush", "seagrass", "tall_seagrass", "tall_grass", "large_fern",
+	"fire", "soul_fire", "vine", "glow_lichen", "resin_clump", "light", "structure_void",
+	"crimson_roots", "warped_roots", "nether_sprouts", "hanging_roots",
+)
+
+// countFields are the properties of the blocks stacked by placing more of them in the same place,
+// with their maximum.
+var countFields = map[string]int{"Layers": 8, "Candles": 4, "Pickles": 4, "Eggs": 4}
+
+type placer struct {
+	PlaceContext
+	pos [3]int
+	// replacingClicked is true if the block is placed in place of the clicked block
+	// instead of next to its clicked face.
+	replacingClicked bool
+	existing         Block
+}
+
+func (p *placer) get(pos [3]int) (StateID, bool) {
+	return p.Get(pos[0], pos[1], pos[2])
+}
+
+// canBeReplaced reports whether the block state can be replaced by the placed block.
+func (p *placer) canBeReplaced(s StateID, id string) bool {
+	b := StateList[s]
+	if b.ID() == id {
+		if t, ok := fieldOf[SlabType](b, "Type"); ok && t != SlabTypeDouble {
+			if !p.replacingClicked {
+				return true
+			}
+			upper := p.clickOffset(1) > 0.5
+			if t == SlabTypeBottom {
+				return p.Face == Up || upper && p.Face.IsHorizontal()
+			}
+			return p.Face == Down || !upper && p.Face.IsHorizontal()
+		}
+		for name, limit := range countFields {
+			if n := intField(b, name, limit); n < limit {
+				// Snow layers are only stacked from above.
+				return !p.replacingClicked || name != "Layers" || p.Face == Up
+			}
+		}
+	}
+	if _, ok := b.(Snow); ok {
+		return intField(b, "Layers", 1) == 1
+	}
+	return replaceableNames[b.ID()]
+}
+
+// clickOffset returns the coordinate of the clicked point on the axis relative to the placed block.
+func (p *placer) clickOffset(axis int) float64 {
+	return float64(p.Clicked[axis]) + p.Cursor[axis] - float64(p.pos[axis])
+}
+
+func (p *placer) horizontalDirection() Direction {
+	return HorizontalDirection(p.Yaw)
+}
+
+// lookingDirections returns the six directions ordered by how much the player is looking at them.
+// Like vanilla's Direction.orderedByNearest.
+func (p *placer) lookingDirections() [6]Direction {
+	const rad = math.Pi / 180
+	pitch, yaw := float64(p.Pitch)*rad, -float64(p.Yaw)*rad
+	sinPitch, cosPitch := math.Sin(pitch), math.Cos(pitch)
+	sinYaw, cosYaw := math.Sin(yaw), math.Cos(yaw)
+	x, y, z := East, Up, South
+	if sinYaw <= 0 {
+		x = West
+	}
+	if sinPitch >= 0 {
+		y = Down
+	}
+	if cosYaw <= 0 {
+		z = North
+	}
+	dx, dy, dz := math.Abs(sinYaw), math.Abs(sinPitch), math.Abs(cosYaw)
+	hx, hz := dx*cosPitch, dz*cosPitch
+	order := func(a, b, c Direction) [6]Direction {
+		return [6]Direction{a, b, c, c.Opposite(), b.Opposite(), a.Opposite()}
+	}
+	switch {
+	case dx > dz && dy > hx:
+		return order(y, x, z)
+	case dx > dz && hz > dy:
+		return order(x, z, y)
+	case dx > dz:
+		return order(x, y, z)
+	case dy > hz:
+		return order(y, z, x)
+	case hx > dy:
+		return order(z, x, y)
+	default:
+		return order(z, y, x)
+	}
+}
+
+// nearestLookingDirections returns the directions the block is tried to be attached to, in order.
+// It's the directions the player is looking at, but the clicked block comes first.
+func (p *placer) nearestLookingDirections() []Direction {
+	dirs := p.lookingDirections()
+	if p.replacingClicked {
+		return dirs[:]
+	}
+	clicked := p.Face.Opposite()
+	list := []Direction{clicked}
+	for _, d := range dirs {
+		if d != clicked {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+// standingOrWall returns the state of the block, or of its wall variant,
+// attached to the first supporting block in the nearest looking directions.
+func (p *placer) standingOrWall(id, wall string) (Block, bool) {
+	attach := Down
+	if strings.HasSuffix(id, "_hanging_sign") {
+		attach = Up
+	}
+	wallState, wallOK := p.stateFor(wall)
+	for _, d := range p.nearestLookingDirections() {
+		if d == attach.Opposite() {
+			continue
+		}
+		b, ok := wallState, wallOK
+		if d == attach {
+			b, ok = p.stateFor(id)
+		}
+		if ok && p.canSurvive(b, p.pos) {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// stateFor returns the state of the block placed by the context, like vanilla's getStateForPlacement.
+func (p *placer) stateFor(id string) (b Block, ok bool) {
+	s, ok := DefaultState(id)
+	if !ok {
+		return nil, false
+	}
+	b = StateList[s]
+	if p.existing.ID() == id {
+		if _, ok := fieldOf[SlabType](b, "Type"); ok {
+			b = p.existing
+			b, _ = withField(b, "Type", SlabTypeDouble)
+			b, _ = withField(b, "Waterlogged", Boolean(false))
+			return b, true
+		}
+		for name, limit := range countFields {
+			if n := intField(p.existing, name, -1); n >= 0 {
+				b, _ = withField(p.existing, name, Integer(min(n+1, limit)))
+				return b, true
+			}
+		}
+	}
+
+	face := p.Face
+	horizontal := p.horizontalDirection()
+	// The bottom half is chosen by clicking the lower half of a side, or the top of a block.
+	bottom := face != Down && (face == Up || p.clickOffset(1) <= 0.5)
+	_, hasFacing := fieldOf[Direction](b, "Facing")
+	switch {
+	case hasType[SlabType](b, "Type"):
+		b, _ = withField(b, "Type", pick(bottom, SlabTypeBottom, SlabTypeTop))
+
+	case strings.HasSuffix(id, "_stairs"):
+		b, _ = withField(b, "Facing", horizontal)
+		b, _ = withField(b, "Half", pick(bottom, Bottom, Top))
+
+	case strings.HasSuffix(id, "_trapdoor"):
+		if !p.replacingClicked && face.IsHorizontal() {
+			b, _ = withField(b, "Facing", face)
+			b, _ = withField(b, "Half", pick(p.clickOffset(1) > 0.5, Top, Bottom))
+		} else {
+			b, _ = withField(b, "Facing", horizontal.Opposite())
+			b, _ = withField(b, "Half", pick(face == Up, Bottom, Top))
+		}
+
+	case hasType[DoorHingeSide](b, "Hinge"):
+		if !p.replaceable(Up.Relative(p.pos), id) {
+			return nil, false
+		}
+		b, _ = withField(b, "Facing", horizontal)
+		b, _ = withField(b, "Hinge", p.doorHinge(id, horizontal))
+		b, _ = withField(b, "Half", DoubleBlockHalfLower)
+
+	case hasType[BedPart](b, "Part"):
+		if !p.replaceable(horizontal.Relative(p.pos), id) {
+			return nil, false
+		}
+		b, _ = withField(b, "Facing", horizontal)
+		b, _ = withField(b, "Part", BedPartFoot)
+
+	case hasType[DoubleBlockHalf](b, "Half"):
+		if !p.replaceable(Up.Relative(p.pos), id) {
+			return nil, false
+		}
+		b, _ = withField(b, "Half", DoubleBlockHalfLower)
+		b, _ = withField(b, "Facing", horizontal.Opposite())
+
+	case hasType[AttachFace](b, "Face"):
+		// Buttons, levers and grindstones are attached to the first block around in the looking directions.
+		for _, d := range p.nearestLookingDirections() {
+			attached := b
+			if d.IsHorizontal() {
+				attached, _ = withField(attached, "Face", AttachFaceWall)
+				attached, _ = withField(attached, "Facing", d.Opposite())
+			} else {
+				attached, _ = withField(attached, "Face", pick(d == Up, AttachFaceCeiling, AttachFaceFloor))
+				attached, _ = withField(attached, "Facing", horizontal)
+			}
+			if p.canSurvive(attached, p.pos) {
+				b = attached
+				break
+			}
+		}
+
+	case hasType[Axis](b, "Axis"):
+		b, _ = withField(b, "Axis", face.Axis())
+
+	case hasType[Integer](b, "Rotation"):
+		rotation := rotationSegment(p.Yaw + 180)
+		switch {
+		case strings.HasSuffix(id, "_skull"), strings.HasSuffix(id, "_head"):
+			rotation = rotationSegment(p.Yaw)
+		case strings.HasSuffix(id, "_hanging_sign"):
+			// Hanging signs under a full block face one of the four directions, and hang by two chains.
+			above, _ := p.get(Up.Relative(p.pos))
+			attached := ShapeOf(above) != ShapeFull
+			if !attached {
+				rotation = horizontal.yawIndex() * 4
+			}
+			b, _ = withField(b, "Attached", Boolean(attached))
+		}
+		b, _ = withField(b, "Rotation", Integer(rotation))
+
+	case hasType[Boolean](b, "Hanging"):
+		for _, d := range p.nearestLookingDirections() {
+			if d.IsHorizontal() {
+				continue
+			}
+			hanging, _ := withField(b, "Hanging", Boolean(d == Up))
+			if p.canSurvive(hanging, p.pos) {
+				b = hanging
+				break
+			}
+		}
+
+	case hasFacing:
+		b = p.facingFor(id, b, horizontal)
+	}
+
+	if existing, ok := p.existing.(Water); ok && existing.Level == 0 {
+		b, _ = withField(b, "Waterlogged", Boolean(true))
+	}
+	return b, isState(b)
+}
+
+// facingFor sets the facing of the blocks whose only directional property is it.
+func (p *placer) facingFor(id string, b Block, horizontal Direction) Block {
+	switch {
+	case strings.Contains(id, "wall_"), id == "minecraft:ladder", id == "minecraft:tripwire_hook",
+		id == "minecraft:cocoa":
+		// Facing away from the block it's attached to, except for cocoa which faces the log.
+		for _, d := range p.nearestLookingDirections() {
+			if !d.IsHorizontal() {
+				continue
+			}
+			attached, _ := withField(b, "Facing", pick(id == "minecraft:cocoa", d, d.Opposite()))
+			if p.canSurvive(attached, p.pos) {
+				return attached
+			}
+		}
+		return b
+	case strings.HasSuffix(id, "_rod"), strings.HasSuffix(id, "shulker_box"),
+		strings.HasSuffix(id, "amethyst_cluster"), strings.HasSuffix(id, "amethyst_bud"):
+		b, _ = withField(b, "Facing", p.Face)
+	case id == "minecraft:hopper":
+		b, _ = withField(b, "Facing", pick(p.Face.IsHorizontal(), p.Face.Opposite(), Down))
+	case id == "minecraft:observer":
+		b, _ = withField(b, "Facing", p.lookingDirections()[0])
+	case strings.HasSuffix(id, "anvil"):
+		b, _ = withField(b, "Facing", horizontal.Clockwise())
+	case strings.HasSuffix(id, "_fence_gate"), strings.HasSuffix(id, "campfire"):
+		b, _ = withField(b, "Facing", horizontal)
+	default:
+		if up, _ := withField(b, "Facing", Up); isState(up) {
+			// Blocks facing any direction, like pistons and dispensers, face the player.
+			b, _ = withField(b, "Facing", p.lookingDirections()[0].Opposite())
+		} else {
+			b, _ = withField(b, "Facing", horizontal.Opposite())
+		}
+	}
+	return b
+}
+
+// replaceable reports whether the block at pos is loaded and can be replaced by the other part
+// of a two-block structure.
+func (p *placer) replaceable(pos [3]int, id string) bool {
+	s, ok := p.get(pos)
+	if !ok {
+		return false
+	}
+	q := *p
+	q.pos, q.replacingClicked = pos, false
+	return q.canBeReplaced(s, id)
+}
+
+// doorHinge returns the side of the hinge of a door placed facing the direction.
+// The hinge is put next to a wall or away from another door, otherwise on the side the player clicked.
+func (p *placer) doorHinge(id string, facing Direction) DoorHingeSide {
+	above := Up.Relative(p.pos)
+	left, right := facing.CounterClockwise(), facing.Clockwise()
+	full := func(pos [3]int) int {
+		if s, ok := p.get(pos); ok && ShapeOf(s) == ShapeFull {
+			return 1
+		}
+		return 0
+	}
+	isDoor := func(pos [3]int) bool {
+		s, ok := p.get(pos)
+		if !ok || StateList[s].ID() != id {
+			return false
+		}
+		half, _ := fieldOf[DoubleBlockHalf](StateList[s], "Half")
+		return half == DoubleBlockHalfLower
+	}
+	walls := full(right.Relative(p.pos)) + full(right.Relative(above)) -
+		full(left.Relative(p.pos)) - full(left.Relative(above))
+	doorLeft, doorRight := isDoor(left.Relative(p.pos)), isDoor(right.Relative(p.pos))
+	switch {
+	case doorLeft && !doorRight, walls > 0:
+		return DoorHingeSideRight
+	case doorRight && !doorLeft, walls < 0:
+		return DoorHingeSideLeft
+	}
+	step := facing.Offset()
+	x, z := p.clickOffset(0), p.clickOffset(2)
+	if step[0] < 0 && z < 0.5 || step[0] > 0 && z > 0.5 || step[2] < 0 && x > 0.5 || step[2] > 0 && x < 0.5 {
+		return DoorHingeSideRight
+	}
+	return DoorHingeSideLeft
+}
+
+// otherPart returns the second block of a two-block structure.
+func (p *placer) otherPart(b Block) (pos [3]int, other Block, ok bool) {
+	if half, ok := fieldOf[DoubleBlockHalf](b, "Half"); ok && half == DoubleBlockHalfLower {
+		pos = Up.Relative(p.pos)
+		other, _ = withField(b, "Half", DoubleBlockHalfUpper)
+		s, _ := p.get(pos)
+		if w, ok := StateList[s].(Water); ok && w.Level == 0 {
+			other, _ = withField(other, "Waterlogged", Boolean(true))
+		} else {
+			other, _ = withField(other, "Waterlogged", Boolean(false))
+		}
+		return pos, other, true
+	}
+	if part, ok := fieldOf[BedPart](b, "Part"); ok && part == BedPartFoot {
+		facing, _ := fieldOf[Direction](b, "Facing")
+		other, _ = withField(b, "Part", BedPartHead)
+		return facing.Relative(p.pos), other, true
+	}
+	return pos, nil, false
+}
+
+func (p *placer) canSurvive(b Block, pos [3]int) bool {
+	return CanSurvive(b, pos, p.Get)
+}
+
+// rotationSegment returns the rotation property of standing signs, banners and skulls for the yaw.
+func rotationSegment(yaw float32) int {
+	return int(math.Floor(float64(yaw)/22.5+0.5)) & 15
+}
+
+// pick returns a if cond is true, otherwise b.
+func pick[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// fieldOf returns the value of the named property of b if it has the type T.
+func fieldOf[T any](b Block, name string) (v T, ok bool) {
+	f := reflect.ValueOf(b).FieldByName(name)
+	if !f.IsValid() || f.Type() != reflect.TypeOf(v) {
+		return v, false
+	}
+	return f.Interface().(T), true
+}
+
+// isState reports whether the properties of b are a valid block state.
+func isState(b Block) bool {
+	_, ok := ToStateID[b]
+	return ok
+}
+
+func hasType[T any](b Block, name string) bool {
+	_, ok := fieldOf[T](b, name)
+	return ok
+}
+
+// withField returns b with the named property set to v,
+// or b unchanged and false if it doesn't have the property of the type.
+func withField(b Block, name string, v any) (Block, bool) {
+	val := reflect.ValueOf(v)
+	copied := reflect.New(reflect.TypeOf(b)).Elem()
+	copied.Set(reflect.ValueOf(b))
+	f := copied.FieldByName(name)
+	if !f.IsValid() || f.Type() != val.Type() {
+		return b, false
+	}
+	f.Set(val)
+	return copied.Interface().(Block), true
+}
@@ -0,0 +1,166 @@
+package block
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// testWorld is a stone floor at y=63 and air above it.
+type testWorld map[[3]int]Block
+
+func (w testWorld) get(x, y, z int) (StateID, bool) {
+	if b, ok := w[[3]int{x, y, z}]; ok {
+		return ToStateID[b], true
+	}
+	if y <= 63 {
+		return ToStateID[Stone{}], true
+	}
+	return ToStateID[Air{}], true
+}
+
+func TestPlace(t *testing.T) {
+	w := testWorld{{0, 64, 3}: Stone{}, {0, 65, 3}: Stone{}, {5, 64, 0}: OakSlab{Type: SlabTypeBottom}, {30, 63, 0}: GrassBlock{}}
+	for _, tt := range []struct {
+		name string
+		ctx  PlaceContext
+		want []Placed
+	}{
+		{
+			"stairs on the floor facing the player direction",
+			PlaceContext{Item: "minecraft:oak_stairs", Clicked: [3]int{0, 63, 0}, Face: Up, Cursor: [3]float64{0.5, 1, 0.5}, Yaw: 0},
+			[]Placed{{[3]int{0, 64, 0}, ToStateID[OakStairs{Facing: South, Half: Bottom}]}},
+		},
+		{
+			"upside down stairs by clicking the upper half of a side",
+			PlaceContext{Item: "minecraft:oak_stairs", Clicked: [3]int{0, 64, 3}, Face: North, Cursor: [3]float64{0.5, 0.8, 0}, Yaw: 180},
+			[]Placed{{[3]int{0, 64, 2}, ToStateID[OakStairs{Facing: North, Half: Top}]}},
+		},
+		{
+			"log along the clicked axis",
+			PlaceContext{Item: "minecraft:oak_log", Clicked: [3]int{0, 64, 3}, Face: North, Cursor: [3]float64{0.5, 0.5, 0}},
+			[]Placed{{[3]int{0, 64, 2}, ToStateID[OakLog{Axis: Z}]}},
+		},
+		{
+			"slab completed into a double slab",
+			PlaceContext{Item: "minecraft:oak_slab", Clicked: [3]int{5, 64, 0}, Face: Up, Cursor: [3]float64{0.5, 0.5, 0.5}},
+			[]Placed{{[3]int{5, 64, 0}, ToStateID[OakSlab{Type: SlabTypeDouble}]}},
+		},
+		{
+			"torch on a wall",
+			PlaceContext{Item: "minecraft:torch", Clicked: [3]int{0, 65, 3}, Face: North, Cursor: [3]float64{0.5, 0.5, 0}, Yaw: 180},
+			[]Placed{{[3]int{0, 65, 2}, ToStateID[WallTorch{Facing: North}]}},
+		},
+		{
+			"door with its upper half, hinged on the clicked side",
+			PlaceContext{Item: "minecraft:oak_door", Clicked: [3]int{10, 63, 0}, Face: Up, Cursor: [3]float64{0.2, 1, 0.5}, Yaw: 0},
+			[]Placed{
+				{[3]int{10, 64, 0}, ToStateID[OakDoor{Facing: South, Half: DoubleBlockHalfLower, Hinge: DoorHingeSideRight}]},
+				{[3]int{10, 65, 0}, ToStateID[OakDoor{Facing: South, Half: DoubleBlockHalfUpper, Hinge: DoorHingeSideRight}]},
+			},
+		},
+		{
+			"bed with its head in the player direction",
+			PlaceContext{Item: "minecraft:red_bed", Clicked: [3]int{20, 63, 0}, Face: Up, Cursor: [3]float64{0.5, 1, 0.5}, Yaw: -90},
+			[]Placed{
+				{[3]int{20, 64, 0}, ToStateID[RedBed{Facing: East, Part: BedPartFoot}]},
+				{[3]int{21, 64, 0}, ToStateID[RedBed{Facing: East, Part: BedPartHead}]},
+			},
+		},
+		{
+			"sunflower with its upper half",
+			PlaceContext{Item: "minecraft:sunflower", Clicked: [3]int{30, 63, 0}, Face: Up, Cursor: [3]float64{0.5, 1, 0.5}},
+			[]Placed{
+				{[3]int{30, 64, 0}, ToStateID[Sunflower{Half: DoubleBlockHalfLower}]},
+				{[3]int{30, 65, 0}, ToStateID[Sunflower{Half: DoubleBlockHalfUpper}]},
+			},
+		},
+		{
+			"flowers need soil",
+			PlaceContext{Item: "minecraft:sunflower", Clicked: [3]int{0, 63, 0}, Face: Up, Cursor: [3]float64{0.5, 1, 0.5}},
+			nil,
+		},
+		{
+			"furnace facing the player",
+			PlaceContext{Item: "minecraft:furnace", Clicked: [3]int{0, 63, 0}, Face: Up, Cursor: [3]float64{0.5, 1, 0.5}, Yaw: 90},
+			[]Placed{{[3]int{0, 64, 0}, ToStateID[Furnace{Facing: East}]}},
+		},
+		{
+			"seeds need farmland",
+			PlaceContext{Item: "minecraft:wheat_seeds", Clicked: [3]int{0, 63, 0}, Face: Up, Cursor: [3]float64{0.5, 1, 0.5}},
+			nil,
+		},
+		{
+			"not a block item",
+			PlaceContext{Item: "minecraft:diamond", Clicked: [3]int{0, 63, 0}, Face: Up},
+			nil,
+		},
+		{
+			"occupied target",
+			PlaceContext{Item: "minecraft:stone", Clicked: [3]int{0, 64, 3}, Face: Down},
+			nil,
+		},
+	} {
+		tt.ctx.Get = w.get
+		got, ok := Place(tt.ctx)
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Place() = %v, %v, want %v", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestPlace_standingSign(t *testing.T) {
+	w := testWorld{}
+	got, ok := Place(PlaceContext{
+		Item: "minecraft:oak_sign", Clicked: [3]int{0, 63, 0}, Face: Up,
+		Cursor: [3]float64{0.5, 1, 0.5}, Yaw: 0, Pitch: 60, Get: w.get,
+	})
+	// Looking south, the text faces north to the player.
+	want := []Placed{{[3]int{0, 64, 0}, ToStateID[OakSign{Rotation: 8}]}}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Place() = %v, %v, want %v", got, ok, want)
+	}
+}
+
+func TestHorizontalDirection(t *testing.T) {
+	for yaw, want := range map[float32]Direction{0: South, 90: West, 180: North, -90: East, 270: East, 44: South, 46: West} {
+		if got := HorizontalDirection(yaw); got != want {
+			t.Errorf("HorizontalDirection(%v) = %v, want %v", yaw, got, want)
+		}
+	}
+}
+
+func TestMatchPredicate(t *testing.T) {
+	stone, _ := DefaultState("minecraft:stone")
+	stairs := ToStateID[OakStairs{Facing: East, Half: Top}]
+	stoneID, stairsID := pk.VarInt(registryIDs["minecraft:stone"]), pk.VarInt(registryIDs["minecraft:oak_stairs"])
+	for _, tt := range []struct {
+		p     component.BlockPredicate
+		s     StateID
+		match bool
+	}{
+		{component.BlockPredicate{}, stone, true},
+		{component.BlockPredicate{Blocks: &component.IDSet{IDs: []pk.VarInt{stoneID}}}, stone, true},
+		{component.BlockPredicate{Blocks: &component.IDSet{IDs: []pk.VarInt{stoneID}}}, stairs, false},
+		{component.BlockPredicate{Blocks: &component.IDSet{Tag: "minecraft:mineable/pickaxe"}}, stone, false},
+		{component.BlockPredicate{
+			Blocks:     &component.IDSet{IDs: []pk.VarInt{stairsID}},
+			Properties: []component.PropertyMatcher{{Name: "half", IsExact: true, ExactValue: "top"}},
+		}, stairs, true},
+		{component.BlockPredicate{
+			Properties: []component.PropertyMatcher{{Name: "facing", IsExact: true, ExactValue: "west"}},
+		}, stairs, false},
+		{component.BlockPredicate{
+			Properties: []component.PropertyMatcher{{Name: "facing", MinValue: pk.Option[pk.String, *pk.String]{Has: true, Val: "south"}}},
+		}, stairs, true},
+		{component.BlockPredicate{
+			Properties: []component.PropertyMatcher{{Name: "half", IsExact: true, ExactValue: "top"}},
+		}, stone, false},
+	} {
+		if got := MatchPredicate(tt.p, tt.s); got != tt.match {
+			t.Errorf("MatchPredicate(%+v, %v) = %v, want %v", tt.p, StateList[tt.s], got, tt.match)
+		}
+	}
+}
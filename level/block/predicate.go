@@ -0,0 +1,106 @@
+package block
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/level/component"
+)
+
+// registryIDs maps the block ids to their index in the minecraft:block registry.
+var registryIDs = make(map[string]int32, len(registryid.Block))
+
+func init() {
+	for i, id := range registryid.Block {
+		registryIDs[id] = int32(i)
+	}
+}
+
+// MatchPredicate reports whether the block state matches the predicate
+// of the adventure mode components CanPlaceOn and CanBreak.
+//
+// The block tags aren't known, so a predicate of a tag never matches,
+// and neither does one of block entity data.
+func MatchPredicate(p component.BlockPredicate, s StateID) bool {
+	b := StateList[s]
+	if p.Blocks != nil {
+		if p.Blocks.Tag != "" {
+			return false
+		}
+		i, ok := registryIDs[b.ID()]
+		if !ok || !containsID(p.Blocks.IDs, i) {
+			return false
+		}
+	}
+	for _, m := range p.Properties {
+		if !matchProperty(b, m) {
+			return false
+		}
+	}
+	return p.NBT == nil
+}
+
+func containsID[T ~int32](ids []T, id int32) bool {
+	for _, v := range ids {
+		if int32(v) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProperty reports whether the named property of the block has the exact value,
+// or is in the range. Like vanilla, the values are ordered as they are declared.
+func matchProperty(b Block, m component.PropertyMatcher) bool {
+	v := reflect.ValueOf(b)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("nbt") != string(m.Name) {
+			continue
+		}
+		f := v.Field(i)
+		if m.IsExact {
+			text, err := f.Interface().(encoding.TextMarshaler).MarshalText()
+			return err == nil && string(text) == string(m.ExactValue)
+		}
+		if m.MinValue.Has {
+			bound, ok := parseProperty(f.Type(), string(m.MinValue.Val))
+			if !ok || compareProperty(f, bound) < 0 {
+				return false
+			}
+		}
+		if m.MaxValue.Has {
+			bound, ok := parseProperty(f.Type(), string(m.MaxValue.Val))
+			if !ok || compareProperty(f, bound) > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func parseProperty(t reflect.Type, text string) (reflect.Value, bool) {
+	v := reflect.New(t)
+	err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	return v.Elem(), err == nil
+}
+
+func compareProperty(a, b reflect.Value) int {
+	var x, y int64
+	switch a.Kind() {
+	case reflect.Bool:
+		x, y = int64(pick(a.Bool(), 1, 0)), int64(pick(b.Bool(), 1, 0))
+	case reflect.Int:
+		x, y = a.Int(), b.Int()
+	default:
+		x, y = int64(a.Uint()), int64(b.Uint())
+	}
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
@@ -1,6 +1,9 @@
 package block
 
-import "strconv"
+import (
+	"math"
+	"strconv"
+)
 
 //go:generate go run ./generator/properties/main.go
 
@@ -56,3 +59,83 @@ func (f FrontAndTop) Directions() (front, top Direction) {
 		panic("invalid FrontAndTop")
 	}
 }
+
+// Offset returns the unit vector of the direction.
+func (d Direction) Offset() [3]int {
+	switch d {
+	case Down:
+		return [3]int{0, -1, 0}
+	case Up:
+		return [3]int{0, 1, 0}
+	case North:
+		return [3]int{0, 0, -1}
+	case South:
+		return [3]int{0, 0, 1}
+	case West:
+		return [3]int{-1, 0, 0}
+	case East:
+		return [3]int{1, 0, 0}
+	default:
+		panic("invalid Direction")
+	}
+}
+
+// Relative returns the position next to pos in the direction.
+func (d Direction) Relative(pos [3]int) [3]int {
+	o := d.Offset()
+	return [3]int{pos[0] + o[0], pos[1] + o[1], pos[2] + o[2]}
+}
+
+// Opposite returns the reverse direction.
+func (d Direction) Opposite() Direction { return d ^ 1 }
+
+// Axis returns the axis the direction is along.
+func (d Direction) Axis() Axis {
+	switch d {
+	case West, East:
+		return X
+	case Down, Up:
+		return Y
+	default:
+		return Z
+	}
+}
+
+// IsHorizontal reports whether the direction is one of the four horizontal ones.
+func (d Direction) IsHorizontal() bool { return d >= North }
+
+// horizontalDirections are the horizontal directions clockwise from south,
+// which is the order of the yaw of the entities.
+var horizontalDirections = [4]Direction{South, West, North, East}
+
+// Clockwise returns the horizontal direction rotated clockwise seen from above.
+// Vertical directions are returned unchanged.
+func (d Direction) Clockwise() Direction {
+	if !d.IsHorizontal() {
+		return d
+	}
+	return horizontalDirections[(d.yawIndex()+1)&3]
+}
+
+// CounterClockwise returns the horizontal direction rotated counterclockwise seen from above.
+// Vertical directions are returned unchanged.
+func (d Direction) CounterClockwise() Direction {
+	if !d.IsHorizontal() {
+		return d
+	}
+	return horizontalDirections[(d.yawIndex()+3)&3]
+}
+
+func (d Direction) yawIndex() int {
+	for i, h := range horizontalDirections {
+		if h == d {
+			return i
+		}
+	}
+	return 0
+}
+
+// HorizontalDirection returns the horizontal direction an entity with the yaw is facing.
+func HorizontalDirection(yaw float32) Direction {
+	return horizontalDirections[int(math.Floor(float64(yaw)/90+0.5))&3]
+}
@@ -0,0 +1,175 @@
+package block
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Shape is a coarse classification of a block state's collision shape.
+//
+// The block data shipped with this package doesn't include shapes, so
+// they are derived from the block names. The result matches vanilla
+// for the full and empty cases and groups everything else as partial.
+type Shape uint8
+
+const (
+	// ShapeEmpty blocks have no collision: air, fluids, plants, torches...
+	ShapeEmpty Shape = iota
+	// ShapeFull blocks occupy the whole 1x1x1 cube.
+	ShapeFull
+	// ShapePartial blocks collide but are not a full cube: slabs, stairs,
+	// fences, chests...
+	ShapePartial
+)
+
+var (
+	shapes      []Shape
+	transparent []bool
+	waterlogged []bool
+)
+
+// ShapeOf returns the shape of the block state.
+func ShapeOf(s StateID) Shape { return shapes[s] }
+
+// IsTransparent reports whether the state is a full block that can be
+// seen through, such as glass, leaves or ice.
+func IsTransparent(s StateID) bool { return transparent[s] }
+
+// IsWaterlogged reports whether the state contains water, including
+// the water block itself.
+func IsWaterlogged(s StateID) bool { return waterlogged[s] }
+
+// Name tables used by shapeOf. Blocks not listed here are full cubes.
+var (
+	emptyNames = names(
+		"air", "cave_air", "void_air", "water", "lava", "bubble_column",
+		"short_grass", "tall_grass", "fern", "large_fern", "dead_bush",
+		"seagrass", "tall_seagrass", "kelp", "kelp_plant",
+		"dandelion", "torchflower", "poppy", "blue_orchid", "allium", "azure_bluet",
+		"oxeye_daisy", "cornflower", "wither_rose", "lily_of_the_valley",
+		"sunflower", "lilac", "rose_bush", "peony", "pitcher_plant", "pink_petals",
+		"open_eyeblossom", "closed_eyeblossom",
+		"brown_mushroom", "red_mushroom", "crimson_fungus", "warped_fungus",
+		"crimson_roots", "warped_roots", "hanging_roots", "nether_sprouts",
+		"wheat", "carrots", "potatoes", "beetroots", "torchflower_crop", "pitcher_crop",
+		"pumpkin_stem", "melon_stem", "attached_pumpkin_stem", "attached_melon_stem",
+		"nether_wart", "sweet_berry_bush", "sugar_cane", "bamboo_sapling",
+		"mangrove_propagule", "spore_blossom", "small_dripleaf", "big_dripleaf_stem",
+		"vine", "glow_lichen", "sculk_vein", "resin_clump", "cave_vines", "cave_vines_plant",
+		"weeping_vines", "weeping_vines_plant", "twisting_vines", "twisting_vines_plant",
+		"pale_hanging_moss", "frogspawn",
+		"torch", "wall_torch", "soul_torch", "soul_wall_torch",
+		"redstone_torch", "redstone_wall_torch", "redstone_wire",
+		"lever", "tripwire", "tripwire_hook", "fire", "soul_fire",
+		"nether_portal", "end_portal", "end_gateway", "cobweb",
+		"structure_void", "light", "powder_snow", "moving_piston",
+	)
+	emptySuffixes = []string{
+		"_sapling", "_tulip", "_button", "_pressure_plate", "_sign", "_banner",
+		"rail", "_coral", "_coral_fan", "_coral_wall_fan",
+	}
+	partialNames = names(
+		"snow", "cake", "candle", "lantern", "soul_lantern", "chain", "end_rod",
+		"lightning_rod", "ladder", "flower_pot", "iron_bars", "chest", "trapped_chest",
+		"ender_chest", "shulker_box", "anvil", "chipped_anvil", "damaged_anvil",
+		"cauldron", "water_cauldron", "lava_cauldron", "powder_snow_cauldron",
+		"hopper", "brewing_stand", "enchanting_table", "lectern", "grindstone",
+		"stonecutter", "bell", "campfire", "soul_campfire", "composter", "conduit",
+		"dragon_egg", "turtle_egg", "sniffer_egg", "sea_pickle", "farmland", "dirt_path",
+		"cactus", "bamboo", "lily_pad", "scaffolding", "amethyst_cluster",
+		"pointed_dripstone", "azalea", "flowering_azalea", "big_dripleaf",
+		"daylight_detector", "repeater", "comparator", "piston_head",
+		"sculk_sensor", "calibrated_sculk_sensor", "sculk_shrieker", "honey_block",
+		"decorated_pot", "heavy_core", "vault", "chorus_plant", "cocoa",
+		"end_portal_frame", "moss_carpet", "pale_moss_carpet",
+	)
+	partialSuffixes = []string{
+		"_slab", "_stairs", "_fence", "_fence_gate", "_wall", "_pane", "_door",
+		"_trapdoor", "_carpet", "_bed", "_shulker_box", "_candle", "_candle_cake",
+		"_head", "_skull", "_amethyst_bud",
+	}
+	// transparentNames are full blocks that let light through.
+	transparentNames = names(
+		"glass", "ice", "frosted_ice", "slime_block", "spawner", "trial_spawner",
+		"beacon", "barrier", "mangrove_roots", "powder_snow",
+	)
+	transparentSuffixes = []string{"_stained_glass", "_leaves", "copper_grate"}
+)
+
+func names(s ...string) map[string]bool {
+	m := make(map[string]bool, len(s))
+	for _, v := range s {
+		m["minecraft:"+v] = true
+	}
+	return m
+}
+
+func hasAny(s string, match func(string, string) bool, list []string) bool {
+	for _, v := range list {
+		if match(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func shapeOf(id string) Shape {
+	switch {
+	case emptyNames[id], hasAny(id, strings.HasSuffix, emptySuffixes):
+		return ShapeEmpty
+	case partialNames[id], hasAny(id, strings.HasSuffix, partialSuffixes),
+		strings.HasPrefix(id, "minecraft:potted_"):
+		return ShapePartial
+	default:
+		return ShapeFull
+	}
+}
+
+// boolField returns the value of the named Boolean property of b, or
+// false if the block doesn't have it.
+func boolField(b Block, name string) bool {
+	f := reflect.ValueOf(b).FieldByName(name)
+	return f.IsValid() && f.Kind() == reflect.Bool && f.Bool()
+}
+
+// intField returns the value of the named Integer property of b, or
+// def if the block doesn't have it.
+func intField(b Block, name string, def int) int {
+	f := reflect.ValueOf(b).FieldByName(name)
+	if !f.IsValid() || f.Kind() != reflect.Int {
+		return def
+	}
+	return int(f.Int())
+}
+
+func init() {
+	shapes = make([]Shape, len(StateList))
+	transparent = make([]bool, len(StateList))
+	waterlogged = make([]bool, len(StateList))
+	cache := make(map[string]Shape)
+	for i, b := range StateList {
+		id := b.ID()
+		s, ok := cache[id]
+		if !ok {
+			s = shapeOf(id)
+			cache[id] = s
+		}
+		if f := reflect.ValueOf(b).FieldByName("Type"); f.IsValid() && f.Type() == reflect.TypeOf(SlabTypeDouble) {
+			if f.Interface() == SlabTypeDouble {
+				s = ShapeFull // double slabs
+			}
+		}
+		shapes[i] = s
+		transparent[i] = s == ShapeFull &&
+			(transparentNames[id] || hasAny(id, strings.HasSuffix, transparentSuffixes))
+		switch b.(type) {
+		case Water, BubbleColumn, Kelp, KelpPlant, Seagrass, TallSeagrass:
+			waterlogged[i] = true
+		default:
+			waterlogged[i] = boolField(b, "Waterlogged")
+		}
+	}
+	initLight()
+	initCollisions()
+	initFluids()
+}
@@ -0,0 +1,150 @@
+package block
+
+import "strings"
+
+// Name tables used by CanSurvive.
+var (
+	// soilNames are the blocks plants can grow on.
+	soilNames = names(
+		"grass_block", "dirt", "coarse_dirt", "podzol", "rooted_dirt", "mycelium",
+		"moss_block", "pale_moss_block", "mud", "muddy_mangrove_roots", "farmland",
+	)
+	plantNames = names(
+		"short_grass", "fern", "tall_grass", "large_fern",
+		"dandelion", "torchflower", "poppy", "blue_orchid", "allium", "azure_bluet",
+		"oxeye_daisy", "cornflower", "wither_rose", "lily_of_the_valley",
+		"sunflower", "lilac", "rose_bush", "peony", "pitcher_plant", "pink_petals",
+		"open_eyeblossom", "closed_eyeblossom", "sweet_berry_bush",
+	)
+	cropNames = names(
+		"wheat", "carrots", "potatoes", "beetroots", "torchflower_crop", "pitcher_crop",
+		"pumpkin_stem", "melon_stem",
+	)
+	// floorNames are the blocks which need a full block below them.
+	floorNames = names("redstone_wire", "repeater", "comparator")
+	// torchNames are the blocks which stand on the center of the block below.
+	torchNames     = names("torch", "soul_torch", "redstone_torch")
+	jungleLogNames = names("jungle_log", "jungle_wood", "stripped_jungle_log", "stripped_jungle_wood")
+)
+
+// faceFull reports whether the side of the block in the direction is a full face.
+// Besides full blocks, it's the top of top slabs and upside down stairs, and the bottom of bottom ones.
+func faceFull(b Block, d Direction) bool {
+	return b != nil && stateFaceFull(ToStateID[b], d)
+}
+
+func stateFaceFull(s StateID, d Direction) bool {
+	switch ShapeOf(s) {
+	case ShapeFull:
+		return true
+	case ShapeEmpty:
+		return false
+	}
+	b := StateList[s]
+	slab, isSlab := fieldOf[SlabType](b, "Type")
+	half, _ := fieldOf[Half](b, "Half")
+	isStairs := strings.HasSuffix(b.ID(), "_stairs")
+	switch d {
+	case Up:
+		return isSlab && slab == SlabTypeTop || isStairs && half == Top
+	case Down:
+		return isSlab && slab == SlabTypeBottom || isStairs && half == Bottom
+	}
+	return false
+}
+
+// CanSurvive reports whether the block can stay at the world coordinates,
+// which depends on the blocks it's attached to. get returns the block states around,
+// and false if they aren't loaded.
+//
+// The sturdy faces of vanilla are approximated by full blocks, since the block shapes aren't known.
+func CanSurvive(b Block, pos [3]int, get func(x, y, z int) (StateID, bool)) bool {
+	at := func(d Direction) Block {
+		p := d.Relative(pos)
+		if s, ok := get(p[0], p[1], p[2]); ok {
+			return StateList[s]
+		}
+		return nil
+	}
+	// full reports whether the face of the neighbour in the direction towards pos is full.
+	full := func(d Direction) bool { return faceFull(at(d), d.Opposite()) }
+	// center reports whether the top of the block below supports a block on its center.
+	center := func() bool {
+		n := at(Down)
+		return full(Down) || isWall(n) || isPane(n) || n != nil && strings.HasSuffix(n.ID(), "_fence")
+	}
+	solid := func(d Direction) bool {
+		n := at(d)
+		return n != nil && ShapeOf(ToStateID[n]) != ShapeEmpty
+	}
+	id := b.ID()
+	facing, hasFacing := fieldOf[Direction](b, "Facing")
+	switch {
+	case hasType[AttachFace](b, "Face"):
+		if id == "minecraft:grindstone" {
+			return true
+		}
+		switch face, _ := fieldOf[AttachFace](b, "Face"); face {
+		case AttachFaceFloor:
+			return full(Down)
+		case AttachFaceCeiling:
+			return full(Up)
+		default:
+			return full(facing.Opposite())
+		}
+
+	case hasFacing && (strings.Contains(id, "wall_torch") || strings.HasSuffix(id, "_wall_sign") ||
+		strings.HasSuffix(id, "_wall_banner") || strings.HasSuffix(id, "coral_wall_fan") ||
+		id == "minecraft:ladder" || id == "minecraft:tripwire_hook"):
+		return full(facing.Opposite())
+
+	case torchNames[id]:
+		return center()
+
+	case id == "minecraft:cocoa":
+		n := at(facing)
+		return n != nil && jungleLogNames[n.ID()]
+
+	case floorNames[id], strings.HasSuffix(id, "rail"), strings.HasSuffix(id, "_pressure_plate"),
+		strings.HasSuffix(id, "_coral"), strings.HasSuffix(id, "_coral_fan"):
+		return full(Down)
+
+	case strings.HasSuffix(id, "_hanging_sign") && !strings.Contains(id, "_wall_"):
+		return solid(Up)
+
+	case strings.HasSuffix(id, "_sign") && !strings.Contains(id, "_wall_"),
+		strings.HasSuffix(id, "_banner") && !strings.Contains(id, "_wall_"):
+		return solid(Down)
+
+	case hasType[Boolean](b, "Hanging"):
+		if boolField(b, "Hanging") {
+			return solid(Up)
+		}
+		return solid(Down)
+
+	case strings.HasSuffix(id, "_carpet"):
+		n := at(Down)
+		return n != nil && !IsAirBlock(n)
+	}
+
+	if half, ok := fieldOf[DoubleBlockHalf](b, "Half"); ok && half == DoubleBlockHalfUpper {
+		// The upper half stays on its lower half.
+		n := at(Down)
+		if n == nil || n.ID() != id {
+			return false
+		}
+		lower, _ := fieldOf[DoubleBlockHalf](n, "Half")
+		return lower == DoubleBlockHalfLower
+	}
+	switch {
+	case strings.HasSuffix(id, "_door"):
+		return full(Down)
+	case plantNames[id], strings.HasSuffix(id, "_sapling"), strings.HasSuffix(id, "_tulip"):
+		n := at(Down)
+		return n != nil && soilNames[n.ID()]
+	case cropNames[id]:
+		n := at(Down)
+		return n != nil && n.ID() == "minecraft:farmland"
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package block
+
+import "math/rand"
+
+// TickPriority orders the ticks scheduled for the same game tick, the lowest first.
+// The values are the ones saved in the chunks by vanilla.
+type TickPriority int8
+
+const (
+	TickPriorityExtremelyHigh TickPriority = iota - 3
+	TickPriorityVeryHigh
+	TickPriorityHigh
+	TickPriorityNormal
+	TickPriorityLow
+	TickPriorityVeryLow
+	TickPriorityExtremelyLow
+)
+
+// TickHandler is called on a scheduled or random tick of the block at pos.
+type TickHandler func(l Level, pos [3]int, b Block, r *rand.Rand)
+
+// FluidTickHandler is called on a scheduled tick of the fluid at pos, where the block state is s.
+type FluidTickHandler func(l Level, pos [3]int, s StateID, r *rand.Rand)
+
+var (
+	tickHandlers       = make(map[string]TickHandler)
+	randomTickHandlers = make(map[string]TickHandler)
+	fluidTickHandlers  = make(map[string]FluidTickHandler)
+)
+
+// RegisterTickHandler sets the handler of the scheduled ticks of the blocks of the ids.
+func RegisterTickHandler(h TickHandler, ids ...string) {
+	for _, id := range ids {
+		tickHandlers[id] = h
+	}
+}
+
+// RegisterRandomTickHandler sets the handler of the random ticks of the blocks of the ids.
+func RegisterRandomTickHandler(h TickHandler, ids ...string) {
+	for _, id := range ids {
+		randomTickHandlers[id] = h
+	}
+}
+
+// RegisterFluidTickHandler sets the handler of the scheduled ticks of the fluids of the ids.
+func RegisterFluidTickHandler(h FluidTickHandler, ids ...string) {
+	for _, id := range ids {
+		fluidTickHandlers[id] = h
+	}
+}
+
+// Tick calls the scheduled tick handler of the block at pos.
+func Tick(l Level, pos [3]int, s StateID, r *rand.Rand) {
+	b := StateList[s]
+	if h, ok := tickHandlers[b.ID()]; ok {
+		h(l, pos, b, r)
+	}
+}
+
+// RandomTick calls the random tick handler of the block at pos.
+func RandomTick(l Level, pos [3]int, s StateID, r *rand.Rand) {
+	b := StateList[s]
+	if h, ok := randomTickHandlers[b.ID()]; ok {
+		h(l, pos, b, r)
+	}
+}
+
+// IsRandomlyTicking reports whether the block has a random tick handler.
+func IsRandomlyTicking(s StateID) bool {
+	_, ok := randomTickHandlers[StateList[s].ID()]
+	return ok
+}
+
+// FluidTick calls the scheduled tick handler of the fluid at pos, where the block state is s.
+// The tick is dropped if the fluid there isn't the one of the id any more.
+func FluidTick(l Level, pos [3]int, fluid string, s StateID, r *rand.Rand) {
+	if FluidOf(s).ID() != fluid {
+		return
+	}
+	if h, ok := fluidTickHandlers[fluid]; ok {
+		h(l, pos, s, r)
+	}
+}
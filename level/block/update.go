@@ -0,0 +1,209 @@
+package block
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Getter returns the block state at the world coordinates,
+// or false if the chunk isn't loaded or y is out of the world.
+type Getter = func(x, y, z int) (StateID, bool)
+
+// Level is the world the neighbour and tick handlers change blocks in.
+type Level interface {
+	GetBlock(x, y, z int) (StateID, bool)
+	// SetBlock sets the block, whose neighbours are then updated too.
+	SetBlock(x, y, z int, s StateID)
+	// ScheduleTick schedules a tick of the block of the id at the position in delay game ticks.
+	// Like vanilla, it's ignored if one is already scheduled there,
+	// and the tick is dropped if the block has changed to another one by then.
+	ScheduleTick(x, y, z int, id string, delay int, priority TickPriority)
+	// ScheduleFluidTick is ScheduleTick for the fluid of the id at the position.
+	ScheduleFluidTick(x, y, z int, id string, delay int, priority TickPriority)
+	// Ultrawarm reports whether the dimension is ultrawarm like the nether, where lava flows faster.
+	Ultrawarm() bool
+}
+
+// ShapeHandler returns the new state of the block at pos after its neighbour in the direction changed,
+// like vanilla's Block.updateShape. It mustn't change other blocks.
+type ShapeHandler func(b Block, pos [3]int, dir Direction, neighbor StateID, get Getter) Block
+
+// NeighborHandler is called after a neighbour of the block at pos, or the block itself, is set.
+// Unlike a ShapeHandler it may change any block of the level.
+type NeighborHandler func(l Level, pos [3]int, b Block)
+
+var (
+	shapeHandlers    = make(map[string]ShapeHandler)
+	neighborHandlers = make(map[string]NeighborHandler)
+	// connectors compute the properties depending on all the neighbours,
+	// which are also set when the block is placed.
+	connectors = make(map[string]func(b Block, pos [3]int, get Getter) Block)
+)
+
+// RegisterShapeHandler sets the shape handler of the blocks of the ids.
+func RegisterShapeHandler(h ShapeHandler, ids ...string) {
+	for _, id := range ids {
+		shapeHandlers[id] = h
+	}
+}
+
+// RegisterNeighborHandler sets the neighbour handler of the blocks of the ids.
+func RegisterNeighborHandler(h NeighborHandler, ids ...string) {
+	for _, id := range ids {
+		neighborHandlers[id] = h
+	}
+}
+
+// registerConnector sets the shape handler of the blocks to recompute their connections with all the neighbours.
+func registerConnector(connect func(b Block, pos [3]int, get Getter) Block, ids ...string) {
+	for _, id := range ids {
+		connectors[id] = connect
+	}
+	RegisterShapeHandler(func(b Block, pos [3]int, _ Direction, _ StateID, get Getter) Block {
+		return connect(b, pos, get)
+	}, ids...)
+}
+
+// UpdateShape returns the new state of the block at pos after its neighbour in the direction changed to the state.
+// A block which can't survive any more is removed, and leaves its water if it was waterlogged.
+// The blocks which aren't loaded are taken as supporting it,
+// so that the blocks at the border of the loaded area stay.
+func UpdateShape(s StateID, pos [3]int, dir Direction, neighbor StateID, get Getter) StateID {
+	b := StateList[s]
+	if h, ok := shapeHandlers[b.ID()]; ok {
+		b = h(b, pos, dir, neighbor, get)
+	}
+	supports := func(x, y, z int) (StateID, bool) {
+		if s, ok := get(x, y, z); ok {
+			return s, true
+		}
+		return ToStateID[Stone{}], true
+	}
+	if !IsAirBlock(b) && !CanSurvive(b, pos, supports) {
+		return removedState(b)
+	}
+	if s, ok := ToStateID[b]; ok {
+		return s
+	}
+	return s
+}
+
+// NeighborChanged calls the neighbour handler of the block at pos.
+// The fluid in the block is then scheduled to flow.
+func NeighborChanged(l Level, pos [3]int, s StateID) {
+	b := StateList[s]
+	if h, ok := neighborHandlers[b.ID()]; ok {
+		h(l, pos, b)
+		if s, ok = l.GetBlock(pos[0], pos[1], pos[2]); !ok {
+			return
+		}
+	}
+	fluidChanged(l, pos, s)
+}
+
+// connect sets the connections of a block being placed.
+func connect(b Block, pos [3]int, get Getter) Block {
+	if c, ok := connectors[b.ID()]; ok {
+		return c(b, pos, get)
+	}
+	return b
+}
+
+// removedState is what's left of a block that is broken.
+func removedState(b Block) StateID {
+	if boolField(b, "Waterlogged") {
+		return ToStateID[Water{}]
+	}
+	return ToStateID[Air{}]
+}
+
+func init() {
+	var fences, panes, walls, stairs, doubles, beds, falling []string
+	for id, b := range FromID {
+		switch {
+		case strings.HasSuffix(id, "_fence"):
+			fences = append(fences, id)
+		case strings.HasSuffix(id, "_pane"), id == "minecraft:iron_bars":
+			panes = append(panes, id)
+		case strings.HasSuffix(id, "_wall") && hasType[WallSide](b, "North"):
+			walls = append(walls, id)
+		case strings.HasSuffix(id, "_stairs"):
+			stairs = append(stairs, id)
+		case hasType[DoubleBlockHalf](b, "Half"):
+			doubles = append(doubles, id)
+		case hasType[BedPart](b, "Part"):
+			beds = append(beds, id)
+		case fallingNames[id], strings.HasSuffix(id, "_concrete_powder"), strings.HasSuffix(id, "anvil"):
+			falling = append(falling, id)
+		}
+	}
+	registerConnector(fenceConnections, fences...)
+	registerConnector(paneConnections, panes...)
+	registerConnector(wallConnections, walls...)
+	registerConnector(stairsShape, stairs...)
+	registerConnector(wireConnections, "minecraft:redstone_wire")
+	RegisterShapeHandler(doubleBlockShape, doubles...)
+	RegisterShapeHandler(bedShape, beds...)
+	RegisterNeighborHandler(scheduleFall, falling...)
+	RegisterTickHandler(fall, falling...)
+}
+
+var fallingNames = names("sand", "red_sand", "gravel", "suspicious_sand", "suspicious_gravel", "dragon_egg")
+
+// doubleBlockShape removes a half of a door or a tall plant without the other half,
+// and copies the properties of the other half of doors.
+func doubleBlockShape(b Block, _ [3]int, dir Direction, neighbor StateID, _ Getter) Block {
+	half, _ := fieldOf[DoubleBlockHalf](b, "Half")
+	if dir != pick(half == DoubleBlockHalfLower, Up, Down) {
+		return b
+	}
+	other := StateList[neighbor]
+	if otherHalf, ok := fieldOf[DoubleBlockHalf](other, "Half"); !ok || other.ID() != b.ID() || otherHalf == half {
+		return StateList[removedState(b)]
+	}
+	if hasType[DoorHingeSide](b, "Hinge") {
+		b, _ = withField(other, "Half", half)
+	}
+	return b
+}
+
+// bedShape removes a part of a bed without the other part.
+func bedShape(b Block, _ [3]int, dir Direction, neighbor StateID, _ Getter) Block {
+	part, _ := fieldOf[BedPart](b, "Part")
+	facing, _ := fieldOf[Direction](b, "Facing")
+	if dir != pick(part == BedPartFoot, facing, facing.Opposite()) {
+		return b
+	}
+	other := StateList[neighbor]
+	if otherPart, ok := fieldOf[BedPart](other, "Part"); !ok || other.ID() != b.ID() || otherPart == part {
+		return StateList[removedState(b)]
+	}
+	return b
+}
+
+// fallDelay is the number of game ticks before a block falls, like vanilla.
+const fallDelay = 2
+
+// scheduleFall schedules the tick of a falling block, when it's placed or a neighbour changes.
+func scheduleFall(l Level, pos [3]int, b Block) {
+	l.ScheduleTick(pos[0], pos[1], pos[2], b.ID(), fallDelay, TickPriorityNormal)
+}
+
+// fall moves the block down until it lands on a block it can't fall through.
+// Vanilla spawns a falling block entity instead, so the block falls at once here.
+func fall(l Level, pos [3]int, b Block, _ *rand.Rand) {
+	land := pos
+	for {
+		below := Down.Relative(land)
+		s, ok := l.GetBlock(below[0], below[1], below[2])
+		if !ok || !replaceableNames[StateList[s].ID()] {
+			break
+		}
+		land = below
+	}
+	if land == pos {
+		return
+	}
+	l.SetBlock(pos[0], pos[1], pos[2], ToStateID[Air{}])
+	l.SetBlock(land[0], land[1], land[2], ToStateID[b])
+}
@@ -0,0 +1,144 @@
+package block
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateShape(t *testing.T) {
+	w := testWorld{
+		{1, 64, 0}:  OakFence{},
+		{-1, 64, 0}: Stone{},
+		{0, 64, 1}:  Glass{},
+		{0, 64, -1}: GlassPane{},
+		// Stairs facing north with stairs facing east behind them.
+		{10, 64, 0}:  OakStairs{Facing: North, Half: Bottom},
+		{10, 64, -1}: OakStairs{Facing: East, Half: Bottom},
+		{20, 64, 0}:  Torch{},
+		{20, 63, 0}:  Air{},
+		{30, 64, 0}:  OakDoor{Half: DoubleBlockHalfUpper},
+	}
+	for _, tt := range []struct {
+		name string
+		s    StateID
+		pos  [3]int
+		dir  Direction
+		want Block
+	}{
+		{
+			"fence connected to fences and full blocks but not to panes",
+			ToStateID[OakFence{}], [3]int{0, 64, 0}, East,
+			OakFence{East: true, West: true, South: true},
+		},
+		{
+			"nether brick fence not connected to wooden fences",
+			ToStateID[NetherBrickFence{}], [3]int{0, 64, 0}, East,
+			NetherBrickFence{West: true, South: true},
+		},
+		{
+			"pane connected to panes and full blocks but not to fences",
+			ToStateID[GlassPane{}], [3]int{0, 64, 0}, East,
+			GlassPane{North: true, West: true, South: true},
+		},
+		{
+			"outer corner of stairs",
+			ToStateID[OakStairs{Facing: North, Half: Bottom}], [3]int{10, 64, 0}, North,
+			OakStairs{Facing: North, Half: Bottom, Shape: StairsShapeOuterRight},
+		},
+		{
+			"torch without support removed",
+			ToStateID[Torch{}], [3]int{20, 64, 0}, Down,
+			Air{},
+		},
+		{
+			"lower half of a door without its upper half removed",
+			ToStateID[OakDoor{Half: DoubleBlockHalfLower}], [3]int{30, 63, 0}, Up,
+			Air{},
+		},
+		{
+			"waterlogged block leaves its water",
+			ToStateID[Ladder{Facing: North, Waterlogged: true}], [3]int{40, 64, 0}, South,
+			Water{},
+		},
+	} {
+		p := tt.dir.Relative(tt.pos)
+		neighbor, _ := w.get(p[0], p[1], p[2])
+		got := StateList[UpdateShape(tt.s, tt.pos, tt.dir, neighbor, w.get)]
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: UpdateShape() = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateShape_unloaded(t *testing.T) {
+	get := func(x, y, z int) (StateID, bool) {
+		if y == 64 {
+			return ToStateID[Air{}], true
+		}
+		return 0, false
+	}
+	if got := UpdateShape(ToStateID[Torch{}], [3]int{0, 64, 0}, East, ToStateID[Air{}], get); got != ToStateID[Torch{}] {
+		t.Errorf("a torch on an unloaded block is updated to %#v", StateList[got])
+	}
+}
+
+func TestWireConnections(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		w    testWorld
+		want Block
+	}{
+		{
+			"a wire alone is a cross",
+			testWorld{},
+			RedstoneWire{North: RedstoneSideSide, South: RedstoneSideSide, West: RedstoneSideSide, East: RedstoneSideSide},
+		},
+		{
+			"a wire connected on a side is a line",
+			testWorld{{1, 64, 0}: RedstoneWire{}},
+			RedstoneWire{North: RedstoneSideNone, South: RedstoneSideNone, West: RedstoneSideSide, East: RedstoneSideSide},
+		},
+		{
+			"a wire climbs up a block with a wire on it",
+			testWorld{{1, 64, 0}: Stone{}, {1, 65, 0}: RedstoneWire{}, {0, 64, 1}: Lever{Facing: North}},
+			RedstoneWire{North: RedstoneSideNone, South: RedstoneSideSide, West: RedstoneSideNone, East: RedstoneSideUp},
+		},
+	} {
+		b := wireConnections(RedstoneWire{}, [3]int{0, 64, 0}, tt.w.get)
+		if !reflect.DeepEqual(b, tt.want) {
+			t.Errorf("%s: wireConnections() = %#v, want %#v", tt.name, b, tt.want)
+		}
+	}
+}
+
+// testLevel is a testWorld whose blocks can be set, recording the scheduled ticks.
+type testLevel struct {
+	testWorld
+	ticks map[[3]int]string
+}
+
+func (l testLevel) GetBlock(x, y, z int) (StateID, bool) { return l.get(x, y, z) }
+
+func (l testLevel) SetBlock(x, y, z int, s StateID) { l.testWorld[[3]int{x, y, z}] = StateList[s] }
+
+func (l testLevel) ScheduleTick(x, y, z int, id string, _ int, _ TickPriority) {
+	l.ticks[[3]int{x, y, z}] = id
+}
+
+func (l testLevel) ScheduleFluidTick(x, y, z int, id string, _ int, _ TickPriority) {
+	l.ticks[[3]int{x, y, z}] = id
+}
+
+func (testLevel) Ultrawarm() bool { return false }
+
+func TestFall(t *testing.T) {
+	l := testLevel{testWorld{{0, 70, 0}: Sand{}, {0, 66, 0}: ShortGrass{}}, make(map[[3]int]string)}
+	NeighborChanged(l, [3]int{0, 70, 0}, ToStateID[Sand{}])
+	if l.ticks[[3]int{0, 70, 0}] != "minecraft:sand" {
+		t.Fatalf("the scheduled ticks are %v", l.ticks)
+	}
+	Tick(l, [3]int{0, 70, 0}, ToStateID[Sand{}], nil)
+	if l.testWorld[[3]int{0, 70, 0}] != (Air{}) || l.testWorld[[3]int{0, 64, 0}] != (Sand{}) {
+		t.Errorf("the sand hasn't fallen to the floor: %v", l.testWorld)
+	}
+}
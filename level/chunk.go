@@ -58,16 +58,9 @@ func EmptyChunk(secs int) *Chunk {
 		}
 	}
 	return &Chunk{
-		Sections: sections,
-		HeightMaps: HeightMaps{
-			WorldSurfaceWG:         NewBitStorage(bits.Len(uint(secs)*16+1), 16*16, nil),
-			WorldSurface:           NewBitStorage(bits.Len(uint(secs)*16+1), 16*16, nil),
-			OceanFloorWG:           NewBitStorage(bits.Len(uint(secs)*16+1), 16*16, nil),
-			OceanFloor:             NewBitStorage(bits.Len(uint(secs)*16+1), 16*16, nil),
-			MotionBlocking:         NewBitStorage(bits.Len(uint(secs)*16+1), 16*16, nil),
-			MotionBlockingNoLeaves: NewBitStorage(bits.Len(uint(secs)*16+1), 16*16, nil),
-		},
-		Status: StatusEmpty,
+		Sections:   sections,
+		HeightMaps: NewHeightMaps(secs * 16),
+		Status:     StatusEmpty,
 	}
 }
 
@@ -117,12 +110,12 @@ func ChunkFromSave(c *save.Chunk) (*Chunk, error) {
 	return &Chunk{
 		Sections: sections,
 		HeightMaps: HeightMaps{
-			WorldSurface:           NewBitStorage(bitsForHeight, 16*16, c.Heightmaps["WORLD_SURFACE_WG"]),
-			WorldSurfaceWG:         NewBitStorage(bitsForHeight, 16*16, c.Heightmaps["WORLD_SURFACE"]),
-			OceanFloorWG:           NewBitStorage(bitsForHeight, 16*16, c.Heightmaps["OCEAN_FLOOR_WG"]),
-			OceanFloor:             NewBitStorage(bitsForHeight, 16*16, c.Heightmaps["OCEAN_FLOOR"]),
-			MotionBlocking:         NewBitStorage(bitsForHeight, 16*16, c.Heightmaps["MOTION_BLOCKING"]),
-			MotionBlockingNoLeaves: NewBitStorage(bitsForHeight, 16*16, c.Heightmaps["MOTION_BLOCKING_NO_LEAVES"]),
+			WorldSurfaceWG:         heightMapFromSave(bitsForHeight, c.Heightmaps["WORLD_SURFACE_WG"]),
+			WorldSurface:           heightMapFromSave(bitsForHeight, c.Heightmaps["WORLD_SURFACE"]),
+			OceanFloorWG:           heightMapFromSave(bitsForHeight, c.Heightmaps["OCEAN_FLOOR_WG"]),
+			OceanFloor:             heightMapFromSave(bitsForHeight, c.Heightmaps["OCEAN_FLOOR"]),
+			MotionBlocking:         heightMapFromSave(bitsForHeight, c.Heightmaps["MOTION_BLOCKING"]),
+			MotionBlockingNoLeaves: heightMapFromSave(bitsForHeight, c.Heightmaps["MOTION_BLOCKING_NO_LEAVES"]),
 		},
 		BlockEntity: blockEntities,
 		Status:      ChunkStatus(c.Status),
@@ -193,6 +186,10 @@ func ChunkToSave(c *Chunk, dst *save.Chunk) (err error) {
 		s.BlockLight = v.BlockLight
 	}
 	dst.Sections = sections
+	dst.BlockEntities = make([]nbt.RawMessage, len(c.BlockEntity))
+	for i, v := range c.BlockEntity {
+		dst.BlockEntities[i] = v.Data
+	}
 	if dst.Heightmaps == nil {
 		dst.Heightmaps = make(map[string][]uint64)
 	}
@@ -260,16 +257,21 @@ func (c *Chunk) WriteTo(w io.Writer) (int64, error) {
 		SkyLight:       []pk.ByteArray{},
 		BlockLight:     []pk.ByteArray{},
 	}
+	// light sections start from the one below the world,
+	// so section i of the chunk is bit i+1 of the masks.
 	for i, v := range c.Sections {
 		if v.SkyLight != nil {
-			light.SkyLightMask.Set(i, true)
+			light.SkyLightMask.Set(i+1, true)
 			light.SkyLight = append(light.SkyLight, v.SkyLight)
 		}
 		if v.BlockLight != nil {
-			light.BlockLightMask.Set(i, true)
+			light.BlockLightMask.Set(i+1, true)
 			light.BlockLight = append(light.BlockLight, v.BlockLight)
 		}
 	}
+	// the section above the world is always lit by the sky.
+	light.SkyLightMask.Set(len(c.Sections)+1, true)
+	light.SkyLight = append(light.SkyLight, fullSkyLight[:])
 	return pk.Tuple{
 		// Heightmaps
 		pk.NBT(struct {
@@ -348,6 +350,9 @@ type HeightMaps struct {
 	MotionBlockingNoLeaves *BitStorage // test = BlocksMotion or isFluid
 }
 
+// BlockEntity is a block entity in the chunk, XZ packs its coordinates relative to the chunk.
+// The Data has the id and the world coordinates when it's read from and written to the save files,
+// but the clients only need the other fields.
 type BlockEntity struct {
 	XZ   int8
 	Y    int16
@@ -467,6 +472,14 @@ func (s *Section) ReadFrom(r io.Reader) (int64, error) {
 	}.ReadFrom(r)
 }
 
+// fullSkyLight is a light array of a section fully exposed to the sky.
+var fullSkyLight = func() (l [2048]byte) {
+	for i := range l {
+		l[i] = 0xFF
+	}
+	return
+}()
+
 type lightData struct {
 	SkyLightMask   pk.BitSet
 	BlockLightMask pk.BitSet
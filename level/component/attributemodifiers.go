@@ -1,10 +1,43 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*AttributeModifiers)(nil)
 
-type AttributeModifiers struct{}
+type AttributeModifiers struct {
+	Modifiers     []AttributeModifier
+	ShowInTooltip pk.Boolean
+}
+
+// The operations of AttributeModifier.
+const (
+	AddValue int32 = iota
+	AddMultipliedBase
+	AddMultipliedTotal
+)
+
+// AttributeModifier changes an attribute of the entity holding the item in the slot.
+type AttributeModifier struct {
+	// AttributeID is the ID in the minecraft:attribute registry.
+	AttributeID pk.VarInt
+	ModifierID  pk.Identifier
+	Value       pk.Double
+	Operation   pk.VarInt
+	// Slot is the equipment slot group: any, main hand, off hand, hand, feet, legs, chest, head, armor or body.
+	Slot pk.VarInt
+}
+
+func (a AttributeModifier) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&a.AttributeID, &a.ModifierID, &a.Value, &a.Operation, &a.Slot}.WriteTo(w)
+}
+
+func (a *AttributeModifier) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&a.AttributeID, &a.ModifierID, &a.Value, &a.Operation, &a.Slot}.ReadFrom(r)
+}
 
 // ID implements DataComponent.
 func (AttributeModifiers) ID() string {
@@ -13,10 +46,10 @@ func (AttributeModifiers) ID() string {
 
 // ReadFrom implements DataComponent.
 func (a *AttributeModifiers) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{pk.Array(&a.Modifiers), &a.ShowInTooltip}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (a *AttributeModifiers) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{pk.Array(&a.Modifiers), &a.ShowInTooltip}.WriteTo(w)
 }
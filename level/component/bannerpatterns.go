@@ -0,0 +1,57 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*BannerPatterns)(nil)
+
+// BannerPatterns are the pattern layers of a banner, from the bottom.
+type BannerPatterns struct {
+	Layers []BannerLayer
+}
+
+// BannerLayer is a pattern of a banner in a dye color.
+type BannerLayer struct {
+	Pattern Holder[BannerPattern, *BannerPattern]
+	Color   pk.VarInt
+}
+
+func (b BannerLayer) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&b.Pattern, &b.Color}.WriteTo(w)
+}
+
+func (b *BannerLayer) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&b.Pattern, &b.Color}.ReadFrom(r)
+}
+
+// BannerPattern is an entry of the minecraft:banner_pattern registry.
+type BannerPattern struct {
+	AssetID        pk.Identifier
+	TranslationKey pk.String
+}
+
+func (b BannerPattern) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&b.AssetID, &b.TranslationKey}.WriteTo(w)
+}
+
+func (b *BannerPattern) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&b.AssetID, &b.TranslationKey}.ReadFrom(r)
+}
+
+// ID implements DataComponent.
+func (BannerPatterns) ID() string {
+	return "minecraft:banner_patterns"
+}
+
+// ReadFrom implements DataComponent.
+func (b *BannerPatterns) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Array(&b.Layers).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (b *BannerPatterns) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Array(&b.Layers).WriteTo(w)
+}
@@ -0,0 +1,15 @@
+package component
+
+import pk "github.com/mrhaoxx/go-mc/net/packet"
+
+var _ DataComponent = (*BaseColor)(nil)
+
+// BaseColor is the dye color of a banner or a shield.
+type BaseColor struct {
+	pk.VarInt
+}
+
+// ID implements DataComponent.
+func (BaseColor) ID() string {
+	return "minecraft:base_color"
+}
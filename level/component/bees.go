@@ -0,0 +1,44 @@
+package component
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*Bees)(nil)
+
+// Bees are the bees in a beehive or a bee nest.
+type Bees struct {
+	Bees []Bee
+}
+
+type Bee struct {
+	EntityData     dynbt.Value
+	TicksInHive    pk.VarInt
+	MinTicksInHive pk.VarInt
+}
+
+func (b Bee) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.NBT(&b.EntityData), &b.TicksInHive, &b.MinTicksInHive}.WriteTo(w)
+}
+
+func (b *Bee) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{pk.NBT(&b.EntityData), &b.TicksInHive, &b.MinTicksInHive}.ReadFrom(r)
+}
+
+// ID implements DataComponent.
+func (Bees) ID() string {
+	return "minecraft:bees"
+}
+
+// ReadFrom implements DataComponent.
+func (b *Bees) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Array(&b.Bees).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (b *Bees) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Array(&b.Bees).WriteTo(w)
+}
@@ -0,0 +1,76 @@
+package component
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// BlockPredicate matches blocks for the adventure mode components CanBreak and CanPlaceOn.
+// The nil fields match any block.
+type BlockPredicate struct {
+	Blocks     *IDSet
+	Properties []PropertyMatcher
+	NBT        *dynbt.Value
+}
+
+func (b BlockPredicate) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.Boolean(b.Blocks != nil),
+		pk.Opt{Has: b.Blocks != nil, Field: b.Blocks},
+		pk.Boolean(b.Properties != nil),
+		pk.Opt{Has: b.Properties != nil, Field: pk.Array(b.Properties)},
+		pk.Boolean(b.NBT != nil),
+		pk.Opt{Has: b.NBT != nil, Field: func() pk.FieldEncoder { return pk.NBT(b.NBT) }},
+	}.WriteTo(w)
+}
+
+func (b *BlockPredicate) ReadFrom(r io.Reader) (int64, error) {
+	var hasBlocks, hasProperties, hasNBT pk.Boolean
+	return pk.Tuple{
+		&hasBlocks,
+		pk.Opt{Has: &hasBlocks, Field: func() pk.FieldDecoder {
+			b.Blocks = new(IDSet)
+			return b.Blocks
+		}},
+		&hasProperties,
+		pk.Opt{Has: &hasProperties, Field: func() pk.FieldDecoder {
+			b.Properties = []PropertyMatcher{}
+			return pk.Array(&b.Properties)
+		}},
+		&hasNBT,
+		pk.Opt{Has: &hasNBT, Field: func() pk.FieldDecoder {
+			b.NBT = new(dynbt.Value)
+			return pk.NBT(b.NBT)
+		}},
+	}.ReadFrom(r)
+}
+
+// PropertyMatcher matches a block state property with the exact value, or in a range of values.
+type PropertyMatcher struct {
+	Name       pk.String
+	IsExact    pk.Boolean
+	ExactValue pk.String
+	// MinValue and MaxValue are the bounds of the range if the match isn't exact.
+	MinValue pk.Option[pk.String, *pk.String]
+	MaxValue pk.Option[pk.String, *pk.String]
+}
+
+func (p PropertyMatcher) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		&p.Name,
+		&p.IsExact,
+		pk.Opt{Has: &p.IsExact, Field: &p.ExactValue},
+		pk.Opt{Has: !p.IsExact, Field: pk.Tuple{&p.MinValue, &p.MaxValue}},
+	}.WriteTo(w)
+}
+
+func (p *PropertyMatcher) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{
+		&p.Name,
+		&p.IsExact,
+		pk.Opt{Has: &p.IsExact, Field: &p.ExactValue},
+		pk.Opt{Has: func() bool { return !bool(p.IsExact) }, Field: pk.Tuple{&p.MinValue, &p.MaxValue}},
+	}.ReadFrom(r)
+}
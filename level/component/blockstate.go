@@ -0,0 +1,42 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*BlockState)(nil)
+
+// BlockState are the block state properties set when the item is placed.
+type BlockState struct {
+	Properties []BlockStateProperty
+}
+
+type BlockStateProperty struct {
+	Name  pk.String
+	Value pk.String
+}
+
+func (p BlockStateProperty) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&p.Name, &p.Value}.WriteTo(w)
+}
+
+func (p *BlockStateProperty) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&p.Name, &p.Value}.ReadFrom(r)
+}
+
+// ID implements DataComponent.
+func (BlockState) ID() string {
+	return "minecraft:block_state"
+}
+
+// ReadFrom implements DataComponent.
+func (b *BlockState) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Array(&b.Properties).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (b *BlockState) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Array(&b.Properties).WriteTo(w)
+}
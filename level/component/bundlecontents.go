@@ -1,11 +1,15 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*BundleContents)(nil)
 
 type BundleContents struct {
-	// TODO
+	Items []Slot
 }
 
 // ID implements DataComponent.
@@ -15,10 +19,10 @@ func (BundleContents) ID() string {
 
 // ReadFrom implements DataComponent.
 func (b *BundleContents) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Array(&b.Items).ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (b *BundleContents) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Array(&b.Items).WriteTo(w)
 }
@@ -1,10 +1,17 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*CanBreak)(nil)
 
-type CanBreak struct{}
+type CanBreak struct {
+	Predicates    []BlockPredicate
+	ShowInTooltip pk.Boolean
+}
 
 // ID implements DataComponent.
 func (CanBreak) ID() string {
@@ -13,10 +20,10 @@ func (CanBreak) ID() string {
 
 // ReadFrom implements DataComponent.
 func (c *CanBreak) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{pk.Array(&c.Predicates), &c.ShowInTooltip}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (c *CanBreak) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{pk.Array(&c.Predicates), &c.ShowInTooltip}.WriteTo(w)
 }
@@ -1,10 +1,17 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*CanPlaceOn)(nil)
 
-type CanPlaceOn struct{}
+type CanPlaceOn struct {
+	Predicates    []BlockPredicate
+	ShowInTooltip pk.Boolean
+}
 
 // ID implements DataComponent.
 func (CanPlaceOn) ID() string {
@@ -13,10 +20,10 @@ func (CanPlaceOn) ID() string {
 
 // ReadFrom implements DataComponent.
 func (c *CanPlaceOn) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{pk.Array(&c.Predicates), &c.ShowInTooltip}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (c *CanPlaceOn) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{pk.Array(&c.Predicates), &c.ShowInTooltip}.WriteTo(w)
 }
@@ -1,11 +1,15 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*ChargedProjectiles)(nil)
 
 type ChargedProjectiles struct {
-	// TODO
+	Projectiles []Slot
 }
 
 // ID implements DataComponent.
@@ -15,10 +19,10 @@ func (ChargedProjectiles) ID() string {
 
 // ReadFrom implements DataComponent.
 func (c *ChargedProjectiles) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Array(&c.Projectiles).ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (c *ChargedProjectiles) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Array(&c.Projectiles).WriteTo(w)
 }
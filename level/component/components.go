@@ -1,12 +1,17 @@
 package component
 
-import pk "github.com/mrhaoxx/go-mc/net/packet"
+import (
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 type DataComponent interface {
 	pk.Field
 	ID() string
 }
 
+// NewComponent returns a new component of the type ID in the minecraft:data_component_type registry,
+// or nil if the ID is unknown.
 func NewComponent(id int32) DataComponent {
 	switch id {
 	case 0:
@@ -24,92 +29,139 @@ func NewComponent(id int32) DataComponent {
 	case 6:
 		return new(ItemName)
 	case 7:
-		return new(Lore)
+		return new(ItemModel)
 	case 8:
-		return new(Rarity)
+		return new(Lore)
 	case 9:
-		return new(Enchantments)
+		return new(Rarity)
 	case 10:
-		return new(CanPlaceOn)
+		return new(Enchantments)
 	case 11:
-		return new(CanBreak)
+		return new(CanPlaceOn)
 	case 12:
-		return new(AttributeModifiers)
+		return new(CanBreak)
 	case 13:
-		return new(CustomModelData)
+		return new(AttributeModifiers)
 	case 14:
-		return new(HideAdditionalTooptip)
+		return new(CustomModelData)
 	case 15:
-		return new(HideTooptip)
+		return new(HideAdditionalTooptip)
 	case 16:
-		return new(RepairCost)
+		return new(HideTooptip)
 	case 17:
-		return new(CreativeSlotLock)
+		return new(RepairCost)
 	case 18:
-		return new(EnchantmentGlintOverride)
+		return new(CreativeSlotLock)
 	case 19:
-		return new(IntangibleProjectile)
+		return new(EnchantmentGlintOverride)
 	case 20:
-		return new(Food)
+		return new(IntangibleProjectile)
 	case 21:
-		return new(FireResistant)
+		return new(Food)
 	case 22:
-		return new(Tool)
+		return new(Consumable)
 	case 23:
-		return new(StoredEnchantments)
+		return new(UseRemainder)
 	case 24:
-		return new(DyedColor)
+		return new(UseCooldown)
 	case 25:
-		return new(MapColor)
+		return new(DamageResistant)
 	case 26:
-		return new(MapID)
+		return new(Tool)
 	case 27:
-		return new(MapDecorations)
+		return new(Enchantable)
 	case 28:
-		return new(MapPostProcessing)
+		return new(Equippable)
 	case 29:
-		return new(ChargedProjectiles)
+		return new(Repairable)
 	case 30:
-		return new(BundleContents)
+		return new(Glider)
 	case 31:
-		return new(PotionContents)
+		return new(TooltipStyle)
 	case 32:
-		return new(SuspiciousStewEffects)
+		return new(DeathProtection)
 	case 33:
-		return new(WritableBookContent)
+		return new(StoredEnchantments)
 	case 34:
+		return new(DyedColor)
 	case 35:
-		return new(Trim)
+		return new(MapColor)
 	case 36:
-		return new(DebugStickState)
+		return new(MapID)
 	case 37:
-		return new(EntityData)
+		return new(MapDecorations)
 	case 38:
-		return new(BucketEntityData)
+		return new(MapPostProcessing)
 	case 39:
-		return new(BlockEntityData)
+		return new(ChargedProjectiles)
 	case 40:
-		return new(Instrument)
+		return new(BundleContents)
 	case 41:
-		return new(OminousBottleAmplifier)
+		return new(PotionContents)
 	case 42:
-		return new(JukeboxPlayable)
+		return new(SuspiciousStewEffects)
 	case 43:
-		return new(Recipes)
+		return new(WritableBookContent)
 	case 44:
-		return new(LodestoneTracker)
+		return new(WrittenBookContent)
 	case 45:
+		return new(Trim)
 	case 46:
+		return new(DebugStickState)
 	case 47:
+		return new(EntityData)
 	case 48:
+		return new(BucketEntityData)
 	case 49:
+		return new(BlockEntityData)
 	case 50:
+		return new(Instrument)
 	case 51:
+		return new(OminousBottleAmplifier)
 	case 52:
+		return new(JukeboxPlayable)
 	case 53:
+		return new(Recipes)
 	case 54:
+		return new(LodestoneTracker)
 	case 55:
+		return new(FireworkExplosion)
 	case 56:
+		return new(Fireworks)
+	case 57:
+		return new(Profile)
+	case 58:
+		return new(NoteBlockSound)
+	case 59:
+		return new(BannerPatterns)
+	case 60:
+		return new(BaseColor)
+	case 61:
+		return new(PotDecorations)
+	case 62:
+		return new(Container)
+	case 63:
+		return new(BlockState)
+	case 64:
+		return new(Bees)
+	case 65:
+		return new(LockCode)
+	case 66:
+		return new(ContainerLoot)
 	}
 	return nil
 }
+
+var typeIDs = make(map[string]int32, len(registryid.DataComponentType))
+
+func init() {
+	for i, id := range registryid.DataComponentType {
+		typeIDs[id] = int32(i)
+	}
+}
+
+// TypeID returns the ID of the component's type in the minecraft:data_component_type registry.
+func TypeID(c DataComponent) (int32, bool) {
+	id, ok := typeIDs[c.ID()]
+	return id, ok
+}
@@ -1,10 +1,251 @@
 package component_test
 
-// func TestNewComponent(t *testing.T) {
-// 	for i, want := range registryid.DataComponentType {
-// 		comp := component.NewComponent(int32(i))
-// 		if got := comp.ID(); got != want {
-// 			t.Errorf("DataComponent type mismatched: %s != %s", got, want)
-// 		}
-// 	}
-// }
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+func TestNewComponent(t *testing.T) {
+	for i, want := range registryid.DataComponentType {
+		comp := component.NewComponent(int32(i))
+		if comp == nil {
+			t.Errorf("DataComponent type %s not found", want)
+			continue
+		}
+		if got := comp.ID(); got != want {
+			t.Errorf("DataComponent type mismatched: %s != %s", got, want)
+		}
+	}
+}
+
+func TestComponents_roundTrip(t *testing.T) {
+	samples := sampleComponents()
+	seen := make(map[string]bool)
+	for _, c := range samples {
+		seen[c.ID()] = true
+		var buf bytes.Buffer
+		if _, err := c.WriteTo(&buf); err != nil {
+			t.Errorf("%s: write: %v", c.ID(), err)
+			continue
+		}
+		data := buf.Bytes()
+		id, _ := component.TypeID(c)
+		got := component.NewComponent(id)
+		r := bytes.NewReader(data)
+		if _, err := got.ReadFrom(r); err != nil {
+			t.Errorf("%s: read: %v", c.ID(), err)
+			continue
+		}
+		if r.Len() != 0 {
+			t.Errorf("%s: %d bytes left unread", c.ID(), r.Len())
+		}
+		var again bytes.Buffer
+		if _, err := got.WriteTo(&again); err != nil {
+			t.Errorf("%s: write again: %v", c.ID(), err)
+			continue
+		}
+		if !bytes.Equal(data, again.Bytes()) {
+			t.Errorf("%s: round trip changed the data\n% x\n% x", c.ID(), data, again.Bytes())
+		}
+	}
+	for _, id := range registryid.DataComponentType {
+		if !seen[id] {
+			t.Errorf("no sample of %s", id)
+		}
+	}
+}
+
+func TestPatch(t *testing.T) {
+	patch := component.Patch{
+		Added:   []component.DataComponent{&component.Damage{VarInt: 5}, ptr(component.Common)},
+		Removed: []int32{4},
+	}
+	var buf bytes.Buffer
+	if _, err := (component.Slot{Count: 1, ItemID: 2, Patch: patch}).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// count, item, 2 added, 1 removed, damage 5, rarity common, unbreakable removed
+	want := []byte{1, 2, 2, 1, 3, 5, 9, 0, 4}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("encoded slot % x, want % x", buf.Bytes(), want)
+	}
+	var slot component.Slot
+	if _, err := slot.ReadFrom(bytes.NewReader(want)); err != nil {
+		t.Fatal(err)
+	}
+	if slot.Count != 1 || slot.ItemID != 2 || !reflect.DeepEqual(slot.Patch, patch) {
+		t.Errorf("decoded slot %+v, want %+v", slot, patch)
+	}
+}
+
+func TestSlot_empty(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := (component.Slot{ItemID: 1}).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), []byte{0}) {
+		t.Errorf("empty slot encoded as % x", buf.Bytes())
+	}
+}
+
+func TestPatch_unknown(t *testing.T) {
+	var patch component.Patch
+	if _, err := patch.ReadFrom(bytes.NewReader([]byte{1, 0, 100})); err == nil {
+		t.Error("unknown component type read without error")
+	}
+}
+
+func sampleComponents() []component.DataComponent {
+	text := chat.Text("Hello")
+	compound := dynbt.NewCompound()
+	compound.Set("id", dynbt.NewString("minecraft:pig"))
+	str := func(s string) pk.Option[pk.String, *pk.String] {
+		return pk.Option[pk.String, *pk.String]{Has: true, Val: pk.String(s)}
+	}
+	sound := component.Sound{Inline: &component.SoundEvent{
+		SoundName:  "minecraft:entity.generic.eat",
+		FixedRange: pk.Option[pk.Float, *pk.Float]{Has: true, Val: 16},
+	}}
+	effect := component.PotionEffect{TypeID: 1, Details: component.EffectDetails{
+		Amplifier: 1, Duration: 200, ShowIcon: true,
+		HiddenEffect: &component.EffectDetails{Duration: 400},
+	}}
+	slot := component.Slot{Count: 3, ItemID: 42, Patch: component.Patch{
+		Added:   []component.DataComponent{&component.MaxStackSize{VarInt: 16}},
+		Removed: []int32{3},
+	}}
+	predicate := component.BlockPredicate{
+		Blocks: &component.IDSet{IDs: []pk.VarInt{1, 2}},
+		Properties: []component.PropertyMatcher{
+			{Name: "facing", IsExact: true, ExactValue: "east"},
+			{Name: "age", MinValue: str("1")},
+		},
+		NBT: compound,
+	}
+	return []component.DataComponent{
+		&component.CustomData{Value: *compound},
+		&component.MaxStackSize{VarInt: 16},
+		&component.MaxDamage{VarInt: 250},
+		&component.Damage{VarInt: 3},
+		&component.Unbreakable{ShowInTooltip: true},
+		&component.CustomName{Name: text},
+		&component.ItemName{Name: text},
+		&component.ItemModel{Model: "minecraft:stone"},
+		&component.Lore{Lines: []chat.Message{text, text}},
+		ptr(component.Epic),
+		&component.Enchantments{Enchantments: []component.EnchantmentLevel{{Type: 1, Level: 3}}, ShowInTooltip: true},
+		&component.CanPlaceOn{Predicates: []component.BlockPredicate{predicate, {}}, ShowInTooltip: true},
+		&component.CanBreak{Predicates: []component.BlockPredicate{{Blocks: &component.IDSet{Tag: "minecraft:logs"}}}},
+		&component.AttributeModifiers{Modifiers: []component.AttributeModifier{{
+			AttributeID: 2, ModifierID: "minecraft:base_attack_damage", Value: 5, Operation: pk.VarInt(component.AddValue), Slot: 1,
+		}}, ShowInTooltip: true},
+		&component.CustomModelData{Floats: []pk.Float{1.5}, Flags: []pk.Boolean{true}, Strings: []pk.String{"a"}, Colors: []pk.Int{0xFF0000}},
+		&component.HideAdditionalTooptip{},
+		&component.HideTooptip{},
+		&component.RepairCost{VarInt: 1},
+		&component.CreativeSlotLock{},
+		&component.EnchantmentGlintOverride{HasGlint: true},
+		&component.IntangibleProjectile{},
+		&component.Food{Nutrition: 4, Saturation: 2.4},
+		&component.Consumable{
+			ConsumeSeconds: 1.6, Animation: pk.VarInt(component.AnimationEat), Sound: sound, HasConsumeParticles: true,
+			OnConsumeEffects: []component.ConsumeEffect{
+				{Type: pk.VarInt(component.ApplyEffects), Effects: []component.PotionEffect{effect}, Probability: 0.5},
+				{Type: pk.VarInt(component.RemoveEffects), RemovedEffects: component.IDSet{IDs: []pk.VarInt{3}}},
+				{Type: pk.VarInt(component.ClearAllEffects)},
+				{Type: pk.VarInt(component.TeleportRandomly), Diameter: 16},
+				{Type: pk.VarInt(component.PlaySound), Sound: component.Sound{ID: 7}},
+			},
+		},
+		&component.UseRemainder{Item: slot},
+		&component.UseCooldown{Seconds: 1, CooldownGroup: pk.Option[pk.Identifier, *pk.Identifier]{Has: true, Val: "minecraft:pearl"}},
+		&component.DamageResistant{Types: "minecraft:is_fire"},
+		&component.Tool{Rules: []component.ToolRule{{
+			Blocks:         component.IDSet{Tag: "minecraft:mineable/pickaxe"},
+			Speed:          pk.Option[pk.Float, *pk.Float]{Has: true, Val: 8},
+			CorrectForDrop: pk.Option[pk.Boolean, *pk.Boolean]{Has: true, Val: true},
+		}}, DefaultMiningSpeed: 1, DamagePerBlock: 1},
+		&component.Enchantable{VarInt: 10},
+		&component.Equippable{
+			Slot: pk.VarInt(component.SlotHead), EquipSound: component.Sound{ID: 1},
+			AssetID:         pk.Option[pk.Identifier, *pk.Identifier]{Has: true, Val: "minecraft:diamond"},
+			AllowedEntities: &component.IDSet{IDs: []pk.VarInt{5}},
+			Dispensable:     true, Swappable: true, DamageOnHurt: true,
+		},
+		&component.Repairable{Items: component.IDSet{IDs: []pk.VarInt{1}}},
+		&component.Glider{},
+		&component.TooltipStyle{Style: "minecraft:fancy"},
+		&component.DeathProtection{DeathEffects: []component.ConsumeEffect{{Type: pk.VarInt(component.ClearAllEffects)}}},
+		&component.StoredEnchantments{Enchantments: []component.EnchantmentLevel{{Type: 2, Level: 1}}},
+		&component.DyedColor{RGB: 0x00FF00, ShowInTooltip: true},
+		&component.MapColor{Int: 0x0000FF},
+		&component.MapID{VarInt: 7},
+		&component.MapDecorations{Value: *compound},
+		ptr(component.Scale),
+		&component.ChargedProjectiles{Projectiles: []component.Slot{slot}},
+		&component.BundleContents{Items: []component.Slot{slot, {Count: 1, ItemID: 1}}},
+		&component.PotionContents{
+			PotionID:      pk.Option[pk.VarInt, *pk.VarInt]{Has: true, Val: 5},
+			CustomEffects: []component.PotionEffect{effect},
+			CustomName:    str("water"),
+		},
+		&component.SuspiciousStewEffects{Effects: []component.StewEffect{{TypeID: 1, Duration: 160}}},
+		&component.WritableBookContent{Pages: []component.Page{{Raw: "page", Filtered: str("p")}}},
+		&component.WrittenBookContent{
+			RawTitle: "Title", Author: "Tnze", Generation: 1, Resolved: true,
+			Pages: []component.TextPage{{Raw: text}, {Raw: text, Filtered: pk.Option[chat.Message, *chat.Message]{Has: true, Val: text}}},
+		},
+		&component.Trim{
+			Material: component.Holder[component.TrimMaterial, *component.TrimMaterial]{Inline: &component.TrimMaterial{
+				AssetName: "gold", Ingredient: 3, Description: text,
+				OverrideArmorAssets: []component.ArmorAssetOverride{{Asset: "minecraft:gold", AssetName: "gold_darker"}},
+			}},
+			Pattern: component.Holder[component.TrimPattern, *component.TrimPattern]{ID: 4},
+		},
+		&component.DebugStickState{Properties: map[string]string{"minecraft:oak_stairs": "facing"}},
+		&component.EntityData{Value: *compound},
+		&component.BucketEntityData{Value: *compound},
+		&component.BlockEntityData{Value: *compound},
+		&component.Instrument{Holder: component.Holder[component.InstrumentData, *component.InstrumentData]{
+			Inline: &component.InstrumentData{SoundEvent: component.Sound{ID: 2}, UseDuration: 7, Range: 256, Description: text},
+		}},
+		&component.OminousBottleAmplifier{VarInt: 2},
+		&component.JukeboxPlayable{Song: component.Holder[component.JukeboxSong, *component.JukeboxSong]{
+			Inline: &component.JukeboxSong{SoundEvent: sound, Description: text, LengthInSeconds: 60, ComparatorOutput: 3},
+		}, ShowInTooltip: true},
+		&component.Recipes{Data: *dynbt.NewList(dynbt.NewString("minecraft:stick"))},
+		&component.LodestoneTracker{HasGlobalPosition: true, Dimension: "minecraft:overworld", Position: pk.Position{X: 1, Y: 2, Z: 3}, Tracked: true},
+		&component.FireworkExplosion{Shape: pk.VarInt(component.Star), Colors: []pk.Int{1}, FadeColors: []pk.Int{2, 3}, HasTrail: true},
+		&component.Fireworks{FlightDuration: 2, Explosions: []component.FireworkExplosion{{Shape: pk.VarInt(component.Creeper)}}},
+		&component.Profile{
+			Name:       str("Tnze"),
+			UUID:       pk.Option[pk.UUID, *pk.UUID]{Has: true, Val: pk.UUID(uuid.New())},
+			Properties: []component.ProfileProperty{{Name: "textures", Value: "e30=", Signature: str("sig")}},
+		},
+		&component.NoteBlockSound{Sound: "minecraft:block.note_block.harp"},
+		&component.BannerPatterns{Layers: []component.BannerLayer{
+			{Pattern: component.Holder[component.BannerPattern, *component.BannerPattern]{ID: 1}, Color: 14},
+			{Pattern: component.Holder[component.BannerPattern, *component.BannerPattern]{Inline: &component.BannerPattern{
+				AssetID: "minecraft:creeper", TranslationKey: "block.minecraft.banner.creeper",
+			}}},
+		}},
+		&component.BaseColor{VarInt: 4},
+		&component.PotDecorations{Decorations: []pk.VarInt{1, 2, 3, 4}},
+		&component.Container{Items: []component.Slot{slot, {}, slot}},
+		&component.BlockState{Properties: []component.BlockStateProperty{{Name: "honey_level", Value: "5"}}},
+		&component.Bees{Bees: []component.Bee{{EntityData: *compound, TicksInHive: 10, MinTicksInHive: 600}}},
+		&component.LockCode{Value: *compound},
+		&component.ContainerLoot{Value: *compound},
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
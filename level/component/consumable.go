@@ -0,0 +1,59 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*Consumable)(nil)
+
+// The animations of Consumable.
+const (
+	AnimationNone int32 = iota
+	AnimationEat
+	AnimationDrink
+	AnimationBlock
+	AnimationBow
+	AnimationSpear
+	AnimationCrossbow
+	AnimationSpyglass
+	AnimationTootHorn
+	AnimationBrush
+)
+
+// Consumable makes an item eaten or drunk when it's used.
+type Consumable struct {
+	ConsumeSeconds      pk.Float
+	Animation           pk.VarInt
+	Sound               Sound
+	HasConsumeParticles pk.Boolean
+	OnConsumeEffects    []ConsumeEffect
+}
+
+// ID implements DataComponent.
+func (Consumable) ID() string {
+	return "minecraft:consumable"
+}
+
+// ReadFrom implements DataComponent.
+func (c *Consumable) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{
+		&c.ConsumeSeconds,
+		&c.Animation,
+		&c.Sound,
+		&c.HasConsumeParticles,
+		pk.Array(&c.OnConsumeEffects),
+	}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (c *Consumable) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{
+		&c.ConsumeSeconds,
+		&c.Animation,
+		&c.Sound,
+		&c.HasConsumeParticles,
+		pk.Array(&c.OnConsumeEffects),
+	}.WriteTo(w)
+}
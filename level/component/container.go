@@ -0,0 +1,29 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*Container)(nil)
+
+// Container is the contents of a container item like a shulker box, with up to 256 slots.
+type Container struct {
+	Items []Slot
+}
+
+// ID implements DataComponent.
+func (Container) ID() string {
+	return "minecraft:container"
+}
+
+// ReadFrom implements DataComponent.
+func (c *Container) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Array(&c.Items).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (c *Container) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Array(&c.Items).WriteTo(w)
+}
@@ -0,0 +1,30 @@
+package component
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*ContainerLoot)(nil)
+
+// ContainerLoot is the loot table of a container which isn't opened yet.
+type ContainerLoot struct {
+	dynbt.Value
+}
+
+// ID implements DataComponent.
+func (ContainerLoot) ID() string {
+	return "minecraft:container_loot"
+}
+
+// ReadFrom implements DataComponent.
+func (c *ContainerLoot) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.NBT(&c.Value).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (c *ContainerLoot) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.NBT(&c.Value).WriteTo(w)
+}
@@ -8,8 +8,12 @@ import (
 
 var _ DataComponent = (*CustomModelData)(nil)
 
+// CustomModelData are the values read by the item model definitions.
 type CustomModelData struct {
-	Value pk.VarInt
+	Floats  []pk.Float
+	Flags   []pk.Boolean
+	Strings []pk.String
+	Colors  []pk.Int
 }
 
 // ID implements DataComponent.
@@ -19,10 +23,20 @@ func (CustomModelData) ID() string {
 
 // ReadFrom implements DataComponent.
 func (c *CustomModelData) ReadFrom(r io.Reader) (n int64, err error) {
-	return c.Value.ReadFrom(r)
+	return pk.Tuple{
+		pk.Array(&c.Floats),
+		pk.Array(&c.Flags),
+		pk.Array(&c.Strings),
+		pk.Array(&c.Colors),
+	}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (c *CustomModelData) WriteTo(w io.Writer) (n int64, err error) {
-	return c.Value.WriteTo(w)
+	return pk.Tuple{
+		pk.Array(&c.Floats),
+		pk.Array(&c.Flags),
+		pk.Array(&c.Strings),
+		pk.Array(&c.Colors),
+	}.WriteTo(w)
 }
@@ -0,0 +1,30 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*DamageResistant)(nil)
+
+// DamageResistant makes the item entity immune to the damage types in the tag, like netherite in fire.
+type DamageResistant struct {
+	// Types is the damage type tag, without the #.
+	Types pk.Identifier
+}
+
+// ID implements DataComponent.
+func (DamageResistant) ID() string {
+	return "minecraft:damage_resistant"
+}
+
+// ReadFrom implements DataComponent.
+func (d *DamageResistant) ReadFrom(r io.Reader) (n int64, err error) {
+	return d.Types.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (d *DamageResistant) WriteTo(w io.Writer) (n int64, err error) {
+	return d.Types.WriteTo(w)
+}
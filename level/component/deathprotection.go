@@ -0,0 +1,29 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*DeathProtection)(nil)
+
+// DeathProtection saves the holder from death like a totem of undying, applying the effects.
+type DeathProtection struct {
+	DeathEffects []ConsumeEffect
+}
+
+// ID implements DataComponent.
+func (DeathProtection) ID() string {
+	return "minecraft:death_protection"
+}
+
+// ReadFrom implements DataComponent.
+func (d *DeathProtection) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Array(&d.DeathEffects).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (d *DeathProtection) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Array(&d.DeathEffects).WriteTo(w)
+}
@@ -3,14 +3,14 @@ package component
 import (
 	"io"
 
-	"github.com/mrhaoxx/go-mc/level/block"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
 
 var _ DataComponent = (*DebugStickState)(nil)
 
 type DebugStickState struct {
-	Data block.State
+	// Properties are the selected property of each block, keyed by the block id.
+	Properties map[string]string
 }
 
 // ID implements DataComponent.
@@ -20,10 +20,10 @@ func (DebugStickState) ID() string {
 
 // ReadFrom implements DataComponent.
 func (d *DebugStickState) ReadFrom(r io.Reader) (n int64, err error) {
-	return pk.NBT(&d.Data).ReadFrom(r)
+	return pk.NBT(&d.Properties).ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (d *DebugStickState) WriteTo(w io.Writer) (n int64, err error) {
-	return pk.NBT(&d.Data).WriteTo(w)
+	return pk.NBT(d.Properties).WriteTo(w)
 }
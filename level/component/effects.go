@@ -0,0 +1,117 @@
+package component
+
+import (
+	"errors"
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// PotionEffect is a status effect of an item, like the effects of a potion.
+type PotionEffect struct {
+	// TypeID is the ID in the minecraft:mob_effect registry.
+	TypeID  pk.VarInt
+	Details EffectDetails
+}
+
+func (p PotionEffect) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&p.TypeID, &p.Details}.WriteTo(w)
+}
+
+func (p *PotionEffect) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&p.TypeID, &p.Details}.ReadFrom(r)
+}
+
+// EffectDetails are the properties of a status effect.
+type EffectDetails struct {
+	Amplifier     pk.VarInt
+	Duration      pk.VarInt // in ticks, -1 for infinite
+	Ambient       pk.Boolean
+	ShowParticles pk.Boolean
+	ShowIcon      pk.Boolean
+	// HiddenEffect is the weaker effect of the same type restored when this one runs out.
+	HiddenEffect *EffectDetails
+}
+
+func (e EffectDetails) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		&e.Amplifier,
+		&e.Duration,
+		&e.Ambient,
+		&e.ShowParticles,
+		&e.ShowIcon,
+		pk.Boolean(e.HiddenEffect != nil),
+		pk.Opt{Has: e.HiddenEffect != nil, Field: e.HiddenEffect},
+	}.WriteTo(w)
+}
+
+func (e *EffectDetails) ReadFrom(r io.Reader) (int64, error) {
+	var hasHidden pk.Boolean
+	return pk.Tuple{
+		&e.Amplifier,
+		&e.Duration,
+		&e.Ambient,
+		&e.ShowParticles,
+		&e.ShowIcon,
+		&hasHidden,
+		pk.Opt{Has: &hasHidden, Field: func() pk.FieldDecoder {
+			e.HiddenEffect = new(EffectDetails)
+			return e.HiddenEffect
+		}},
+	}.ReadFrom(r)
+}
+
+// The types of ConsumeEffect, in the order of the minecraft:consume_effect_type registry.
+const (
+	ApplyEffects int32 = iota
+	RemoveEffects
+	ClearAllEffects
+	TeleportRandomly
+	PlaySound
+)
+
+// ConsumeEffect is an effect applied when an item is consumed.
+// Only the fields of its Type are used.
+type ConsumeEffect struct {
+	Type pk.VarInt
+	// Effects and Probability are the fields of ApplyEffects.
+	Effects     []PotionEffect
+	Probability pk.Float
+	// RemovedEffects are the effects removed by RemoveEffects.
+	RemovedEffects IDSet
+	// Diameter is the range of TeleportRandomly.
+	Diameter pk.Float
+	// Sound is played by PlaySound.
+	Sound Sound
+}
+
+func (c ConsumeEffect) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&c.Type, c.fields()}.WriteTo(w)
+}
+
+func (c *ConsumeEffect) ReadFrom(r io.Reader) (n int64, err error) {
+	n, err = c.Type.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if c.Type < 0 || int32(c.Type) > PlaySound {
+		return n, errors.New("unknown consume effect type")
+	}
+	n1, err := c.fields().ReadFrom(r)
+	return n + n1, err
+}
+
+func (c *ConsumeEffect) fields() pk.Tuple {
+	switch int32(c.Type) {
+	case ApplyEffects:
+		return pk.Tuple{pk.Array(&c.Effects), &c.Probability}
+	case RemoveEffects:
+		return pk.Tuple{&c.RemovedEffects}
+	case TeleportRandomly:
+		return pk.Tuple{&c.Diameter}
+	case PlaySound:
+		return pk.Tuple{&c.Sound}
+	default: // ClearAllEffects
+		return nil
+	}
+}
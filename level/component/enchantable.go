@@ -0,0 +1,15 @@
+package component
+
+import pk "github.com/mrhaoxx/go-mc/net/packet"
+
+var _ DataComponent = (*Enchantable)(nil)
+
+// Enchantable is the enchantability of the item in an enchanting table.
+type Enchantable struct {
+	pk.VarInt
+}
+
+// ID implements DataComponent.
+func (Enchantable) ID() string {
+	return "minecraft:enchantable"
+}
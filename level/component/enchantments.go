@@ -1,10 +1,32 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*Enchantments)(nil)
 
-type Enchantments struct{}
+type Enchantments struct {
+	Enchantments  []EnchantmentLevel
+	ShowInTooltip pk.Boolean
+}
+
+// EnchantmentLevel is an enchantment of an item.
+type EnchantmentLevel struct {
+	// Type is the ID in the minecraft:enchantment registry.
+	Type  pk.VarInt
+	Level pk.VarInt
+}
+
+func (e EnchantmentLevel) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&e.Type, &e.Level}.WriteTo(w)
+}
+
+func (e *EnchantmentLevel) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&e.Type, &e.Level}.ReadFrom(r)
+}
 
 // ID implements DataComponent.
 func (Enchantments) ID() string {
@@ -12,11 +34,17 @@ func (Enchantments) ID() string {
 }
 
 // ReadFrom implements DataComponent.
-func (r *Enchantments) ReadFrom(reader io.Reader) (n int64, err error) {
-	panic("unimplemented")
+func (e *Enchantments) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{
+		pk.Array(&e.Enchantments),
+		&e.ShowInTooltip,
+	}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
-func (r *Enchantments) WriteTo(writer io.Writer) (n int64, err error) {
-	panic("unimplemented")
+func (e *Enchantments) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{
+		pk.Array(&e.Enchantments),
+		&e.ShowInTooltip,
+	}.WriteTo(w)
 }
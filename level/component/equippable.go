@@ -0,0 +1,73 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*Equippable)(nil)
+
+// The equipment slots of Equippable.
+const (
+	SlotMainHand int32 = iota
+	SlotFeet
+	SlotLegs
+	SlotChest
+	SlotHead
+	SlotOffHand
+	SlotBody
+)
+
+// Equippable makes the item worn in the slot.
+type Equippable struct {
+	Slot       pk.VarInt
+	EquipSound Sound
+	// AssetID is the equipment asset rendered when worn.
+	AssetID       pk.Option[pk.Identifier, *pk.Identifier]
+	CameraOverlay pk.Option[pk.Identifier, *pk.Identifier]
+	// AllowedEntities are the entity types which can wear the item, any if it's nil.
+	AllowedEntities *IDSet
+	Dispensable     pk.Boolean
+	Swappable       pk.Boolean
+	DamageOnHurt    pk.Boolean
+}
+
+// ID implements DataComponent.
+func (Equippable) ID() string {
+	return "minecraft:equippable"
+}
+
+// ReadFrom implements DataComponent.
+func (e *Equippable) ReadFrom(r io.Reader) (n int64, err error) {
+	var hasAllowedEntities pk.Boolean
+	return pk.Tuple{
+		&e.Slot,
+		&e.EquipSound,
+		&e.AssetID,
+		&e.CameraOverlay,
+		&hasAllowedEntities,
+		pk.Opt{Has: &hasAllowedEntities, Field: func() pk.FieldDecoder {
+			e.AllowedEntities = new(IDSet)
+			return e.AllowedEntities
+		}},
+		&e.Dispensable,
+		&e.Swappable,
+		&e.DamageOnHurt,
+	}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (e *Equippable) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{
+		&e.Slot,
+		&e.EquipSound,
+		&e.AssetID,
+		&e.CameraOverlay,
+		pk.Boolean(e.AllowedEntities != nil),
+		pk.Opt{Has: e.AllowedEntities != nil, Field: e.AllowedEntities},
+		&e.Dispensable,
+		&e.Swappable,
+		&e.DamageOnHurt,
+	}.WriteTo(w)
+}
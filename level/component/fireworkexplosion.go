@@ -0,0 +1,54 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*FireworkExplosion)(nil)
+
+// The shapes of FireworkExplosion.
+const (
+	SmallBall int32 = iota
+	LargeBall
+	Star
+	Creeper
+	Burst
+)
+
+// FireworkExplosion is the explosion of a firework star, or one of the explosions of a firework rocket.
+type FireworkExplosion struct {
+	Shape      pk.VarInt
+	Colors     []pk.Int
+	FadeColors []pk.Int
+	HasTrail   pk.Boolean
+	HasTwinkle pk.Boolean
+}
+
+// ID implements DataComponent.
+func (FireworkExplosion) ID() string {
+	return "minecraft:firework_explosion"
+}
+
+// ReadFrom implements DataComponent.
+func (f *FireworkExplosion) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{
+		&f.Shape,
+		pk.Array(&f.Colors),
+		pk.Array(&f.FadeColors),
+		&f.HasTrail,
+		&f.HasTwinkle,
+	}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (f FireworkExplosion) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{
+		&f.Shape,
+		pk.Array(f.Colors),
+		pk.Array(f.FadeColors),
+		&f.HasTrail,
+		&f.HasTwinkle,
+	}.WriteTo(w)
+}
@@ -0,0 +1,30 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*Fireworks)(nil)
+
+// Fireworks are the properties of a firework rocket.
+type Fireworks struct {
+	FlightDuration pk.VarInt
+	Explosions     []FireworkExplosion
+}
+
+// ID implements DataComponent.
+func (Fireworks) ID() string {
+	return "minecraft:fireworks"
+}
+
+// ReadFrom implements DataComponent.
+func (f *Fireworks) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{&f.FlightDuration, pk.Array(&f.Explosions)}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (f *Fireworks) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{&f.FlightDuration, pk.Array(&f.Explosions)}.WriteTo(w)
+}
@@ -8,13 +8,11 @@ import (
 
 var _ DataComponent = (*Food)(nil)
 
+// Food is the nutrition of an item, which is eaten as defined by its Consumable component.
 type Food struct {
 	Nutrition    pk.VarInt
 	Saturation   pk.Float
 	CanAlwaysEat pk.Boolean
-	EatSeconds   pk.Float
-	// TODO: using_converts_to
-	// TODO: effects
 }
 
 // ID implements DataComponent.
@@ -24,24 +22,18 @@ func (Food) ID() string {
 
 // ReadFrom implements DataComponent.
 func (f *Food) ReadFrom(r io.Reader) (n int64, err error) {
-	pk.Tuple{
+	return pk.Tuple{
 		&f.Nutrition,
 		&f.Saturation,
 		&f.CanAlwaysEat,
-		&f.EatSeconds,
-		// TODO
 	}.ReadFrom(r)
-	panic("unimplemented")
 }
 
 // WriteTo implements DataComponent.
 func (f *Food) WriteTo(w io.Writer) (n int64, err error) {
-	pk.Tuple{
+	return pk.Tuple{
 		&f.Nutrition,
 		&f.Saturation,
 		&f.CanAlwaysEat,
-		&f.EatSeconds,
-		// TODO
 	}.WriteTo(w)
-	panic("unimplemented")
 }
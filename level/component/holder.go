@@ -0,0 +1,109 @@
+package component
+
+import (
+	"errors"
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Holder is a registry entry which is referenced by its ID,
+// or defined inline if Inline isn't nil, known as "ID or X" on the wire.
+type Holder[T pk.FieldEncoder, P interface {
+	*T
+	pk.FieldDecoder
+}] struct {
+	ID     int32
+	Inline *T
+}
+
+func (h Holder[T, P]) WriteTo(w io.Writer) (n int64, err error) {
+	if h.Inline == nil {
+		return pk.VarInt(h.ID + 1).WriteTo(w)
+	}
+	n1, err := pk.VarInt(0).WriteTo(w)
+	if err != nil {
+		return n1, err
+	}
+	n2, err := (*h.Inline).WriteTo(w)
+	return n1 + n2, err
+}
+
+func (h *Holder[T, P]) ReadFrom(r io.Reader) (n int64, err error) {
+	var id pk.VarInt
+	n1, err := id.ReadFrom(r)
+	if err != nil || id != 0 {
+		h.ID, h.Inline = int32(id)-1, nil
+		return n1, err
+	}
+	h.ID, h.Inline = 0, new(T)
+	n2, err := P(h.Inline).ReadFrom(r)
+	return n1 + n2, err
+}
+
+// SoundEvent is a sound defined inline.
+type SoundEvent struct {
+	SoundName  pk.Identifier
+	FixedRange pk.Option[pk.Float, *pk.Float]
+}
+
+func (s SoundEvent) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&s.SoundName, &s.FixedRange}.WriteTo(w)
+}
+
+func (s *SoundEvent) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&s.SoundName, &s.FixedRange}.ReadFrom(r)
+}
+
+// Sound is a sound of the minecraft:sound_event registry, or defined inline.
+type Sound = Holder[SoundEvent, *SoundEvent]
+
+// IDSet is a set of registry entries, either a tag or a list of IDs.
+type IDSet struct {
+	// Tag is the name of the tag, without the #. The IDs are used if it's empty.
+	Tag pk.Identifier
+	IDs []pk.VarInt
+}
+
+func (s IDSet) WriteTo(w io.Writer) (n int64, err error) {
+	if s.Tag != "" {
+		return pk.Tuple{pk.VarInt(0), s.Tag}.WriteTo(w)
+	}
+	n, err = pk.VarInt(len(s.IDs) + 1).WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	for _, id := range s.IDs {
+		n1, err := id.WriteTo(w)
+		n += n1
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (s *IDSet) ReadFrom(r io.Reader) (n int64, err error) {
+	var typ pk.VarInt
+	n, err = typ.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	s.Tag, s.IDs = "", nil
+	if typ < 0 {
+		return n, errors.New("negative ID set length")
+	}
+	if typ == 0 {
+		n1, err := s.Tag.ReadFrom(r)
+		return n + n1, err
+	}
+	s.IDs = make([]pk.VarInt, typ-1)
+	for i := range s.IDs {
+		n1, err := s.IDs[i].ReadFrom(r)
+		n += n1
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
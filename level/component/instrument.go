@@ -3,83 +3,34 @@ package component
 import (
 	"io"
 
+	"github.com/mrhaoxx/go-mc/chat"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
 
 var _ DataComponent = (*Instrument)(nil)
 
-// TODO
+// Instrument is the sound of a goat horn.
 type Instrument struct {
-	Type        pk.VarInt
-	SoundEvent  SoundEvent
-	UseDuration pk.Float
-	Range       pk.Float
-}
-
-// ID implements DataComponent.
-func (Instrument) ID() string {
-	return "minecraft:instrument"
+	Holder[InstrumentData, *InstrumentData]
 }
 
-// ReadFrom implements DataComponent.
-func (i *Instrument) ReadFrom(r io.Reader) (n int64, err error) {
-	return pk.Tuple{
-		&i.Type,
-		pk.Opt{
-			Has: func() bool { return i.Type == 0 },
-			Field: pk.Tuple{
-				&i.SoundEvent,
-				&i.UseDuration,
-				&i.Range,
-			},
-		},
-	}.ReadFrom(r)
-}
-
-// WriteTo implements DataComponent.
-func (i *Instrument) WriteTo(w io.Writer) (n int64, err error) {
-	return pk.Tuple{
-		&i.Type,
-		pk.Opt{
-			Has: func() bool { return i.Type == 0 },
-			Field: pk.Tuple{
-				&i.SoundEvent,
-				&i.UseDuration,
-				&i.Range,
-			},
-		},
-	}.WriteTo(w)
+// InstrumentData is an entry of the minecraft:instrument registry.
+type InstrumentData struct {
+	SoundEvent  Sound
+	UseDuration pk.Float
+	Range       pk.Float
+	Description chat.Message
 }
 
-// TODO
-type SoundEvent struct {
-	Type       pk.VarInt
-	SoundName  pk.Identifier
-	FixedRange pk.Option[pk.Float, *pk.Float]
+func (i InstrumentData) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&i.SoundEvent, &i.UseDuration, &i.Range, &i.Description}.WriteTo(w)
 }
 
-func (s *SoundEvent) ReadFrom(r io.Reader) (int64, error) {
-	return pk.Tuple{
-		&s.Type,
-		pk.Opt{
-			Has: func() bool { return s.Type == 0 },
-			Field: pk.Tuple{
-				&s.SoundName,
-				&s.FixedRange,
-			},
-		},
-	}.ReadFrom(r)
+func (i *InstrumentData) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&i.SoundEvent, &i.UseDuration, &i.Range, &i.Description}.ReadFrom(r)
 }
 
-func (s SoundEvent) WriteTo(w io.Writer) (int64, error) {
-	return pk.Tuple{
-		&s.Type,
-		pk.Opt{
-			Has: func() bool { return s.Type == 0 },
-			Field: pk.Tuple{
-				&s.SoundName,
-				&s.FixedRange,
-			},
-		},
-	}.WriteTo(w)
+// ID implements DataComponent.
+func (Instrument) ID() string {
+	return "minecraft:instrument"
 }
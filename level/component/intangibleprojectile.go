@@ -1,9 +1,14 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*IntangibleProjectile)(nil)
 
+// IntangibleProjectile has no value, but unlike the other empty components it's sent as an empty NBT compound.
 type IntangibleProjectile struct{}
 
 // ID implements DataComponent.
@@ -13,10 +18,10 @@ func (IntangibleProjectile) ID() string {
 
 // ReadFrom implements DataComponent.
 func (i *IntangibleProjectile) ReadFrom(r io.Reader) (n int64, err error) {
-	return 0, nil
+	return pk.NBTField{V: &struct{}{}, AllowUnknownFields: true}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (i *IntangibleProjectile) WriteTo(w io.Writer) (n int64, err error) {
-	return 0, nil
+	return pk.NBT(struct{}{}).WriteTo(w)
 }
@@ -0,0 +1,29 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*ItemModel)(nil)
+
+// ItemModel is the item model definition used to render the item.
+type ItemModel struct {
+	Model pk.Identifier
+}
+
+// ID implements DataComponent.
+func (ItemModel) ID() string {
+	return "minecraft:item_model"
+}
+
+// ReadFrom implements DataComponent.
+func (i *ItemModel) ReadFrom(r io.Reader) (n int64, err error) {
+	return i.Model.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (i *ItemModel) WriteTo(w io.Writer) (n int64, err error) {
+	return i.Model.WriteTo(w)
+}
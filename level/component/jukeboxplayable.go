@@ -2,12 +2,36 @@ package component
 
 import (
 	"io"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
 
 var _ DataComponent = (*JukeboxPlayable)(nil)
 
+// JukeboxPlayable is the song played when the item is put in a jukebox.
 type JukeboxPlayable struct {
-	// TODO
+	// Song is used if SongName is empty.
+	Song Holder[JukeboxSong, *JukeboxSong]
+	// SongName is the key of the song in the minecraft:jukebox_song registry.
+	SongName      pk.Identifier
+	ShowInTooltip pk.Boolean
+}
+
+// JukeboxSong is an entry of the minecraft:jukebox_song registry.
+type JukeboxSong struct {
+	SoundEvent       Sound
+	Description      chat.Message
+	LengthInSeconds  pk.Float
+	ComparatorOutput pk.VarInt
+}
+
+func (j JukeboxSong) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&j.SoundEvent, &j.Description, &j.LengthInSeconds, &j.ComparatorOutput}.WriteTo(w)
+}
+
+func (j *JukeboxSong) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&j.SoundEvent, &j.Description, &j.LengthInSeconds, &j.ComparatorOutput}.ReadFrom(r)
 }
 
 // ID implements DataComponent.
@@ -17,10 +41,22 @@ func (JukeboxPlayable) ID() string {
 
 // ReadFrom implements DataComponent.
 func (j *JukeboxPlayable) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	var hasHolder pk.Boolean
+	return pk.Tuple{
+		&hasHolder,
+		pk.Opt{Has: &hasHolder, Field: &j.Song},
+		pk.Opt{Has: func() bool { return !bool(hasHolder) }, Field: &j.SongName},
+		&j.ShowInTooltip,
+	}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (j *JukeboxPlayable) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	hasHolder := j.SongName == ""
+	return pk.Tuple{
+		pk.Boolean(hasHolder),
+		pk.Opt{Has: hasHolder, Field: &j.Song},
+		pk.Opt{Has: !hasHolder, Field: &j.SongName},
+		&j.ShowInTooltip,
+	}.WriteTo(w)
 }
@@ -0,0 +1,30 @@
+package component
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*LockCode)(nil)
+
+// LockCode is the minecraft:lock component, the item predicate of the key opening a container.
+type LockCode struct {
+	dynbt.Value
+}
+
+// ID implements DataComponent.
+func (LockCode) ID() string {
+	return "minecraft:lock"
+}
+
+// ReadFrom implements DataComponent.
+func (l *LockCode) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.NBT(&l.Value).ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (l *LockCode) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.NBT(&l.Value).WriteTo(w)
+}
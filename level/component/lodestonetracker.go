@@ -12,7 +12,8 @@ type LodestoneTracker struct {
 	HasGlobalPosition pk.Boolean
 	Dimension         pk.Identifier
 	Position          pk.Position
-	Tracked           pk.Boolean
+	// Tracked is whether the component is removed when the lodestone is broken.
+	Tracked pk.Boolean
 }
 
 // ID implements DataComponent.
@@ -24,8 +25,7 @@ func (LodestoneTracker) ID() string {
 func (l *LodestoneTracker) ReadFrom(r io.Reader) (n int64, err error) {
 	return pk.Tuple{
 		&l.HasGlobalPosition,
-		&l.Dimension,
-		&l.Position,
+		pk.Opt{Has: &l.HasGlobalPosition, Field: pk.Tuple{&l.Dimension, &l.Position}},
 		&l.Tracked,
 	}.ReadFrom(r)
 }
@@ -34,8 +34,7 @@ func (l *LodestoneTracker) ReadFrom(r io.Reader) (n int64, err error) {
 func (l *LodestoneTracker) WriteTo(w io.Writer) (n int64, err error) {
 	return pk.Tuple{
 		&l.HasGlobalPosition,
-		&l.Dimension,
-		&l.Position,
+		pk.Opt{Has: &l.HasGlobalPosition, Field: pk.Tuple{&l.Dimension, &l.Position}},
 		&l.Tracked,
 	}.WriteTo(w)
 }
@@ -0,0 +1,29 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*NoteBlockSound)(nil)
+
+// NoteBlockSound is the sound played by a note block when the player head is placed on it.
+type NoteBlockSound struct {
+	Sound pk.Identifier
+}
+
+// ID implements DataComponent.
+func (NoteBlockSound) ID() string {
+	return "minecraft:note_block_sound"
+}
+
+// ReadFrom implements DataComponent.
+func (s *NoteBlockSound) ReadFrom(r io.Reader) (n int64, err error) {
+	return s.Sound.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (s *NoteBlockSound) WriteTo(w io.Writer) (n int64, err error) {
+	return s.Sound.WriteTo(w)
+}
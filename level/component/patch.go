@@ -0,0 +1,109 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Patch is the difference between the components of an item stack and the default components of its item.
+// It's sent with the item in a Slot.
+type Patch struct {
+	// Added are the components set on the item stack, replacing the default ones of the same type.
+	Added []DataComponent
+	// Removed are the type IDs of the default components removed from the item stack.
+	Removed []int32
+}
+
+func (p Patch) WriteTo(w io.Writer) (n int64, err error) {
+	fields := pk.Tuple{pk.VarInt(len(p.Added)), pk.VarInt(len(p.Removed))}
+	for _, c := range p.Added {
+		id, ok := TypeID(c)
+		if !ok {
+			return 0, fmt.Errorf("unknown data component %q", c.ID())
+		}
+		fields = append(fields, pk.VarInt(id), c)
+	}
+	for _, id := range p.Removed {
+		fields = append(fields, pk.VarInt(id))
+	}
+	return fields.WriteTo(w)
+}
+
+func (p *Patch) ReadFrom(r io.Reader) (n int64, err error) {
+	var added, removed pk.VarInt
+	n, err = pk.Tuple{&added, &removed}.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if added < 0 || removed < 0 {
+		return n, errors.New("negative number of components")
+	}
+	p.Added, p.Removed = nil, nil
+	if added > 0 {
+		p.Added = make([]DataComponent, added)
+	}
+	for i := range p.Added {
+		var id pk.VarInt
+		n1, err := id.ReadFrom(r)
+		n += n1
+		if err != nil {
+			return n, err
+		}
+		c := NewComponent(int32(id))
+		if c == nil {
+			return n, fmt.Errorf("unknown data component type %d", id)
+		}
+		n1, err = c.ReadFrom(r)
+		n += n1
+		if err != nil {
+			return n, fmt.Errorf("read data component %s: %w", c.ID(), err)
+		}
+		p.Added[i] = c
+	}
+	if removed > 0 {
+		p.Removed = make([]int32, removed)
+	}
+	for i := range p.Removed {
+		var id pk.VarInt
+		n1, err := id.ReadFrom(r)
+		n += n1
+		if err != nil {
+			return n, err
+		}
+		p.Removed[i] = int32(id)
+	}
+	return n, nil
+}
+
+// Slot is an item stack in the network format, with its components as a Patch.
+// The components holding items, like the contents of a bundle, use it.
+type Slot struct {
+	// Count is the number of items, the stack is empty if it's zero.
+	Count int32
+	// ItemID is the ID in the minecraft:item registry.
+	ItemID int32
+	Patch  Patch
+}
+
+func (s Slot) WriteTo(w io.Writer) (int64, error) {
+	if s.Count <= 0 {
+		return pk.VarInt(0).WriteTo(w)
+	}
+	return pk.Tuple{pk.VarInt(s.Count), pk.VarInt(s.ItemID), &s.Patch}.WriteTo(w)
+}
+
+func (s *Slot) ReadFrom(r io.Reader) (n int64, err error) {
+	var count pk.VarInt
+	n, err = count.ReadFrom(r)
+	if err != nil || count <= 0 {
+		*s = Slot{}
+		return n, err
+	}
+	var itemID pk.VarInt
+	n1, err := pk.Tuple{&itemID, &s.Patch}.ReadFrom(r)
+	s.Count, s.ItemID = int32(count), int32(itemID)
+	return n + n1, err
+}
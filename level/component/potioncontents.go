@@ -9,9 +9,12 @@ import (
 var _ DataComponent = (*PotionContents)(nil)
 
 type PotionContents struct {
+	// PotionID is the ID in the minecraft:potion registry.
 	PotionID      pk.Option[pk.VarInt, *pk.VarInt]
 	CustomColor   pk.Option[pk.Int, *pk.Int]
-	PotionEffects []any
+	CustomEffects []PotionEffect
+	// CustomName is the suffix of the translation key of the item name.
+	CustomName pk.Option[pk.String, *pk.String]
 }
 
 // ID implements DataComponent.
@@ -21,10 +24,20 @@ func (PotionContents) ID() string {
 
 // ReadFrom implements DataComponent.
 func (p *PotionContents) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{
+		&p.PotionID,
+		&p.CustomColor,
+		pk.Array(&p.CustomEffects),
+		&p.CustomName,
+	}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (p *PotionContents) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{
+		&p.PotionID,
+		&p.CustomColor,
+		pk.Array(&p.CustomEffects),
+		&p.CustomName,
+	}.WriteTo(w)
 }
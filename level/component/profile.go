@@ -0,0 +1,46 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*Profile)(nil)
+
+// Profile is the game profile of a player head.
+type Profile struct {
+	Name       pk.Option[pk.String, *pk.String]
+	UUID       pk.Option[pk.UUID, *pk.UUID]
+	Properties []ProfileProperty
+}
+
+// ProfileProperty is a property of a game profile, like the skin textures.
+type ProfileProperty struct {
+	Name      pk.String
+	Value     pk.String
+	Signature pk.Option[pk.String, *pk.String]
+}
+
+func (p ProfileProperty) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&p.Name, &p.Value, &p.Signature}.WriteTo(w)
+}
+
+func (p *ProfileProperty) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&p.Name, &p.Value, &p.Signature}.ReadFrom(r)
+}
+
+// ID implements DataComponent.
+func (Profile) ID() string {
+	return "minecraft:profile"
+}
+
+// ReadFrom implements DataComponent.
+func (p *Profile) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{&p.Name, &p.UUID, pk.Array(&p.Properties)}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (p *Profile) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{&p.Name, &p.UUID, pk.Array(&p.Properties)}.WriteTo(w)
+}
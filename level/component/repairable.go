@@ -0,0 +1,25 @@
+package component
+
+import "io"
+
+var _ DataComponent = (*Repairable)(nil)
+
+// Repairable are the items repairing the item in an anvil.
+type Repairable struct {
+	Items IDSet
+}
+
+// ID implements DataComponent.
+func (Repairable) ID() string {
+	return "minecraft:repairable"
+}
+
+// ReadFrom implements DataComponent.
+func (r *Repairable) ReadFrom(reader io.Reader) (n int64, err error) {
+	return r.Items.ReadFrom(reader)
+}
+
+// WriteTo implements DataComponent.
+func (r *Repairable) WriteTo(writer io.Writer) (n int64, err error) {
+	return r.Items.WriteTo(writer)
+}
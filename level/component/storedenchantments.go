@@ -9,10 +9,7 @@ import (
 var _ DataComponent = (*StoredEnchantments)(nil)
 
 type StoredEnchantments struct {
-	Enchantments []struct {
-		Type  pk.VarInt
-		Level pk.VarInt
-	}
+	Enchantments  []EnchantmentLevel
 	ShowInTooltip pk.Boolean
 }
 
@@ -2,12 +2,29 @@ package component
 
 import (
 	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
 
 var _ DataComponent = (*SuspiciousStewEffects)(nil)
 
 type SuspiciousStewEffects struct {
-	Effects []any
+	Effects []StewEffect
+}
+
+// StewEffect is an effect given by eating a suspicious stew.
+type StewEffect struct {
+	// TypeID is the ID in the minecraft:mob_effect registry.
+	TypeID   pk.VarInt
+	Duration pk.VarInt
+}
+
+func (s StewEffect) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&s.TypeID, &s.Duration}.WriteTo(w)
+}
+
+func (s *StewEffect) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&s.TypeID, &s.Duration}.ReadFrom(r)
 }
 
 // ID implements DataComponent.
@@ -17,10 +34,10 @@ func (SuspiciousStewEffects) ID() string {
 
 // ReadFrom implements DataComponent.
 func (s *SuspiciousStewEffects) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Array(&s.Effects).ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (s *SuspiciousStewEffects) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Array(&s.Effects).WriteTo(w)
 }
@@ -1,10 +1,35 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*Tool)(nil)
 
-type Tool struct{}
+// Tool is how the item mines blocks.
+type Tool struct {
+	Rules              []ToolRule
+	DefaultMiningSpeed pk.Float
+	// DamagePerBlock is the durability used when a block is mined.
+	DamagePerBlock pk.VarInt
+}
+
+// ToolRule overrides the mining speed or the drops of the blocks.
+type ToolRule struct {
+	Blocks         IDSet
+	Speed          pk.Option[pk.Float, *pk.Float]
+	CorrectForDrop pk.Option[pk.Boolean, *pk.Boolean]
+}
+
+func (t ToolRule) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&t.Blocks, &t.Speed, &t.CorrectForDrop}.WriteTo(w)
+}
+
+func (t *ToolRule) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&t.Blocks, &t.Speed, &t.CorrectForDrop}.ReadFrom(r)
+}
 
 // ID implements DataComponent.
 func (Tool) ID() string {
@@ -13,10 +38,18 @@ func (Tool) ID() string {
 
 // ReadFrom implements DataComponent.
 func (t *Tool) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{
+		pk.Array(&t.Rules),
+		&t.DefaultMiningSpeed,
+		&t.DamagePerBlock,
+	}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (t *Tool) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{
+		pk.Array(&t.Rules),
+		&t.DefaultMiningSpeed,
+		&t.DamagePerBlock,
+	}.WriteTo(w)
 }
@@ -0,0 +1,29 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*TooltipStyle)(nil)
+
+// TooltipStyle is the prefix of the sprites used for the background and frame of the tooltip.
+type TooltipStyle struct {
+	Style pk.Identifier
+}
+
+// ID implements DataComponent.
+func (TooltipStyle) ID() string {
+	return "minecraft:tooltip_style"
+}
+
+// ReadFrom implements DataComponent.
+func (t *TooltipStyle) ReadFrom(r io.Reader) (n int64, err error) {
+	return t.Style.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (t *TooltipStyle) WriteTo(w io.Writer) (n int64, err error) {
+	return t.Style.WriteTo(w)
+}
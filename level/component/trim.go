@@ -1,10 +1,78 @@
 package component
 
-import "io"
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
 
 var _ DataComponent = (*Trim)(nil)
 
+// Trim is the armor trim of an item.
 type Trim struct {
+	Material      Holder[TrimMaterial, *TrimMaterial]
+	Pattern       Holder[TrimPattern, *TrimPattern]
+	ShowInTooltip pk.Boolean
+}
+
+// TrimMaterial is an entry of the minecraft:trim_material registry.
+type TrimMaterial struct {
+	AssetName pk.String
+	// Ingredient is the ID of the item in the minecraft:item registry.
+	Ingredient pk.VarInt
+	// OverrideArmorAssets are the asset names used instead of AssetName for the equipment assets.
+	OverrideArmorAssets []ArmorAssetOverride
+	Description         chat.Message
+}
+
+// ArmorAssetOverride is the asset name of a trim material for an equipment asset.
+type ArmorAssetOverride struct {
+	Asset     pk.Identifier
+	AssetName pk.String
+}
+
+func (a ArmorAssetOverride) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&a.Asset, &a.AssetName}.WriteTo(w)
+}
+
+func (a *ArmorAssetOverride) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&a.Asset, &a.AssetName}.ReadFrom(r)
+}
+
+func (t TrimMaterial) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		&t.AssetName,
+		&t.Ingredient,
+		pk.Array(t.OverrideArmorAssets),
+		&t.Description,
+	}.WriteTo(w)
+}
+
+func (t *TrimMaterial) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{
+		&t.AssetName,
+		&t.Ingredient,
+		pk.Array(&t.OverrideArmorAssets),
+		&t.Description,
+	}.ReadFrom(r)
+}
+
+// TrimPattern is an entry of the minecraft:trim_pattern registry.
+type TrimPattern struct {
+	AssetName pk.Identifier
+	// TemplateItem is the ID of the smithing template in the minecraft:item registry.
+	TemplateItem pk.VarInt
+	Description  chat.Message
+	Decal        pk.Boolean
+}
+
+func (t TrimPattern) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&t.AssetName, &t.TemplateItem, &t.Description, &t.Decal}.WriteTo(w)
+}
+
+func (t *TrimPattern) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&t.AssetName, &t.TemplateItem, &t.Description, &t.Decal}.ReadFrom(r)
 }
 
 // ID implements DataComponent.
@@ -14,10 +82,10 @@ func (Trim) ID() string {
 
 // ReadFrom implements DataComponent.
 func (t *Trim) ReadFrom(r io.Reader) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{&t.Material, &t.Pattern, &t.ShowInTooltip}.ReadFrom(r)
 }
 
 // WriteTo implements DataComponent.
 func (t *Trim) WriteTo(w io.Writer) (n int64, err error) {
-	panic("unimplemented")
+	return pk.Tuple{&t.Material, &t.Pattern, &t.ShowInTooltip}.WriteTo(w)
 }
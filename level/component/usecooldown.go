@@ -0,0 +1,31 @@
+package component
+
+import (
+	"io"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*UseCooldown)(nil)
+
+// UseCooldown puts the items of the group on cooldown after the item is used.
+type UseCooldown struct {
+	Seconds pk.Float
+	// CooldownGroup defaults to the item id.
+	CooldownGroup pk.Option[pk.Identifier, *pk.Identifier]
+}
+
+// ID implements DataComponent.
+func (UseCooldown) ID() string {
+	return "minecraft:use_cooldown"
+}
+
+// ReadFrom implements DataComponent.
+func (u *UseCooldown) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{&u.Seconds, &u.CooldownGroup}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (u *UseCooldown) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{&u.Seconds, &u.CooldownGroup}.WriteTo(w)
+}
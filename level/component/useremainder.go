@@ -0,0 +1,25 @@
+package component
+
+import "io"
+
+var _ DataComponent = (*UseRemainder)(nil)
+
+// UseRemainder is the item left after the item is used up, like the bowl of a stew.
+type UseRemainder struct {
+	Item Slot
+}
+
+// ID implements DataComponent.
+func (UseRemainder) ID() string {
+	return "minecraft:use_remainder"
+}
+
+// ReadFrom implements DataComponent.
+func (u *UseRemainder) ReadFrom(r io.Reader) (n int64, err error) {
+	return u.Item.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (u *UseRemainder) WriteTo(w io.Writer) (n int64, err error) {
+	return u.Item.WriteTo(w)
+}
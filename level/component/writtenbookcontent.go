@@ -0,0 +1,64 @@
+package component
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ DataComponent = (*WrittenBookContent)(nil)
+
+type WrittenBookContent struct {
+	RawTitle      pk.String
+	FilteredTitle pk.Option[pk.String, *pk.String]
+	Author        pk.String
+	// Generation is 0 for the original, 1 for a copy of the original, 2 for a copy of a copy and 3 for tattered.
+	Generation pk.VarInt
+	Pages      []TextPage
+	// Resolved is whether the text components of the pages have been resolved.
+	Resolved pk.Boolean
+}
+
+// TextPage is a page of a written book.
+type TextPage struct {
+	Raw      chat.Message
+	Filtered pk.Option[chat.Message, *chat.Message]
+}
+
+func (p TextPage) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{&p.Raw, &p.Filtered}.WriteTo(w)
+}
+
+func (p *TextPage) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&p.Raw, &p.Filtered}.ReadFrom(r)
+}
+
+// ID implements DataComponent.
+func (WrittenBookContent) ID() string {
+	return "minecraft:written_book_content"
+}
+
+// ReadFrom implements DataComponent.
+func (b *WrittenBookContent) ReadFrom(r io.Reader) (n int64, err error) {
+	return pk.Tuple{
+		&b.RawTitle,
+		&b.FilteredTitle,
+		&b.Author,
+		&b.Generation,
+		pk.Array(&b.Pages),
+		&b.Resolved,
+	}.ReadFrom(r)
+}
+
+// WriteTo implements DataComponent.
+func (b *WrittenBookContent) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Tuple{
+		&b.RawTitle,
+		&b.FilteredTitle,
+		&b.Author,
+		&b.Generation,
+		pk.Array(&b.Pages),
+		&b.Resolved,
+	}.WriteTo(w)
+}
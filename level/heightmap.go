@@ -0,0 +1,146 @@
+package level
+
+import (
+	"math/bits"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// HeightMapKind is one of the heightmaps stored for a chunk.
+// A heightmap records, for each column, the lowest y above the highest block passing the test of its kind.
+type HeightMapKind uint8
+
+const (
+	WorldSurface HeightMapKind = iota
+	OceanFloor
+	MotionBlocking
+	MotionBlockingNoLeaves
+)
+
+// HeightMapKinds are the heightmaps kept up to date for the full chunks.
+var HeightMapKinds = [...]HeightMapKind{WorldSurface, OceanFloor, MotionBlocking, MotionBlockingNoLeaves}
+
+func (k HeightMapKind) String() string {
+	switch k {
+	case WorldSurface:
+		return "WORLD_SURFACE"
+	case OceanFloor:
+		return "OCEAN_FLOOR"
+	case MotionBlocking:
+		return "MOTION_BLOCKING"
+	case MotionBlockingNoLeaves:
+		return "MOTION_BLOCKING_NO_LEAVES"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Test reports whether the block is counted by the heightmap.
+func (k HeightMapKind) Test(s BlocksState) bool {
+	switch k {
+	case WorldSurface:
+		return !block.IsAir(s)
+	case OceanFloor:
+		return block.ShapeOf(s) != block.ShapeEmpty
+	case MotionBlocking:
+		return block.ShapeOf(s) != block.ShapeEmpty || hasFluid(s)
+	case MotionBlockingNoLeaves:
+		return (block.ShapeOf(s) != block.ShapeEmpty || hasFluid(s)) &&
+			!strings.HasSuffix(block.StateList[s].ID(), "_leaves")
+	default:
+		return false
+	}
+}
+
+func hasFluid(s BlocksState) bool {
+	if block.IsWaterlogged(s) {
+		return true
+	}
+	_, ok := block.StateList[s].(block.Lava)
+	return ok
+}
+
+// NewHeightMaps returns empty heightmaps for a chunk of the height in blocks.
+func NewHeightMaps(height int) HeightMaps {
+	bitsForHeight := bits.Len(uint(height) + 1)
+	return HeightMaps{
+		WorldSurfaceWG:         NewBitStorage(bitsForHeight, 16*16, nil),
+		WorldSurface:           NewBitStorage(bitsForHeight, 16*16, nil),
+		OceanFloorWG:           NewBitStorage(bitsForHeight, 16*16, nil),
+		OceanFloor:             NewBitStorage(bitsForHeight, 16*16, nil),
+		MotionBlocking:         NewBitStorage(bitsForHeight, 16*16, nil),
+		MotionBlockingNoLeaves: NewBitStorage(bitsForHeight, 16*16, nil),
+	}
+}
+
+// heightMapFromSave reads a saved heightmap, missing or malformed data gives an empty one.
+func heightMapFromSave(bitsForHeight int, data []uint64) *BitStorage {
+	if len(data) != calcBitStorageSize(bitsForHeight, 16*16) {
+		data = nil
+	}
+	return NewBitStorage(bitsForHeight, 16*16, data)
+}
+
+// Get returns the heightmap of the kind.
+func (h *HeightMaps) Get(k HeightMapKind) *BitStorage {
+	switch k {
+	case WorldSurface:
+		return h.WorldSurface
+	case OceanFloor:
+		return h.OceanFloor
+	case MotionBlocking:
+		return h.MotionBlocking
+	case MotionBlockingNoLeaves:
+		return h.MotionBlockingNoLeaves
+	default:
+		return nil
+	}
+}
+
+// Compute fills the heightmaps by scanning every column from the top.
+// The height is the number of blocks of the chunk,
+// and get returns the block at the chunk relative coordinates, y counted from the bottom of the chunk.
+func (h *HeightMaps) Compute(height int, get func(x, y, z int) BlocksState) {
+	for _, k := range HeightMapKinds {
+		hm := h.Get(k)
+		for z := 0; z < 16; z++ {
+			for x := 0; x < 16; x++ {
+				hm.Set(z<<4|x, highest(k, x, height-1, z, get))
+			}
+		}
+	}
+}
+
+// Update updates the heightmaps after the block at the chunk relative coordinates has been set to s.
+func (h *HeightMaps) Update(x, y, z int, s BlocksState, get func(x, y, z int) BlocksState) {
+	i := z<<4 | x
+	for _, k := range HeightMapKinds {
+		hm := h.Get(k)
+		switch cur := hm.Get(i); {
+		case k.Test(s):
+			if y+1 > cur {
+				hm.Set(i, y+1)
+			}
+		case y+1 == cur:
+			hm.Set(i, highest(k, x, y-1, z, get))
+		}
+	}
+}
+
+// highest returns the height above the highest block passing the test at or below y.
+func highest(k HeightMapKind, x, y, z int, get func(x, y, z int) BlocksState) int {
+	for ; y >= 0; y-- {
+		if k.Test(get(x, y, z)) {
+			return y + 1
+		}
+	}
+	return 0
+}
+
+// ComputeHeightMaps fills the heightmaps of the chunk from its blocks.
+func (c *Chunk) ComputeHeightMaps() {
+	c.HeightMaps.Compute(len(c.Sections)*16, func(x, y, z int) BlocksState {
+		return c.Sections[y>>4].GetBlock((y&15)<<8 | z<<4 | x)
+	})
+}
@@ -0,0 +1,59 @@
+package level
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestHeightMaps_Update(t *testing.T) {
+	c := EmptyChunk(4)
+	states := []BlocksState{
+		block.ToStateID[block.Air{}],
+		block.ToStateID[block.Stone{}],
+		block.ToStateID[block.Water{}],
+		block.ToStateID[block.Torch{}],
+		block.ToStateID[block.OakLeaves{Distance: 7}],
+	}
+	get := func(x, y, z int) BlocksState {
+		return c.Sections[y>>4].GetBlock((y&15)<<8 | z<<4 | x)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y, z := r.Intn(16), r.Intn(64), r.Intn(16)
+		s := states[r.Intn(len(states))]
+		c.Sections[y>>4].SetBlock((y&15)<<8|z<<4|x, s)
+		c.HeightMaps.Update(x, y, z, s, get)
+	}
+
+	want := NewHeightMaps(64)
+	want.Compute(64, get)
+	for _, k := range HeightMapKinds {
+		for i := 0; i < 16*16; i++ {
+			if got, want := c.HeightMaps.Get(k).Get(i), want.Get(k).Get(i); got != want {
+				t.Fatalf("%v[%d]: want %d, got %d", k, i, want, got)
+			}
+		}
+	}
+}
+
+func TestHeightMapKind_Test(t *testing.T) {
+	for _, tc := range []struct {
+		block block.Block
+		want  [len(HeightMapKinds)]bool
+	}{
+		{block.Air{}, [...]bool{false, false, false, false}},
+		{block.Stone{}, [...]bool{true, true, true, true}},
+		{block.Water{}, [...]bool{true, false, true, true}},
+		{block.Torch{}, [...]bool{true, false, false, false}},
+		{block.OakLeaves{Distance: 7}, [...]bool{true, true, true, false}},
+	} {
+		s := block.ToStateID[tc.block]
+		for i, k := range HeightMapKinds {
+			if got := k.Test(s); got != tc.want[i] {
+				t.Errorf("%v.Test(%s): want %v, got %v", k, tc.block.ID(), tc.want[i], got)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package item
+
+import (
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+)
+
+var attributeModifiersType = mustTypeID(&component.AttributeModifiers{})
+
+// The IDs of the attributes in the minecraft:attribute registry.
+const (
+	attributeArmor int32 = iota
+	attributeArmorToughness
+)
+
+// armorMaterial is the armor points of the pieces of a material, by the slot from the feet to the head, and their toughness.
+type armorMaterial struct {
+	points    [4]float64
+	toughness float64
+}
+
+var armorMaterials = map[string]armorMaterial{
+	"leather":   {points: [4]float64{1, 2, 3, 1}},
+	"chainmail": {points: [4]float64{1, 4, 5, 2}},
+	"iron":      {points: [4]float64{2, 5, 6, 2}},
+	"golden":    {points: [4]float64{1, 3, 5, 2}},
+	"diamond":   {points: [4]float64{3, 6, 8, 3}, toughness: 2},
+	"netherite": {points: [4]float64{3, 6, 8, 3}, toughness: 3},
+	"turtle":    {points: [4]float64{0, 0, 0, 2}},
+}
+
+// Armor returns the armor points and toughness the stack gives to the player wearing it.
+// They're set by the armor modifiers of the minecraft:attribute_modifiers component,
+// otherwise they're guessed from the item name since the default components of the items aren't known.
+func (s ItemStack) Armor() (armor, toughness float64) {
+	if c, ok := s.Component(attributeModifiersType); ok {
+		for _, m := range c.(*component.AttributeModifiers).Modifiers {
+			if int32(m.Operation) != component.AddValue {
+				continue
+			}
+			switch int32(m.AttributeID) {
+			case attributeArmor:
+				armor += float64(m.Value)
+			case attributeArmorToughness:
+				toughness += float64(m.Value)
+			}
+		}
+		return
+	}
+	if _, removed := s.Components[attributeModifiersType]; removed {
+		return 0, 0
+	}
+	slot, ok := s.EquipmentSlot()
+	if !ok || slot < component.SlotFeet || slot > component.SlotHead {
+		return 0, 0
+	}
+	name := strings.TrimPrefix(s.ItemID.Name(), "minecraft:")
+	material, ok := armorMaterials[name[:max(strings.IndexByte(name, '_'), 0)]]
+	if !ok {
+		return 0, 0
+	}
+	return material.points[slot-component.SlotFeet], material.toughness
+}
@@ -0,0 +1,35 @@
+package item
+
+import "github.com/mrhaoxx/go-mc/level/component"
+
+var enchantmentsType = mustTypeID(&component.Enchantments{})
+
+// Enchantments are the names of the vanilla enchantments,
+// in the order of the minecraft:enchantment registry the client has from the vanilla data pack.
+var Enchantments = []string{
+	"minecraft:aqua_affinity", "minecraft:bane_of_arthropods", "minecraft:binding_curse", "minecraft:blast_protection",
+	"minecraft:breach", "minecraft:channeling", "minecraft:density", "minecraft:depth_strider",
+	"minecraft:efficiency", "minecraft:feather_falling", "minecraft:fire_aspect", "minecraft:fire_protection",
+	"minecraft:flame", "minecraft:fortune", "minecraft:frost_walker", "minecraft:impaling",
+	"minecraft:infinity", "minecraft:knockback", "minecraft:looting", "minecraft:loyalty",
+	"minecraft:luck_of_the_sea", "minecraft:lure", "minecraft:mending", "minecraft:multishot",
+	"minecraft:piercing", "minecraft:power", "minecraft:projectile_protection", "minecraft:protection",
+	"minecraft:punch", "minecraft:quick_charge", "minecraft:respiration", "minecraft:riptide",
+	"minecraft:sharpness", "minecraft:silk_touch", "minecraft:smite", "minecraft:soul_speed",
+	"minecraft:sweeping_edge", "minecraft:swift_sneak", "minecraft:thorns", "minecraft:unbreaking",
+	"minecraft:vanishing_curse", "minecraft:wind_burst",
+}
+
+// EnchantmentLevel returns the level of the enchantment of the name on the stack, 0 if it hasn't it.
+func (s ItemStack) EnchantmentLevel(name string) int32 {
+	c, ok := s.Component(enchantmentsType)
+	if !ok {
+		return 0
+	}
+	for _, e := range c.(*component.Enchantments).Enchantments {
+		if int(e.Type) >= 0 && int(e.Type) < len(Enchantments) && Enchantments[e.Type] == name {
+			return int32(e.Level)
+		}
+	}
+	return 0
+}
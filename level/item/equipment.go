@@ -0,0 +1,48 @@
+package item
+
+import (
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+)
+
+var equippableType = mustTypeID(&component.Equippable{})
+
+// EquipmentSlot returns the slot the stack is worn in, like component.SlotHead.
+// It's set by the minecraft:equippable component,
+// otherwise it's guessed from the item name since the default components of the items aren't known.
+func (s ItemStack) EquipmentSlot() (slot int32, ok bool) {
+	if c, ok := s.Component(equippableType); ok {
+		return int32(c.(*component.Equippable).Slot), true
+	}
+	if _, removed := s.Components[equippableType]; removed {
+		return 0, false
+	}
+	name := s.ItemID.Name()
+	switch {
+	case strings.HasSuffix(name, "_helmet"), strings.HasSuffix(name, "_head"), strings.HasSuffix(name, "_skull"),
+		name == "minecraft:carved_pumpkin":
+		return component.SlotHead, true
+	case strings.HasSuffix(name, "_chestplate"), name == "minecraft:elytra":
+		return component.SlotChest, true
+	case strings.HasSuffix(name, "_leggings"):
+		return component.SlotLegs, true
+	case strings.HasSuffix(name, "_boots"):
+		return component.SlotFeet, true
+	}
+	return 0, false
+}
+
+var gliderType = mustTypeID(&component.Glider{})
+
+// IsGlider reports whether the player wearing the stack on the chest can glide.
+// It's set by the minecraft:glider component, which the elytra has by default.
+func (s ItemStack) IsGlider() bool {
+	if _, ok := s.Component(gliderType); ok {
+		return true
+	}
+	if _, removed := s.Components[gliderType]; removed {
+		return false
+	}
+	return s.ItemID.Name() == "minecraft:elytra"
+}
@@ -0,0 +1,219 @@
+// Package item implements the item stacks in the inventories,
+// which are sent to the clients and stored in the save files.
+package item
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+
+	itemdata "github.com/mrhaoxx/go-mc/data/item"
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/level/component"
+)
+
+// ID is the id of an item in the minecraft:item registry.
+// It's stored as the name of the item in NBT.
+type ID int32
+
+// Air is the item of the empty stacks.
+const Air ID = 0
+
+var itemIDs = make(map[string]ID, len(registryid.Item))
+
+func init() {
+	for i, name := range registryid.Item {
+		itemIDs[name] = ID(i)
+	}
+}
+
+// Name returns the name of the item, like minecraft:stone.
+func (id ID) Name() string {
+	if id < 0 || int(id) >= len(registryid.Item) {
+		return ""
+	}
+	return registryid.Item[id]
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	name := id.Name()
+	if name == "" {
+		return nil, errors.New("invalid item id")
+	}
+	return []byte(name), nil
+}
+
+func (id *ID) UnmarshalText(text []byte) error {
+	v, ok := itemIDs[string(text)]
+	if !ok {
+		return errors.New("unknown item " + string(text))
+	}
+	*id = v
+	return nil
+}
+
+// ItemStack is a number of items of a type with their components.
+// The zero value is an empty stack.
+type ItemStack struct {
+	ItemID     ID         `nbt:"id"`
+	Count      int32      `nbt:"count"`
+	Components Components `nbt:"components,omitempty"`
+}
+
+// Components is the patch of an item stack over the default components of its item,
+// keyed by the id in the minecraft:data_component_type registry.
+// A nil component removes the default one.
+type Components map[int32]component.DataComponent
+
+// New returns a stack of count items without components.
+func New(id ID, count int32) ItemStack {
+	return ItemStack{ItemID: id, Count: count}
+}
+
+// IsEmpty reports whether the stack holds no item.
+func (s ItemStack) IsEmpty() bool {
+	return s.ItemID == Air || s.Count <= 0
+}
+
+// Component returns the component of the type set on the stack.
+func (s ItemStack) Component(typ int32) (c component.DataComponent, ok bool) {
+	c, ok = s.Components[typ]
+	return c, ok && c != nil
+}
+
+// Set sets the component on the stack, replacing the one of the same type.
+func (s *ItemStack) Set(c component.DataComponent) {
+	typ, ok := component.TypeID(c)
+	if !ok {
+		panic("item: unknown data component " + c.ID())
+	}
+	if s.Components == nil {
+		s.Components = make(Components)
+	}
+	s.Components[typ] = c
+}
+
+// Remove removes the component of the type, including the default one of the item.
+func (s *ItemStack) Remove(typ int32) {
+	if s.Components == nil {
+		s.Components = make(Components)
+	}
+	s.Components[typ] = nil
+}
+
+// Copy returns a stack with the same item and count, whose components can be changed separately.
+// The components themselves are shared.
+func (s ItemStack) Copy() ItemStack {
+	if s.Components != nil {
+		comps := make(Components, len(s.Components))
+		for k, v := range s.Components {
+			comps[k] = v
+		}
+		s.Components = comps
+	}
+	return s
+}
+
+// MaxStackSize returns the number of items a slot can hold,
+// set by the minecraft:max_stack_size component or the item.
+func (s ItemStack) MaxStackSize() int32 {
+	if c, ok := s.Component(maxStackSizeType); ok {
+		return int32(c.(*component.MaxStackSize).VarInt)
+	}
+	if item, ok := itemdata.ByID[itemdata.ID(s.ItemID)]; ok {
+		return int32(item.StackSize)
+	}
+	return 64
+}
+
+// IsStackable reports whether the stack can hold more than one item.
+func (s ItemStack) IsStackable() bool {
+	return s.MaxStackSize() > 1
+}
+
+// SameItem reports whether the stacks hold the same item, whatever their components.
+func SameItem(a, b ItemStack) bool {
+	return a.ItemID == b.ItemID
+}
+
+// SameItemSameComponents reports whether the stacks hold the same item with the same components,
+// so they can be merged.
+func SameItemSameComponents(a, b ItemStack) bool {
+	if a.ItemID != b.ItemID || len(a.Components) != len(b.Components) {
+		return false
+	}
+	for typ, c := range a.Components {
+		other, ok := b.Components[typ]
+		if !ok || !reflect.DeepEqual(c, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether the stacks are the same, including their counts.
+func Equal(a, b ItemStack) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return a.IsEmpty() == b.IsEmpty()
+	}
+	return a.Count == b.Count && SameItemSameComponents(a, b)
+}
+
+// Merge moves as many items of other as the stack can hold into it,
+// and returns the number of moved items.
+// The stack takes the item of other if it's empty.
+func (s *ItemStack) Merge(other *ItemStack) int32 {
+	if other.IsEmpty() {
+		return 0
+	}
+	if s.IsEmpty() {
+		*s = ItemStack{ItemID: other.ItemID, Components: other.Copy().Components}
+	} else if !SameItemSameComponents(*s, *other) {
+		return 0
+	}
+	n := min(other.Count, s.MaxStackSize()-s.Count)
+	if n <= 0 {
+		return 0
+	}
+	s.Count += n
+	other.Count -= n
+	if other.Count == 0 {
+		*other = ItemStack{}
+	}
+	return n
+}
+
+// Split takes at most n items out of the stack and returns them.
+func (s *ItemStack) Split(n int32) ItemStack {
+	n = min(n, s.Count)
+	if n <= 0 || s.IsEmpty() {
+		return ItemStack{}
+	}
+	split := s.Copy()
+	split.Count = n
+	s.Count -= n
+	if s.Count == 0 {
+		*s = ItemStack{}
+	}
+	return split
+}
+
+// sortedTypes returns the component types of the patch in order.
+func (c Components) sortedTypes() []int32 {
+	types := make([]int32, 0, len(c))
+	for typ := range c {
+		types = append(types, typ)
+	}
+	slices.Sort(types)
+	return types
+}
+
+var maxStackSizeType = mustTypeID(&component.MaxStackSize{})
+
+func mustTypeID(c component.DataComponent) int32 {
+	id, ok := component.TypeID(c)
+	if !ok {
+		panic("item: unknown data component " + c.ID())
+	}
+	return id
+}
@@ -0,0 +1,208 @@
+package item
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/nbt"
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+)
+
+func TestItemStack_MaxStackSize(t *testing.T) {
+	for _, tt := range []struct {
+		stack ItemStack
+		want  int32
+	}{
+		{New(mustID("minecraft:stone"), 1), 64},
+		{New(mustID("minecraft:ender_pearl"), 1), 16},
+		{New(mustID("minecraft:diamond_sword"), 1), 1},
+		{withComponents(New(mustID("minecraft:stone"), 1), &component.MaxStackSize{VarInt: 8}), 8},
+	} {
+		if got := tt.stack.MaxStackSize(); got != tt.want {
+			t.Errorf("max stack size of %s = %d, want %d", tt.stack.ItemID.Name(), got, tt.want)
+		}
+	}
+}
+
+func TestItemStack_Merge(t *testing.T) {
+	stone := mustID("minecraft:stone")
+	a, b := New(stone, 60), New(stone, 10)
+	if n := a.Merge(&b); n != 4 || a.Count != 64 || b.Count != 6 {
+		t.Errorf("merged %d, counts %d and %d, want 4, 64 and 6", n, a.Count, b.Count)
+	}
+
+	named := withComponents(New(stone, 1), &component.CustomName{Name: chat.Text("x")})
+	a = New(stone, 1)
+	if n := a.Merge(&named); n != 0 {
+		t.Errorf("merged %d items with different components", n)
+	}
+
+	var empty ItemStack
+	if n := empty.Merge(&named); n != 1 || !named.IsEmpty() || !Equal(empty, withComponents(New(stone, 1), &component.CustomName{Name: chat.Text("x")})) {
+		t.Errorf("merge into an empty stack moved %d items: %+v", n, empty)
+	}
+}
+
+func TestItemStack_Split(t *testing.T) {
+	s := New(mustID("minecraft:stone"), 5)
+	split := s.Split(3)
+	if split.Count != 3 || s.Count != 2 || !SameItem(s, split) {
+		t.Errorf("split into %+v and %+v", split, s)
+	}
+	s.Split(5)
+	if !s.IsEmpty() {
+		t.Errorf("stack not empty after split all: %+v", s)
+	}
+}
+
+func TestItemStack_network(t *testing.T) {
+	s := withComponents(New(mustID("minecraft:diamond_sword"), 1),
+		&component.Damage{VarInt: 10},
+		&component.Unbreakable{ShowInTooltip: true},
+	)
+	s.Remove(mustTypeID(&component.Enchantments{}))
+
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got ItemStack
+	if _, err := got.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(s, got) {
+		t.Errorf("got %+v, want %+v", got, s)
+	}
+}
+
+func TestItemStack_NBT(t *testing.T) {
+	data := dynbt.NewCompound()
+	data.Set("foo", dynbt.NewString("bar"))
+	s := withComponents(New(mustID("minecraft:diamond_sword"), 1),
+		&component.Damage{VarInt: 10},
+		&component.CustomName{Name: chat.Text("Excalibur")},
+		&component.Lore{Lines: []chat.Message{chat.Text("a"), chat.Text("b")}},
+		&component.Unbreakable{},
+		&component.DyedColor{RGB: 0xFF00FF, ShowInTooltip: true},
+		&component.CustomData{Value: *data},
+		ptr(component.Epic),
+		&component.BaseColor{VarInt: 14},
+		&component.HideTooptip{},
+	)
+	s.Remove(mustTypeID(&component.AttributeModifiers{}))
+
+	b, err := nbt.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ItemStack
+	if err := nbt.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, got) {
+		t.Errorf("got %+v, want %+v", got, s)
+	}
+}
+
+func TestItemStack_UnmarshalNBT(t *testing.T) {
+	snbt := nbt.StringifiedMessage(`{id:"minecraft:stone",count:3,components:{"minecraft:damage":2,"minecraft:unbreakable":{},"!minecraft:lore":{}}}`)
+	var buf bytes.Buffer
+	if err := nbt.NewEncoder(&buf).Encode(snbt, ""); err != nil {
+		t.Fatal(err)
+	}
+	var got ItemStack
+	if err := nbt.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := withComponents(New(mustID("minecraft:stone"), 3),
+		&component.Damage{VarInt: 2},
+		&component.Unbreakable{ShowInTooltip: true},
+	)
+	want.Remove(mustTypeID(&component.Lore{}))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func withComponents(s ItemStack, comps ...component.DataComponent) ItemStack {
+	for _, c := range comps {
+		s.Set(c)
+	}
+	return s
+}
+
+func mustID(name string) (id ID) {
+	if err := id.UnmarshalText([]byte(name)); err != nil {
+		panic(err)
+	}
+	return
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestItemStack_Tool(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		speed float32
+		ok    bool
+	}{
+		{"minecraft:golden_pickaxe", 12, true},
+		{"minecraft:stone_shovel", 4, true},
+		{"minecraft:diamond_sword", 0, true},
+		{"minecraft:stone", 0, false},
+		{"minecraft:stick", 0, false},
+	} {
+		tool, ok := New(mustID(tt.name), 1).Tool()
+		if ok != tt.ok {
+			t.Errorf("%s is a tool: %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if tt.speed != 0 && (len(tool.Rules) != 2 || float32(tool.Rules[1].Speed.Val) != tt.speed) {
+			t.Errorf("%s rules = %+v, want the speed %v", tt.name, tool.Rules, tt.speed)
+		}
+	}
+	custom := withComponents(New(mustID("minecraft:stick"), 1), &component.Tool{DefaultMiningSpeed: 3})
+	if tool, ok := custom.Tool(); !ok || tool.DefaultMiningSpeed != 3 {
+		t.Errorf("the tool component wasn't used: %v, %v", tool, ok)
+	}
+}
+
+func TestItemStack_EnchantmentLevel(t *testing.T) {
+	s := withComponents(New(mustID("minecraft:iron_pickaxe"), 1), &component.Enchantments{
+		Enchantments: []component.EnchantmentLevel{{Type: 8, Level: 4}},
+	})
+	if got := s.EnchantmentLevel("minecraft:efficiency"); got != 4 {
+		t.Errorf("efficiency level = %d, want 4", got)
+	}
+	if got := s.EnchantmentLevel("minecraft:silk_touch"); got != 0 {
+		t.Errorf("silk touch level = %d, want 0", got)
+	}
+}
+
+func TestItemStack_Armor(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		armor, toughness float64
+	}{
+		{"minecraft:leather_chestplate", 3, 0},
+		{"minecraft:iron_leggings", 5, 0},
+		{"minecraft:diamond_helmet", 3, 2},
+		{"minecraft:netherite_boots", 3, 3},
+		{"minecraft:turtle_helmet", 2, 0},
+		{"minecraft:elytra", 0, 0},
+		{"minecraft:diamond_sword", 0, 0},
+	} {
+		if armor, toughness := New(mustID(tt.name), 1).Armor(); armor != tt.armor || toughness != tt.toughness {
+			t.Errorf("%s armor = %v, %v, want %v, %v", tt.name, armor, toughness, tt.armor, tt.toughness)
+		}
+	}
+	custom := withComponents(New(mustID("minecraft:iron_helmet"), 1), &component.AttributeModifiers{
+		Modifiers: []component.AttributeModifier{{AttributeID: 0, Value: 7}, {AttributeID: 1, Value: 1}},
+	})
+	if armor, toughness := custom.Armor(); armor != 7 || toughness != 1 {
+		t.Errorf("the attribute modifiers weren't used: %v, %v", armor, toughness)
+	}
+}
@@ -0,0 +1,335 @@
+package item
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/nbt"
+	"github.com/mrhaoxx/go-mc/nbt/dynbt"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+func (c Components) TagType() byte {
+	return nbt.TagCompound
+}
+
+// MarshalNBT writes the components in the format of the save files.
+// The components without a persistent form, like minecraft:creative_slot_lock, are skipped.
+func (c Components) MarshalNBT(w io.Writer) error {
+	m := make(map[string]any, len(c))
+	for _, typ := range c.sortedTypes() {
+		name := registryid.DataComponentType[typ]
+		comp := c[typ]
+		if comp == nil {
+			m["!"+name] = struct{}{}
+			continue
+		}
+		codec, ok := nbtCodecs[name]
+		if !ok {
+			continue
+		}
+		v, err := codec.encode(comp)
+		if err != nil {
+			return fmt.Errorf("encode component %s: %w", name, err)
+		}
+		m[name] = v
+	}
+	var buf bytes.Buffer
+	if err := nbt.NewEncoder(&buf).Encode(m, ""); err != nil {
+		return err
+	}
+	buf.Next(3) // tag type and empty name
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// UnmarshalNBT reads the components in the format of the save files.
+// The components without a persistent form here are dropped.
+func (c *Components) UnmarshalNBT(tagType byte, r nbt.DecoderReader) error {
+	if tagType != nbt.TagCompound {
+		return errors.New("item components should be a compound")
+	}
+	var raw nbt.RawMessage
+	if err := raw.UnmarshalNBT(tagType, r); err != nil {
+		return err
+	}
+	var m map[string]nbt.RawMessage
+	if err := raw.Unmarshal(&m); err != nil {
+		return err
+	}
+	*c = make(Components, len(m))
+	for name, v := range m {
+		if removed, ok := strings.CutPrefix(name, "!"); ok {
+			typ := slices.Index(registryid.DataComponentType, removed)
+			if typ == -1 {
+				return errors.New("unknown data component " + removed)
+			}
+			(*c)[int32(typ)] = nil
+			continue
+		}
+		codec, ok := nbtCodecs[name]
+		if !ok {
+			continue
+		}
+		comp, err := codec.decode(v)
+		if err != nil {
+			return fmt.Errorf("decode component %s: %w", name, err)
+		}
+		typ, _ := component.TypeID(comp)
+		(*c)[typ] = comp
+	}
+	return nil
+}
+
+// nbtCodec converts a data component to and from the value of its NBT form.
+type nbtCodec struct {
+	encode func(c component.DataComponent) (any, error)
+	decode func(m nbt.RawMessage) (component.DataComponent, error)
+}
+
+// codec makes the nbtCodec of the component type T whose NBT form is decoded into a V.
+func codec[V, T any, P interface {
+	*T
+	component.DataComponent
+}](encode func(c P) (V, error), decode func(v V) (T, error)) nbtCodec {
+	return nbtCodec{
+		encode: func(c component.DataComponent) (any, error) {
+			return encode(c.(P))
+		},
+		decode: func(m nbt.RawMessage) (component.DataComponent, error) {
+			var v V
+			if err := m.Unmarshal(&v); err != nil {
+				return nil, err
+			}
+			c, err := decode(v)
+			if err != nil {
+				return nil, err
+			}
+			return P(&c), nil
+		},
+	}
+}
+
+// intCodec is the codec of the components holding a single int.
+func intCodec[T any, P interface {
+	*T
+	component.DataComponent
+}](field func(c P) *pk.VarInt) nbtCodec {
+	return codec(
+		func(c P) (int32, error) { return int32(*field(c)), nil },
+		func(v int32) (c T, err error) { *field(&c) = pk.VarInt(v); return },
+	)
+}
+
+// unitCodec is the codec of the components without a value, stored as an empty compound.
+func unitCodec[T any, P interface {
+	*T
+	component.DataComponent
+}]() nbtCodec {
+	return codec(
+		func(P) (struct{}, error) { return struct{}{}, nil },
+		func(struct{}) (c T, err error) { return },
+	)
+}
+
+// valueCodec is the codec of the components holding a NBT value.
+func valueCodec[T any, P interface {
+	*T
+	component.DataComponent
+}](field func(c P) *dynbt.Value) nbtCodec {
+	return codec(
+		func(c P) (*dynbt.Value, error) { return field(c), nil },
+		func(v *dynbt.Value) (c T, err error) {
+			if v != nil {
+				*field(&c) = *v
+			}
+			return
+		},
+	)
+}
+
+// identifierCodec is the codec of the components holding a resource location.
+func identifierCodec[T any, P interface {
+	*T
+	component.DataComponent
+}](field func(c P) *pk.Identifier) nbtCodec {
+	return codec(
+		func(c P) (string, error) { return string(*field(c)), nil },
+		func(v string) (c T, err error) { *field(&c) = pk.Identifier(v); return },
+	)
+}
+
+// textCodec is the codec of the components holding a text component, stored as JSON.
+func textCodec[T any, P interface {
+	*T
+	component.DataComponent
+}](field func(c P) *chat.Message) nbtCodec {
+	return codec(
+		func(c P) (string, error) { return textJSON(*field(c)) },
+		func(v string) (c T, err error) {
+			err = json.Unmarshal([]byte(v), field(&c))
+			return
+		},
+	)
+}
+
+func textJSON(msg chat.Message) (string, error) {
+	data, err := json.Marshal(msg)
+	return string(data), err
+}
+
+// enumCodec is the codec of the components holding one of the names, stored as a string.
+func enumCodec[T any, P interface {
+	*T
+	component.DataComponent
+}](names []string, field func(c P) *pk.VarInt) nbtCodec {
+	return codec(
+		func(c P) (string, error) {
+			i := int(*field(c))
+			if i < 0 || i >= len(names) {
+				return "", fmt.Errorf("invalid value %d", i)
+			}
+			return names[i], nil
+		},
+		func(v string) (c T, err error) {
+			i := slices.Index(names, v)
+			if i == -1 {
+				return c, errors.New("unknown value " + v)
+			}
+			*field(&c) = pk.VarInt(i)
+			return
+		},
+	)
+}
+
+var (
+	rarities  = []string{"common", "uncommon", "rare", "epic"}
+	dyeColors = []string{
+		"white", "orange", "magenta", "light_blue", "yellow", "lime", "pink", "gray",
+		"light_gray", "cyan", "purple", "blue", "brown", "green", "red", "black",
+	}
+)
+
+// nbtCodecs are the NBT forms of the components, keyed by their ids.
+var nbtCodecs = map[string]nbtCodec{
+	"minecraft:custom_data":    valueCodec(func(c *component.CustomData) *dynbt.Value { return &c.Value }),
+	"minecraft:max_stack_size": intCodec(func(c *component.MaxStackSize) *pk.VarInt { return &c.VarInt }),
+	"minecraft:max_damage":     intCodec(func(c *component.MaxDamage) *pk.VarInt { return &c.VarInt }),
+	"minecraft:damage":         intCodec(func(c *component.Damage) *pk.VarInt { return &c.VarInt }),
+	"minecraft:unbreakable": {
+		encode: func(c component.DataComponent) (any, error) {
+			return unbreakable{ShowInTooltip: bool(c.(*component.Unbreakable).ShowInTooltip)}, nil
+		},
+		decode: func(m nbt.RawMessage) (component.DataComponent, error) {
+			v := unbreakable{ShowInTooltip: true}
+			err := m.Unmarshal(&v)
+			return &component.Unbreakable{ShowInTooltip: pk.Boolean(v.ShowInTooltip)}, err
+		},
+	},
+	"minecraft:custom_name": textCodec(func(c *component.CustomName) *chat.Message { return &c.Name }),
+	"minecraft:item_name":   textCodec(func(c *component.ItemName) *chat.Message { return &c.Name }),
+	"minecraft:item_model":  identifierCodec(func(c *component.ItemModel) *pk.Identifier { return &c.Model }),
+	"minecraft:lore": codec(
+		func(c *component.Lore) (lines []string, err error) {
+			lines = make([]string, len(c.Lines))
+			for i, msg := range c.Lines {
+				if lines[i], err = textJSON(msg); err != nil {
+					return nil, err
+				}
+			}
+			return lines, nil
+		},
+		func(lines []string) (c component.Lore, err error) {
+			c.Lines = make([]chat.Message, len(lines))
+			for i, line := range lines {
+				if err = json.Unmarshal([]byte(line), &c.Lines[i]); err != nil {
+					return
+				}
+			}
+			return
+		},
+	),
+	"minecraft:rarity": codec(
+		func(c *component.Rarity) (string, error) {
+			if *c < 0 || int(*c) >= len(rarities) {
+				return "", fmt.Errorf("invalid rarity %d", *c)
+			}
+			return rarities[*c], nil
+		},
+		func(v string) (component.Rarity, error) {
+			i := slices.Index(rarities, v)
+			if i == -1 {
+				return 0, errors.New("unknown rarity " + v)
+			}
+			return component.Rarity(i), nil
+		},
+	),
+	"minecraft:hide_additional_tooltip": unitCodec[component.HideAdditionalTooptip](),
+	"minecraft:hide_tooltip":            unitCodec[component.HideTooptip](),
+	"minecraft:repair_cost":             intCodec(func(c *component.RepairCost) *pk.VarInt { return &c.VarInt }),
+	"minecraft:enchantment_glint_override": codec(
+		func(c *component.EnchantmentGlintOverride) (bool, error) { return bool(c.HasGlint), nil },
+		func(v bool) (c component.EnchantmentGlintOverride, err error) {
+			c.HasGlint = pk.Boolean(v)
+			return
+		},
+	),
+	"minecraft:intangible_projectile": unitCodec[component.IntangibleProjectile](),
+	"minecraft:enchantable":           intCodec(func(c *component.Enchantable) *pk.VarInt { return &c.VarInt }),
+	"minecraft:glider":                unitCodec[component.Glider](),
+	"minecraft:tooltip_style":         identifierCodec(func(c *component.TooltipStyle) *pk.Identifier { return &c.Style }),
+	"minecraft:dyed_color": {
+		encode: func(c component.DataComponent) (any, error) {
+			d := c.(*component.DyedColor)
+			if d.ShowInTooltip {
+				return int32(d.RGB), nil
+			}
+			return dyedColor{RGB: int32(d.RGB), ShowInTooltip: false}, nil
+		},
+		decode: func(m nbt.RawMessage) (component.DataComponent, error) {
+			if m.Type == nbt.TagInt {
+				var rgb int32
+				err := m.Unmarshal(&rgb)
+				return &component.DyedColor{RGB: pk.Int(rgb), ShowInTooltip: true}, err
+			}
+			v := dyedColor{ShowInTooltip: true}
+			err := m.Unmarshal(&v)
+			return &component.DyedColor{RGB: pk.Int(v.RGB), ShowInTooltip: pk.Boolean(v.ShowInTooltip)}, err
+		},
+	},
+	"minecraft:map_color": codec(
+		func(c *component.MapColor) (int32, error) { return int32(c.Int), nil },
+		func(v int32) (c component.MapColor, err error) {
+			c.Int = pk.Int(v)
+			return
+		},
+	),
+	"minecraft:map_id":                   intCodec(func(c *component.MapID) *pk.VarInt { return &c.VarInt }),
+	"minecraft:map_decorations":          valueCodec(func(c *component.MapDecorations) *dynbt.Value { return &c.Value }),
+	"minecraft:entity_data":              valueCodec(func(c *component.EntityData) *dynbt.Value { return &c.Value }),
+	"minecraft:bucket_entity_data":       valueCodec(func(c *component.BucketEntityData) *dynbt.Value { return &c.Value }),
+	"minecraft:block_entity_data":        valueCodec(func(c *component.BlockEntityData) *dynbt.Value { return &c.Value }),
+	"minecraft:ominous_bottle_amplifier": intCodec(func(c *component.OminousBottleAmplifier) *pk.VarInt { return &c.VarInt }),
+	"minecraft:recipes":                  valueCodec(func(c *component.Recipes) *dynbt.Value { return &c.Data }),
+	"minecraft:note_block_sound":         identifierCodec(func(c *component.NoteBlockSound) *pk.Identifier { return &c.Sound }),
+	"minecraft:base_color":               enumCodec(dyeColors, func(c *component.BaseColor) *pk.VarInt { return &c.VarInt }),
+	"minecraft:lock":                     valueCodec(func(c *component.LockCode) *dynbt.Value { return &c.Value }),
+	"minecraft:container_loot":           valueCodec(func(c *component.ContainerLoot) *dynbt.Value { return &c.Value }),
+}
+
+type unbreakable struct {
+	ShowInTooltip bool `nbt:"show_in_tooltip"`
+}
+
+type dyedColor struct {
+	RGB           int32 `nbt:"rgb"`
+	ShowInTooltip bool  `nbt:"show_in_tooltip"`
+}
@@ -0,0 +1,56 @@
+package item
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var _ pk.Field = (*ItemStack)(nil)
+
+// Slot converts the stack to the network format.
+// The components are sorted by their types.
+func (s ItemStack) Slot() component.Slot {
+	if s.IsEmpty() {
+		return component.Slot{}
+	}
+	slot := component.Slot{Count: s.Count, ItemID: int32(s.ItemID)}
+	for _, typ := range s.Components.sortedTypes() {
+		if c := s.Components[typ]; c != nil {
+			slot.Patch.Added = append(slot.Patch.Added, c)
+		} else {
+			slot.Patch.Removed = append(slot.Patch.Removed, typ)
+		}
+	}
+	return slot
+}
+
+// FromSlot converts a stack in the network format,
+// like the ones in the components holding items.
+func FromSlot(slot component.Slot) (s ItemStack) {
+	if slot.Count <= 0 {
+		return s
+	}
+	s = New(ID(slot.ItemID), slot.Count)
+	for _, c := range slot.Patch.Added {
+		s.Set(c)
+	}
+	for _, typ := range slot.Patch.Removed {
+		s.Remove(typ)
+	}
+	return s
+}
+
+// WriteTo writes the stack in the Slot format of the protocol.
+func (s ItemStack) WriteTo(w io.Writer) (int64, error) {
+	return s.Slot().WriteTo(w)
+}
+
+// ReadFrom reads a stack in the Slot format of the protocol.
+func (s *ItemStack) ReadFrom(r io.Reader) (int64, error) {
+	var slot component.Slot
+	n, err := slot.ReadFrom(r)
+	*s = FromSlot(slot)
+	return n, err
+}
@@ -0,0 +1,99 @@
+package item
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/level/component"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+var toolType = mustTypeID(&component.Tool{})
+
+// toolMaterials are the mining speeds of the tool materials and the tags of the blocks they can't mine, like vanilla.
+var toolMaterials = map[string]struct {
+	speed     float32
+	incorrect string
+}{
+	"wooden":    {2, "minecraft:incorrect_for_wooden_tool"},
+	"stone":     {4, "minecraft:incorrect_for_stone_tool"},
+	"iron":      {6, "minecraft:incorrect_for_iron_tool"},
+	"golden":    {12, "minecraft:incorrect_for_gold_tool"},
+	"diamond":   {8, "minecraft:incorrect_for_diamond_tool"},
+	"netherite": {9, "minecraft:incorrect_for_netherite_tool"},
+}
+
+// Tool returns how the stack mines blocks, set by the minecraft:tool component.
+// Otherwise it's the default component of the vanilla pickaxes, axes, shovels, hoes, swords and shears,
+// guessed from the item name since the default components of the items aren't known.
+func (s ItemStack) Tool() (*component.Tool, bool) {
+	if c, ok := s.Component(toolType); ok {
+		return c.(*component.Tool), true
+	}
+	if _, removed := s.Components[toolType]; removed {
+		return nil, false
+	}
+	name := strings.TrimPrefix(s.ItemID.Name(), "minecraft:")
+	switch {
+	case name == "shears":
+		return &component.Tool{
+			Rules: []component.ToolRule{
+				blockRule([]string{"minecraft:cobweb"}, 15, true),
+				tagRule("minecraft:leaves", 15, false),
+				tagRule("minecraft:wool", 5, false),
+				blockRule([]string{"minecraft:vine", "minecraft:glow_lichen"}, 2, false),
+			},
+			DefaultMiningSpeed: 1,
+			DamagePerBlock:     1,
+		}, true
+	case strings.HasSuffix(name, "_sword"):
+		if _, ok := toolMaterials[strings.TrimSuffix(name, "_sword")]; !ok {
+			return nil, false
+		}
+		return &component.Tool{
+			Rules: []component.ToolRule{
+				blockRule([]string{"minecraft:cobweb"}, 15, true),
+				tagRule("minecraft:sword_efficient", 1.5, false),
+			},
+			DefaultMiningSpeed: 1,
+			DamagePerBlock:     2,
+		}, true
+	}
+	material, kind, ok := strings.Cut(name, "_")
+	m, isMaterial := toolMaterials[material]
+	if !ok || !isMaterial || kind != "pickaxe" && kind != "axe" && kind != "shovel" && kind != "hoe" {
+		return nil, false
+	}
+	return &component.Tool{
+		Rules: []component.ToolRule{
+			{Blocks: component.IDSet{Tag: pk.Identifier(m.incorrect)}, CorrectForDrop: pk.Option[pk.Boolean, *pk.Boolean]{Has: true, Val: false}},
+			tagRule("minecraft:mineable/"+kind, m.speed, true),
+		},
+		DefaultMiningSpeed: 1,
+		DamagePerBlock:     1,
+	}, true
+}
+
+// tagRule is a rule of the blocks of the tag, which also sets the drops if correct is set.
+func tagRule(tag string, speed float32, correct bool) component.ToolRule {
+	r := component.ToolRule{
+		Blocks: component.IDSet{Tag: pk.Identifier(tag)},
+		Speed:  pk.Option[pk.Float, *pk.Float]{Has: true, Val: pk.Float(speed)},
+	}
+	if correct {
+		r.CorrectForDrop = pk.Option[pk.Boolean, *pk.Boolean]{Has: true, Val: true}
+	}
+	return r
+}
+
+// blockRule is tagRule for the blocks of the names.
+func blockRule(blocks []string, speed float32, correct bool) component.ToolRule {
+	r := tagRule("", speed, correct)
+	for _, name := range blocks {
+		if i := slices.Index(registryid.Block, name); i >= 0 {
+			r.Blocks.IDs = append(r.Blocks.IDs, pk.VarInt(i))
+		}
+	}
+	return r
+}
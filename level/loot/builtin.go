@@ -0,0 +1,13 @@
+package loot
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed builtin
+var builtin embed.FS
+
+// Builtin is a data pack of the loot tables of a few basic blocks which don't drop themselves, like stone and ores,
+// so that they drop the right items without the vanilla data pack, which can be extracted from the server jar.
+var Builtin, _ = fs.Sub(builtin, "builtin")
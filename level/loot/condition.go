@@ -0,0 +1,165 @@
+package loot
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/component"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Condition is a predicate of a loot table, which must pass for a pool, an entry or a function to be used.
+type Condition struct {
+	Condition string `json:"condition"`
+	// Terms are the conditions of any_of and all_of, and Term the one of inverted.
+	Terms []Condition `json:"terms"`
+	Term  *Condition  `json:"term"`
+	// Chance is the chance of random_chance.
+	Chance *Number `json:"chance"`
+	// Block and Properties are the block and its properties of block_state_property.
+	Block      string                   `json:"block"`
+	Properties map[string]propertyRange `json:"properties"`
+	// Predicate is the item of match_tool.
+	Predicate *ItemPredicate `json:"predicate"`
+	// Enchantment and Chances are the chances for each level of the enchantment of table_bonus.
+	Enchantment string    `json:"enchantment"`
+	Chances     []float64 `json:"chances"`
+}
+
+// ItemPredicate matches the tool used.
+type ItemPredicate struct {
+	Items      itemList `json:"items"`
+	Predicates struct {
+		Enchantments []struct {
+			Enchantments string   `json:"enchantments"`
+			Levels       intRange `json:"levels"`
+		} `json:"minecraft:enchantments"`
+	} `json:"predicates"`
+}
+
+// itemList is an item, a list of items or an item tag.
+type itemList []string
+
+func (l *itemList) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*l = itemList{name}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// intRange is an exact number or a range whose bounds are optional.
+type intRange struct {
+	Min, Max *int
+}
+
+func (r *intRange) UnmarshalJSON(data []byte) error {
+	var exact int
+	if err := json.Unmarshal(data, &exact); err == nil {
+		*r = intRange{&exact, &exact}
+		return nil
+	}
+	var v struct {
+		Min *int `json:"min"`
+		Max *int `json:"max"`
+	}
+	err := json.Unmarshal(data, &v)
+	*r = intRange{v.Min, v.Max}
+	return err
+}
+
+func (r intRange) contains(n int) bool {
+	return (r.Min == nil || n >= *r.Min) && (r.Max == nil || n <= *r.Max)
+}
+
+// propertyRange is the exact value of a block property, or its range.
+type propertyRange component.PropertyMatcher
+
+func (p *propertyRange) UnmarshalJSON(data []byte) error {
+	str := func(data json.RawMessage) string {
+		var s string
+		if json.Unmarshal(data, &s) != nil {
+			// Numbers and booleans are accepted like vanilla.
+			s = string(data)
+		}
+		return s
+	}
+	var bounds struct {
+		Min json.RawMessage `json:"min"`
+		Max json.RawMessage `json:"max"`
+	}
+	if json.Unmarshal(data, &bounds) != nil {
+		*p = propertyRange{IsExact: true, ExactValue: pk.String(str(data))}
+		return nil
+	}
+	*p = propertyRange{}
+	if bounds.Min != nil {
+		p.MinValue = pk.Option[pk.String, *pk.String]{Has: true, Val: pk.String(str(bounds.Min))}
+	}
+	if bounds.Max != nil {
+		p.MaxValue = pk.Option[pk.String, *pk.String]{Has: true, Val: pk.String(str(bounds.Max))}
+	}
+	return nil
+}
+
+func allPass(ctx *Context, conditions []Condition) bool {
+	for i := range conditions {
+		if !conditions[i].pass(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Condition) pass(ctx *Context) bool {
+	switch c.Condition {
+	case "minecraft:survives_explosion":
+		return true
+	case "minecraft:inverted":
+		return c.Term != nil && !c.Term.pass(ctx)
+	case "minecraft:all_of":
+		return allPass(ctx, c.Terms)
+	case "minecraft:any_of":
+		for i := range c.Terms {
+			if c.Terms[i].pass(ctx) {
+				return true
+			}
+		}
+		return false
+	case "minecraft:random_chance":
+		return c.Chance != nil && ctx.Rand.Float64() < c.Chance.Float(ctx)
+	case "minecraft:table_bonus":
+		if len(c.Chances) == 0 {
+			return false
+		}
+		level := int(ctx.Tool.EnchantmentLevel(c.Enchantment))
+		return ctx.Rand.Float64() < c.Chances[min(level, len(c.Chances)-1)]
+	case "minecraft:match_tool":
+		return c.Predicate != nil && c.Predicate.match(ctx)
+	case "minecraft:block_state_property":
+		if block.StateList[ctx.Block].ID() != c.Block {
+			return false
+		}
+		p := component.BlockPredicate{}
+		for name, r := range c.Properties {
+			r.Name = pk.String(name)
+			p.Properties = append(p.Properties, component.PropertyMatcher(r))
+		}
+		return block.MatchPredicate(p, ctx.Block)
+	}
+	return false
+}
+
+func (p *ItemPredicate) match(ctx *Context) bool {
+	if p.Items != nil && (ctx.Tool.IsEmpty() || !slices.Contains(p.Items, ctx.Tool.ItemID.Name())) {
+		return false
+	}
+	for _, e := range p.Predicates.Enchantments {
+		if !e.Levels.contains(int(ctx.Tool.EnchantmentLevel(e.Enchantments))) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package loot
+
+import (
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// Function changes the items of a loot table.
+type Function struct {
+	Function   string      `json:"function"`
+	Conditions []Condition `json:"conditions"`
+	// Count and Add are the count of set_count, which is added to the current one if Add is set.
+	Count *Number `json:"count"`
+	Add   bool    `json:"add"`
+	// Enchantment, Formula and Parameters are the bonus of the enchantment of apply_bonus.
+	Enchantment string `json:"enchantment"`
+	Formula     string `json:"formula"`
+	Parameters  struct {
+		BonusMultiplier int     `json:"bonusMultiplier"`
+		Extra           int     `json:"extra"`
+		Probability     float64 `json:"probability"`
+	} `json:"parameters"`
+	// Limit is the range of limit_count.
+	Limit intRange `json:"limit"`
+}
+
+func (f *Function) apply(ctx *Context, s *item.ItemStack) {
+	if s.IsEmpty() || !allPass(ctx, f.Conditions) {
+		return
+	}
+	count := int(s.Count)
+	switch f.Function {
+	case "minecraft:set_count":
+		if f.Count == nil {
+			return
+		}
+		if n := f.Count.Int(ctx); f.Add {
+			count += n
+		} else {
+			count = n
+		}
+	case "minecraft:apply_bonus":
+		level := int(ctx.Tool.EnchantmentLevel(f.Enchantment))
+		switch f.Formula {
+		case "minecraft:ore_drops":
+			if level > 0 {
+				count *= max(ctx.Rand.Intn(level+2)-1, 0) + 1
+			}
+		case "minecraft:uniform_bonus_count":
+			count += ctx.Rand.Intn(f.Parameters.BonusMultiplier*level + 1)
+		case "minecraft:binomial_with_bonus_count":
+			for range level + f.Parameters.Extra {
+				if ctx.Rand.Float64() < f.Parameters.Probability {
+					count++
+				}
+			}
+		}
+	case "minecraft:limit_count":
+		if f.Limit.Min != nil {
+			count = max(count, *f.Limit.Min)
+		}
+		if f.Limit.Max != nil {
+			count = min(count, *f.Limit.Max)
+		}
+	default:
+		return
+	}
+	s.Count = int32(min(max(count, 0), int(s.MaxStackSize())))
+}
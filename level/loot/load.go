@@ -0,0 +1,77 @@
+package loot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// Tables holds the loot tables loaded from the data packs, keyed by id like minecraft:blocks/stone.
+type Tables struct {
+	tables map[string]*Table
+}
+
+// Load reads the loot tables of the data packs, the latter overriding the former.
+// The tables which can't be read are reported in the error, the Tables returned have all the others.
+func Load(packs ...fs.FS) (*Tables, error) {
+	t := &Tables{tables: make(map[string]*Table)}
+	var errs []error
+	for _, pack := range packs {
+		err := fs.WalkDir(pack, "data", func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || path.Ext(p) != ".json" {
+				return err
+			}
+			// data/<namespace>/loot_table/<path>.json
+			parts := strings.SplitN(strings.TrimSuffix(p, ".json"), "/", 4)
+			if len(parts) < 4 || parts[2] != "loot_table" {
+				return nil
+			}
+			id := parts[1] + ":" + parts[3]
+			data, err := fs.ReadFile(pack, p)
+			if err != nil {
+				return err
+			}
+			var table Table
+			if err := json.Unmarshal(data, &table); err != nil {
+				errs = append(errs, fmt.Errorf("loot table %s: %w", id, err))
+				return nil
+			}
+			t.tables[id] = &table
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return t, errors.Join(errs...)
+}
+
+// Len returns the number of tables.
+func (t *Tables) Len() int { return len(t.tables) }
+
+// Get returns the table of the id.
+func (t *Tables) Get(id string) (*Table, bool) {
+	table, ok := t.tables[id]
+	return table, ok
+}
+
+// BlockDrops returns the items dropped by the block of the context, rolled from the table blocks/<name> of the block.
+// The blocks without tables drop their items, since the vanilla tables are only loaded from the vanilla data pack.
+func (t *Tables) BlockDrops(ctx Context) []item.ItemStack {
+	ctx.tables = t
+	namespace, name, _ := strings.Cut(block.StateList[ctx.Block].ID(), ":")
+	if table, ok := t.Get(namespace + ":blocks/" + name); ok {
+		return table.Roll(&ctx)
+	}
+	var id item.ID
+	if err := id.UnmarshalText([]byte(namespace + ":" + name)); err != nil || id == item.Air {
+		return nil
+	}
+	return []item.ItemStack{item.New(id, 1)}
+}
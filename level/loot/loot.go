@@ -0,0 +1,176 @@
+// Package loot rolls the loot tables of the data packs, which decide the items dropped by the broken blocks.
+//
+// The tables are read from the JSON files of the vanilla data packs, in data/<namespace>/loot_table.
+// There are no explosions, luck or item tags yet, so the survives_explosion conditions always pass,
+// the explosion_decay functions do nothing and the tags never match.
+// The functions copying the block entities to the items aren't supported and are ignored,
+// and so are the unknown functions, while the unknown conditions never pass.
+package loot
+
+import (
+	"math/rand"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// Context is what the items rolled depend on.
+type Context struct {
+	// Block is the state of the broken block.
+	Block block.StateID
+	// Tool is the item the block is broken with, which may be empty.
+	Tool item.ItemStack
+	Rand *rand.Rand
+
+	tables *Tables
+}
+
+// Table is a loot table, whose pools are rolled in turn.
+type Table struct {
+	Pools     []Pool     `json:"pools"`
+	Functions []Function `json:"functions"`
+}
+
+// Pool is a part of a loot table, which adds the items of some of its entries.
+type Pool struct {
+	Rolls      Number      `json:"rolls"`
+	Entries    []Entry     `json:"entries"`
+	Conditions []Condition `json:"conditions"`
+	Functions  []Function  `json:"functions"`
+}
+
+// Entry is an entry of a pool. The composite entries alternatives, group and sequence have children.
+type Entry struct {
+	Type string `json:"type"`
+	// Name is the item, the tag or the loot table of the entry.
+	Name       string      `json:"name"`
+	Children   []Entry     `json:"children"`
+	Weight     int         `json:"weight"`
+	Conditions []Condition `json:"conditions"`
+	Functions  []Function  `json:"functions"`
+}
+
+// Roll returns the items of the table.
+func (t *Table) Roll(ctx *Context) []item.ItemStack {
+	var items []item.ItemStack
+	for i := range t.Pools {
+		items = append(items, t.Pools[i].roll(ctx)...)
+	}
+	for i := range items {
+		for _, f := range t.Functions {
+			f.apply(ctx, &items[i])
+		}
+	}
+	return nonEmpty(items)
+}
+
+func (p *Pool) roll(ctx *Context) []item.ItemStack {
+	if !allPass(ctx, p.Conditions) {
+		return nil
+	}
+	var items []item.ItemStack
+	for range p.Rolls.Int(ctx) {
+		var choices []*Entry
+		for i := range p.Entries {
+			p.Entries[i].expand(ctx, func(e *Entry) { choices = append(choices, e) })
+		}
+		e := pickWeighted(ctx, choices)
+		if e == nil {
+			continue
+		}
+		created := e.create(ctx)
+		for i := range created {
+			for _, f := range p.Functions {
+				f.apply(ctx, &created[i])
+			}
+		}
+		items = append(items, created...)
+	}
+	return items
+}
+
+// expand adds the entries producing items the entry stands for, like vanilla's LootPoolEntryContainer.expand.
+// It reports whether the conditions of the entry passed.
+func (e *Entry) expand(ctx *Context, add func(*Entry)) bool {
+	if !allPass(ctx, e.Conditions) {
+		return false
+	}
+	switch e.Type {
+	case "minecraft:alternatives":
+		for i := range e.Children {
+			if e.Children[i].expand(ctx, add) {
+				return true
+			}
+		}
+		return false
+	case "minecraft:group":
+		for i := range e.Children {
+			e.Children[i].expand(ctx, add)
+		}
+	case "minecraft:sequence":
+		for i := range e.Children {
+			if !e.Children[i].expand(ctx, add) {
+				break
+			}
+		}
+	default:
+		add(e)
+	}
+	return true
+}
+
+// create returns the items of a single entry.
+func (e *Entry) create(ctx *Context) []item.ItemStack {
+	var items []item.ItemStack
+	switch e.Type {
+	case "minecraft:item":
+		var id item.ID
+		if err := id.UnmarshalText([]byte(e.Name)); err != nil {
+			return nil
+		}
+		items = []item.ItemStack{item.New(id, 1)}
+	case "minecraft:loot_table":
+		if t, ok := ctx.tables.Get(e.Name); ok {
+			items = t.Roll(ctx)
+		}
+	}
+	for i := range items {
+		for _, f := range e.Functions {
+			f.apply(ctx, &items[i])
+		}
+	}
+	return items
+}
+
+// pickWeighted picks one of the entries with a chance proportional to their weights.
+func pickWeighted(ctx *Context, entries []*Entry) *Entry {
+	weight := func(e *Entry) int { return max(e.Weight, 1) }
+	switch len(entries) {
+	case 0:
+		return nil
+	case 1:
+		return entries[0]
+	}
+	total := 0
+	for _, e := range entries {
+		total += weight(e)
+	}
+	n := ctx.Rand.Intn(total)
+	for _, e := range entries {
+		if n -= weight(e); n < 0 {
+			return e
+		}
+	}
+	return nil
+}
+
+func nonEmpty(items []item.ItemStack) []item.ItemStack {
+	n := 0
+	for _, s := range items {
+		if !s.IsEmpty() {
+			items[n] = s
+			n++
+		}
+	}
+	return items[:n]
+}
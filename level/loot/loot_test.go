@@ -0,0 +1,92 @@
+package loot
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+func stack(name string, count int32) item.ItemStack {
+	var id item.ID
+	if err := id.UnmarshalText([]byte(name)); err != nil {
+		panic(err)
+	}
+	return item.New(id, count)
+}
+
+func enchanted(s item.ItemStack, name string, level int32) item.ItemStack {
+	s.Set(&component.Enchantments{Enchantments: []component.EnchantmentLevel{
+		{Type: pk.VarInt(slices.Index(item.Enchantments, name)), Level: pk.VarInt(level)},
+	}})
+	return s
+}
+
+func TestLoad(t *testing.T) {
+	tables, err := Load(Builtin, fstest.MapFS{
+		"data/minecraft/loot_table/blocks/glass.json": {Data: []byte(`{"pools": [{"rolls": 1, "entries": [{"type": "minecraft:item", "name": "minecraft:sand"}]}]}`)},
+		"data/foo/loot_table/broken.json":             {Data: []byte(`{"pools": [{"rolls": {"type": "minecraft:uniform"}}]}`)},
+	})
+	if err == nil {
+		t.Error("the broken table should be reported")
+	}
+	if _, ok := tables.Get("foo:broken"); ok {
+		t.Error("the broken table was loaded")
+	}
+	got := tables.BlockDrops(Context{Block: block.ToStateID[block.Glass{}], Rand: rand.New(rand.NewSource(1))})
+	if want := []item.ItemStack{stack("minecraft:sand", 1)}; !slices.EqualFunc(got, want, item.Equal) {
+		t.Errorf("the glass table wasn't overridden: %v", got)
+	}
+}
+
+func TestTables_BlockDrops(t *testing.T) {
+	tables, err := Load(Builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pickaxe := stack("minecraft:iron_pickaxe", 1)
+	for _, tt := range []struct {
+		name string
+		b    block.Block
+		tool item.ItemStack
+		want []item.ItemStack
+	}{
+		{"stone", block.Stone{}, pickaxe, []item.ItemStack{stack("minecraft:cobblestone", 1)}},
+		{"stone with silk touch", block.Stone{}, enchanted(pickaxe, "minecraft:silk_touch", 1), []item.ItemStack{stack("minecraft:stone", 1)}},
+		{"glass", block.Glass{}, item.ItemStack{}, nil},
+		{"double slab", block.OakSlab{Type: block.SlabTypeDouble}, item.ItemStack{}, []item.ItemStack{stack("minecraft:oak_slab", 2)}},
+		{"bottom slab", block.OakSlab{Type: block.SlabTypeBottom}, item.ItemStack{}, []item.ItemStack{stack("minecraft:oak_slab", 1)}},
+		{"grass with shears", block.ShortGrass{}, stack("minecraft:shears", 1), []item.ItemStack{stack("minecraft:short_grass", 1)}},
+		{"block without a table", block.Dirt{}, item.ItemStack{}, []item.ItemStack{stack("minecraft:dirt", 1)}},
+		{"block without an item", block.Fire{}, item.ItemStack{}, nil},
+	} {
+		got := tables.BlockDrops(Context{Block: block.ToStateID[tt.b], Tool: tt.tool, Rand: rand.New(rand.NewSource(1))})
+		if !slices.EqualFunc(got, tt.want, item.Equal) {
+			t.Errorf("%s: BlockDrops() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTables_BlockDrops_fortune(t *testing.T) {
+	tables, _ := Load(Builtin)
+	r := rand.New(rand.NewSource(1))
+	ore := block.ToStateID[block.DiamondOre{}]
+	tool := enchanted(stack("minecraft:iron_pickaxe", 1), "minecraft:fortune", 3)
+	total := 0
+	for range 1000 {
+		drops := tables.BlockDrops(Context{Block: ore, Tool: tool, Rand: r})
+		if len(drops) != 1 || drops[0].ItemID != stack("minecraft:diamond", 1).ItemID || drops[0].Count < 1 || drops[0].Count > 4 {
+			t.Fatalf("BlockDrops() = %v, want 1 to 4 diamonds", drops)
+		}
+		total += int(drops[0].Count)
+	}
+	// Fortune III drops 2.2 diamonds on average.
+	if total < 2000 || total > 2400 {
+		t.Errorf("%d diamonds dropped by 1000 ores", total)
+	}
+}
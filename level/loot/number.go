@@ -0,0 +1,83 @@
+package loot
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// Number is a number provider: a constant, or a number rolled between min and max, or by a binomial distribution.
+type Number struct {
+	Type        string
+	Value       float64
+	Min, Max    float64
+	N           int
+	Probability float64
+}
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &n.Value); err == nil {
+		n.Type = "minecraft:constant"
+		return nil
+	}
+	var v struct {
+		Type  string   `json:"type"`
+		Value float64  `json:"value"`
+		Min   *Number  `json:"min"`
+		Max   *Number  `json:"max"`
+		N     *Number  `json:"n"`
+		P     *float64 `json:"p"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = Number{Type: v.Type, Value: v.Value}
+	switch v.Type {
+	case "", "minecraft:uniform":
+		if v.Min == nil || v.Max == nil {
+			return errors.New("uniform number without min or max")
+		}
+		n.Type, n.Min, n.Max = "minecraft:uniform", v.Min.Value, v.Max.Value
+	case "minecraft:binomial":
+		if v.N == nil || v.P == nil {
+			return errors.New("binomial number without n or p")
+		}
+		n.N, n.Probability = int(v.N.Value), *v.P
+	case "minecraft:constant":
+	default:
+		return errors.New("unsupported number provider " + v.Type)
+	}
+	return nil
+}
+
+// Float returns the number rolled.
+func (n Number) Float(ctx *Context) float64 {
+	switch n.Type {
+	case "minecraft:uniform":
+		return n.Min + ctx.Rand.Float64()*(n.Max-n.Min)
+	case "minecraft:binomial":
+		return float64(n.Int(ctx))
+	}
+	return n.Value
+}
+
+// Int returns the number rolled as an integer, like vanilla's NumberProvider.getInt.
+func (n Number) Int(ctx *Context) int {
+	switch n.Type {
+	case "minecraft:uniform":
+		lo, hi := int(math.Floor(n.Min)), int(math.Floor(n.Max))
+		if hi <= lo {
+			return lo
+		}
+		return lo + ctx.Rand.Intn(hi-lo+1)
+	case "minecraft:binomial":
+		count := 0
+		for range n.N {
+			if ctx.Rand.Float64() < n.Probability {
+				count++
+			}
+		}
+		return count
+	}
+	return int(math.Round(n.Value))
+}
@@ -0,0 +1,13 @@
+package recipe
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed builtin
+var builtin embed.FS
+
+// Builtin is a data pack of a few basic vanilla recipes, like the planks, the sticks and the crafting table,
+// so that crafting works without the vanilla data pack, which can be extracted from the server jar.
+var Builtin, _ = fs.Sub(builtin, "builtin")
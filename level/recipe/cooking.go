@@ -0,0 +1,90 @@
+package recipe
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Cooking is a recipe of a furnace, a blast furnace, a smoker or a campfire.
+type Cooking struct {
+	group
+	typ        Type
+	Category   string
+	Ingredient *Ingredient
+	Result     item.ItemStack
+	Experience float32
+	// CookingTime is the number of ticks to cook the ingredient.
+	CookingTime int32
+}
+
+func (r *Cooking) UnmarshalJSON(data []byte) error {
+	v := struct {
+		group
+		Category    string      `json:"category"`
+		Ingredient  *Ingredient `json:"ingredient"`
+		Result      result      `json:"result"`
+		Experience  float32     `json:"experience"`
+		CookingTime int32       `json:"cookingtime"`
+	}{CookingTime: 100}
+	if r.typ == Smelting {
+		v.CookingTime = 200
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.group, r.Category, r.Ingredient, r.Result = v.group, v.Category, v.Ingredient, item.ItemStack(v.Result)
+	r.Experience, r.CookingTime = v.Experience, v.CookingTime
+	return nil
+}
+
+func (r *Cooking) Type() Type { return r.typ }
+
+// Matches reports whether the stack can be cooked with the recipe.
+func (r *Cooking) Matches(s item.ItemStack) bool { return r.Ingredient.Test(s) }
+
+func (r *Cooking) bookCategory() int32 {
+	switch r.typ {
+	case Blasting:
+		if r.Category == "blocks" {
+			return categoryBlastFurnaceBlocks
+		}
+		return categoryBlastFurnaceMisc
+	case Smoking:
+		return categorySmokerFood
+	case CampfireCooking:
+		return categoryCampfire
+	}
+	switch r.Category {
+	case "food":
+		return categoryFurnaceFood
+	case "blocks":
+		return categoryFurnaceBlocks
+	default:
+		return categoryFurnaceMisc
+	}
+}
+
+func (r *Cooking) ingredients() []*Ingredient  { return []*Ingredient{r.Ingredient} }
+func (r *Cooking) requirements() []*Ingredient { return r.ingredients() }
+
+func (r *Cooking) display(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.VarInt(recipeDisplayFurnace),
+		r.Ingredient.display(),
+		anyFuelDisplay{},
+		stackDisplay(r.Result),
+		cookingStations[r.typ],
+		pk.VarInt(r.CookingTime),
+		pk.Float(r.Experience),
+	}.WriteTo(w)
+}
+
+var cookingStations = map[Type]slotDisplay{
+	Smelting:        itemDisplay(mustItem("minecraft:furnace")),
+	Blasting:        itemDisplay(mustItem("minecraft:blast_furnace")),
+	Smoking:         itemDisplay(mustItem("minecraft:smoker")),
+	CampfireCooking: itemDisplay(mustItem("minecraft:campfire")),
+}
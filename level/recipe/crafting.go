@@ -0,0 +1,329 @@
+package recipe
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// CraftingInput is the items of a crafting grid, trimmed to the smallest rectangle holding them.
+type CraftingInput struct {
+	Width, Height int
+	// Items are the stacks row by row.
+	Items []item.ItemStack
+}
+
+// NewCraftingInput returns the input of a crafting grid of the size, whose items are row by row.
+func NewCraftingInput(width, height int, items []item.ItemStack) CraftingInput {
+	left, top, right, bottom := width, height, -1, -1
+	for i, s := range items {
+		if s.IsEmpty() {
+			continue
+		}
+		x, y := i%width, i/width
+		left, right = min(left, x), max(right, x)
+		top, bottom = min(top, y), max(bottom, y)
+	}
+	if right < 0 {
+		return CraftingInput{}
+	}
+	in := CraftingInput{Width: right - left + 1, Height: bottom - top + 1}
+	for y := top; y <= bottom; y++ {
+		in.Items = append(in.Items, items[y*width+left:y*width+right+1]...)
+	}
+	return in
+}
+
+// Item returns the stack at the column x and the row y.
+func (in CraftingInput) Item(x, y int) item.ItemStack {
+	return in.Items[y*in.Width+x]
+}
+
+// ingredientCount returns the number of stacks in the grid.
+func (in CraftingInput) ingredientCount() (n int) {
+	for _, s := range in.Items {
+		if !s.IsEmpty() {
+			n++
+		}
+	}
+	return
+}
+
+// CraftingRecipe is a recipe made in a crafting grid.
+type CraftingRecipe interface {
+	Recipe
+	// Matches reports whether the items are the ingredients of the recipe.
+	Matches(in CraftingInput) bool
+	// Assemble returns the result of the recipe made with the items, which match the recipe.
+	Assemble(in CraftingInput) item.ItemStack
+	// Arrange returns the ingredient of each slot of a grid of the size, row by row,
+	// to make the recipe from the recipe book. The empty slots are nil.
+	// It reports false if the recipe doesn't fit in the grid.
+	Arrange(width, height int) ([]*Ingredient, bool)
+}
+
+type crafting struct {
+	group
+	Category string `json:"category"`
+}
+
+func (crafting) Type() Type            { return Crafting }
+func (c crafting) bookCategory() int32 { return craftingCategory(c.Category) }
+
+// Shaped is a crafting recipe whose ingredients are at given places in the grid.
+// The pattern can be mirrored horizontally.
+type Shaped struct {
+	crafting
+	Width, Height int
+	// Ingredients are the ingredients of the pattern row by row, nil for the empty slots.
+	Ingredients []*Ingredient
+	Result      item.ItemStack
+}
+
+func (r *Shaped) UnmarshalJSON(data []byte) error {
+	var v struct {
+		crafting
+		Key     map[string]*Ingredient `json:"key"`
+		Pattern []string               `json:"pattern"`
+		Result  result                 `json:"result"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Pattern) == 0 || len(v.Pattern) > 3 {
+		return errors.New("invalid pattern height")
+	}
+	r.crafting, r.Result = v.crafting, item.ItemStack(v.Result)
+	r.Width, r.Height = len(v.Pattern[0]), len(v.Pattern)
+	if r.Width == 0 || r.Width > 3 {
+		return errors.New("invalid pattern width")
+	}
+	used := make(map[string]bool)
+	for _, row := range v.Pattern {
+		if len(row) != r.Width {
+			return errors.New("pattern rows must be the same width")
+		}
+		for _, c := range row {
+			if c == ' ' {
+				r.Ingredients = append(r.Ingredients, nil)
+				continue
+			}
+			in, ok := v.Key[string(c)]
+			if !ok {
+				return errors.New("pattern references undefined symbol '" + string(c) + "'")
+			}
+			used[string(c)] = true
+			r.Ingredients = append(r.Ingredients, in)
+		}
+	}
+	if len(used) != len(v.Key) {
+		return errors.New("key defines symbols that aren't used in pattern")
+	}
+	return nil
+}
+
+func (r *Shaped) Matches(in CraftingInput) bool {
+	if in.Width != r.Width || in.Height != r.Height {
+		return false
+	}
+	return r.matches(in, false) || r.matches(in, true)
+}
+
+func (r *Shaped) matches(in CraftingInput, mirrored bool) bool {
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			ingredient := r.Ingredients[y*r.Width+x]
+			if mirrored {
+				ingredient = r.Ingredients[y*r.Width+r.Width-1-x]
+			}
+			s := in.Item(x, y)
+			if ingredient == nil && !s.IsEmpty() || ingredient != nil && !ingredient.Test(s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *Shaped) Assemble(CraftingInput) item.ItemStack { return r.Result.Copy() }
+
+func (r *Shaped) Arrange(width, height int) ([]*Ingredient, bool) {
+	if r.Width > width || r.Height > height {
+		return nil, false
+	}
+	slots := make([]*Ingredient, width*height)
+	for y := 0; y < r.Height; y++ {
+		copy(slots[y*width:], r.Ingredients[y*r.Width:(y+1)*r.Width])
+	}
+	return slots, true
+}
+
+func (r *Shaped) ingredients() []*Ingredient { return r.requirements() }
+
+func (r *Shaped) requirements() (ingredients []*Ingredient) {
+	for _, in := range r.Ingredients {
+		if in != nil {
+			ingredients = append(ingredients, in)
+		}
+	}
+	return
+}
+
+func (r *Shaped) display(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.VarInt(recipeDisplayShaped),
+		pk.VarInt(r.Width),
+		pk.VarInt(r.Height),
+		pk.Array(ingredientsDisplay(r.Ingredients)),
+		stackDisplay(r.Result),
+		craftingTable,
+	}.WriteTo(w)
+}
+
+// Shapeless is a crafting recipe whose ingredients can be anywhere in the grid.
+type Shapeless struct {
+	crafting
+	Ingredients []*Ingredient
+	Result      item.ItemStack
+}
+
+func (r *Shapeless) UnmarshalJSON(data []byte) error {
+	var v struct {
+		crafting
+		Ingredients []*Ingredient `json:"ingredients"`
+		Result      result        `json:"result"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Ingredients) == 0 || len(v.Ingredients) > 9 {
+		return errors.New("invalid number of ingredients")
+	}
+	r.crafting, r.Ingredients, r.Result = v.crafting, v.Ingredients, item.ItemStack(v.Result)
+	return nil
+}
+
+func (r *Shapeless) Matches(in CraftingInput) bool {
+	if in.ingredientCount() != len(r.Ingredients) {
+		return false
+	}
+	var stacks []item.ItemStack
+	for _, s := range in.Items {
+		if !s.IsEmpty() {
+			stacks = append(stacks, s)
+		}
+	}
+	return matchAll(r.Ingredients, stacks)
+}
+
+// matchAll reports whether each stack can be given to a different ingredient,
+// by finding a matching of the bipartite graph with augmenting paths.
+func matchAll(ingredients []*Ingredient, stacks []item.ItemStack) bool {
+	owner := make([]int, len(ingredients)) // the stack given to each ingredient, plus one
+	var assign func(s int, visited []bool) bool
+	assign = func(s int, visited []bool) bool {
+		for i, in := range ingredients {
+			if visited[i] || !in.Test(stacks[s]) {
+				continue
+			}
+			visited[i] = true
+			if owner[i] == 0 || assign(owner[i]-1, visited) {
+				owner[i] = s + 1
+				return true
+			}
+		}
+		return false
+	}
+	for s := range stacks {
+		if !assign(s, make([]bool, len(ingredients))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Shapeless) Assemble(CraftingInput) item.ItemStack { return r.Result.Copy() }
+
+func (r *Shapeless) Arrange(width, height int) ([]*Ingredient, bool) {
+	if len(r.Ingredients) > width*height {
+		return nil, false
+	}
+	slots := make([]*Ingredient, width*height)
+	copy(slots, r.Ingredients)
+	return slots, true
+}
+
+func (r *Shapeless) ingredients() []*Ingredient  { return r.Ingredients }
+func (r *Shapeless) requirements() []*Ingredient { return r.Ingredients }
+
+func (r *Shapeless) display(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.VarInt(recipeDisplayShapeless),
+		pk.Array(ingredientsDisplay(r.Ingredients)),
+		stackDisplay(r.Result),
+		craftingTable,
+	}.WriteTo(w)
+}
+
+// Transmute is a crafting recipe changing the item of the input with a material,
+// keeping the components of the input, like dyeing shulker boxes.
+type Transmute struct {
+	crafting
+	Input    *Ingredient `json:"input"`
+	Material *Ingredient `json:"material"`
+	Result   item.ID     `json:"result"`
+}
+
+func (r *Transmute) Matches(in CraftingInput) bool {
+	if in.ingredientCount() != 2 {
+		return false
+	}
+	var input, material bool
+	for _, s := range in.Items {
+		switch {
+		case s.IsEmpty():
+		case !input && r.Input.Test(s):
+			input = true
+		case !material && r.Material.Test(s):
+			material = true
+		default:
+			return false
+		}
+	}
+	return input && material
+}
+
+func (r *Transmute) Assemble(in CraftingInput) item.ItemStack {
+	for _, s := range in.Items {
+		if r.Input.Test(s) {
+			s = s.Copy()
+			s.ItemID, s.Count = r.Result, 1
+			return s
+		}
+	}
+	return item.ItemStack{}
+}
+
+func (r *Transmute) Arrange(width, height int) ([]*Ingredient, bool) {
+	if width*height < 2 {
+		return nil, false
+	}
+	slots := make([]*Ingredient, width*height)
+	slots[0], slots[1] = r.Input, r.Material
+	return slots, true
+}
+
+func (r *Transmute) ingredients() []*Ingredient  { return []*Ingredient{r.Input, r.Material} }
+func (r *Transmute) requirements() []*Ingredient { return r.ingredients() }
+
+func (r *Transmute) display(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.VarInt(recipeDisplayShapeless),
+		pk.Array(ingredientsDisplay(r.requirements())),
+		itemDisplay(r.Result),
+		craftingTable,
+	}.WriteTo(w)
+}
@@ -0,0 +1,78 @@
+package recipe
+
+import (
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// The ids in the minecraft:slot_display registry.
+const (
+	slotDisplayEmpty int32 = iota
+	slotDisplayAnyFuel
+	slotDisplayItem
+	slotDisplayItemStack
+	slotDisplayTag
+	slotDisplaySmithingTrim
+	slotDisplayWithRemainder
+	slotDisplayComposite
+)
+
+// The ids in the minecraft:recipe_display registry.
+const (
+	recipeDisplayShapeless int32 = iota
+	recipeDisplayShaped
+	recipeDisplayFurnace
+	recipeDisplayStonecutter
+	recipeDisplaySmithing
+)
+
+// slotDisplay is how the client shows a slot of a recipe in the recipe book.
+type slotDisplay = pk.FieldEncoder
+
+type emptyDisplay struct{}
+
+func (emptyDisplay) WriteTo(w io.Writer) (int64, error) {
+	return pk.VarInt(slotDisplayEmpty).WriteTo(w)
+}
+
+type anyFuelDisplay struct{}
+
+func (anyFuelDisplay) WriteTo(w io.Writer) (int64, error) {
+	return pk.VarInt(slotDisplayAnyFuel).WriteTo(w)
+}
+
+type itemDisplay item.ID
+
+func (d itemDisplay) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.VarInt(slotDisplayItem), pk.VarInt(d)}.WriteTo(w)
+}
+
+type stackDisplay item.ItemStack
+
+func (d stackDisplay) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.VarInt(slotDisplayItemStack), item.ItemStack(d)}.WriteTo(w)
+}
+
+type compositeDisplay []slotDisplay
+
+func (d compositeDisplay) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.VarInt(slotDisplayComposite), pk.Array([]slotDisplay(d))}.WriteTo(w)
+}
+
+// The blocks the recipes are made with.
+var (
+	craftingTable = itemDisplay(mustItem("minecraft:crafting_table"))
+	stonecutter   = itemDisplay(mustItem("minecraft:stonecutter"))
+	smithingTable = itemDisplay(mustItem("minecraft:smithing_table"))
+)
+
+// ingredientsDisplay returns the displays of the ingredients, where nil is an empty slot.
+func ingredientsDisplay(ingredients []*Ingredient) []slotDisplay {
+	displays := make([]slotDisplay, len(ingredients))
+	for i, in := range ingredients {
+		displays[i] = in.display()
+	}
+	return displays
+}
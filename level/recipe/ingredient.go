@@ -0,0 +1,96 @@
+package recipe
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Ingredient is the items accepted in a slot of a recipe.
+//
+// In JSON, it's an item id, an item tag prefixed with # or a list of item ids.
+// The tags are resolved to the items when the recipes are loaded.
+type Ingredient struct {
+	// Tag is the item tag of the ingredient, if any.
+	Tag string
+	// Items are the accepted items.
+	Items []item.ID
+}
+
+// Test reports whether the stack is one of the items of the ingredient.
+func (i *Ingredient) Test(s item.ItemStack) bool {
+	return !s.IsEmpty() && slices.Contains(i.Items, s.ItemID)
+}
+
+func (i *Ingredient) UnmarshalJSON(data []byte) error {
+	var names []string
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &names); err != nil {
+			return err
+		}
+	} else {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		if tag, ok := strings.CutPrefix(name, "#"); ok {
+			i.Tag = resourceLocation(tag)
+			return nil
+		}
+		names = []string{name}
+	}
+	if len(names) == 0 {
+		return errors.New("empty ingredient")
+	}
+	for _, name := range names {
+		var id item.ID
+		if err := id.UnmarshalText([]byte(resourceLocation(name))); err != nil {
+			return err
+		}
+		i.Items = append(i.Items, id)
+	}
+	return nil
+}
+
+// WriteTo writes the ingredient as a set of item ids.
+// The tags are sent as their items, since the client doesn't know the item tags.
+func (i *Ingredient) WriteTo(w io.Writer) (int64, error) {
+	n, err := pk.VarInt(len(i.Items) + 1).WriteTo(w)
+	for _, id := range i.Items {
+		if err != nil {
+			return n, err
+		}
+		var n1 int64
+		n1, err = pk.VarInt(id).WriteTo(w)
+		n += n1
+	}
+	return n, err
+}
+
+// display returns how the ingredient is shown in the recipe book.
+func (i *Ingredient) display() slotDisplay {
+	if i == nil {
+		return emptyDisplay{}
+	}
+	if len(i.Items) == 1 {
+		return itemDisplay(i.Items[0])
+	}
+	items := make(compositeDisplay, len(i.Items))
+	for j, id := range i.Items {
+		items[j] = itemDisplay(id)
+	}
+	return items
+}
+
+// resourceLocation adds the default namespace to the id if it has none.
+func resourceLocation(id string) string {
+	if strings.Contains(id, ":") {
+		return id
+	}
+	return "minecraft:" + id
+}
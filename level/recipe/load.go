@@ -0,0 +1,207 @@
+package recipe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// Load reads the recipes and the item tags of the data packs, the latter overriding the former.
+// The recipes which can't be read are reported in the error,
+// the Manager returned has all the others.
+func Load(packs ...fs.FS) (*Manager, error) {
+	recipes := make(map[string][]byte)
+	tags := make(map[string][]tagEntry)
+	for _, pack := range packs {
+		err := fs.WalkDir(pack, "data", func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || path.Ext(p) != ".json" {
+				return err
+			}
+			// data/<namespace>/<kind>/<path>.json
+			parts := strings.SplitN(strings.TrimSuffix(p, ".json"), "/", 4)
+			if len(parts) < 4 {
+				return nil
+			}
+			namespace, rest := parts[1], parts[2]+"/"+parts[3]
+			var id string
+			switch {
+			case strings.HasPrefix(rest, "recipe/"):
+				id = namespace + ":" + strings.TrimPrefix(rest, "recipe/")
+			case strings.HasPrefix(rest, "tags/item/"):
+				id = namespace + ":" + strings.TrimPrefix(rest, "tags/item/")
+			default:
+				return nil
+			}
+			data, err := fs.ReadFile(pack, p)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(rest, "recipe/") {
+				recipes[id] = data
+				return nil
+			}
+			var tag struct {
+				Replace bool       `json:"replace"`
+				Values  []tagEntry `json:"values"`
+			}
+			if err := json.Unmarshal(data, &tag); err != nil {
+				return fmt.Errorf("item tag %s: %w", id, err)
+			}
+			if tag.Replace {
+				tags[id] = nil
+			}
+			tags[id] = append(tags[id], tag.Values...)
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	r := resolver{tags: tags, items: make(map[string][]item.ID)}
+	m := &Manager{byID: make(map[string]int32)}
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(recipes)) {
+		recipe, err := r.decode(recipes[id])
+		switch {
+		case errors.Is(err, errSpecial):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("recipe %s: %w", id, err))
+			continue
+		}
+		m.byID[id] = int32(len(m.recipes))
+		m.recipes = append(m.recipes, Holder{ID: id, Recipe: recipe})
+	}
+	return m, errors.Join(errs...)
+}
+
+// tagEntry is a value of an item tag, an item id, a tag prefixed with #,
+// or {"id": ..., "required": false} for the optional ones.
+type tagEntry struct {
+	ID       string
+	Required bool
+}
+
+func (e *tagEntry) UnmarshalJSON(data []byte) error {
+	e.Required = true
+	if len(data) > 0 && data[0] == '{' {
+		v := struct {
+			ID       string `json:"id"`
+			Required *bool  `json:"required"`
+		}{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		e.ID = v.ID
+		if v.Required != nil {
+			e.Required = *v.Required
+		}
+		return nil
+	}
+	return json.Unmarshal(data, &e.ID)
+}
+
+// errSpecial is returned for the recipes whose type isn't supported.
+var errSpecial = errors.New("special recipe")
+
+type resolver struct {
+	tags  map[string][]tagEntry
+	items map[string][]item.ID // the resolved tags
+}
+
+func (r *resolver) decode(data []byte) (Recipe, error) {
+	var typ struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typ); err != nil {
+		return nil, err
+	}
+	var recipe Recipe
+	switch resourceLocation(typ.Type) {
+	case "minecraft:crafting_shaped":
+		recipe = new(Shaped)
+	case "minecraft:crafting_shapeless":
+		recipe = new(Shapeless)
+	case "minecraft:crafting_transmute":
+		recipe = new(Transmute)
+	case "minecraft:smelting":
+		recipe = &Cooking{typ: Smelting}
+	case "minecraft:blasting":
+		recipe = &Cooking{typ: Blasting}
+	case "minecraft:smoking":
+		recipe = &Cooking{typ: Smoking}
+	case "minecraft:campfire_cooking":
+		recipe = &Cooking{typ: CampfireCooking}
+	case "minecraft:stonecutting":
+		recipe = new(Stonecutter)
+	case "minecraft:smithing_transform":
+		recipe = new(SmithingTransform)
+	default:
+		return nil, errSpecial
+	}
+	if err := json.Unmarshal(data, recipe); err != nil {
+		return nil, err
+	}
+	for _, in := range recipe.ingredients() {
+		if in == nil {
+			return nil, errors.New("missing ingredient")
+		}
+		if in.Tag == "" {
+			continue
+		}
+		items, err := r.resolve(in.Tag, nil)
+		if err != nil {
+			return nil, err
+		}
+		in.Items = items
+	}
+	return recipe, nil
+}
+
+// resolve returns the items of the tag. The tags being resolved are in the stack, to detect the cycles.
+func (r *resolver) resolve(tag string, stack []string) ([]item.ID, error) {
+	if items, ok := r.items[tag]; ok {
+		return items, nil
+	}
+	entries, ok := r.tags[tag]
+	if !ok {
+		return nil, errors.New("unknown item tag #" + tag)
+	}
+	if slices.Contains(stack, tag) {
+		return nil, errors.New("item tag #" + tag + " references itself")
+	}
+	var items []item.ID
+	for _, e := range entries {
+		if name, ok := strings.CutPrefix(e.ID, "#"); ok {
+			values, err := r.resolve(resourceLocation(name), append(stack, tag))
+			if err != nil {
+				if !e.Required {
+					continue
+				}
+				return nil, err
+			}
+			items = append(items, values...)
+			continue
+		}
+		var id item.ID
+		if err := id.UnmarshalText([]byte(resourceLocation(e.ID))); err != nil {
+			if !e.Required {
+				continue
+			}
+			return nil, err
+		}
+		if !slices.Contains(items, id) {
+			items = append(items, id)
+		}
+	}
+	r.items[tag] = items
+	return items, nil
+}
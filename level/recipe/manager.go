@@ -0,0 +1,196 @@
+package recipe
+
+import (
+	"io"
+	"slices"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Holder is a recipe with its id, like minecraft:oak_planks.
+type Holder struct {
+	ID     string
+	Recipe Recipe
+}
+
+// Display returns the recipe as shown in the recipe book, for the ClientboundPlaceGhostRecipe packet.
+func (h Holder) Display() pk.FieldEncoder { return displayEncoder{h.Recipe} }
+
+type displayEncoder struct{ Recipe }
+
+func (d displayEncoder) WriteTo(w io.Writer) (int64, error) { return d.display(w) }
+
+// Manager holds the recipes loaded from the data packs.
+// The recipes are identified by their index, which is also their id in the recipe book of the clients.
+type Manager struct {
+	recipes []Holder
+	byID    map[string]int32
+}
+
+// Recipes returns all the recipes, sorted by id.
+func (m *Manager) Recipes() []Holder { return m.recipes }
+
+// Get returns the recipe of the index.
+func (m *Manager) Get(index int32) (Holder, bool) {
+	if index < 0 || int(index) >= len(m.recipes) {
+		return Holder{}, false
+	}
+	return m.recipes[index], true
+}
+
+// Index returns the index of the recipe of the id.
+func (m *Manager) Index(id string) (int32, bool) {
+	i, ok := m.byID[id]
+	return i, ok
+}
+
+// Crafting returns the first crafting recipe matching the items of the grid.
+func (m *Manager) Crafting(in CraftingInput) (CraftingRecipe, bool) {
+	if len(in.Items) == 0 {
+		return nil, false
+	}
+	for _, h := range m.recipes {
+		if r, ok := h.Recipe.(CraftingRecipe); ok && r.Matches(in) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Cooking returns the recipe of the type cooking the stack,
+// which is Smelting, Blasting, Smoking or CampfireCooking.
+func (m *Manager) Cooking(typ Type, s item.ItemStack) (*Cooking, bool) {
+	for _, h := range m.recipes {
+		if r, ok := h.Recipe.(*Cooking); ok && r.typ == typ && r.Matches(s) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Stonecutting returns the recipes of the stonecutter for the stack.
+func (m *Manager) Stonecutting(s item.ItemStack) (recipes []*Stonecutter) {
+	for _, h := range m.recipes {
+		if r, ok := h.Recipe.(*Stonecutter); ok && r.Matches(s) {
+			recipes = append(recipes, r)
+		}
+	}
+	return
+}
+
+// Smithing returns the first smithing recipe matching the items.
+func (m *Manager) Smithing(in SmithingInput) (*SmithingTransform, bool) {
+	for _, h := range m.recipes {
+		if r, ok := h.Recipe.(*SmithingTransform); ok && r.Matches(in) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// BookEntries returns the entries of the recipe book of all the recipes,
+// which are sent in the ClientboundRecipeBookAdd packet to unlock them.
+func (m *Manager) BookEntries() []BookEntry {
+	groups := make(map[string]int32)
+	entries := make([]BookEntry, len(m.recipes))
+	for i, h := range m.recipes {
+		entries[i] = BookEntry{ID: int32(i), Recipe: h.Recipe}
+		if name := h.Recipe.Group(); name != "" {
+			if _, ok := groups[name]; !ok {
+				groups[name] = int32(len(groups))
+			}
+			entries[i].Group = groups[name] + 1
+		}
+	}
+	return entries
+}
+
+// BookEntry is a recipe shown in the recipe book of the client.
+type BookEntry struct {
+	// ID is the index of the recipe, which the client sends back when the recipe is clicked.
+	ID     int32
+	Recipe Recipe
+	// Group is the id of the group of the recipe plus one, or 0 if it has none.
+	Group int32
+	// Notification shows a toast when the recipe is unlocked.
+	Notification bool
+	// Highlight marks the recipe as new in the recipe book.
+	Highlight bool
+}
+
+func (e BookEntry) WriteTo(w io.Writer) (int64, error) {
+	var flags byte
+	if e.Notification {
+		flags |= 1
+	}
+	if e.Highlight {
+		flags |= 2
+	}
+	requirements := e.Recipe.requirements()
+	return pk.Tuple{
+		pk.VarInt(e.ID),
+		displayEncoder{e.Recipe},
+		pk.VarInt(e.Group),
+		pk.VarInt(e.Recipe.bookCategory()),
+		pk.Boolean(requirements != nil),
+		pk.Opt{Has: requirements != nil, Field: pk.Array(requirements)},
+		pk.Byte(flags),
+	}.WriteTo(w)
+}
+
+// UpdateRecipes returns the content of the ClientboundUpdateRecipes packet,
+// the items accepted by the furnaces and the smithing tables, and the recipes of the stonecutter.
+func (m *Manager) UpdateRecipes() pk.FieldEncoder {
+	sets := make(map[string][]item.ID)
+	add := func(set string, in *Ingredient) {
+		if in == nil {
+			return
+		}
+		for _, id := range in.Items {
+			if !slices.Contains(sets[set], id) {
+				sets[set] = append(sets[set], id)
+			}
+		}
+	}
+	var stonecutter []pk.FieldEncoder
+	for _, h := range m.recipes {
+		switch r := h.Recipe.(type) {
+		case *Cooking:
+			add(cookingInputs[r.typ], r.Ingredient)
+		case *SmithingTransform:
+			add("minecraft:smithing_template", r.Template)
+			add("minecraft:smithing_base", r.Base)
+			add("minecraft:smithing_addition", r.Addition)
+		case *Stonecutter:
+			stonecutter = append(stonecutter, pk.Tuple{r.Ingredient, stackDisplay(r.Result)})
+		}
+	}
+	var propertySets []pk.FieldEncoder
+	for _, name := range propertySetNames {
+		ids := make([]pk.VarInt, len(sets[name]))
+		for i, id := range sets[name] {
+			ids[i] = pk.VarInt(id)
+		}
+		propertySets = append(propertySets, pk.Tuple{pk.Identifier(name), pk.Array(ids)})
+	}
+	return pk.Tuple{pk.Array(propertySets), pk.Array(stonecutter)}
+}
+
+// propertySetNames are the sets of items of the ClientboundUpdateRecipes packet.
+var propertySetNames = []string{
+	"minecraft:smithing_base",
+	"minecraft:smithing_template",
+	"minecraft:smithing_addition",
+	"minecraft:furnace_input",
+	"minecraft:blast_furnace_input",
+	"minecraft:smoker_input",
+	"minecraft:campfire_input",
+}
+
+var cookingInputs = map[Type]string{
+	Smelting:        "minecraft:furnace_input",
+	Blasting:        "minecraft:blast_furnace_input",
+	Smoking:         "minecraft:smoker_input",
+	CampfireCooking: "minecraft:campfire_input",
+}
@@ -0,0 +1,130 @@
+// Package recipe loads the recipes of the data packs and finds the ones matching the items of the players.
+//
+// The recipes are read from the JSON files of the vanilla data packs,
+// in data/<namespace>/recipe, with the item tags they use in data/<namespace>/tags/item.
+// The special crafting recipes, like dyeing armor or cloning books,
+// and the armor trims aren't supported and are ignored.
+package recipe
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// Type is the id of a recipe type in the minecraft:recipe_type registry.
+type Type int32
+
+const (
+	Crafting Type = iota
+	Smelting
+	Blasting
+	Smoking
+	CampfireCooking
+	Stonecutting
+	Smithing
+)
+
+// The ids in the minecraft:recipe_book_category registry.
+const (
+	categoryCraftingBuildingBlocks int32 = iota
+	categoryCraftingRedstone
+	categoryCraftingEquipment
+	categoryCraftingMisc
+	categoryFurnaceFood
+	categoryFurnaceBlocks
+	categoryFurnaceMisc
+	categoryBlastFurnaceBlocks
+	categoryBlastFurnaceMisc
+	categorySmokerFood
+	categoryStonecutter
+	categorySmithing
+	categoryCampfire
+)
+
+// Recipe is a recipe of any type.
+type Recipe interface {
+	Type() Type
+	// Group is the name grouping the recipe with similar ones in the recipe book, or empty.
+	Group() string
+
+	// bookCategory returns the id of the tab of the recipe book showing the recipe.
+	bookCategory() int32
+	// ingredients returns the ingredients of the recipe, which must not be nil.
+	ingredients() []*Ingredient
+	// requirements returns the ingredients the client looks for in the inventory to show the recipe as craftable.
+	requirements() []*Ingredient
+	// display writes the recipe as shown in the recipe book.
+	display(w io.Writer) (int64, error)
+}
+
+// group is the group of the recipes in the recipe book.
+type group struct {
+	Name string `json:"group"`
+}
+
+func (g group) Group() string { return g.Name }
+
+// craftingCategory returns the recipe book tab of the category of a crafting recipe.
+func craftingCategory(category string) int32 {
+	switch category {
+	case "building":
+		return categoryCraftingBuildingBlocks
+	case "redstone":
+		return categoryCraftingRedstone
+	case "equipment":
+		return categoryCraftingEquipment
+	default:
+		return categoryCraftingMisc
+	}
+}
+
+// result is the item stack made by a recipe, written {"id": "minecraft:stone", "count": 1} in JSON.
+// The components of the result aren't supported.
+type result item.ItemStack
+
+func (r *result) UnmarshalJSON(data []byte) error {
+	v := struct {
+		ID         item.ID         `json:"id"`
+		Count      int32           `json:"count"`
+		Components json.RawMessage `json:"components"`
+	}{Count: 1}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Components != nil {
+		return errUnsupported("components of the result")
+	}
+	*r = result(item.New(v.ID, v.Count))
+	return nil
+}
+
+type errUnsupported string
+
+func (e errUnsupported) Error() string { return string(e) + " isn't supported" }
+
+// craftingRemainders are the items left in the crafting grid after using the items as ingredient.
+var craftingRemainders = map[item.ID]item.ID{
+	mustItem("minecraft:water_bucket"):  mustItem("minecraft:bucket"),
+	mustItem("minecraft:lava_bucket"):   mustItem("minecraft:bucket"),
+	mustItem("minecraft:milk_bucket"):   mustItem("minecraft:bucket"),
+	mustItem("minecraft:honey_bottle"):  mustItem("minecraft:glass_bottle"),
+	mustItem("minecraft:dragon_breath"): mustItem("minecraft:glass_bottle"),
+}
+
+// Remainder returns the item left in the crafting grid after crafting with the stack, like the empty buckets.
+func Remainder(s item.ItemStack) item.ItemStack {
+	if id, ok := craftingRemainders[s.ItemID]; ok && !s.IsEmpty() {
+		return item.New(id, 1)
+	}
+	return item.ItemStack{}
+}
+
+func mustItem(name string) item.ID {
+	var id item.ID
+	if err := id.UnmarshalText([]byte(name)); err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,130 @@
+package recipe
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+func stack(name string, count int32) item.ItemStack {
+	return item.New(mustItem(name), count)
+}
+
+func TestLoad(t *testing.T) {
+	m, err := Load(Builtin, fstest.MapFS{
+		"data/minecraft/tags/item/planks.json": {Data: []byte(`{"replace": true, "values": ["minecraft:birch_planks", {"id": "foo:bar", "required": false}]}`)},
+		"data/minecraft/recipe/stick.json":     {Data: []byte(`{"type": "minecraft:crafting_special_bookcloning"}`)},
+		"data/foo/recipe/broken.json":          {Data: []byte(`{"type": "minecraft:crafting_shaped", "key": {}, "pattern": ["#"]}`)},
+	})
+	if err == nil {
+		t.Error("the broken recipe should be reported")
+	}
+	if _, ok := m.Index("foo:broken"); ok {
+		t.Error("the broken recipe was loaded")
+	}
+	if _, ok := m.Index("minecraft:stick"); ok {
+		t.Error("the special recipe replacing the sticks was loaded")
+	}
+	i, ok := m.Index("minecraft:crafting_table")
+	if !ok {
+		t.Fatal("the crafting table recipe is missing")
+	}
+	h, _ := m.Get(i)
+	planks := h.Recipe.(*Shaped).Ingredients[0]
+	if len(planks.Items) != 1 || planks.Items[0] != mustItem("minecraft:birch_planks") {
+		t.Errorf("the planks tag wasn't replaced: %v", planks.Items)
+	}
+}
+
+func TestManager_Crafting(t *testing.T) {
+	m, err := Load(Builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := item.ItemStack{}
+	for _, tt := range []struct {
+		name   string
+		width  int
+		items  []item.ItemStack
+		result item.ItemStack
+	}{
+		{"shapeless anywhere", 2, []item.ItemStack{empty, empty, empty, stack("minecraft:oak_log", 1)}, stack("minecraft:oak_planks", 4)},
+		{"shaped with tag", 2, []item.ItemStack{empty, stack("minecraft:oak_planks", 1), empty, stack("minecraft:birch_planks", 5)}, stack("minecraft:stick", 4)},
+		{"any of the items", 3, []item.ItemStack{
+			empty, stack("minecraft:charcoal", 1), empty,
+			empty, stack("minecraft:stick", 1), empty,
+			empty, empty, empty,
+		}, stack("minecraft:torch", 4)},
+		{"wrong shape", 3, []item.ItemStack{
+			stack("minecraft:oak_planks", 1), empty, empty,
+			empty, stack("minecraft:oak_planks", 1), empty,
+			empty, empty, empty,
+		}, empty},
+		{"too many items", 2, []item.ItemStack{stack("minecraft:oak_log", 1), stack("minecraft:oak_log", 1), empty, empty}, empty},
+		{"empty grid", 2, make([]item.ItemStack, 4), empty},
+	} {
+		in := NewCraftingInput(tt.width, len(tt.items)/tt.width, tt.items)
+		var got item.ItemStack
+		if r, ok := m.Crafting(in); ok {
+			got = r.Assemble(in)
+		}
+		if !item.Equal(got, tt.result) {
+			t.Errorf("%s: crafted %v, want %v", tt.name, got, tt.result)
+		}
+	}
+}
+
+func TestShaped_mirrored(t *testing.T) {
+	axe := new(Shaped)
+	if err := axe.UnmarshalJSON([]byte(`{"type": "minecraft:crafting_shaped",
+		"key": {"#": "minecraft:stick", "X": ["minecraft:stone", "minecraft:cobblestone"]},
+		"pattern": ["XX", "X#", " #"], "result": {"id": "minecraft:stone_axe"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	empty := item.ItemStack{}
+	in := NewCraftingInput(3, 3, []item.ItemStack{
+		empty, stack("minecraft:cobblestone", 1), stack("minecraft:stone", 1),
+		empty, stack("minecraft:stick", 1), stack("minecraft:stone", 1),
+		empty, stack("minecraft:stick", 1), empty,
+	})
+	if !axe.Matches(in) {
+		t.Error("the mirrored pattern doesn't match")
+	}
+}
+
+func TestMatchAll(t *testing.T) {
+	a, b := stack("minecraft:stone", 1), stack("minecraft:dirt", 1)
+	either := &Ingredient{Items: []item.ID{a.ItemID, b.ItemID}}
+	onlyA := &Ingredient{Items: []item.ID{a.ItemID}}
+	// the first stack must not be given to the ingredient accepting both
+	if !matchAll([]*Ingredient{either, onlyA}, []item.ItemStack{a, b}) {
+		t.Error("the items should match")
+	}
+	if matchAll([]*Ingredient{onlyA, onlyA}, []item.ItemStack{a, b}) {
+		t.Error("the items shouldn't match")
+	}
+}
+
+func TestManager_packets(t *testing.T) {
+	m, err := Load(Builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	for _, e := range m.BookEntries() {
+		if _, err := e.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.UpdateRecipes().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Stonecutting(stack("minecraft:stone", 1))) != 2 {
+		t.Error("there should be 2 stonecutter recipes of the stone")
+	}
+	if r, ok := m.Cooking(Smelting, stack("minecraft:stripped_oak_log", 1)); !ok || r.Result.ItemID != mustItem("minecraft:charcoal") {
+		t.Error("the log should be smelted to charcoal")
+	}
+}
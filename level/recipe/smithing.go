@@ -0,0 +1,92 @@
+package recipe
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// SmithingInput is the items of a smithing table.
+type SmithingInput struct {
+	Template, Base, Addition item.ItemStack
+}
+
+// SmithingTransform is a recipe of the smithing table changing the item of the base,
+// keeping its components, like upgrading to netherite.
+type SmithingTransform struct {
+	group
+	// Template and Addition are nil if the slot must be empty.
+	Template *Ingredient
+	Base     *Ingredient
+	Addition *Ingredient
+	Result   item.ItemStack
+}
+
+func (r *SmithingTransform) UnmarshalJSON(data []byte) error {
+	var v struct {
+		group
+		Template *Ingredient `json:"template"`
+		Base     *Ingredient `json:"base"`
+		Addition *Ingredient `json:"addition"`
+		Result   result      `json:"result"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.group, r.Template, r.Base, r.Addition = v.group, v.Template, v.Base, v.Addition
+	r.Result = item.ItemStack(v.Result)
+	return nil
+}
+
+func (r *SmithingTransform) Type() Type { return Smithing }
+
+// Matches reports whether the items are the ingredients of the recipe.
+func (r *SmithingTransform) Matches(in SmithingInput) bool {
+	return testOptional(r.Template, in.Template) && r.Base.Test(in.Base) && testOptional(r.Addition, in.Addition)
+}
+
+// Assemble returns the base changed to the result, with the components of the base.
+func (r *SmithingTransform) Assemble(in SmithingInput) item.ItemStack {
+	s := in.Base.Copy()
+	s.ItemID, s.Count = r.Result.ItemID, r.Result.Count
+	return s
+}
+
+func testOptional(in *Ingredient, s item.ItemStack) bool {
+	if in == nil {
+		return s.IsEmpty()
+	}
+	return in.Test(s)
+}
+
+func (r *SmithingTransform) bookCategory() int32 { return categorySmithing }
+
+func (r *SmithingTransform) ingredients() []*Ingredient {
+	ingredients := []*Ingredient{r.Base}
+	for _, in := range []*Ingredient{r.Template, r.Addition} {
+		if in != nil {
+			ingredients = append(ingredients, in)
+		}
+	}
+	return ingredients
+}
+
+func (r *SmithingTransform) requirements() []*Ingredient {
+	if r.Template == nil || r.Addition == nil {
+		return nil
+	}
+	return []*Ingredient{r.Template, r.Base, r.Addition}
+}
+
+func (r *SmithingTransform) display(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.VarInt(recipeDisplaySmithing),
+		r.Template.display(),
+		r.Base.display(),
+		r.Addition.display(),
+		stackDisplay(r.Result),
+		smithingTable,
+	}.WriteTo(w)
+}
@@ -0,0 +1,47 @@
+package recipe
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Stonecutter is a recipe of the stonecutter.
+type Stonecutter struct {
+	group
+	Ingredient *Ingredient
+	Result     item.ItemStack
+}
+
+func (r *Stonecutter) UnmarshalJSON(data []byte) error {
+	var v struct {
+		group
+		Ingredient *Ingredient `json:"ingredient"`
+		Result     result      `json:"result"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.group, r.Ingredient, r.Result = v.group, v.Ingredient, item.ItemStack(v.Result)
+	return nil
+}
+
+func (r *Stonecutter) Type() Type { return Stonecutting }
+
+// Matches reports whether the stack can be cut with the recipe.
+func (r *Stonecutter) Matches(s item.ItemStack) bool { return r.Ingredient.Test(s) }
+
+func (r *Stonecutter) bookCategory() int32         { return categoryStonecutter }
+func (r *Stonecutter) ingredients() []*Ingredient  { return []*Ingredient{r.Ingredient} }
+func (r *Stonecutter) requirements() []*Ingredient { return r.ingredients() }
+
+func (r *Stonecutter) display(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.VarInt(recipeDisplayStonecutter),
+		r.Ingredient.display(),
+		stackDisplay(r.Result),
+		stonecutter,
+	}.WriteTo(w)
+}
@@ -17,9 +17,13 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
+	"os/signal"
 	"runtime/debug"
 	"strings"
+	"syscall"
 
 	"github.com/BurntSushi/toml"
 	"go.uber.org/zap"
@@ -90,11 +94,18 @@ func main() {
 		},
 		GamePlay: gp,
 	}
-	logger.Info("Start listening", zap.String("address", config.ListenAddress))
-	err = s.Listen(config.ListenAddress)
-	if err != nil {
-		logger.Error("Server listening error", zap.Error(err))
-	}
+	// the worlds are saved when the server is stopped, or when it can't listen anymore
+	defer gp.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		defer stop()
+		logger.Info("Start listening", zap.String("address", config.ListenAddress))
+		if err := s.Listen(config.ListenAddress); err != nil {
+			logger.Error("Server listening error", zap.Error(err))
+		}
+	}()
+	<-ctx.Done()
 }
 
 // printBuildInfo reading compile information of the binary program with runtime/debug package，and print it to log
@@ -410,6 +410,10 @@ func isEmptyValue(v reflect.Value) bool {
 		return v.Float() == 0
 	case reflect.Interface, reflect.Pointer:
 		return v.IsNil()
+	case reflect.Struct:
+		if m, ok := v.Interface().(RawMessage); ok {
+			return m.Type == TagEnd
+		}
 	}
 	return false
 }
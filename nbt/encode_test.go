@@ -281,9 +281,10 @@ func TestEncoder_Encode_textMarshaler(t *testing.T) {
 
 func TestEncoder_Encode_omitempty(t *testing.T) {
 	type Struct struct {
-		S string `nbt:"test,omitempty"`
-		B []byte `nbt:",omitempty"`
-		I int32  `nbt:",omitempty"`
+		S string     `nbt:"test,omitempty"`
+		B []byte     `nbt:",omitempty"`
+		I int32      `nbt:",omitempty"`
+		R RawMessage `nbt:",omitempty"`
 	}
 
 	tests := []struct {
@@ -304,12 +305,14 @@ func TestEncoder_Encode_omitempty(t *testing.T) {
 				S: "ab",
 				B: []byte{4, 5},
 				I: 9,
+				R: RawMessage{Type: TagByte, Data: []byte{7}},
 			},
 			want: []byte{
 				TagCompound, 0x00, 0x00,
 				TagString, 0x00, 4, 't', 'e', 's', 't', 0, 2, 'a', 'b',
 				TagByteArray, 0x00, 1, 'B', 0x00, 0x00, 0, 2, 4, 5,
 				TagInt, 0x00, 1, 'I', 0x00, 0x00, 0x00, 0x09,
+				TagByte, 0x00, 1, 'R', 7,
 				TagEnd,
 			},
 		},
@@ -57,6 +57,56 @@ func NewNetworkCodec() Registries {
 		},
 		MonsterSpawnBlockLightLimit: 0,
 	})
+	// The chunks of every dimension have the 24 sections of the overworld,
+	// so the nether and the end keep its min_y and height instead of the vanilla ones.
+	def.Put("minecraft:the_nether", Dimension{
+		FixedTime:          18000,
+		HasSkylight:        false,
+		HasCeiling:         true,
+		Ultrawarm:          true,
+		Natural:            false,
+		CoordinateScale:    8.0,
+		BedWorks:           false,
+		RespawnAnchorWorks: 1,
+		MinY:               -64,
+		Height:             384,
+		LogicalHeight:      128,
+		InfiniteBurn:       "#minecraft:infiniburn_nether",
+		Effects:            "minecraft:the_nether",
+		AmbientLight:       0.1,
+		PiglinSafe:         1,
+		HasRaids:           0,
+		MonsterSpawnLightLevel: MonsterSpawnLightLevel{
+			Min_inclusive: 7,
+			Max_inclusive: 7,
+			Type_:         "minecraft:uniform",
+		},
+		MonsterSpawnBlockLightLimit: 15,
+	})
+	def.Put("minecraft:the_end", Dimension{
+		FixedTime:          6000,
+		HasSkylight:        false,
+		HasCeiling:         false,
+		Ultrawarm:          false,
+		Natural:            false,
+		CoordinateScale:    1.0,
+		BedWorks:           false,
+		RespawnAnchorWorks: 0,
+		MinY:               -64,
+		Height:             384,
+		LogicalHeight:      256,
+		InfiniteBurn:       "#minecraft:infiniburn_end",
+		Effects:            "minecraft:the_end",
+		AmbientLight:       0.0,
+		PiglinSafe:         0,
+		HasRaids:           1,
+		MonsterSpawnLightLevel: MonsterSpawnLightLevel{
+			Min_inclusive: 0,
+			Max_inclusive: 7,
+			Type_:         "minecraft:uniform",
+		},
+		MonsterSpawnBlockLightLimit: 0,
+	})
 
 	return Registries{
 		ChatType:        NewRegistry[ChatType](),
@@ -82,8 +132,8 @@ type DamageType struct {
 	MessageID        string  `nbt:"message_id" json:"message_id"`
 	Scaling          string  `nbt:"scaling" json:"scaling"`
 	Exhaustion       float32 `nbt:"exhaustion" json:"exhaustion"`
-	Effects          string  `nbt:"effects,omitempty"`
-	DeathMessageType string  `nbt:"death_message_type,omitempty"`
+	Effects          string  `nbt:"effects,omitempty" json:"effects,omitempty"`
+	DeathMessageType string  `nbt:"death_message_type,omitempty" json:"death_message_type,omitempty"`
 }
 
 type Dimension struct {
@@ -100,7 +100,13 @@ func (r *Registry[E]) WriteTo(w io.Writer) (n int64, err error) {
 		return
 	}
 
+	// The client numbers the entries in the order they are sent,
+	// so they must be written in the order of their ids.
+	keys := make([]string, len(r.values))
 	for key, id := range r.keys {
+		keys[id] = key
+	}
+	for id, key := range keys {
 		_n, err = pk.Identifier(key).WriteTo(w)
 		if err != nil {
 			return
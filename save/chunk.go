@@ -13,20 +13,20 @@ import (
 // Chunk is 16* chunk
 type Chunk struct {
 	BlockEntities  []nbt.RawMessage `nbt:"block_entities"`
-	BlockTicks     nbt.RawMessage   `nbt:"block_ticks"`
+	BlockTicks     []ScheduledTick  `nbt:"block_ticks,omitempty"`
 	CarvingMasks   map[string][]uint64
 	DataVersion    int32
 	Entities       []nbt.RawMessage    `nbt:"entities"`
-	FluidTicks     nbt.RawMessage      `nbt:"fluid_ticks"`
+	FluidTicks     []ScheduledTick     `nbt:"fluid_ticks,omitempty"`
 	Heightmaps     map[string][]uint64 // keys: "WORLD_SURFACE_WG", "WORLD_SURFACE", "WORLD_SURFACE_IGNORE_SNOW", "OCEAN_FLOOR_WG", "OCEAN_FLOOR", "MOTION_BLOCKING", "MOTION_BLOCKING_NO_LEAVES"
 	InhabitedTime  int64
 	IsLightOn      byte `nbt:"isLightOn"`
 	LastUpdate     int64
 	Lights         []nbt.RawMessage
-	PostProcessing nbt.RawMessage
-	Sections       []Section `nbt:"sections"`
+	PostProcessing nbt.RawMessage `nbt:",omitempty"`
+	Sections       []Section      `nbt:"sections"`
 	Status         string
-	Structures     nbt.RawMessage `nbt:"structures"`
+	Structures     nbt.RawMessage `nbt:"structures,omitempty"`
 	XPos           int32          `nbt:"xPos"`
 	YPos           int32          `nbt:"yPos"`
 	ZPos           int32          `nbt:"zPos"`
@@ -40,6 +40,18 @@ type Section struct {
 	BlockLight  []byte
 }
 
+// ScheduledTick is a block or fluid tick scheduled in the chunk.
+type ScheduledTick struct {
+	// ID is the block or fluid the tick is for.
+	ID string `nbt:"i"`
+	X  int32  `nbt:"x"`
+	Y  int32  `nbt:"y"`
+	Z  int32  `nbt:"z"`
+	// Delay is the number of game ticks left.
+	Delay    int32 `nbt:"t"`
+	Priority int32 `nbt:"p"`
+}
+
 type PaletteContainer[T any] struct {
 	Palette []T      `nbt:"palette"`
 	Data    []uint64 `nbt:"data"`
@@ -47,7 +59,7 @@ type PaletteContainer[T any] struct {
 
 type BlockState struct {
 	Name       string
-	Properties nbt.RawMessage
+	Properties nbt.RawMessage `nbt:",omitempty"`
 }
 
 type BiomeState string
@@ -92,7 +104,16 @@ func (c *Chunk) Data(compressingType byte) ([]byte, error) {
 		w = &buff
 	}
 	err := nbt.NewEncoder(w).Encode(c, "")
-	return buff.Bytes(), err
+	if err != nil {
+		return nil, err
+	}
+	// flush the compressor
+	if closer, ok := w.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buff.Bytes(), nil
 }
 
 type Entities struct {
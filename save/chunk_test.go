@@ -71,3 +71,40 @@ func BenchmarkColumn_Load(b *testing.B) {
 		}
 	}
 }
+
+func TestChunk_Data(t *testing.T) {
+	want := Chunk{
+		DataVersion: 4189,
+		XPos:        3,
+		YPos:        -4,
+		ZPos:        -40,
+		Status:      "minecraft:full",
+		BlockTicks:  []ScheduledTick{{ID: "minecraft:sand", X: 48, Y: 70, Z: -634, Delay: 2, Priority: -1}},
+		Sections: []Section{{
+			Y: -4,
+			BlockStates: PaletteContainer[BlockState]{
+				Palette: []BlockState{{Name: "minecraft:stone"}},
+			},
+			Biomes: PaletteContainer[BiomeState]{
+				Palette: []BiomeState{"minecraft:plains"},
+			},
+		}},
+	}
+	for _, compression := range []byte{1, 2, 3} {
+		data, err := want.Data(compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Chunk
+		if err := got.Load(data); err != nil {
+			t.Fatalf("load chunk compressed by %d fail: %v", compression, err)
+		}
+		if got.XPos != want.XPos || got.ZPos != want.ZPos || got.Status != want.Status || len(got.Sections) != 1 ||
+			got.Sections[0].BlockStates.Palette[0].Name != "minecraft:stone" {
+			t.Errorf("chunk compressed by %d mismatch: %+v", compression, got)
+		}
+		if len(got.BlockTicks) != 1 || got.BlockTicks[0] != want.BlockTicks[0] || got.FluidTicks != nil {
+			t.Errorf("the scheduled ticks of the chunk compressed by %d are %v, %v", compression, got.BlockTicks, got.FluidTicks)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package save
 import (
 	"io"
 
+	"github.com/mrhaoxx/go-mc/level/item"
 	"github.com/mrhaoxx/go-mc/nbt"
 )
 
@@ -69,14 +70,18 @@ type PlayerData struct {
 	} `nbt:"recipeBook"`
 }
 
+// Item is an item stack in an inventory or a container, with the index of its slot.
 type Item struct {
-	Count byte
-	Slot  byte
-	ID    string         `nbt:"id"`
-	Tag   map[string]any `nbt:"tag"`
+	Slot byte
+	item.ItemStack
 }
 
 func ReadPlayerData(r io.Reader) (data PlayerData, err error) {
 	_, err = nbt.NewDecoder(r).Decode(&data)
 	return
 }
+
+// WritePlayerData writes the player data in NBT, which is gzipped in the .dat files.
+func WritePlayerData(w io.Writer, data *PlayerData) error {
+	return nbt.NewEncoder(w).Encode(data, "")
+}
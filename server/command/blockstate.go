@@ -0,0 +1,116 @@
+package command
+
+import (
+	"encoding"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/nbt"
+)
+
+// BlockStateParser parses a block with optional properties and block entity data,
+// like minecraft:oak_stairs[facing=east,half=top] or chest{Items:[]}.
+// The properties not given take the value of the default state.
+type BlockStateParser struct{}
+
+// BlockStateData is the value of BlockStateParser.
+type BlockStateData struct {
+	State block.StateID
+	// NBT is the block entity data, or empty if not given.
+	NBT nbt.StringifiedMessage
+}
+
+func (BlockStateParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:block_state").WriteTo(w)
+}
+
+func (BlockStateParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	id, left, err := readResourceLocation(cmd)
+	if err != nil {
+		return cmd, nil, err
+	}
+	state, ok := block.DefaultState(id)
+	if !ok {
+		return cmd, nil, ParseErr{Err: "unknown block type '" + id + "'"}
+	}
+	var data BlockStateData
+	if len(left) > 0 && left[0] == '[' {
+		start := len(cmd) - len(left)
+		b := block.StateList[state]
+		b, left, err = parseProperties(id, b, left)
+		if err != nil {
+			perr := err.(ParseErr)
+			perr.Pos += start
+			return cmd, nil, perr
+		}
+		if state, ok = block.ToStateID[b]; !ok {
+			return cmd, nil, ParseErr{Pos: start, Err: "block " + id + " does not accept the properties"}
+		}
+	}
+	data.State = state
+	if len(left) > 0 && left[0] == '{' {
+		start := len(cmd) - len(left)
+		data.NBT, left, err = readSNBT(left)
+		if err != nil {
+			perr := err.(ParseErr)
+			perr.Pos += start
+			return cmd, nil, perr
+		}
+	}
+	return left, data, nil
+}
+
+// parseProperties parses the properties in the brackets and sets them to b.
+func parseProperties(id string, b block.Block, cmd string) (block.Block, string, error) {
+	v := reflect.New(reflect.TypeOf(b)).Elem()
+	v.Set(reflect.ValueOf(b))
+	seen := make(map[string]bool)
+	left := strings.TrimLeft(cmd[1:], " ")
+	for len(left) == 0 || left[0] != ']' {
+		pos := len(cmd) - len(left)
+		key, rest := readUnquoted(left)
+		if key == "" {
+			return nil, cmd, ParseErr{Pos: pos, Err: "expected property name"}
+		}
+		if seen[key] {
+			return nil, cmd, ParseErr{Pos: pos, Err: "property '" + key + "' can only be set once for block " + id}
+		}
+		seen[key] = true
+		field := propertyField(v, key)
+		if !field.IsValid() {
+			return nil, cmd, ParseErr{Pos: pos, Err: "block " + id + " does not have property '" + key + "'"}
+		}
+		rest = strings.TrimLeft(rest, " ")
+		if len(rest) == 0 || rest[0] != '=' {
+			return nil, cmd, ParseErr{Pos: len(cmd) - len(rest), Err: "expected value for property '" + key + "' on block " + id}
+		}
+		rest = strings.TrimLeft(rest[1:], " ")
+		pos = len(cmd) - len(rest)
+		value, rest := readUnquoted(rest)
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return nil, cmd, ParseErr{Pos: pos, Err: "block " + id + " does not accept '" + value + "' for " + key + " property"}
+		}
+		left = strings.TrimLeft(rest, " ")
+		switch {
+		case len(left) > 0 && left[0] == ',':
+			left = strings.TrimLeft(left[1:], " ")
+		case len(left) > 0 && left[0] == ']':
+		default:
+			return nil, cmd, ParseErr{Pos: len(cmd) - len(left), Err: "expected closing ] for block state properties"}
+		}
+	}
+	return v.Interface().(block.Block), left[1:], nil
+}
+
+// propertyField returns the field of the block property, which is tagged with the property name.
+func propertyField(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("nbt") == name {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
@@ -34,6 +34,12 @@ type LiteralBuilder struct {
 	current *Node
 }
 
+// Requires sets the permission level needed to see and use the node.
+func (n LiteralBuilder) Requires(level int) LiteralBuilder {
+	n.current.Permission = level
+	return n
+}
+
 func (n LiteralBuilder) AppendLiteral(node *Literal) LiteralBuilderWithLiteral {
 	n.current.Children = append(n.current.Children, node.index)
 	return LiteralBuilderWithLiteral{n: n}
@@ -49,14 +55,28 @@ func (n LiteralBuilder) HandleFunc(f HandlerFunc) *Literal {
 	return (*Literal)(n.current)
 }
 
+// Unhandle finishes the node without a handler,
+// so the command is incomplete without one of its children.
 func (n LiteralBuilder) Unhandle() *Literal {
-	return n.HandleFunc(unhandledCmd)
+	return n.HandleFunc(nil)
 }
 
 type ArgumentBuilder struct {
 	current *Node
 }
 
+// Requires sets the permission level needed to see and use the node.
+func (n ArgumentBuilder) Requires(level int) ArgumentBuilder {
+	n.current.Permission = level
+	return n
+}
+
+// Suggests sets the provider of the suggestions for the argument.
+func (n ArgumentBuilder) Suggests(p SuggestionProvider) ArgumentBuilder {
+	n.current.Suggests = p
+	return n
+}
+
 func (n ArgumentBuilder) AppendLiteral(node *Literal) ArgumentBuilderWithLiteral {
 	n.current.Children = append(n.current.Children, node.index)
 	return ArgumentBuilderWithLiteral{n: n}
@@ -72,8 +92,10 @@ func (n ArgumentBuilder) HandleFunc(f HandlerFunc) *Argument {
 	return (*Argument)(n.current)
 }
 
+// Unhandle finishes the node without a handler,
+// so the command is incomplete without one of its children.
 func (n ArgumentBuilder) Unhandle() *Argument {
-	return n.HandleFunc(unhandledCmd)
+	return n.HandleFunc(nil)
 }
 
 type LiteralBuilderWithLiteral struct {
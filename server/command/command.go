@@ -26,32 +26,37 @@ func NewGraph() *Graph {
 	return &g
 }
 
+// Execute parses the command and runs the handler of the last node.
+// Nodes requiring a higher permission level than the Source in ctx are treated as not existing.
+// Parsing errors are returned as ParseErr, with the Pos in cmd.
 func (g *Graph) Execute(ctx context.Context, cmd string) error {
-	var args []ParsedData
-	node := g.nodes[0] // root
-	for {
-		// parser command
-		left, value, err := node.parse(cmd)
-		if err != nil {
-			return err
-		}
-		args = append(args, value)
-		left = strings.TrimSpace(left)
-		if len(left) == 0 {
-			return node.Run(ctx, args)
+	level := permissionLevel(ctx)
+	args := []ParsedData{nil} // the root node
+	node := g.nodes[0]
+	left := cmd
+	for len(left) > 0 {
+		if node.kind != RootNode {
+			if left[0] != ' ' {
+				return ParseErr{Pos: len(cmd) - len(left), Err: "expected whitespace to end one argument, but found trailing data"}
+			}
+			left = left[1:]
 		}
-		// find next node
-		next, err := node.next(left)
+		next, rest, value, err := node.next(left, level)
 		if err != nil {
+			var perr ParseErr
+			if errors.As(err, &perr) {
+				perr.Pos += len(cmd) - len(left)
+				return perr
+			}
 			return err
 		}
-		if next == 0 {
-			return errors.New("command contains extra text: " + left)
-		}
-
-		cmd = left
-		node = g.nodes[next]
+		args = append(args, value)
+		node, left = next, rest
 	}
+	if node.Run == nil {
+		return ParseErr{Pos: len(cmd), Err: "unknown or incomplete command"}
+	}
+	return node.Run(ctx, args)
 }
 
 type ParsedData any
@@ -64,70 +69,56 @@ type Node struct {
 	index int32
 	kind  byte
 
-	Name            string
-	Children        []int32
+	Name     string
+	Children []int32
+	// SuggestionsType is the id of the suggestions computed by the client, like minecraft:summonable_entities.
 	SuggestionsType string
-	Parser          Parser
-	Run             HandlerFunc
+	// Suggests makes the client ask the server for the suggestions of the argument.
+	Suggests SuggestionProvider
+	Parser   Parser
+	Run      HandlerFunc
+	// Permission is the permission level required to see and use the node.
+	Permission int
 }
 type (
 	Literal  Node
 	Argument Node
 )
 
-func (n *Node) parse(cmd string) (left string, value ParsedData, err error) {
-	switch n.kind & 0x03 {
-	case RootNode:
-		left = cmd
-		value = nil
-		err = nil
-
-	case LiteralNode:
-		if !strings.HasPrefix(cmd, n.Name) {
-			panic("expect " + cmd + " prefixed with " + n.Name)
-		}
-		left = strings.TrimPrefix(cmd, n.Name)
-		value = LiteralData(n.Name)
-
-	case ArgumentNode:
-		left, value, err = n.Parser.Parse(cmd)
-
-	default:
-		panic("unreachable")
+// next finds the child matching the beginning of cmd, literals are tried first.
+// It returns the child, the text after the parsed part and the parsed value.
+func (n *Node) next(cmd string, level int) (next *Node, left string, value ParsedData, err error) {
+	word := cmd
+	if i := strings.IndexByte(cmd, ' '); i != -1 {
+		word = cmd[:i]
 	}
-	return
-}
-
-func (n *Node) next(left string) (next int32, err error) {
-	if len(n.Children) == 0 {
-		return 0, nil
+	for _, i := range n.Children {
+		child := n.g.nodes[i]
+		if child.kind == LiteralNode && child.Permission <= level && child.Name == word {
+			return child, cmd[len(word):], LiteralData(child.Name), nil
+		}
 	}
-	// look up the first child's type
-	switch n.g.nodes[n.Children[0]].kind & 0x03 {
-	case RootNode:
-		panic("root node can't be child")
-	default:
-		panic("unreachable")
-	case LiteralNode:
-		_, value, err := StringParser(0).Parse(strings.TrimSpace(left))
-		if err != nil {
-			return 0, err
+	for _, i := range n.Children {
+		child := n.g.nodes[i]
+		if child.kind != ArgumentNode || child.Permission > level {
+			continue
 		}
-		literal := value.(string)
-		for _, i := range n.Children {
-			if n.g.nodes[i].Name == literal {
-				next = i
-				break
-			}
+		left, value, perr := child.Parser.Parse(cmd)
+		if perr == nil {
+			return child, left, value, nil
+		}
+		if err == nil {
+			err = perr
 		}
-	case ArgumentNode:
-		next = n.Children[0]
 	}
-	return
-}
-
-func unhandledCmd(context.Context, []ParsedData) error {
-	return errors.New("unhandled function")
+	if err == nil {
+		if n.kind == RootNode {
+			err = ParseErr{Err: "unknown command"}
+		} else {
+			err = ParseErr{Err: "incorrect argument for command"}
+		}
+	}
+	return nil, cmd, nil, err
 }
 
 type LiteralData string
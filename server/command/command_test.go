@@ -1,8 +1,11 @@
 package command
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"log"
+	"slices"
 	"testing"
 )
 
@@ -31,3 +34,81 @@ func TestRoot_Run(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type testSource int
+
+func (s testSource) PermissionLevel() int { return int(s) }
+
+func TestGraph_permission(t *testing.T) {
+	var ran string
+	handler := func(name string) HandlerFunc {
+		return func(ctx context.Context, args []ParsedData) error {
+			ran = name
+			return nil
+		}
+	}
+	g := NewGraph()
+	g.AppendLiteral(g.Literal("ping").
+		HandleFunc(handler("ping")),
+	).AppendLiteral(g.Literal("stop").Requires(4).
+		HandleFunc(handler("stop")),
+	)
+
+	player := WithSource(context.TODO(), testSource(0))
+	if err := g.Execute(player, "ping"); err != nil || ran != "ping" {
+		t.Fatalf("ping: ran %q, err %v", ran, err)
+	}
+	var perr ParseErr
+	if err := g.Execute(player, "stop"); !errors.As(err, &perr) {
+		t.Fatalf("stop without permission: want ParseErr, got %v", err)
+	}
+	op := WithSource(context.TODO(), testSource(4))
+	if err := g.Execute(op, "stop"); err != nil || ran != "stop" {
+		t.Fatalf("stop: ran %q, err %v", ran, err)
+	}
+
+	var player0, op4 bytes.Buffer
+	if _, err := g.Visible(0).WriteTo(&player0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Visible(4).WriteTo(&op4); err != nil {
+		t.Fatal(err)
+	}
+	if player0.Len() >= op4.Len() {
+		t.Errorf("the graph visible to players should be smaller: %d >= %d", player0.Len(), op4.Len())
+	}
+}
+
+func TestGraph_Suggest(t *testing.T) {
+	g := NewGraph()
+	g.AppendLiteral(g.Literal("setblock").
+		AppendArgument(g.Argument("pos", BlockPosParser{}).Suggests(SuggestBlockPos).
+			AppendArgument(g.Argument("block", BlockStateParser{}).Suggests(SuggestBlocks).
+				HandleFunc(func(context.Context, []ParsedData) error { return nil })).
+			Unhandle()).
+		Unhandle(),
+	).AppendLiteral(g.Literal("seed").
+		HandleFunc(func(context.Context, []ParsedData) error { return nil }),
+	)
+
+	for _, tt := range []struct {
+		cmd   string
+		start int
+		want  []string
+	}{
+		{"se", 0, []string{"setblock", "seed"}},
+		{"setblock ", 9, []string{"~", "~ ~", "~ ~ ~"}},
+		{"setblock 1 2", 9, []string{"1 2 ~"}},
+		{"setblock 1 2 3 oak_pla", 15, []string{"minecraft:oak_planks"}},
+		{"seed x", 5, nil},
+	} {
+		start, suggestions := g.Suggest(context.TODO(), tt.cmd)
+		var got []string
+		for _, s := range suggestions {
+			got = append(got, s.Text)
+		}
+		if start != tt.start || !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q) = %d, %q, want %d, %q", tt.cmd, start, got, tt.start, tt.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package command
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Coordinate is one component of a position argument.
+type Coordinate struct {
+	Value float64
+	// Relative is true for coordinates prefixed with ~, or ^ for local coordinates.
+	Relative bool
+}
+
+// Coordinates is the value of the position parsers.
+// Relative coordinates are offsets from the position of the source,
+// local coordinates are offsets along the left, up and forwards axes of the source's rotation.
+// The parsers of 2D positions leave Y relative with no offset.
+type Coordinates struct {
+	X, Y, Z Coordinate
+	Local   bool
+}
+
+// Resolve returns the position from a source at pos with the rotation of yaw and pitch in degrees.
+func (c Coordinates) Resolve(pos [3]float64, rot [2]float32) [3]float64 {
+	if c.Local {
+		return localToWorld(pos, rot, c.X.Value, c.Y.Value, c.Z.Value)
+	}
+	res := [3]float64{c.X.Value, c.Y.Value, c.Z.Value}
+	for i, v := range [3]Coordinate{c.X, c.Y, c.Z} {
+		if v.Relative {
+			res[i] += pos[i]
+		}
+	}
+	return res
+}
+
+// BlockPos returns the position of the block containing the resolved position.
+func (c Coordinates) BlockPos(pos [3]float64, rot [2]float32) [3]int {
+	p := c.Resolve(pos, rot)
+	return [3]int{int(math.Floor(p[0])), int(math.Floor(p[1])), int(math.Floor(p[2]))}
+}
+
+// localToWorld is how vanilla resolves the ^left ^up ^forwards coordinates.
+func localToWorld(pos [3]float64, rot [2]float32, left, up, forwards float64) [3]float64 {
+	const rad = math.Pi / 180
+	yaw, pitch := float64(rot[0]), float64(rot[1])
+	f, f1 := math.Cos((yaw+90)*rad), math.Sin((yaw+90)*rad)
+	f2, f3 := math.Cos(-pitch*rad), math.Sin(-pitch*rad)
+	f4, f5 := math.Cos((-pitch+90)*rad), math.Sin((-pitch+90)*rad)
+	fw := [3]float64{f * f2, f3, f1 * f2}
+	u := [3]float64{f * f4, f5, f1 * f4}
+	// l = -(fw × u)
+	l := [3]float64{
+		-(fw[1]*u[2] - fw[2]*u[1]),
+		-(fw[2]*u[0] - fw[0]*u[2]),
+		-(fw[0]*u[1] - fw[1]*u[0]),
+	}
+	var res [3]float64
+	for i := range res {
+		res[i] = pos[i] + fw[i]*forwards + u[i]*up + l[i]*left
+	}
+	return res
+}
+
+// BlockPosParser parses the x, y and z of a block, like 1 ~2 ~-3 or ^ ^1 ^.
+type BlockPosParser struct{}
+
+func (BlockPosParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:block_pos").WriteTo(w)
+}
+
+func (BlockPosParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	return parseCoordinates(cmd, 3, true, false)
+}
+
+// ColumnPosParser parses the x and z of a block column.
+type ColumnPosParser struct{}
+
+func (ColumnPosParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:column_pos").WriteTo(w)
+}
+
+func (ColumnPosParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	return parseCoordinates(cmd, 2, true, false)
+}
+
+// Vec3Parser parses a position, like 1.5 ~ ~-3 or ^ ^1 ^.
+type Vec3Parser struct {
+	// Exact disables the vanilla centering, which adds 0.5 to the x and z written as integers.
+	Exact bool
+}
+
+func (Vec3Parser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:vec3").WriteTo(w)
+}
+
+func (p Vec3Parser) Parse(cmd string) (left string, value ParsedData, err error) {
+	return parseCoordinates(cmd, 3, false, !p.Exact)
+}
+
+// Vec2Parser parses the x and z of a position.
+type Vec2Parser struct {
+	// Exact disables the vanilla centering, which adds 0.5 to the x and z written as integers.
+	Exact bool
+}
+
+func (Vec2Parser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:vec2").WriteTo(w)
+}
+
+func (p Vec2Parser) Parse(cmd string) (left string, value ParsedData, err error) {
+	return parseCoordinates(cmd, 2, false, !p.Exact)
+}
+
+// parseCoordinates parses n space separated coordinates, which are x, y and z, or x and z.
+// Local coordinates are only allowed for 3 coordinates.
+func parseCoordinates(cmd string, n int, integer, center bool) (left string, value ParsedData, err error) {
+	var c Coordinates
+	c.Local = n == 3 && len(cmd) > 0 && cmd[0] == '^'
+	c.Y.Relative = n == 2
+	axes := []*Coordinate{&c.X, &c.Y, &c.Z}
+	if n == 2 {
+		axes = []*Coordinate{&c.X, &c.Z}
+	}
+	left = cmd
+	for i, axis := range axes {
+		if i > 0 {
+			if len(left) == 0 || left[0] != ' ' {
+				return cmd, nil, ParseErr{Pos: len(cmd) - len(left), Err: "incomplete (expected " + strconv.Itoa(n) + " coordinates)"}
+			}
+			left = left[1:]
+		}
+		pos := len(cmd) - len(left)
+		if c.Local {
+			*axis, left, err = parseLocal(left)
+		} else {
+			// Only x and z are centered
+			*axis, left, err = parseWorld(left, integer, center && axis != &c.Y)
+		}
+		if err != nil {
+			perr := err.(ParseErr)
+			perr.Pos += pos
+			return cmd, nil, perr
+		}
+	}
+	return left, c, nil
+}
+
+var errMixedCoordinates = ParseErr{Err: "cannot mix world & local coordinates (everything must either use ^ or not)"}
+
+func parseWorld(cmd string, integer, center bool) (c Coordinate, left string, err error) {
+	if len(cmd) == 0 {
+		return c, cmd, ParseErr{Err: "expected coordinate"}
+	}
+	left = cmd
+	switch left[0] {
+	case '^':
+		return c, cmd, errMixedCoordinates
+	case '~':
+		c.Relative = true
+		left = left[1:]
+	}
+	num, left := readNumber(left)
+	if num == "" {
+		if c.Relative {
+			return c, left, nil
+		}
+		return c, cmd, ParseErr{Err: "expected coordinate"}
+	}
+	if integer && !c.Relative {
+		v, err := strconv.ParseInt(num, 10, 32)
+		if err != nil {
+			return c, cmd, ParseErr{Err: "invalid integer '" + num + "'"}
+		}
+		c.Value = float64(v)
+		return c, left, nil
+	}
+	c.Value, err = strconv.ParseFloat(num, 64)
+	if err != nil {
+		return c, cmd, ParseErr{Err: "invalid double '" + num + "'"}
+	}
+	if center && !c.Relative && !strings.Contains(num, ".") {
+		c.Value += 0.5
+	}
+	return c, left, nil
+}
+
+func parseLocal(cmd string) (c Coordinate, left string, err error) {
+	if len(cmd) == 0 || cmd[0] != '^' {
+		return c, cmd, errMixedCoordinates
+	}
+	c.Relative = true
+	num, left := readNumber(cmd[1:])
+	if num == "" {
+		return c, left, nil
+	}
+	c.Value, err = strconv.ParseFloat(num, 64)
+	if err != nil {
+		return c, cmd, ParseErr{Err: "invalid double '" + num + "'"}
+	}
+	return c, left, nil
+}
@@ -0,0 +1,139 @@
+package command
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/nbt"
+)
+
+// ItemStackParser parses an item with optional data components,
+// like minecraft:diamond_sword[damage=5,!unbreakable].
+type ItemStackParser struct{}
+
+// ItemStackData is the value of ItemStackParser.
+type ItemStackData struct {
+	// Item is the id in the minecraft:item registry.
+	Item int32
+	// Components are the values of the data components set in the brackets.
+	Components map[string]nbt.StringifiedMessage
+	// Removed are the data components removed from the default ones with a !.
+	Removed []string
+}
+
+func (ItemStackParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:item_stack").WriteTo(w)
+}
+
+func (ItemStackParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	id, left, err := readResourceLocation(cmd)
+	if err != nil {
+		return cmd, nil, err
+	}
+	item := slices.Index(registryid.Item, id)
+	if item == -1 {
+		return cmd, nil, ParseErr{Err: "unknown item '" + id + "'"}
+	}
+	data := ItemStackData{Item: int32(item)}
+	if len(left) == 0 || left[0] != '[' {
+		return left, data, nil
+	}
+	start := len(cmd) - len(left)
+	left, err = parseComponents(&data, left)
+	if err != nil {
+		perr := err.(ParseErr)
+		perr.Pos += start
+		return cmd, nil, perr
+	}
+	return left, data, nil
+}
+
+// parseComponents parses the data components in the brackets.
+func parseComponents(data *ItemStackData, cmd string) (string, error) {
+	seen := make(map[string]bool)
+	left := strings.TrimLeft(cmd[1:], " ")
+	for len(left) == 0 || left[0] != ']' {
+		pos := len(cmd) - len(left)
+		removed := len(left) > 0 && left[0] == '!'
+		if removed {
+			left = left[1:]
+		}
+		key, rest, err := readResourceLocation(left)
+		if err != nil {
+			return cmd, ParseErr{Pos: pos, Err: "expected component"}
+		}
+		if !slices.Contains(registryid.DataComponentType, key) {
+			return cmd, ParseErr{Pos: pos, Err: "unknown component '" + key + "'"}
+		}
+		if seen[key] {
+			return cmd, ParseErr{Pos: pos, Err: "component '" + key + "' can only be specified once"}
+		}
+		seen[key] = true
+		rest = strings.TrimLeft(rest, " ")
+		if removed {
+			data.Removed = append(data.Removed, key)
+		} else {
+			if len(rest) == 0 || rest[0] != '=' {
+				return cmd, ParseErr{Pos: len(cmd) - len(rest), Err: "expected value for component '" + key + "'"}
+			}
+			rest = strings.TrimLeft(rest[1:], " ")
+			pos = len(cmd) - len(rest)
+			value, after, err := readComponentValue(rest)
+			if err != nil {
+				perr := err.(ParseErr)
+				perr.Pos += pos
+				return cmd, perr
+			}
+			if data.Components == nil {
+				data.Components = make(map[string]nbt.StringifiedMessage)
+			}
+			data.Components[key] = value
+			rest = after
+		}
+		left = strings.TrimLeft(rest, " ")
+		switch {
+		case len(left) > 0 && left[0] == ',':
+			left = strings.TrimLeft(left[1:], " ")
+		case len(left) > 0 && left[0] == ']':
+		default:
+			return cmd, ParseErr{Pos: len(cmd) - len(left), Err: "expected closing ] for item components"}
+		}
+	}
+	return left[1:], nil
+}
+
+// readComponentValue reads a value in the stringified NBT format, ending before a top level , or ].
+func readComponentValue(cmd string) (nbt.StringifiedMessage, string, error) {
+	if len(cmd) > 0 && (cmd[0] == '{' || cmd[0] == '[') {
+		return readSNBT(cmd)
+	}
+	end := len(cmd)
+	var quote byte
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quote = c
+		} else if c == ',' || c == ']' {
+			end = i
+			break
+		}
+	}
+	value := nbt.StringifiedMessage(strings.TrimRight(cmd[:end], " "))
+	if value == "" {
+		return "", cmd, ParseErr{Err: "expected value"}
+	}
+	if err := value.MarshalNBT(io.Discard); err != nil {
+		return "", cmd, ParseErr{Err: "invalid NBT: " + err.Error()}
+	}
+	return value, cmd[len(value):], nil
+}
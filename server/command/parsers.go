@@ -1,14 +1,21 @@
 package command
 
 import (
+	"fmt"
 	"io"
+	"math"
 	"strconv"
 	"strings"
 
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	"github.com/mrhaoxx/go-mc/nbt"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
 
+// Parser parses an argument at the beginning of cmd.
+// WriteTo writes the parser identifier and properties sent to the client.
 type Parser interface {
+	pk.FieldEncoder
 	Parse(cmd string) (left string, value ParsedData, err error)
 }
 
@@ -16,7 +23,7 @@ type StringParser int32
 
 func (s StringParser) WriteTo(w io.Writer) (int64, error) {
 	return pk.Tuple{
-		pk.Identifier("brigadier:string"),
+		parserID("brigadier:string"),
 		pk.VarInt(s),
 	}.WriteTo(w)
 }
@@ -41,7 +48,7 @@ func (s StringParser) Parse(cmd string) (left string, value ParsedData, err erro
 				} else if v == '\\' {
 					isEscaping = true
 				} else if v == '"' {
-					return cmd[:i], sb.String(), nil
+					return cmd[i+2:], sb.String(), nil
 				} else {
 					sb.WriteRune(v)
 				}
@@ -63,6 +70,326 @@ func (s StringParser) Parse(cmd string) (left string, value ParsedData, err erro
 	}
 }
 
+// BoolParser parses true or false as a bool.
+type BoolParser struct{}
+
+func (BoolParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("brigadier:bool").WriteTo(w)
+}
+
+func (BoolParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	word, left := readUnquoted(cmd)
+	switch word {
+	case "true":
+		return left, true, nil
+	case "false":
+		return left, false, nil
+	case "":
+		return cmd, nil, ParseErr{Err: "expected bool"}
+	default:
+		return cmd, nil, ParseErr{Err: "invalid bool, expected true or false but found '" + word + "'"}
+	}
+}
+
+// Flags of the numeric parser properties.
+const (
+	hasMin = 1 << iota
+	hasMax
+)
+
+// IntegerParser parses an int32 between Min and Max inclusive.
+// Use math.MinInt32 and math.MaxInt32 for no bounds.
+type IntegerParser struct{ Min, Max int32 }
+
+func (p IntegerParser) WriteTo(w io.Writer) (int64, error) {
+	return writeBounds(w, "brigadier:integer",
+		p.Min != math.MinInt32, pk.Int(p.Min),
+		p.Max != math.MaxInt32, pk.Int(p.Max))
+}
+
+func (p IntegerParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	num, left := readNumber(cmd)
+	if num == "" {
+		return cmd, nil, ParseErr{Err: "expected integer"}
+	}
+	v, err := strconv.ParseInt(num, 10, 32)
+	if err != nil {
+		return cmd, nil, ParseErr{Err: "invalid integer '" + num + "'"}
+	}
+	if err := checkBounds("integer", int32(v), p.Min, p.Max); err != nil {
+		return cmd, nil, err
+	}
+	return left, int32(v), nil
+}
+
+// LongParser parses an int64 between Min and Max inclusive.
+// Use math.MinInt64 and math.MaxInt64 for no bounds.
+type LongParser struct{ Min, Max int64 }
+
+func (p LongParser) WriteTo(w io.Writer) (int64, error) {
+	return writeBounds(w, "brigadier:long",
+		p.Min != math.MinInt64, pk.Long(p.Min),
+		p.Max != math.MaxInt64, pk.Long(p.Max))
+}
+
+func (p LongParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	num, left := readNumber(cmd)
+	if num == "" {
+		return cmd, nil, ParseErr{Err: "expected long"}
+	}
+	v, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return cmd, nil, ParseErr{Err: "invalid long '" + num + "'"}
+	}
+	if err := checkBounds("long", v, p.Min, p.Max); err != nil {
+		return cmd, nil, err
+	}
+	return left, v, nil
+}
+
+// FloatParser parses a float32 between Min and Max inclusive.
+// Use -math.MaxFloat32 and math.MaxFloat32 for no bounds.
+type FloatParser struct{ Min, Max float32 }
+
+func (p FloatParser) WriteTo(w io.Writer) (int64, error) {
+	return writeBounds(w, "brigadier:float",
+		p.Min != -math.MaxFloat32, pk.Float(p.Min),
+		p.Max != math.MaxFloat32, pk.Float(p.Max))
+}
+
+func (p FloatParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	num, left := readNumber(cmd)
+	if num == "" {
+		return cmd, nil, ParseErr{Err: "expected float"}
+	}
+	v, err := strconv.ParseFloat(num, 32)
+	if err != nil {
+		return cmd, nil, ParseErr{Err: "invalid float '" + num + "'"}
+	}
+	if err := checkBounds("float", float32(v), p.Min, p.Max); err != nil {
+		return cmd, nil, err
+	}
+	return left, float32(v), nil
+}
+
+// DoubleParser parses a float64 between Min and Max inclusive.
+// Use -math.MaxFloat64 and math.MaxFloat64 for no bounds.
+type DoubleParser struct{ Min, Max float64 }
+
+func (p DoubleParser) WriteTo(w io.Writer) (int64, error) {
+	return writeBounds(w, "brigadier:double",
+		p.Min != -math.MaxFloat64, pk.Double(p.Min),
+		p.Max != math.MaxFloat64, pk.Double(p.Max))
+}
+
+func (p DoubleParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	num, left := readNumber(cmd)
+	if num == "" {
+		return cmd, nil, ParseErr{Err: "expected double"}
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return cmd, nil, ParseErr{Err: "invalid double '" + num + "'"}
+	}
+	if err := checkBounds("double", v, p.Min, p.Max); err != nil {
+		return cmd, nil, err
+	}
+	return left, v, nil
+}
+
+func writeBounds(w io.Writer, name string, minSet bool, min pk.FieldEncoder, maxSet bool, max pk.FieldEncoder) (int64, error) {
+	var flags byte
+	if minSet {
+		flags |= hasMin
+	}
+	if maxSet {
+		flags |= hasMax
+	}
+	return pk.Tuple{
+		parserID(name),
+		pk.Byte(flags),
+		pk.Opt{Has: minSet, Field: min},
+		pk.Opt{Has: maxSet, Field: max},
+	}.WriteTo(w)
+}
+
+func checkBounds[T int32 | int64 | float32 | float64](kind string, v, min, max T) error {
+	switch {
+	case v < min:
+		return ParseErr{Err: fmt.Sprintf("%s must not be less than %v, found %v", kind, min, v)}
+	case v > max:
+		return ParseErr{Err: fmt.Sprintf("%s must not be more than %v, found %v", kind, max, v)}
+	}
+	return nil
+}
+
+// ResourceLocationParser parses an identifier like minecraft:stone.
+// The value is the identifier as a string, with the default namespace minecraft added if omitted.
+type ResourceLocationParser struct{}
+
+func (ResourceLocationParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:resource_location").WriteTo(w)
+}
+
+func (ResourceLocationParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	id, left, err := readResourceLocation(cmd)
+	if err != nil {
+		return cmd, nil, err
+	}
+	return left, id, nil
+}
+
+// DimensionParser parses the name of a dimension like minecraft:the_nether,
+// which the client completes with the dimension names sent at login.
+// The value is the name as a string, the command handler checks that the dimension exists.
+type DimensionParser struct{}
+
+func (DimensionParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:dimension").WriteTo(w)
+}
+
+func (DimensionParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	return ResourceLocationParser{}.Parse(cmd)
+}
+
+// TimeParser parses a duration with an optional unit d, s or t, the value is the number of ticks as int32.
+type TimeParser struct {
+	// Min is the minimum number of ticks.
+	Min int32
+}
+
+func (p TimeParser) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		parserID("minecraft:time"),
+		pk.Int(p.Min),
+	}.WriteTo(w)
+}
+
+func (p TimeParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	num, left := readNumber(cmd)
+	if num == "" {
+		return cmd, nil, ParseErr{Err: "expected float"}
+	}
+	v, err := strconv.ParseFloat(num, 32)
+	if err != nil {
+		return cmd, nil, ParseErr{Err: "invalid float '" + num + "'"}
+	}
+	unit, left := readUnquoted(left)
+	var ticks int32
+	switch unit {
+	case "", "t":
+		ticks = int32(math.Round(v))
+	case "s":
+		ticks = int32(math.Round(v * 20))
+	case "d":
+		ticks = int32(math.Round(v * 24000))
+	default:
+		return cmd, nil, ParseErr{Pos: len(num), Err: "invalid unit"}
+	}
+	if ticks < p.Min {
+		return cmd, nil, ParseErr{Err: fmt.Sprintf("tick count must not be less than %d, found %d", p.Min, ticks)}
+	}
+	return left, ticks, nil
+}
+
+// MessageParser takes the rest of the command as a string.
+type MessageParser struct{}
+
+func (MessageParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:message").WriteTo(w)
+}
+
+func (MessageParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	return "", cmd, nil
+}
+
+// readUnquoted reads the characters allowed in an unquoted string of brigadier.
+func readUnquoted(cmd string) (word, left string) {
+	i := strings.IndexFunc(cmd, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' ||
+			r == '_' || r == '-' || r == '.' || r == '+')
+	})
+	if i == -1 {
+		return cmd, ""
+	}
+	return cmd[:i], cmd[i:]
+}
+
+// readNumber reads the characters which can be part of a number.
+func readNumber(cmd string) (num, left string) {
+	i := strings.IndexFunc(cmd, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.' || r == '-')
+	})
+	if i == -1 {
+		return cmd, ""
+	}
+	return cmd[:i], cmd[i:]
+}
+
+// readResourceLocation reads an identifier, adding the minecraft namespace if omitted.
+func readResourceLocation(cmd string) (id, left string, err error) {
+	i := strings.IndexFunc(cmd, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' ||
+			r == '_' || r == ':' || r == '/' || r == '.' || r == '-')
+	})
+	if i == -1 {
+		i = len(cmd)
+	}
+	id, left = cmd[:i], cmd[i:]
+	namespace, path, found := strings.Cut(id, ":")
+	if !found {
+		namespace, path = "minecraft", id
+	} else if namespace == "" {
+		namespace = "minecraft"
+	}
+	if path == "" || strings.Contains(namespace, "/") || strings.Contains(path, ":") {
+		return "", cmd, ParseErr{Err: "invalid ID"}
+	}
+	return namespace + ":" + path, left, nil
+}
+
+// readSNBT reads a compound or list in the stringified NBT format, cmd must begin with { or [.
+func readSNBT(cmd string) (snbt nbt.StringifiedMessage, left string, err error) {
+	var depth int
+	var quote byte
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		}
+		if depth == 0 {
+			snbt = nbt.StringifiedMessage(cmd[:i+1])
+			if err := snbt.MarshalNBT(io.Discard); err != nil {
+				return "", cmd, ParseErr{Err: "invalid NBT: " + err.Error()}
+			}
+			return snbt, cmd[i+1:], nil
+		}
+	}
+	return "", cmd, ParseErr{Pos: len(cmd), Err: "unterminated NBT"}
+}
+
+// parserID returns the id of the argument type in the minecraft:command_argument_type registry,
+// which is sent to the client in place of the name.
+func parserID(name string) pk.VarInt {
+	for i, v := range registryid.CommandArgumentType {
+		if v == name {
+			return pk.VarInt(i)
+		}
+	}
+	panic("unknown command argument type: " + name)
+}
+
 type ParseErr struct {
 	Pos int
 	Err string
@@ -0,0 +1,155 @@
+package command
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestParsers(t *testing.T) {
+	oakStairs := block.StateList[mustDefault("minecraft:oak_stairs")].(block.OakStairs)
+	oakStairs.Facing, oakStairs.Half = block.East, block.Top
+	stairs := block.ToStateID[oakStairs]
+	for _, tt := range []struct {
+		parser Parser
+		cmd    string
+		left   string
+		value  ParsedData
+	}{
+		{BoolParser{}, "true rest", " rest", true},
+		{IntegerParser{Min: 0, Max: 10}, "7", "", int32(7)},
+		{LongParser{Min: math.MinInt64, Max: math.MaxInt64}, "-9000000000 x", " x", int64(-9000000000)},
+		{FloatParser{Min: -math.MaxFloat32, Max: math.MaxFloat32}, "1.5", "", float32(1.5)},
+		{DoubleParser{Min: 0, Max: 1}, ".25", "", 0.25},
+		{ResourceLocationParser{}, "stone", "", "minecraft:stone"},
+		{ResourceLocationParser{}, "foo:bar/baz x", " x", "foo:bar/baz"},
+		{DimensionParser{}, "the_nether", "", "minecraft:the_nether"},
+		{TimeParser{}, "1.5s", "", int32(30)},
+		{TimeParser{}, "2d", "", int32(48000)},
+		{TimeParser{}, "7", "", int32(7)},
+		{MessageParser{}, "hello world", "", "hello world"},
+		{BlockPosParser{}, "1 ~2 ~-3", "", Coordinates{
+			X: Coordinate{1, false}, Y: Coordinate{2, true}, Z: Coordinate{-3, true},
+		}},
+		{BlockPosParser{}, "^ ^1 ^", "", Coordinates{
+			X: Coordinate{0, true}, Y: Coordinate{1, true}, Z: Coordinate{0, true}, Local: true,
+		}},
+		{Vec3Parser{}, "1 2.0 3.5 x", " x", Coordinates{
+			X: Coordinate{1.5, false}, Y: Coordinate{2, false}, Z: Coordinate{3.5, false},
+		}},
+		{Vec2Parser{Exact: true}, "1 ~", "", Coordinates{
+			X: Coordinate{1, false}, Y: Coordinate{0, true}, Z: Coordinate{0, true},
+		}},
+		{ColumnPosParser{}, "-4 8", "", Coordinates{
+			X: Coordinate{-4, false}, Y: Coordinate{0, true}, Z: Coordinate{8, false},
+		}},
+		{BlockStateParser{}, "oak_stairs[facing=east, half=top]", "", BlockStateData{State: stairs}},
+		{BlockStateParser{}, "chest{Items:[]} x", " x", BlockStateData{
+			State: mustDefault("minecraft:chest"), NBT: "{Items:[]}",
+		}},
+		{EntityParser{}, "@e[type=pig,limit=3] x", " x", EntitySelector{Target: 'e', Options: []SelectorOption{
+			{Key: "type", Value: "minecraft:pig"}, {Key: "limit", Value: "3"},
+		}}},
+		{EntityParser{Single: true, PlayersOnly: true}, "@p[name=!\"Steve\",distance=..5]", "", EntitySelector{Target: 'p', Options: []SelectorOption{
+			{Key: "name", Value: "Steve", Negate: true}, {Key: "distance", Value: "..5"},
+		}}},
+		{EntityParser{}, "Tnze", "", EntitySelector{Name: "Tnze"}},
+		{GameProfileParser{}, "@a", "", EntitySelector{Target: 'a'}},
+	} {
+		left, value, err := tt.parser.Parse(tt.cmd)
+		if err != nil {
+			t.Errorf("%T.Parse(%q): %v", tt.parser, tt.cmd, err)
+			continue
+		}
+		if left != tt.left || !reflect.DeepEqual(value, tt.value) {
+			t.Errorf("%T.Parse(%q) = %q, %#v, want %q, %#v", tt.parser, tt.cmd, left, value, tt.left, tt.value)
+		}
+	}
+}
+
+func TestParsers_error(t *testing.T) {
+	for _, tt := range []struct {
+		parser Parser
+		cmd    string
+		pos    int
+	}{
+		{BoolParser{}, "yes", 0},
+		{IntegerParser{Min: 0, Max: 10}, "11", 0},
+		{IntegerParser{Min: math.MinInt32, Max: math.MaxInt32}, "1.5", 0},
+		{TimeParser{}, "1x", 1},
+		{ResourceLocationParser{}, "a:b:c", 0},
+		{BlockPosParser{}, "1 ^ 3", 2},
+		{BlockPosParser{}, "1 2", 3},
+		{BlockStateParser{}, "stone_stairs[facing=up]", 12},
+		{BlockStateParser{}, "stone[foo=bar]", 6},
+		{BlockStateParser{}, "not_a_block", 0},
+		{ItemStackParser{}, "diamond_sword[not_a_component=1]", 14},
+		{EntityParser{Single: true}, "@e", 0},
+		{EntityParser{PlayersOnly: true}, "@e[type=pig]", 0},
+		{EntityParser{}, "@a[type=pig]", 3},
+		{EntityParser{}, "@e[limit=0]", 9},
+		{GameProfileParser{}, "@e", 0},
+	} {
+		_, _, err := tt.parser.Parse(tt.cmd)
+		perr, ok := err.(ParseErr)
+		if !ok {
+			t.Errorf("%T.Parse(%q): want ParseErr, got %v", tt.parser, tt.cmd, err)
+			continue
+		}
+		if perr.Pos != tt.pos {
+			t.Errorf("%T.Parse(%q): error %q at %d, want at %d", tt.parser, tt.cmd, perr.Err, perr.Pos, tt.pos)
+		}
+	}
+}
+
+func TestItemStackParser(t *testing.T) {
+	_, value, err := ItemStackParser{}.Parse(`diamond_sword[damage=5,custom_name='"x, y"',!unbreakable]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := value.(ItemStackData)
+	if data.Components["minecraft:damage"] != "5" ||
+		data.Components["minecraft:custom_name"] != `'"x, y"'` ||
+		!reflect.DeepEqual(data.Removed, []string{"minecraft:unbreakable"}) {
+		t.Errorf("unexpected components: %#v", data)
+	}
+}
+
+func TestCoordinates_Resolve(t *testing.T) {
+	// Facing south, ^1 is east.
+	c := Coordinates{X: Coordinate{1, true}, Y: Coordinate{0, true}, Z: Coordinate{2, true}, Local: true}
+	got := c.Resolve([3]float64{10, 64, 10}, [2]float32{0, 0})
+	want := [3]float64{11, 64, 12}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("local coordinates resolved to %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGraph_WriteTo(t *testing.T) {
+	g := NewGraph()
+	for _, p := range []Parser{
+		BoolParser{}, IntegerParser{Min: 1, Max: math.MaxInt32}, LongParser{}, FloatParser{}, DoubleParser{},
+		StringParser(0), EntityParser{Single: true}, GameProfileParser{}, BlockPosParser{}, ColumnPosParser{},
+		Vec3Parser{}, Vec2Parser{}, BlockStateParser{}, ItemStackParser{}, MessageParser{},
+		ResourceLocationParser{}, TimeParser{},
+	} {
+		g.AppendLiteral(g.Literal("test").AppendArgument(g.Argument("arg", p).HandleFunc(nil)).Unhandle())
+	}
+	var buf bytes.Buffer
+	if _, err := g.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustDefault(id string) block.StateID {
+	s, ok := block.DefaultState(id)
+	if !ok {
+		panic(id)
+	}
+	return s
+}
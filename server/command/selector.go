@@ -0,0 +1,333 @@
+package command
+
+import (
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Flags of the minecraft:entity parser properties.
+const (
+	selectorSingle = 1 << iota
+	selectorPlayersOnly
+)
+
+// EntityParser parses a player name, an entity UUID or a target selector like @e[type=pig,limit=3].
+// The value is an EntitySelector, which is resolved by the command handler.
+type EntityParser struct {
+	// Single only accepts selectors matching at most one entity.
+	Single bool
+	// PlayersOnly only accepts selectors matching players.
+	PlayersOnly bool
+}
+
+func (p EntityParser) WriteTo(w io.Writer) (int64, error) {
+	var flags byte
+	if p.Single {
+		flags |= selectorSingle
+	}
+	if p.PlayersOnly {
+		flags |= selectorPlayersOnly
+	}
+	return pk.Tuple{
+		parserID("minecraft:entity"),
+		pk.Byte(flags),
+	}.WriteTo(w)
+}
+
+func (p EntityParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	s, left, err := parseSelector(cmd)
+	if err != nil {
+		return cmd, nil, err
+	}
+	if p.Single && s.Limit() > 1 {
+		if p.PlayersOnly {
+			return cmd, nil, ParseErr{Err: "only one player is allowed, but the provided selector allows more than one"}
+		}
+		return cmd, nil, ParseErr{Err: "only one entity is allowed, but the provided selector allows more than one"}
+	}
+	if p.PlayersOnly && s.IncludesEntities() && s.Target != 's' {
+		return cmd, nil, ParseErr{Err: "only players may be affected by this command, but the provided selector includes entities"}
+	}
+	return left, s, nil
+}
+
+// GameProfileParser parses a player name or a target selector matching players.
+// The value is an EntitySelector.
+type GameProfileParser struct{}
+
+func (GameProfileParser) WriteTo(w io.Writer) (int64, error) {
+	return parserID("minecraft:game_profile").WriteTo(w)
+}
+
+func (GameProfileParser) Parse(cmd string) (left string, value ParsedData, err error) {
+	if len(cmd) > 0 && cmd[0] == '@' {
+		s, left, err := parseSelector(cmd)
+		if err != nil {
+			return cmd, nil, err
+		}
+		if s.IncludesEntities() {
+			return cmd, nil, ParseErr{Err: "only players may be affected by this command, but the provided selector includes entities"}
+		}
+		return left, s, nil
+	}
+	name, left := readWord(cmd)
+	if name == "" {
+		return cmd, nil, ParseErr{Err: "expected player name"}
+	}
+	return left, EntitySelector{Name: name}, nil
+}
+
+// EntitySelector is the value of EntityParser and GameProfileParser.
+type EntitySelector struct {
+	// Target is the selector variable p, a, r, s, e or n, or 0 for a player name or an UUID.
+	Target byte
+	// Name is the player name if the selector is a name.
+	Name string
+	// UUID is the entity UUID if the selector is an UUID.
+	UUID uuid.UUID
+	// Options are the filters in the brackets of a target selector, in order.
+	Options []SelectorOption
+}
+
+// SelectorOption is an option of a target selector like type=!pig.
+// The Value is unquoted, compounds and ranges are kept as written.
+type SelectorOption struct {
+	Key    string
+	Value  string
+	Negate bool
+}
+
+// Option returns the first option of the key.
+func (s EntitySelector) Option(key string) (opt SelectorOption, ok bool) {
+	for _, o := range s.Options {
+		if o.Key == key {
+			return o, true
+		}
+	}
+	return opt, false
+}
+
+// Limit returns the maximum number of entities matched by the selector.
+func (s EntitySelector) Limit() int {
+	if o, ok := s.Option("limit"); ok {
+		n, _ := strconv.Atoi(o.Value)
+		return n
+	}
+	switch s.Target {
+	case 'a', 'e':
+		return math.MaxInt
+	default:
+		return 1
+	}
+}
+
+// IncludesEntities reports whether the selector can match entities other than players.
+func (s EntitySelector) IncludesEntities() bool {
+	switch s.Target {
+	case 'p', 'a', 'r':
+		return false
+	case 0:
+		return s.Name == ""
+	}
+	for _, o := range s.Options {
+		if o.Key == "type" && !o.Negate && o.Value == "minecraft:player" {
+			return false
+		}
+	}
+	return true
+}
+
+// selectorOptions are the options of target selectors and how their values are checked.
+var selectorOptions = map[string]func(value string) bool{
+	"name":         func(string) bool { return true },
+	"distance":     func(v string) bool { return isRange(v, false, true) },
+	"level":        func(v string) bool { return isRange(v, true, true) },
+	"x":            isDouble,
+	"y":            isDouble,
+	"z":            isDouble,
+	"dx":           isDouble,
+	"dy":           isDouble,
+	"dz":           isDouble,
+	"x_rotation":   func(v string) bool { return isRange(v, false, false) },
+	"y_rotation":   func(v string) bool { return isRange(v, false, false) },
+	"limit":        func(v string) bool { n, err := strconv.Atoi(v); return err == nil && n >= 1 },
+	"sort":         func(v string) bool { return slices.Contains(selectorSorts, v) },
+	"gamemode":     func(v string) bool { return slices.Contains(gameModes, v) },
+	"team":         func(string) bool { return true },
+	"type":         func(v string) bool { return strings.HasPrefix(v, "#") || slices.Contains(registryid.EntityType, v) },
+	"tag":          func(string) bool { return true },
+	"nbt":          func(v string) bool { return strings.HasPrefix(v, "{") },
+	"scores":       func(v string) bool { return strings.HasPrefix(v, "{") },
+	"advancements": func(v string) bool { return strings.HasPrefix(v, "{") },
+	"predicate":    func(string) bool { return true },
+}
+
+var (
+	selectorSorts = []string{"nearest", "furthest", "random", "arbitrary"}
+	gameModes     = []string{"survival", "creative", "adventure", "spectator"}
+)
+
+// negatable are the options which can be negated with a !.
+var negatable = []string{"name", "gamemode", "team", "type", "tag", "nbt", "predicate"}
+
+func parseSelector(cmd string) (s EntitySelector, left string, err error) {
+	if len(cmd) == 0 {
+		return s, cmd, ParseErr{Err: "expected name or UUID"}
+	}
+	if cmd[0] != '@' {
+		word, left := readWord(cmd)
+		if id, err := uuid.Parse(word); err == nil && strings.Count(word, "-") == 4 {
+			return EntitySelector{UUID: id}, left, nil
+		}
+		if word == "" || len(word) > 16 {
+			return s, cmd, ParseErr{Err: "invalid name or UUID"}
+		}
+		return EntitySelector{Name: word}, left, nil
+	}
+	if len(cmd) < 2 || !strings.ContainsRune("parsen", rune(cmd[1])) {
+		return s, cmd, ParseErr{Err: "unknown selector type '" + cmd[:min(len(cmd), 2)] + "'"}
+	}
+	s.Target = cmd[1]
+	left = cmd[2:]
+	if len(left) > 0 && left[0] == '[' {
+		start := len(cmd) - len(left)
+		s.Options, left, err = parseSelectorOptions(s.Target, left)
+		if err != nil {
+			perr := err.(ParseErr)
+			perr.Pos += start
+			return s, cmd, perr
+		}
+	}
+	return s, left, nil
+}
+
+func parseSelectorOptions(target byte, cmd string) (opts []SelectorOption, left string, err error) {
+	left = strings.TrimLeft(cmd[1:], " ")
+	for len(left) == 0 || left[0] != ']' {
+		pos := len(cmd) - len(left)
+		key, rest := readUnquoted(left)
+		check, ok := selectorOptions[key]
+		if !ok {
+			return nil, cmd, ParseErr{Pos: pos, Err: "unknown option '" + key + "'"}
+		}
+		if (key == "type" && strings.ContainsRune("par", rune(target))) ||
+			((key == "limit" || key == "sort") && target == 's') {
+			return nil, cmd, ParseErr{Pos: pos, Err: "option '" + key + "' isn't applicable here"}
+		}
+		rest = strings.TrimLeft(rest, " ")
+		if len(rest) == 0 || rest[0] != '=' {
+			return nil, cmd, ParseErr{Pos: len(cmd) - len(rest), Err: "expected value for option '" + key + "'"}
+		}
+		rest = strings.TrimLeft(rest[1:], " ")
+		opt := SelectorOption{Key: key}
+		if len(rest) > 0 && rest[0] == '!' {
+			if !slices.Contains(negatable, key) {
+				return nil, cmd, ParseErr{Pos: len(cmd) - len(rest), Err: "option '" + key + "' can't be negated"}
+			}
+			opt.Negate = true
+			rest = strings.TrimLeft(rest[1:], " ")
+		}
+		pos = len(cmd) - len(rest)
+		opt.Value, rest, err = readOptionValue(rest)
+		if err != nil {
+			perr := err.(ParseErr)
+			perr.Pos += pos
+			return nil, cmd, perr
+		}
+		if key == "type" && !strings.HasPrefix(opt.Value, "#") && !strings.Contains(opt.Value, ":") {
+			opt.Value = "minecraft:" + opt.Value
+		}
+		if !check(opt.Value) {
+			return nil, cmd, ParseErr{Pos: pos, Err: "invalid value '" + opt.Value + "' for option '" + key + "'"}
+		}
+		opts = append(opts, opt)
+		left = strings.TrimLeft(rest, " ")
+		switch {
+		case len(left) > 0 && left[0] == ',':
+			left = strings.TrimLeft(left[1:], " ")
+		case len(left) > 0 && left[0] == ']':
+		default:
+			return nil, cmd, ParseErr{Pos: len(cmd) - len(left), Err: "expected end of options"}
+		}
+	}
+	return opts, left[1:], nil
+}
+
+// readOptionValue reads a quoted string, a compound, or an unquoted value ending before a space, , or ].
+func readOptionValue(cmd string) (value, left string, err error) {
+	if len(cmd) == 0 {
+		return "", cmd, ParseErr{Err: "expected value"}
+	}
+	switch cmd[0] {
+	case '{':
+		snbt, left, err := readSNBT(cmd)
+		return string(snbt), left, err
+	case '"', '\'':
+		var sb strings.Builder
+		for i := 1; i < len(cmd); i++ {
+			switch c := cmd[i]; {
+			case c == '\\' && i+1 < len(cmd):
+				i++
+				sb.WriteByte(cmd[i])
+			case c == cmd[0]:
+				return sb.String(), cmd[i+1:], nil
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", cmd, ParseErr{Pos: len(cmd), Err: "unclosed quoted string"}
+	}
+	i := strings.IndexAny(cmd, " ,]")
+	if i == -1 {
+		i = len(cmd)
+	}
+	return cmd[:i], cmd[i:], nil
+}
+
+// readWord reads until the next space.
+func readWord(cmd string) (word, left string) {
+	i := strings.IndexByte(cmd, ' ')
+	if i == -1 {
+		return cmd, ""
+	}
+	return cmd[:i], cmd[i:]
+}
+
+func isDouble(v string) bool {
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+// isRange checks ranges like 1, 1..5, ..5 or 1..
+func isRange(v string, integer, nonNegative bool) bool {
+	low, high, _ := strings.Cut(v, "..")
+	if low == "" && high == "" {
+		return false
+	}
+	for _, bound := range []string{low, high} {
+		if bound == "" {
+			continue
+		}
+		var n float64
+		var err error
+		if integer {
+			var i int
+			i, err = strconv.Atoi(bound)
+			n = float64(i)
+		} else {
+			n, err = strconv.ParseFloat(bound, 64)
+		}
+		if err != nil || nonNegative && n < 0 {
+			return false
+		}
+	}
+	return true
+}
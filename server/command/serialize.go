@@ -2,7 +2,6 @@ package command
 
 import (
 	"io"
-	"unsafe"
 
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
@@ -13,24 +12,74 @@ const (
 	hasSuggestionsType
 )
 
+// WriteTo writes the whole graph as the content of the ClientboundCommands packet.
 func (g *Graph) WriteTo(w io.Writer) (int64, error) {
+	return g.Visible(MaxPermissionLevel).WriteTo(w)
+}
+
+// Visible returns the part of the graph that can be used with the permission level,
+// which is what vanilla sends to each player.
+func (g *Graph) Visible(level int) pk.FieldEncoder {
+	v := visibleGraph{g: g, index: make(map[int32]int32)}
+	v.visit(g.nodes[0], level)
+	return v
+}
+
+type visibleGraph struct {
+	g     *Graph
+	nodes []*Node
+	// index maps the node index in the graph to the one in the packet.
+	index map[int32]int32
+}
+
+func (v *visibleGraph) visit(n *Node, level int) {
+	v.index[n.index] = int32(len(v.nodes))
+	v.nodes = append(v.nodes, n)
+	for _, i := range n.Children {
+		child := v.g.nodes[i]
+		if _, ok := v.index[i]; !ok && child.Permission <= level {
+			v.visit(child, level)
+		}
+	}
+}
+
+func (v visibleGraph) WriteTo(w io.Writer) (int64, error) {
+	nodes := make([]pk.FieldEncoder, len(v.nodes))
+	for i, n := range v.nodes {
+		var children []pk.VarInt
+		for _, c := range n.Children {
+			if j, ok := v.index[c]; ok {
+				children = append(children, pk.VarInt(j))
+			}
+		}
+		nodes[i] = nodeEncoder{n, children}
+	}
 	return pk.Tuple{
-		pk.Array(g.nodes),
-		pk.VarInt(0),
+		pk.Array(nodes),
+		pk.VarInt(0), // root index
 	}.WriteTo(w)
 }
 
-func (n Node) WriteTo(w io.Writer) (int64, error) {
+type nodeEncoder struct {
+	*Node
+	children []pk.VarInt
+}
+
+func (n nodeEncoder) WriteTo(w io.Writer) (int64, error) {
 	var flag byte
 	flag |= n.kind & 0x03
 	if n.Run != nil {
 		flag |= isExecutable
 	}
+	suggestionsType := n.suggestionsType()
+	if n.kind == ArgumentNode && suggestionsType != "" {
+		flag |= hasSuggestionsType
+	}
 	return pk.Tuple{
 		pk.Byte(flag),
-		pk.Array((*[]pk.VarInt)(unsafe.Pointer(&n.Children))),
+		pk.Array(n.children),
 		pk.Opt{
-			Has:   func() bool { return n.kind&hasRedirect != 0 },
+			Has:   func() bool { return flag&hasRedirect != 0 },
 			Field: nil, // TODO: send redirect node
 		},
 		pk.Opt{
@@ -43,7 +92,7 @@ func (n Node) WriteTo(w io.Writer) (int64, error) {
 		},
 		pk.Opt{
 			Has:   func() bool { return flag&hasSuggestionsType != 0 },
-			Field: nil, // TODO: send Suggestions type
+			Field: pk.Identifier(suggestionsType),
 		},
 	}.WriteTo(w)
 }
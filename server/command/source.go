@@ -0,0 +1,34 @@
+package command
+
+import "context"
+
+// Source is the executor of a command, such as a player or the console.
+type Source interface {
+	// PermissionLevel is from 0 for normal players to 4 for the console and operators.
+	PermissionLevel() int
+}
+
+type sourceKey struct{}
+
+// WithSource returns a copy of ctx carrying the Source of a command.
+func WithSource(ctx context.Context, s Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, s)
+}
+
+// SourceFrom returns the Source stored in ctx by WithSource, or nil.
+func SourceFrom(ctx context.Context) Source {
+	s, _ := ctx.Value(sourceKey{}).(Source)
+	return s
+}
+
+// permissionLevel returns the permission level of the Source in ctx,
+// commands executed without a Source have the highest level.
+func permissionLevel(ctx context.Context) int {
+	if s := SourceFrom(ctx); s != nil {
+		return s.PermissionLevel()
+	}
+	return MaxPermissionLevel
+}
+
+// MaxPermissionLevel is the permission level of server operators by default.
+const MaxPermissionLevel = 4
@@ -0,0 +1,163 @@
+package command
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/data/registryid"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// Suggestion is a completion of the argument being typed.
+type Suggestion struct {
+	Text    string
+	Tooltip *chat.Message
+}
+
+func (s Suggestion) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{
+		pk.String(s.Text),
+		pk.Boolean(s.Tooltip != nil),
+		pk.Opt{Has: s.Tooltip != nil, Field: s.Tooltip},
+	}.WriteTo(w)
+}
+
+// SuggestionProvider returns the completions of an argument.
+// The args are the values parsed before the argument, like the args of HandlerFunc,
+// and prefix is the text of the argument typed so far, which the suggestions replace.
+type SuggestionProvider func(ctx context.Context, args []ParsedData, prefix string) []Suggestion
+
+// askServer is the suggestions type making the client request the suggestions of a node from the server.
+const askServer = "minecraft:ask_server"
+
+// suggestionsType returns the suggestions type sent to the client.
+func (n *Node) suggestionsType() string {
+	if n.Suggests != nil {
+		return askServer
+	}
+	return n.SuggestionsType
+}
+
+// Suggest returns the completions at the end of cmd,
+// which replace the text from the start index to the end of cmd.
+func (g *Graph) Suggest(ctx context.Context, cmd string) (start int, suggestions []Suggestion) {
+	level := permissionLevel(ctx)
+	args := []ParsedData{nil} // the root node
+	node := g.nodes[0]
+	left := cmd
+	for {
+		if node.kind != RootNode {
+			if len(left) == 0 || left[0] != ' ' {
+				return len(cmd), nil
+			}
+			left = left[1:]
+		}
+		next, rest, value, err := node.next(left, level)
+		if err != nil || len(rest) == 0 {
+			// The cursor is in the argument of a child node.
+			break
+		}
+		args = append(args, value)
+		node, left = next, rest
+	}
+	start = len(cmd) - len(left)
+	for _, i := range node.Children {
+		child := g.nodes[i]
+		if child.Permission > level {
+			continue
+		}
+		switch {
+		case child.kind == LiteralNode && strings.HasPrefix(child.Name, left):
+			suggestions = append(suggestions, Suggestion{Text: child.Name})
+		case child.kind == ArgumentNode && child.Suggests != nil:
+			suggestions = append(suggestions, child.Suggests(ctx, args, left)...)
+		}
+	}
+	return start, suggestions
+}
+
+// SuggestMatching returns the candidates starting with the prefix.
+// Namespaced ids also match if their path starts with the prefix, so stone matches minecraft:stone.
+func SuggestMatching(prefix string, candidates []string) (suggestions []Suggestion) {
+	for _, c := range candidates {
+		_, path, found := strings.Cut(c, ":")
+		if strings.HasPrefix(c, prefix) || found && strings.HasPrefix(path, prefix) {
+			suggestions = append(suggestions, Suggestion{Text: c})
+		}
+	}
+	return
+}
+
+// PlayerLister is implemented by the Sources that know the online players, for SuggestPlayers.
+type PlayerLister interface {
+	OnlinePlayers() []string
+}
+
+// Targeter is implemented by the Sources that look at the world, for the coordinates suggestions.
+type Targeter interface {
+	// Target returns the position at the crosshair and the block there, or ok is false if there is no block.
+	Target() (pos [3]float64, block [3]int, ok bool)
+}
+
+// SuggestPlayers suggests the names of the online players.
+func SuggestPlayers(ctx context.Context, _ []ParsedData, prefix string) []Suggestion {
+	if l, ok := SourceFrom(ctx).(PlayerLister); ok {
+		return SuggestMatching(prefix, l.OnlinePlayers())
+	}
+	return nil
+}
+
+// SuggestBlocks suggests the block ids.
+func SuggestBlocks(_ context.Context, _ []ParsedData, prefix string) []Suggestion {
+	return SuggestMatching(prefix, registryid.Block)
+}
+
+// SuggestItems suggests the item ids.
+func SuggestItems(_ context.Context, _ []ParsedData, prefix string) []Suggestion {
+	return SuggestMatching(prefix, registryid.Item)
+}
+
+// SuggestBlockPos suggests the coordinates of the block at the crosshair, or ~ ~ ~ if there is none.
+func SuggestBlockPos(ctx context.Context, _ []ParsedData, prefix string) []Suggestion {
+	coords := [3]string{"~", "~", "~"}
+	if t, ok := SourceFrom(ctx).(Targeter); ok {
+		if _, b, ok := t.Target(); ok {
+			for i, v := range b {
+				coords[i] = strconv.Itoa(v)
+			}
+		}
+	}
+	return suggestCoordinates(prefix, coords)
+}
+
+// SuggestVec3 suggests the position at the crosshair, or ~ ~ ~ if there is no block.
+func SuggestVec3(ctx context.Context, _ []ParsedData, prefix string) []Suggestion {
+	coords := [3]string{"~", "~", "~"}
+	if t, ok := SourceFrom(ctx).(Targeter); ok {
+		if pos, _, ok := t.Target(); ok {
+			for i, v := range pos {
+				coords[i] = strconv.FormatFloat(v, 'f', 2, 64)
+			}
+		}
+	}
+	return suggestCoordinates(prefix, coords)
+}
+
+// suggestCoordinates completes the coordinates not typed yet, like vanilla does.
+func suggestCoordinates(prefix string, coords [3]string) []Suggestion {
+	x, y, z := coords[0], coords[1], coords[2]
+	if prefix == "" {
+		return []Suggestion{{Text: x}, {Text: x + " " + y}, {Text: x + " " + y + " " + z}}
+	}
+	switch parts := strings.Split(prefix, " "); len(parts) {
+	case 1:
+		return []Suggestion{{Text: parts[0] + " " + y}, {Text: parts[0] + " " + y + " " + z}}
+	case 2:
+		return []Suggestion{{Text: parts[0] + " " + parts[1] + " " + z}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// BlockEntity returns the block entity at the world coordinates.
+// The ok is false if the chunk isn't loaded or there is no block entity.
+func (w *World) BlockEntity(x, y, z int) (e block.Entity, ok bool) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	lc, ok := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+	if !ok {
+		return nil, false
+	}
+	return lc.BlockEntity(x, y, z)
+}
+
+// SetBlockEntity replaces the block entity at the world coordinates and sends it to the viewers.
+// It reports false if the chunk isn't loaded or the block doesn't have a block entity of the type.
+func (w *World) SetBlockEntity(x, y, z int, e block.Entity) bool {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	lc, ok := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+	if !ok || y < worldMinY || y > worldMaxY {
+		return false
+	}
+	return lc.SetBlockEntity(x, y, z, e)
+}
+
+// BlockEntity returns the block entity at the world coordinates, which must be in this chunk.
+func (lc *LoadedChunk) BlockEntity(x, y, z int) (e block.Entity, ok bool) {
+	lc.Lock()
+	defer lc.Unlock()
+	e, ok = lc.blockEntities[[3]int32{int32(x), int32(y), int32(z)}]
+	return
+}
+
+// SetBlockEntity replaces the block entity at the world coordinates, which must be in this chunk.
+// The entity is sent to the viewers with the next block changes.
+// It reports false if the block doesn't have a block entity of the type.
+func (lc *LoadedChunk) SetBlockEntity(x, y, z int, e block.Entity) bool {
+	lc.Lock()
+	defer lc.Unlock()
+	if !e.IsValidBlock(block.StateList[lc.getBlock(x, y, z)]) {
+		return false
+	}
+	pos := [3]int32{int32(x), int32(y), int32(z)}
+	if lc.blockEntities == nil {
+		lc.blockEntities = make(map[[3]int32]block.Entity)
+	}
+	lc.blockEntities[pos] = e
+	if lc.entityChanges == nil {
+		lc.entityChanges = make(map[[3]int32]struct{})
+	}
+	lc.entityChanges[pos] = struct{}{}
+	lc.dirty = true
+	return true
+}
+
+// updateBlockEntity removes the block entity which isn't valid for the new block at pos,
+// and creates the one of the new block, like vanilla does when a block is set.
+func (lc *LoadedChunk) updateBlockEntity(pos [3]int32, state level.BlocksState) {
+	b := block.StateList[state]
+	if e, ok := lc.blockEntities[pos]; ok {
+		if e.IsValidBlock(b) {
+			return
+		}
+		delete(lc.blockEntities, pos)
+		delete(lc.entityChanges, pos)
+	}
+	if e, ok := block.NewEntity(b); ok {
+		if lc.blockEntities == nil {
+			lc.blockEntities = make(map[[3]int32]block.Entity)
+		}
+		lc.blockEntities[pos] = e
+	}
+}
+
+// blockEntityList returns the block entities in the form of the chunk packet.
+func (lc *LoadedChunk) blockEntityList() []level.BlockEntity {
+	list := make([]level.BlockEntity, 0, len(lc.blockEntities))
+	for pos, e := range lc.blockEntities {
+		data, err := block.EntityData(e)
+		if err != nil {
+			continue
+		}
+		be := level.BlockEntity{Y: int16(pos[1]), Type: block.TypeOf(e), Data: data}
+		be.PackXZ(int(pos[0]&15), int(pos[2]&15))
+		list = append(list, be)
+	}
+	return list
+}
+
+// flushBlockEntities sends the block entities changed since the last call to the viewers.
+func (lc *LoadedChunk) flushBlockEntities() {
+	for pos := range lc.entityChanges {
+		e := lc.blockEntities[pos]
+		data, err := block.EntityData(e)
+		if err != nil {
+			continue
+		}
+		for _, v := range lc.viewers {
+			v.ViewBlockEntityData(pos, block.TypeOf(e), data)
+		}
+	}
+	clear(lc.entityChanges)
+}
+
+// FacingFrontText reports whether a player with the eyes at eye sees the front text of the sign at pos,
+// which is the side the sign faces.
+func FacingFrontText(b block.Block, pos [3]int32, eye [3]float64) bool {
+	var yRot float64
+	v := reflect.ValueOf(b)
+	if f := v.FieldByName("Rotation"); f.IsValid() {
+		yRot = float64(f.Int()) * 22.5
+	} else if f := v.FieldByName("Facing"); f.IsValid() {
+		switch block.Direction(f.Uint()) {
+		case block.West:
+			yRot = 90
+		case block.North:
+			yRot = 180
+		case block.East:
+			yRot = 270
+		}
+	}
+	dx := eye[0] - (float64(pos[0]) + 0.5)
+	dz := eye[2] - (float64(pos[2]) + 0.5)
+	angle := math.Atan2(dz, dx)*180/math.Pi - 90
+	diff := math.Mod(angle-yRot, 360)
+	if diff < -180 {
+		diff += 360
+	} else if diff >= 180 {
+		diff -= 360
+	}
+	return math.Abs(diff) <= 90
+}
+
+// blockEntitiesFromSave decodes the block entities of a chunk read from the save files.
+func blockEntitiesFromSave(list []level.BlockEntity) (map[[3]int32]block.Entity, error) {
+	blockEntities := make(map[[3]int32]block.Entity, len(list))
+	for _, v := range list {
+		e, pos, err := block.UnmarshalEntity(v.Data)
+		if err != nil {
+			return nil, err
+		}
+		blockEntities[pos] = e
+	}
+	return blockEntities, nil
+}
+
+// blockEntitiesToSave encodes the block entities of a chunk in the form of the save files.
+func blockEntitiesToSave(blockEntities map[[3]int32]block.Entity) ([]level.BlockEntity, error) {
+	list := make([]level.BlockEntity, 0, len(blockEntities))
+	for pos, e := range blockEntities {
+		data, err := block.MarshalEntity(e, pos)
+		if err != nil {
+			return nil, err
+		}
+		be := level.BlockEntity{Y: int16(pos[1]), Type: block.TypeOf(e), Data: data}
+		be.PackXZ(int(pos[0]&15), int(pos[2]&15))
+		list = append(list, be)
+	}
+	return list, nil
+}
@@ -0,0 +1,78 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestLoadedChunk_blockEntities(t *testing.T) {
+	c := hpcworld.Alloc()
+	defer c.Free()
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{0, 0}, heightMaps: c.HeightMaps()}
+
+	lc.SetBlock(1, 70, 2, level.BlocksState(block.ToStateID[block.OakSign{}]))
+	e, ok := lc.BlockEntity(1, 70, 2)
+	sign, isSign := e.(block.SignEntity)
+	if !ok || !isSign {
+		t.Fatalf("the block entity of a placed sign is %#v", e)
+	}
+	sign.IsWaxed = true
+	if !lc.SetBlockEntity(1, 70, 2, sign) {
+		t.Fatal("SetBlockEntity refused a sign entity on a sign")
+	}
+	if lc.SetBlockEntity(1, 71, 2, sign) {
+		t.Error("SetBlockEntity accepted a sign entity on air")
+	}
+	if len(lc.blockEntityList()) != 1 {
+		t.Errorf("the chunk packet has %d block entities, want 1", len(lc.blockEntityList()))
+	}
+
+	// Keep the block entity if the block is replaced by one with the same entity.
+	lc.SetBlock(1, 70, 2, level.BlocksState(block.ToStateID[block.SpruceSign{}]))
+	if e, _ := lc.BlockEntity(1, 70, 2); !e.(block.SignEntity).IsWaxed {
+		t.Error("the block entity is replaced")
+	}
+	lc.SetBlock(1, 70, 2, level.BlocksState(block.ToStateID[block.Air{}]))
+	if _, ok := lc.BlockEntity(1, 70, 2); ok {
+		t.Error("the block entity is kept after the sign is removed")
+	}
+
+	saved, err := blockEntitiesToSave(map[[3]int32]block.Entity{{-3, 5, 17}: sign})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, z := saved[0].UnpackXZ(); x != 13 || z != 1 || saved[0].Y != 5 {
+		t.Errorf("the block entity is saved at %d %d %d", x, saved[0].Y, z)
+	}
+	loaded, err := blockEntitiesFromSave(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := loaded[[3]int32{-3, 5, 17}].(block.SignEntity); !ok || !e.IsWaxed {
+		t.Errorf("the block entities are loaded as %v", loaded)
+	}
+}
+
+func TestFacingFrontText(t *testing.T) {
+	pos := [3]int32{10, 64, 10}
+	for _, tt := range []struct {
+		b     block.Block
+		eye   [3]float64
+		front bool
+	}{
+		// Rotation 0 faces south.
+		{block.OakSign{Rotation: 0}, [3]float64{10.5, 65, 13}, true},
+		{block.OakSign{Rotation: 0}, [3]float64{10.5, 65, 8}, false},
+		// Rotation 4 faces west.
+		{block.OakSign{Rotation: 4}, [3]float64{8, 65, 10.5}, true},
+		{block.OakWallSign{Facing: block.North}, [3]float64{10.5, 65, 8}, true},
+		{block.OakWallSign{Facing: block.East}, [3]float64{8, 65, 10.5}, false},
+	} {
+		if got := FacingFrontText(tt.b, pos, tt.eye); got != tt.front {
+			t.Errorf("FacingFrontText(%#v, %v) = %v, want %v", tt.b, tt.eye, got, tt.front)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package container
+
+import (
+	"io"
+	"slices"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+// ClickType is the mode of a click in a window.
+type ClickType int32
+
+const (
+	// Pickup is a left or right click, picking up or putting down the items.
+	Pickup ClickType = iota
+	// QuickMove is a shift-click, moving the stack to the other part of the window.
+	QuickMove
+	// Swap swaps the slot with a hotbar slot or the offhand by a number key or F.
+	Swap
+	// Clone is a middle click, taking a full stack of the item in creative mode.
+	Clone
+	// Throw is a Q press over a slot, dropping one item or the whole stack.
+	Throw
+	// QuickCraft is a drag over several slots, spreading the carried items.
+	QuickCraft
+	// PickupAll is a double click, collecting the items of the same type on the cursor.
+	PickupAll
+)
+
+// OutsideSlot is the slot index of the clicks outside the window, which drop the carried items.
+const OutsideSlot = -999
+
+// ChangedSlot is a slot as the client predicted after a click.
+type ChangedSlot struct {
+	Index int16
+	Item  item.ItemStack
+}
+
+func (c ChangedSlot) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.Short(c.Index), c.Item}.WriteTo(w)
+}
+
+func (c *ChangedSlot) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{(*pk.Short)(&c.Index), &c.Item}.ReadFrom(r)
+}
+
+// Click handles a click of the player sent by the client, with the slots and the carried stack the client predicted.
+// The client is sent all the slots if its stateID is outdated, or the slots it mispredicted.
+func (m *Menu) Click(p Player, stateID int32, slot, button int, typ ClickType, changed []ChangedSlot, carried item.ItemStack) {
+	if !m.IsValidSlotIndex(slot) {
+		return
+	}
+	fullResync := stateID != m.stateID
+	m.suppressSync = true
+	m.Clicked(slot, button, typ, p)
+	for _, c := range changed {
+		m.SetRemoteSlot(int(c.Index), c.Item)
+	}
+	m.SetRemoteCarried(carried)
+	m.suppressSync = false
+	if fullResync {
+		m.SendAllDataToRemote()
+	} else {
+		m.BroadcastChanges()
+	}
+}
+
+// Clicked does the click on the slot like vanilla. The button depends on the click type,
+// it's the mouse button for Pickup, the hotbar slot or 40 for Swap and the drag stage and type for QuickCraft.
+func (m *Menu) Clicked(slot, button int, typ ClickType, p Player) {
+	if typ == QuickCraft {
+		m.quickCraft(slot, button, p)
+		return
+	}
+	if m.quickcraftStatus != 0 {
+		m.resetQuickCraft()
+		return
+	}
+	switch {
+	case (typ == Pickup || typ == QuickMove) && (button == 0 || button == 1):
+		m.pickup(slot, button, typ, p)
+	case typ == Swap && (button >= 0 && button < HotbarSize || button == OffhandSlot):
+		m.swap(slot, button, p)
+	case typ == Clone && p.HasInfiniteMaterials() && m.carried.IsEmpty() && slot >= 0:
+		if s := m.Slots[slot].Item(); !s.IsEmpty() {
+			m.carried = s.Copy()
+			m.carried.Count = s.MaxStackSize()
+		}
+	case typ == Throw && m.carried.IsEmpty() && slot >= 0:
+		s := &m.Slots[slot]
+		n := int32(1)
+		if button != 0 {
+			n = s.Item().Count
+		}
+		dropped := s.safeTake(p, n, MaxStackSize)
+		p.Drop(dropped)
+		for button == 1 && !dropped.IsEmpty() && item.SameItem(s.Item(), dropped) {
+			dropped = s.safeTake(p, n, MaxStackSize)
+			p.Drop(dropped)
+		}
+	case typ == PickupAll && slot >= 0:
+		m.pickupAll(slot, button, p)
+	}
+}
+
+func (m *Menu) pickup(slot, button int, typ ClickType, p Player) {
+	primary := button == 0
+	if slot == OutsideSlot {
+		if !m.carried.IsEmpty() {
+			if primary {
+				p.Drop(m.carried)
+				m.carried = item.ItemStack{}
+			} else {
+				p.Drop(m.carried.Split(1))
+			}
+		}
+		return
+	}
+	if slot < 0 {
+		return
+	}
+	s := &m.Slots[slot]
+	if typ == QuickMove {
+		if !s.mayPickup() || m.QuickMove == nil {
+			return
+		}
+		moved := m.QuickMove(m, p, slot)
+		for !moved.IsEmpty() && item.SameItem(s.Item(), moved) {
+			moved = m.QuickMove(m, p, slot)
+		}
+		return
+	}
+	inSlot := s.Item()
+	switch {
+	case inSlot.IsEmpty():
+		if !m.carried.IsEmpty() {
+			n := int32(1)
+			if primary {
+				n = m.carried.Count
+			}
+			m.carried = s.safeInsert(m.carried, n)
+		}
+	case !s.mayPickup():
+	case m.carried.IsEmpty():
+		n := (inSlot.Count + 1) / 2
+		if primary {
+			n = inSlot.Count
+		}
+		if taken, ok := s.tryRemove(n, MaxStackSize); ok {
+			m.carried = taken
+			s.onTake(p, taken)
+		}
+	case s.mayPlace(m.carried):
+		if item.SameItemSameComponents(inSlot, m.carried) {
+			n := int32(1)
+			if primary {
+				n = m.carried.Count
+			}
+			m.carried = s.safeInsert(m.carried, n)
+		} else if m.carried.Count <= s.maxStackSize(m.carried) {
+			s.Set(m.carried)
+			m.carried = inSlot
+		}
+	case item.SameItemSameComponents(inSlot, m.carried):
+		if taken, ok := s.tryRemove(inSlot.Count, m.carried.MaxStackSize()-m.carried.Count); ok {
+			m.carried.Count += taken.Count
+			s.onTake(p, taken)
+		}
+	}
+}
+
+func (m *Menu) swap(slot, button int, p Player) {
+	if slot < 0 || m.Inventory == nil {
+		return
+	}
+	s := &m.Slots[slot]
+	hotbar := m.Inventory.Item(button)
+	inSlot := s.Item()
+	switch {
+	case hotbar.IsEmpty() && inSlot.IsEmpty():
+	case hotbar.IsEmpty():
+		if s.mayPickup() {
+			m.Inventory.SetItem(button, inSlot)
+			s.Set(item.ItemStack{})
+			s.onTake(p, inSlot)
+		}
+	case inSlot.IsEmpty():
+		if s.mayPlace(hotbar) {
+			if limit := s.maxStackSize(hotbar); hotbar.Count > limit {
+				s.Set(hotbar.Split(limit))
+				m.Inventory.SetItem(button, hotbar)
+			} else {
+				m.Inventory.SetItem(button, item.ItemStack{})
+				s.Set(hotbar)
+			}
+		}
+	case s.mayPickup() && s.mayPlace(hotbar):
+		if limit := s.maxStackSize(hotbar); hotbar.Count > limit {
+			s.Set(hotbar.Split(limit))
+			m.Inventory.SetItem(button, hotbar)
+			s.onTake(p, inSlot)
+			if !m.Inventory.Add(&inSlot) {
+				p.Drop(inSlot)
+			}
+		} else {
+			m.Inventory.SetItem(button, inSlot)
+			s.Set(hotbar)
+			s.onTake(p, inSlot)
+		}
+	}
+}
+
+func (m *Menu) pickupAll(slot, button int, p Player) {
+	s := &m.Slots[slot]
+	if m.carried.IsEmpty() || s.HasItem() && s.mayPickup() {
+		return
+	}
+	start, step := 0, 1
+	if button != 0 {
+		start, step = len(m.Slots)-1, -1
+	}
+	// Take the partial stacks first, then the full ones.
+	for pass := 0; pass < 2; pass++ {
+		for i := start; i >= 0 && i < len(m.Slots) && m.carried.Count < m.carried.MaxStackSize(); i += step {
+			other := &m.Slots[i]
+			if !other.HasItem() || !canItemQuickReplace(other, m.carried, true) || !other.mayPickup() {
+				continue
+			}
+			// like vanilla, the crafting results aren't picked up
+			if _, ok := other.Container.(craftingResult); ok {
+				continue
+			}
+			if inSlot := other.Item(); pass != 0 || inSlot.Count != inSlot.MaxStackSize() {
+				taken := other.safeTake(p, inSlot.Count, m.carried.MaxStackSize()-m.carried.Count)
+				m.carried.Count += taken.Count
+			}
+		}
+	}
+}
+
+// The stages of a drag, in the two lowest bits of the button.
+const (
+	quickcraftStart = iota
+	quickcraftContinue
+	quickcraftEnd
+)
+
+// The types of a drag, in the next two bits of the button.
+const (
+	quickcraftCharitable = iota // left button, split the items evenly
+	quickcraftGreedy            // right button, one item per slot
+	quickcraftClone             // middle button, a full stack per slot in creative mode
+)
+
+func (m *Menu) quickCraft(slot, button int, p Player) {
+	previous := m.quickcraftStatus
+	m.quickcraftStatus = button & 3
+	if (previous != quickcraftContinue || m.quickcraftStatus != quickcraftEnd) && previous != m.quickcraftStatus {
+		m.resetQuickCraft()
+		return
+	}
+	if m.carried.IsEmpty() {
+		m.resetQuickCraft()
+		return
+	}
+	switch m.quickcraftStatus {
+	case quickcraftStart:
+		m.quickcraftType = button >> 2 & 3
+		if m.quickcraftType == quickcraftCharitable || m.quickcraftType == quickcraftGreedy ||
+			m.quickcraftType == quickcraftClone && p.HasInfiniteMaterials() {
+			m.quickcraftStatus = quickcraftContinue
+			m.quickcraftSlots = m.quickcraftSlots[:0]
+		} else {
+			m.resetQuickCraft()
+		}
+	case quickcraftContinue:
+		if slot < 0 || slot >= len(m.Slots) {
+			return
+		}
+		s := &m.Slots[slot]
+		if canItemQuickReplace(s, m.carried, true) && s.mayPlace(m.carried) &&
+			(m.quickcraftType == quickcraftClone || int(m.carried.Count) > len(m.quickcraftSlots)) &&
+			!slices.Contains(m.quickcraftSlots, slot) {
+			m.quickcraftSlots = append(m.quickcraftSlots, slot)
+		}
+	case quickcraftEnd:
+		if len(m.quickcraftSlots) == 1 {
+			only, typ := m.quickcraftSlots[0], m.quickcraftType
+			m.resetQuickCraft()
+			m.Clicked(only, typ, Pickup, p)
+			return
+		}
+		if len(m.quickcraftSlots) > 1 {
+			m.spread()
+		}
+		m.resetQuickCraft()
+	default:
+		m.resetQuickCraft()
+	}
+}
+
+// spread puts the carried items in the dragged slots.
+func (m *Menu) spread() {
+	stack := m.carried.Copy()
+	left := m.carried.Count
+	for _, i := range m.quickcraftSlots {
+		s := &m.Slots[i]
+		if !canItemQuickReplace(s, m.carried, true) || !s.mayPlace(m.carried) ||
+			m.quickcraftType != quickcraftClone && int(m.carried.Count) < len(m.quickcraftSlots) {
+			continue
+		}
+		var before int32
+		if s.HasItem() {
+			before = s.Item().Count
+		}
+		var n int32
+		switch m.quickcraftType {
+		case quickcraftCharitable:
+			n = stack.Count / int32(len(m.quickcraftSlots))
+		case quickcraftGreedy:
+			n = 1
+		case quickcraftClone:
+			n = stack.MaxStackSize()
+		}
+		n = min(n+before, stack.MaxStackSize(), s.maxStackSize(stack))
+		left -= n - before
+		placed := stack.Copy()
+		placed.Count = n
+		s.Set(placed)
+	}
+	stack.Count = left
+	if stack.IsEmpty() {
+		stack = item.ItemStack{}
+	}
+	m.carried = stack
+}
+
+func (m *Menu) resetQuickCraft() {
+	m.quickcraftStatus = quickcraftStart
+	m.quickcraftSlots = m.quickcraftSlots[:0]
+}
@@ -0,0 +1,90 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package container implements the containers holding items and the windows showing them to the players,
+// whose slots are kept on the server and synchronized to the clients like vanilla.
+package container
+
+import "github.com/mrhaoxx/go-mc/level/item"
+
+// Container holds item stacks in numbered slots, like a chest.
+type Container interface {
+	Size() int
+	Item(i int) item.ItemStack
+	SetItem(i int, s item.ItemStack)
+}
+
+// Remover is implemented by the containers which don't split their stacks when the player takes some items,
+// like the result of a crafting grid. RemoveItem returns the items taken out of the slot.
+type Remover interface {
+	RemoveItem(i int, n int32) item.ItemStack
+}
+
+// MaxStackSize is the number of items a slot of a container can hold at most.
+const MaxStackSize = 99
+
+// Simple is a Container holding the items in a slice.
+type Simple []item.ItemStack
+
+// NewSimple returns an empty container with size slots.
+func NewSimple(size int) Simple { return make(Simple, size) }
+
+func (c Simple) Size() int                       { return len(c) }
+func (c Simple) Item(i int) item.ItemStack       { return c[i] }
+func (c Simple) SetItem(i int, s item.ItemStack) { c[i] = s }
+
+// The slots of PlayerInventory.
+const (
+	HotbarSize = 9
+	// InventorySize is the number of slots of the hotbar and the main inventory.
+	InventorySize = 36
+	// ArmorSlot is the slot of the boots, followed by the leggings, the chestplate and the helmet.
+	ArmorSlot   = 36
+	OffhandSlot = 40
+)
+
+// PlayerInventory is the inventory of a player with the slots of vanilla:
+// 0-8 are the hotbar, 9-35 are the main inventory, 36-39 are the armor from feet to head and 40 is the offhand.
+type PlayerInventory [41]item.ItemStack
+
+func (inv *PlayerInventory) Size() int                       { return len(inv) }
+func (inv *PlayerInventory) Item(i int) item.ItemStack       { return inv[i] }
+func (inv *PlayerInventory) SetItem(i int, s item.ItemStack) { inv[i] = s }
+
+// Add puts the stack in the hotbar and the main inventory,
+// filling the stacks of the same item first and then the empty slots.
+// It returns false if the stack didn't fit, with the items left in s.
+func (inv *PlayerInventory) Add(s *item.ItemStack) bool {
+	for i := 0; i < InventorySize && !s.IsEmpty(); i++ {
+		if !inv[i].IsEmpty() && item.SameItemSameComponents(inv[i], *s) {
+			n := min(s.Count, min(inv[i].MaxStackSize(), MaxStackSize)-inv[i].Count)
+			if n > 0 {
+				inv[i].Count += n
+				s.Count -= n
+			}
+		}
+	}
+	for i := 0; i < InventorySize && !s.IsEmpty(); i++ {
+		if inv[i].IsEmpty() {
+			inv[i] = s.Split(min(s.MaxStackSize(), MaxStackSize))
+		}
+	}
+	if s.IsEmpty() {
+		*s = item.ItemStack{}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/data/inventory"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/recipe"
+)
+
+type testPlayer struct {
+	creative bool
+	dropped  []item.ItemStack
+}
+
+func (p *testPlayer) HasInfiniteMaterials() bool { return p.creative }
+func (p *testPlayer) Drop(s item.ItemStack)      { p.dropped = append(p.dropped, s) }
+
+type testViewer struct {
+	contents, slots int
+}
+
+func (v *testViewer) SendContainerSetContent(int32, int32, []item.ItemStack, item.ItemStack) {
+	v.contents++
+}
+func (v *testViewer) SendContainerSetSlot(int32, int32, int16, item.ItemStack) { v.slots++ }
+func (v *testViewer) SendContainerSetData(int32, int16, int16)                 {}
+func (v *testViewer) SendSetCursorItem(item.ItemStack)                         {}
+
+func stack(t *testing.T, name string, count int32) item.ItemStack {
+	var id item.ID
+	if err := id.UnmarshalText([]byte(name)); err != nil {
+		t.Fatal(err)
+	}
+	return item.New(id, count)
+}
+
+func TestMenu_pickup(t *testing.T) {
+	var inv PlayerInventory
+	inv[0] = stack(t, "minecraft:stone", 10)
+	m := NewInventoryMenu(&inv, nil)
+	p := new(testPlayer)
+
+	// right click takes the bigger half
+	m.Clicked(InventoryHotbarSlot, 1, Pickup, p)
+	if m.Carried().Count != 5 || inv[0].Count != 5 {
+		t.Fatalf("carried %d, left %d, want 5 and 5", m.Carried().Count, inv[0].Count)
+	}
+	// right click on an empty slot puts one
+	m.Clicked(InventoryMainSlot, 1, Pickup, p)
+	if m.Carried().Count != 4 || inv[9].Count != 1 {
+		t.Fatalf("carried %d, placed %d, want 4 and 1", m.Carried().Count, inv[9].Count)
+	}
+	// left click on the same item merges all
+	m.Clicked(InventoryHotbarSlot, 0, Pickup, p)
+	if !m.Carried().IsEmpty() || inv[0].Count != 9 {
+		t.Fatalf("carried %v, slot %d, want nothing and 9", m.Carried(), inv[0].Count)
+	}
+	// the result slot doesn't accept items
+	m.Clicked(InventoryHotbarSlot, 0, Pickup, p)
+	m.Clicked(InventoryResultSlot, 0, Pickup, p)
+	if m.Carried().Count != 9 || m.Slots[InventoryResultSlot].HasItem() {
+		t.Fatal("the items were put in the result slot")
+	}
+	// clicking outside drops the carried stack
+	m.Clicked(OutsideSlot, 0, Pickup, p)
+	if !m.Carried().IsEmpty() || len(p.dropped) != 1 || p.dropped[0].Count != 9 {
+		t.Fatalf("carried %v, dropped %v", m.Carried(), p.dropped)
+	}
+}
+
+func TestMenu_armor(t *testing.T) {
+	var inv PlayerInventory
+	inv[0] = stack(t, "minecraft:iron_helmet", 1)
+	m := NewInventoryMenu(&inv, nil)
+	// shift clicking equips the helmet
+	m.Clicked(InventoryHotbarSlot, 0, QuickMove, new(testPlayer))
+	if !inv[0].IsEmpty() || inv[ArmorSlot+3].IsEmpty() {
+		t.Fatalf("the helmet wasn't equipped: %v", inv)
+	}
+	// the boots slot doesn't accept it
+	m.Clicked(InventoryArmorSlot, 0, Pickup, new(testPlayer))
+	m.Clicked(InventoryArmorSlot+3, 0, Pickup, new(testPlayer))
+	if !inv[ArmorSlot].IsEmpty() {
+		t.Fatal("the helmet was put in the boots slot")
+	}
+}
+
+func TestMenu_quickMove(t *testing.T) {
+	var inv PlayerInventory
+	inv[0] = stack(t, "minecraft:stone", 64)
+	chest := NewSimple(27)
+	chest[3] = stack(t, "minecraft:stone", 60)
+	m := NewContainerMenu(inventory.Generic9x3, chest, &inv)
+	p := new(testPlayer)
+
+	hotbar := 27 + 27
+	m.Clicked(hotbar, 0, QuickMove, p)
+	if !inv[0].IsEmpty() || chest[3].Count != 64 || chest[0].Count != 60 {
+		t.Fatalf("hotbar %v, chest %v", inv[0], chest[:4])
+	}
+	// from the chest, the items go to the end of the hotbar first
+	m.Clicked(0, 0, QuickMove, p)
+	if !chest[0].IsEmpty() || inv[8].Count != 60 {
+		t.Fatalf("chest %v, hotbar %v", chest[0], inv[:9])
+	}
+}
+
+func TestMenu_swap(t *testing.T) {
+	var inv PlayerInventory
+	inv[2] = stack(t, "minecraft:stone", 1)
+	inv[9] = stack(t, "minecraft:dirt", 3)
+	m := NewInventoryMenu(&inv, nil)
+	m.Clicked(InventoryMainSlot, 2, Swap, new(testPlayer))
+	if inv[2].Count != 3 || inv[9].Count != 1 {
+		t.Fatalf("hotbar %v, main %v", inv[2], inv[9])
+	}
+	m.Clicked(InventoryMainSlot, OffhandSlot, Swap, new(testPlayer))
+	if !inv[9].IsEmpty() || inv[OffhandSlot].Count != 1 {
+		t.Fatalf("main %v, offhand %v", inv[9], inv[OffhandSlot])
+	}
+}
+
+func TestMenu_quickCraft(t *testing.T) {
+	var inv PlayerInventory
+	m := NewInventoryMenu(&inv, nil)
+	p := new(testPlayer)
+	m.SetCarried(stack(t, "minecraft:stone", 10))
+
+	// left dragging over three slots spreads the stack evenly
+	m.Clicked(OutsideSlot, quickcraftStart|quickcraftCharitable<<2, QuickCraft, p)
+	for _, slot := range []int{9, 10, 11} {
+		m.Clicked(slot, quickcraftContinue|quickcraftCharitable<<2, QuickCraft, p)
+	}
+	m.Clicked(OutsideSlot, quickcraftEnd|quickcraftCharitable<<2, QuickCraft, p)
+	for i := 9; i < 12; i++ {
+		if inv[i].Count != 3 {
+			t.Errorf("slot %d has %d items, want 3", i, inv[i].Count)
+		}
+	}
+	if m.Carried().Count != 1 {
+		t.Errorf("%d items are left, want 1", m.Carried().Count)
+	}
+
+	// double clicking picks up the slot and then all the stones
+	m.Clicked(InventoryMainSlot, 0, Pickup, p)
+	m.Clicked(InventoryMainSlot, 0, Pickup, p)
+	m.Clicked(InventoryMainSlot, 0, PickupAll, p)
+	if m.Carried().Count != 10 {
+		t.Errorf("picked up %d items, want 10", m.Carried().Count)
+	}
+}
+
+func TestMenu_Click(t *testing.T) {
+	var inv PlayerInventory
+	inv[0] = stack(t, "minecraft:stone", 10)
+	m := NewInventoryMenu(&inv, nil)
+	v := new(testViewer)
+	m.SetViewer(v)
+	if v.contents != 1 {
+		t.Fatalf("sent the content %d times when opened, want 1", v.contents)
+	}
+
+	// a click predicted right doesn't send anything back
+	m.Click(new(testPlayer), m.StateID(), InventoryHotbarSlot, 0, Pickup,
+		[]ChangedSlot{{Index: InventoryHotbarSlot}}, stack(t, "minecraft:stone", 10))
+	if v.contents != 1 || v.slots != 0 {
+		t.Errorf("sent %d contents and %d slots, want none", v.contents-1, v.slots)
+	}
+	// a mispredicted slot is corrected
+	m.Click(new(testPlayer), m.StateID(), InventoryMainSlot, 1, Pickup,
+		[]ChangedSlot{{Index: InventoryMainSlot, Item: stack(t, "minecraft:stone", 2)}}, stack(t, "minecraft:stone", 9))
+	if v.contents != 1 || v.slots != 1 {
+		t.Errorf("sent %d contents and %d slots, want the slot", v.contents-1, v.slots)
+	}
+	// an outdated state id resends everything
+	m.Click(new(testPlayer), m.StateID()-1, InventoryMainSlot, 0, Pickup, nil, item.ItemStack{})
+	if v.contents != 2 {
+		t.Errorf("the content wasn't resent")
+	}
+}
+
+func TestMenu_crafting(t *testing.T) {
+	recipes, err := recipe.Load(recipe.Builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inv PlayerInventory
+	inv[0] = stack(t, "minecraft:oak_log", 3)
+	m := NewInventoryMenu(&inv, recipes)
+	p := new(testPlayer)
+
+	m.Clicked(InventoryHotbarSlot, 0, Pickup, p)
+	m.Clicked(InventoryCraftSlot+3, 0, Pickup, p)
+	if result := m.Slots[InventoryResultSlot].Item(); result.Count != 4 || result.ItemID.Name() != "minecraft:oak_planks" {
+		t.Fatalf("the result is %v, want 4 oak planks", result)
+	}
+	// taking the result consumes a log
+	m.Clicked(InventoryResultSlot, 1, Pickup, p)
+	if m.Carried().Count != 4 || m.Slots[InventoryCraftSlot+3].Item().Count != 2 {
+		t.Fatalf("carried %v, grid %v", m.Carried(), m.Slots[InventoryCraftSlot+3].Item())
+	}
+	// shift clicking crafts until the logs run out
+	m.Clicked(InventoryResultSlot, 0, QuickMove, p)
+	if m.Slots[InventoryCraftSlot+3].HasItem() || m.Slots[InventoryResultSlot].HasItem() {
+		t.Fatal("the logs weren't all crafted")
+	}
+	if inv[8].Count != 8 {
+		t.Errorf("crafted %v, want 8 planks at the end of the hotbar", inv[8])
+	}
+
+	// the recipe book fills the grid with the planks
+	inv[8].Count += 4
+	i, _ := recipes.Index("minecraft:crafting_table")
+	h, _ := recipes.Get(i)
+	if !m.PlaceRecipe(p, h.Recipe.(recipe.CraftingRecipe), true) {
+		t.Fatal("the crafting table recipe wasn't placed")
+	}
+	for i := 0; i < 4; i++ {
+		if n := m.Slots[InventoryCraftSlot+i].Item().Count; n != 3 {
+			t.Errorf("grid slot %d has %d planks, want 3", i, n)
+		}
+	}
+	if result := m.Slots[InventoryResultSlot].Item(); result.ItemID.Name() != "minecraft:crafting_table" {
+		t.Errorf("the result is %v, want a crafting table", result)
+	}
+	i, _ = recipes.Index("minecraft:chest")
+	h, _ = recipes.Get(i)
+	if !m.PlaceRecipe(p, h.Recipe.(recipe.CraftingRecipe), false) || m.Slots[InventoryCraftSlot].Item().Count != 3 {
+		t.Error("the chest doesn't fit in the inventory grid, nothing should happen")
+	}
+
+	// the grid and the cursor are emptied when the inventory is closed
+	m.Removed(p)
+	var planks int32
+	for _, s := range inv {
+		planks += s.Count
+	}
+	if planks != 16 || len(p.dropped) != 0 {
+		t.Errorf("%d planks are in the inventory, want 16", planks)
+	}
+}
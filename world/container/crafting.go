@@ -0,0 +1,191 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package container
+
+import (
+	"slices"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/recipe"
+)
+
+// CraftingGrid is the Container of the crafting grid of a menu.
+// It looks for the recipe of its items whenever they change and puts the result in its result slot.
+type CraftingGrid struct {
+	Width, Height int
+
+	items   Simple
+	result  item.ItemStack
+	recipes *recipe.Manager
+}
+
+// NewCraftingGrid returns an empty grid crafting the recipes of the manager, which can be nil to disable crafting.
+func NewCraftingGrid(width, height int, recipes *recipe.Manager) *CraftingGrid {
+	return &CraftingGrid{Width: width, Height: height, items: NewSimple(width * height), recipes: recipes}
+}
+
+func (g *CraftingGrid) Size() int                 { return len(g.items) }
+func (g *CraftingGrid) Item(i int) item.ItemStack { return g.items[i] }
+
+func (g *CraftingGrid) SetItem(i int, s item.ItemStack) {
+	g.items[i] = s
+	g.update()
+}
+
+// Recipes returns the recipes crafted by the grid.
+func (g *CraftingGrid) Recipes() *recipe.Manager { return g.recipes }
+
+// Result returns the Container of the result slot.
+func (g *CraftingGrid) Result() Container { return craftingResult{g} }
+
+// update puts the result of the recipe of the items in the result slot.
+func (g *CraftingGrid) update() {
+	g.result = item.ItemStack{}
+	if g.recipes == nil {
+		return
+	}
+	in := recipe.NewCraftingInput(g.Width, g.Height, g.items)
+	if r, ok := g.recipes.Crafting(in); ok {
+		g.result = r.Assemble(in)
+	}
+}
+
+// consume uses one item of each slot of the grid to craft the result, leaving the remainders.
+// The remainders which don't fit in the grid are given to the player.
+func (g *CraftingGrid) consume(p Player, inv *PlayerInventory) {
+	for i, s := range g.items {
+		if s.IsEmpty() {
+			continue
+		}
+		remainder := recipe.Remainder(s)
+		s.Count--
+		if s.Count == 0 {
+			s = item.ItemStack{}
+		}
+		switch {
+		case remainder.IsEmpty():
+		case s.IsEmpty():
+			s = remainder
+		case item.SameItemSameComponents(s, remainder) && s.Count < s.MaxStackSize():
+			s.Count++
+		case inv == nil || !inv.Add(&remainder):
+			p.Drop(remainder)
+		}
+		g.items[i] = s
+	}
+	g.update()
+}
+
+// clear gives back the items of the grid to the player.
+func (g *CraftingGrid) clear(p Player, inv *PlayerInventory) {
+	for i, s := range g.items {
+		if !s.IsEmpty() && (inv == nil || !inv.Add(&s)) {
+			p.Drop(s)
+		}
+		g.items[i] = item.ItemStack{}
+	}
+	g.update()
+}
+
+// craftingResult is the result slot of a crafting grid, whose items are all taken at once.
+type craftingResult struct{ g *CraftingGrid }
+
+func (r craftingResult) Size() int                       { return 1 }
+func (r craftingResult) Item(int) item.ItemStack         { return r.g.result }
+func (r craftingResult) SetItem(_ int, s item.ItemStack) { r.g.result = s }
+
+func (r craftingResult) RemoveItem(int, int32) item.ItemStack {
+	s := r.g.result
+	r.g.result = item.ItemStack{}
+	return s
+}
+
+// appendCraftingSlots appends the result slot and the slots of the grid.
+// Taking the result consumes the ingredients.
+func appendCraftingSlots(slots []Slot, g *CraftingGrid, inv *PlayerInventory) []Slot {
+	slots = append(slots, Slot{
+		Container: g.Result(),
+		MayPlace:  func(item.ItemStack) bool { return false },
+		OnTake:    func(p Player, _ item.ItemStack) { g.consume(p, inv) },
+	})
+	for i := range g.items {
+		slots = append(slots, Slot{Container: g, Index: i})
+	}
+	return slots
+}
+
+// PlaceRecipe moves the ingredients of the recipe from the inventory to the crafting grid, for the recipe book.
+// The items of the grid are put back in the inventory first, nothing happens if they don't fit.
+// The ingredients of as many crafts as possible are moved if all is set, of one craft otherwise.
+// It reports false if the ingredients aren't in the inventory, the client shows the recipe as a ghost then.
+func (m *Menu) PlaceRecipe(p Player, r recipe.CraftingRecipe, all bool) bool {
+	g, inv := m.Crafting, m.Inventory
+	if g == nil || inv == nil {
+		return true
+	}
+	layout, ok := r.Arrange(g.Width, g.Height)
+	if !ok {
+		return true
+	}
+	test := *inv
+	for _, s := range g.items {
+		if !s.IsEmpty() && !test.Add(&s) {
+			return true
+		}
+	}
+	g.clear(p, inv)
+	crafts := 0
+	for all || crafts == 0 {
+		invBefore, gridBefore := *inv, slices.Clone(g.items)
+		if !g.placeIngredients(layout, inv) {
+			*inv = invBefore
+			copy(g.items, gridBefore)
+			break
+		}
+		crafts++
+	}
+	g.update()
+	return crafts > 0
+}
+
+// placeIngredients moves one item of each ingredient of the layout from the inventory to the grid.
+// It reports false if an ingredient is missing, some items may have been moved then.
+func (g *CraftingGrid) placeIngredients(layout []*recipe.Ingredient, inv *PlayerInventory) bool {
+	for i, in := range layout {
+		if in == nil {
+			continue
+		}
+		current := g.items[i]
+		if !current.IsEmpty() && current.Count >= current.MaxStackSize() {
+			return false
+		}
+		j := slices.IndexFunc(inv[:InventorySize], func(s item.ItemStack) bool {
+			return in.Test(s) && (current.IsEmpty() || item.SameItemSameComponents(current, s))
+		})
+		if j == -1 {
+			return false
+		}
+		taken := inv[j].Split(1)
+		if current.IsEmpty() {
+			current = taken
+		} else {
+			current.Count++
+		}
+		g.items[i] = current
+	}
+	return true
+}
@@ -0,0 +1,232 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package container
+
+import (
+	"github.com/mrhaoxx/go-mc/data/inventory"
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// Player is the player using a menu.
+type Player interface {
+	// HasInfiniteMaterials reports whether the player can clone the stacks, like in creative mode.
+	HasInfiniteMaterials() bool
+	// Drop throws the items out of the inventory of the player.
+	Drop(s item.ItemStack)
+}
+
+// Viewer is the client showing a menu, which receives the changes of the slots.
+type Viewer interface {
+	SendContainerSetContent(windowID, stateID int32, items []item.ItemStack, carried item.ItemStack)
+	SendContainerSetSlot(windowID, stateID int32, slot int16, s item.ItemStack)
+	SendContainerSetData(windowID int32, property, value int16)
+	SendSetCursorItem(s item.ItemStack)
+}
+
+// Menu is a window of a player showing slots of containers, known as a screen handler.
+//
+// The slots are authoritative on the server, the changes made by the player are checked
+// and the client is corrected when it doesn't agree. A Menu isn't safe for concurrent use.
+type Menu struct {
+	// ID is the window id, 0 for the inventory of the player.
+	ID int32
+	// Type is the id in the minecraft:menu registry of the window.
+	Type inventory.InventoryID
+	// Slots are the slots of the window in the order of the protocol.
+	Slots []Slot
+	// Data are the properties of the window, like the progress of a furnace.
+	Data []int16
+	// Inventory is the inventory of the player, which the number keys swap with.
+	Inventory *PlayerInventory
+	// Crafting is the crafting grid of the window, which the recipe book fills, or nil.
+	Crafting *CraftingGrid
+
+	// QuickMove moves the stack in the slot somewhere else when the player shift-clicks it.
+	// It returns a copy of the stack before moving, or an empty stack if nothing moved.
+	// Nothing happens on shift-clicks if it's nil.
+	QuickMove func(m *Menu, p Player, index int) item.ItemStack
+	// ButtonClick handles the clicks on the buttons of the window, like the enchantments in an enchanting table.
+	// It reports whether the click was valid.
+	ButtonClick func(button int32) bool
+	// OnClose is called after the window is closed and the carried stack is back in the inventory.
+	OnClose func(p Player)
+	// Block is the position of the block the window is opened from, which the player must stay close to, or nil.
+	Block *[3]int
+
+	stateID int32
+	carried item.ItemStack
+
+	viewer        Viewer
+	remoteSlots   []item.ItemStack
+	remoteCarried item.ItemStack
+	remoteData    []int16
+	suppressSync  bool
+
+	quickcraftStatus int
+	quickcraftType   int
+	quickcraftSlots  []int
+}
+
+// Carried returns the stack on the cursor of the player.
+func (m *Menu) Carried() item.ItemStack { return m.carried }
+
+// SetCarried puts the stack on the cursor of the player.
+func (m *Menu) SetCarried(s item.ItemStack) { m.carried = s }
+
+// StateID returns the revision of the slots the client should have.
+func (m *Menu) StateID() int32 { return m.stateID }
+
+func (m *Menu) incrementStateID() int32 {
+	m.stateID = (m.stateID + 1) & 0x7FFF
+	return m.stateID
+}
+
+// IsValidSlotIndex reports whether the slot index of a click exists,
+// -999 is outside the window and -1 is the border of it.
+func (m *Menu) IsValidSlotIndex(i int) bool {
+	return i == -1 || i == -999 || i >= 0 && i < len(m.Slots)
+}
+
+// SetViewer sets the client of the menu and sends all the slots to it.
+func (m *Menu) SetViewer(v Viewer) {
+	m.viewer = v
+	m.SendAllDataToRemote()
+}
+
+// SendAllDataToRemote sends all the slots to the client.
+func (m *Menu) SendAllDataToRemote() {
+	items := make([]item.ItemStack, len(m.Slots))
+	m.remoteSlots = make([]item.ItemStack, len(m.Slots))
+	for i := range m.Slots {
+		items[i] = m.Slots[i].Item()
+		m.remoteSlots[i] = items[i].Copy()
+	}
+	m.remoteCarried = m.carried.Copy()
+	m.remoteData = append(m.remoteData[:0], m.Data...)
+	if m.viewer != nil {
+		m.viewer.SendContainerSetContent(m.ID, m.incrementStateID(), items, m.carried)
+		for i, v := range m.Data {
+			m.viewer.SendContainerSetData(m.ID, int16(i), v)
+		}
+	}
+}
+
+// BroadcastChanges sends the slots changed since the last time to the client.
+func (m *Menu) BroadcastChanges() {
+	if m.viewer == nil || m.suppressSync {
+		return
+	}
+	if len(m.remoteSlots) != len(m.Slots) {
+		m.SendAllDataToRemote()
+		return
+	}
+	for i := range m.Slots {
+		s := m.Slots[i].Item()
+		if !item.Equal(s, m.remoteSlots[i]) {
+			m.remoteSlots[i] = s.Copy()
+			m.viewer.SendContainerSetSlot(m.ID, m.incrementStateID(), int16(i), s)
+		}
+	}
+	if !item.Equal(m.carried, m.remoteCarried) {
+		m.remoteCarried = m.carried.Copy()
+		m.viewer.SendSetCursorItem(m.carried)
+	}
+	for i, v := range m.Data {
+		if i >= len(m.remoteData) || m.remoteData[i] != v {
+			m.viewer.SendContainerSetData(m.ID, int16(i), v)
+		}
+	}
+	m.remoteData = append(m.remoteData[:0], m.Data...)
+}
+
+// SetRemoteSlot records the stack the client has in the slot.
+func (m *Menu) SetRemoteSlot(i int, s item.ItemStack) {
+	if i >= 0 && i < len(m.remoteSlots) {
+		m.remoteSlots[i] = s
+	}
+}
+
+// SetRemoteCarried records the stack the client has on the cursor.
+func (m *Menu) SetRemoteCarried(s item.ItemStack) {
+	m.remoteCarried = s
+}
+
+// Removed is called when the window is closed,
+// it puts the carried stack back in the inventory of the player or drops it.
+func (m *Menu) Removed(p Player) {
+	if !m.carried.IsEmpty() {
+		if m.Inventory == nil || !m.Inventory.Add(&m.carried) {
+			p.Drop(m.carried)
+		}
+		m.carried = item.ItemStack{}
+	}
+	if m.OnClose != nil {
+		m.OnClose(p)
+	}
+}
+
+// MoveItemStackTo moves the items of the stack to the slots from start to end,
+// filling the stacks of the same item first and then the first empty slot.
+// The slots are tried backwards if reverse is set. It reports whether any item moved.
+func (m *Menu) MoveItemStackTo(s *item.ItemStack, start, end int, reverse bool) (moved bool) {
+	next := func(i int) int {
+		if reverse {
+			return i - 1
+		}
+		return i + 1
+	}
+	first := start
+	if reverse {
+		first = end - 1
+	}
+	if s.IsStackable() {
+		for i := first; !s.IsEmpty() && i >= start && i < end; i = next(i) {
+			slot := &m.Slots[i]
+			current := slot.Item()
+			if current.IsEmpty() || !item.SameItemSameComponents(*s, current) {
+				continue
+			}
+			limit := slot.maxStackSize(current)
+			if total := current.Count + s.Count; total <= limit {
+				s.Count = 0
+				current.Count = total
+			} else if current.Count < limit {
+				s.Count -= limit - current.Count
+				current.Count = limit
+			} else {
+				continue
+			}
+			slot.Set(current)
+			moved = true
+		}
+	}
+	if !s.IsEmpty() {
+		for i := first; i >= start && i < end; i = next(i) {
+			slot := &m.Slots[i]
+			if slot.HasItem() || !slot.mayPlace(*s) {
+				continue
+			}
+			slot.Set(s.Split(min(s.Count, slot.maxStackSize(*s))))
+			moved = true
+			break
+		}
+	}
+	if s.IsEmpty() {
+		*s = item.ItemStack{}
+	}
+	return moved
+}
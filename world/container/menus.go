@@ -0,0 +1,225 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package container
+
+import (
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/data/inventory"
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/recipe"
+)
+
+// The slots of the inventory menu.
+const (
+	InventoryResultSlot  = 0
+	InventoryCraftSlot   = 1
+	InventoryArmorSlot   = 5
+	InventoryMainSlot    = 9
+	InventoryHotbarSlot  = 36
+	InventoryOffhandSlot = 45
+)
+
+// NewInventoryMenu returns the window of the inventory of the player, whose ID is 0.
+// Its 2x2 crafting grid crafts the recipes of the manager, and gives back the items to the inventory when it's closed.
+func NewInventoryMenu(inv *PlayerInventory, recipes *recipe.Manager) *Menu {
+	grid := NewCraftingGrid(2, 2, recipes)
+	m := &Menu{Inventory: inv, Crafting: grid, QuickMove: quickMoveInventory}
+	m.Slots = appendCraftingSlots(m.Slots, grid, inv)
+	// from head to feet
+	for _, equipment := range []int32{component.SlotHead, component.SlotChest, component.SlotLegs, component.SlotFeet} {
+		m.Slots = append(m.Slots, Slot{
+			Container:    inv,
+			Index:        ArmorSlot + int(equipment-component.SlotFeet),
+			MaxStackSize: 1,
+			MayPlace: func(s item.ItemStack) bool {
+				slot, ok := s.EquipmentSlot()
+				return ok && slot == equipment
+			},
+		})
+	}
+	m.Slots = appendInventorySlots(m.Slots, inv)
+	m.Slots = append(m.Slots, Slot{Container: inv, Index: OffhandSlot})
+	m.OnClose = func(p Player) { grid.clear(p, inv) }
+	return m
+}
+
+func quickMoveInventory(m *Menu, p Player, index int) item.ItemStack {
+	s := &m.Slots[index]
+	stack := s.Item()
+	if stack.IsEmpty() {
+		return item.ItemStack{}
+	}
+	before := stack.Copy()
+	equipment, isEquipment := stack.EquipmentSlot()
+	var moved bool
+	switch {
+	case index == InventoryResultSlot:
+		moved = m.MoveItemStackTo(&stack, InventoryMainSlot, InventoryOffhandSlot, true)
+	case index < InventoryMainSlot: // the crafting grid and the armor
+		moved = m.MoveItemStackTo(&stack, InventoryMainSlot, InventoryOffhandSlot, false)
+	case isEquipment && equipment >= component.SlotFeet && equipment <= component.SlotHead &&
+		!m.Slots[InventoryArmorSlot+int(component.SlotHead-equipment)].HasItem():
+		i := InventoryArmorSlot + int(component.SlotHead-equipment)
+		moved = m.MoveItemStackTo(&stack, i, i+1, false)
+	case isEquipment && equipment == component.SlotOffHand && !m.Slots[InventoryOffhandSlot].HasItem():
+		moved = m.MoveItemStackTo(&stack, InventoryOffhandSlot, InventoryOffhandSlot+1, false)
+	case index < InventoryHotbarSlot:
+		moved = m.MoveItemStackTo(&stack, InventoryHotbarSlot, InventoryOffhandSlot, false)
+	case index < InventoryOffhandSlot:
+		moved = m.MoveItemStackTo(&stack, InventoryMainSlot, InventoryHotbarSlot, false)
+	default:
+		moved = m.MoveItemStackTo(&stack, InventoryMainSlot, InventoryOffhandSlot, false)
+	}
+	if !moved {
+		return item.ItemStack{}
+	}
+	s.Set(stack)
+	if stack.Count == before.Count {
+		return item.ItemStack{}
+	}
+	taken := before.Copy()
+	taken.Count -= stack.Count
+	s.onTake(p, taken)
+	if index == InventoryResultSlot && !stack.IsEmpty() {
+		// the crafted items which don't fit are thrown
+		p.Drop(stack)
+	}
+	return before
+}
+
+// The slots of the crafting table menu.
+const (
+	CraftingResultSlot = 0
+	CraftingGridSlot   = 1
+	CraftingMainSlot   = 10
+	CraftingHotbarSlot = 37
+)
+
+// NewCraftingMenu returns the window of a crafting table, crafting the recipes of the manager.
+// The items of the grid are given back to the inventory when it's closed.
+func NewCraftingMenu(inv *PlayerInventory, recipes *recipe.Manager) *Menu {
+	grid := NewCraftingGrid(3, 3, recipes)
+	m := &Menu{Type: inventory.Crafting, Inventory: inv, Crafting: grid, QuickMove: quickMoveCrafting}
+	m.Slots = appendCraftingSlots(m.Slots, grid, inv)
+	m.Slots = appendInventorySlots(m.Slots, inv)
+	m.OnClose = func(p Player) { grid.clear(p, inv) }
+	return m
+}
+
+func quickMoveCrafting(m *Menu, p Player, index int) item.ItemStack {
+	s := &m.Slots[index]
+	stack := s.Item()
+	if stack.IsEmpty() {
+		return item.ItemStack{}
+	}
+	before := stack.Copy()
+	var moved bool
+	switch {
+	case index == CraftingResultSlot:
+		moved = m.MoveItemStackTo(&stack, CraftingMainSlot, len(m.Slots), true)
+	case index >= CraftingMainSlot:
+		moved = m.MoveItemStackTo(&stack, CraftingGridSlot, CraftingMainSlot, false) ||
+			index < CraftingHotbarSlot && m.MoveItemStackTo(&stack, CraftingHotbarSlot, len(m.Slots), false) ||
+			index >= CraftingHotbarSlot && m.MoveItemStackTo(&stack, CraftingMainSlot, CraftingHotbarSlot, false)
+	default:
+		moved = m.MoveItemStackTo(&stack, CraftingMainSlot, len(m.Slots), false)
+	}
+	if !moved {
+		return item.ItemStack{}
+	}
+	s.Set(stack)
+	if stack.Count == before.Count {
+		return item.ItemStack{}
+	}
+	taken := before.Copy()
+	taken.Count -= stack.Count
+	s.onTake(p, taken)
+	if index == CraftingResultSlot && !stack.IsEmpty() {
+		p.Drop(stack)
+	}
+	return before
+}
+
+// NewContainerMenu returns a window showing the container above the inventory of the player.
+// The type can be a chest of 1 to 6 rows, a dispenser, a hopper or a shulker box,
+// and the container must have the slots of it.
+func NewContainerMenu(typ inventory.InventoryID, c Container, inv *PlayerInventory) *Menu {
+	var size int
+	switch typ {
+	case inventory.Generic9x1, inventory.Generic9x2, inventory.Generic9x3,
+		inventory.Generic9x4, inventory.Generic9x5, inventory.Generic9x6:
+		size = int(typ-inventory.Generic9x1+1) * 9
+	case inventory.Generic3x3:
+		size = 9
+	case inventory.Hopper:
+		size = 5
+	case inventory.ShulkerBox:
+		size = 27
+	default:
+		panic("container: unsupported menu type " + inventory.IDToName(typ))
+	}
+	if c.Size() != size {
+		panic("container: the container doesn't have the slots of " + inventory.IDToName(typ))
+	}
+	m := &Menu{Type: typ, Inventory: inv, QuickMove: quickMoveContainer(size)}
+	for i := 0; i < size; i++ {
+		m.Slots = append(m.Slots, Slot{Container: c, Index: i})
+	}
+	if typ == inventory.ShulkerBox {
+		for i := range m.Slots {
+			m.Slots[i].MayPlace = func(s item.ItemStack) bool {
+				return !strings.HasSuffix(s.ItemID.Name(), "shulker_box")
+			}
+		}
+	}
+	m.Slots = appendInventorySlots(m.Slots, inv)
+	return m
+}
+
+// quickMoveContainer moves the stacks between the container of the size and the inventory of the player.
+func quickMoveContainer(size int) func(m *Menu, p Player, index int) item.ItemStack {
+	return func(m *Menu, p Player, index int) item.ItemStack {
+		s := &m.Slots[index]
+		stack := s.Item()
+		if stack.IsEmpty() {
+			return item.ItemStack{}
+		}
+		before := stack.Copy()
+		if index < size {
+			if !m.MoveItemStackTo(&stack, size, len(m.Slots), true) {
+				return item.ItemStack{}
+			}
+		} else if !m.MoveItemStackTo(&stack, 0, size, false) {
+			return item.ItemStack{}
+		}
+		s.Set(stack)
+		return before
+	}
+}
+
+// appendInventorySlots appends the main inventory and the hotbar, which are at the bottom of every window.
+func appendInventorySlots(slots []Slot, inv *PlayerInventory) []Slot {
+	for i := HotbarSize; i < InventorySize; i++ {
+		slots = append(slots, Slot{Container: inv, Index: i})
+	}
+	for i := 0; i < HotbarSize; i++ {
+		slots = append(slots, Slot{Container: inv, Index: i})
+	}
+	return slots
+}
@@ -0,0 +1,142 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package container
+
+import "github.com/mrhaoxx/go-mc/level/item"
+
+// Slot is a slot of a Menu, showing a slot of a Container.
+// The hooks restrict what the player can do with the slot, nil hooks allow everything.
+type Slot struct {
+	Container Container
+	Index     int
+	// MaxStackSize limits the number of items in the slot, 0 means MaxStackSize.
+	MaxStackSize int32
+	// MayPlace reports whether the player can put the stack in the slot.
+	MayPlace func(s item.ItemStack) bool
+	// MayPickup reports whether the player can take the items from the slot.
+	MayPickup func() bool
+	// OnTake is called with the items the player took from the slot.
+	OnTake func(p Player, s item.ItemStack)
+}
+
+// Item returns the stack in the slot.
+func (s *Slot) Item() item.ItemStack { return s.Container.Item(s.Index) }
+
+// Set puts the stack in the slot.
+func (s *Slot) Set(stack item.ItemStack) { s.Container.SetItem(s.Index, stack) }
+
+// HasItem reports whether the slot isn't empty.
+func (s *Slot) HasItem() bool { return !s.Item().IsEmpty() }
+
+func (s *Slot) mayPlace(stack item.ItemStack) bool {
+	return s.MayPlace == nil || s.MayPlace(stack)
+}
+
+func (s *Slot) mayPickup() bool {
+	return s.MayPickup == nil || s.MayPickup()
+}
+
+func (s *Slot) onTake(p Player, stack item.ItemStack) {
+	if s.OnTake != nil {
+		s.OnTake(p, stack)
+	}
+}
+
+// maxStackSize returns the number of items of the stack the slot can hold.
+func (s *Slot) maxStackSize(stack item.ItemStack) int32 {
+	limit := s.MaxStackSize
+	if limit == 0 {
+		limit = MaxStackSize
+	}
+	return min(limit, stack.MaxStackSize())
+}
+
+// allowModification reports whether the player can both take and put back the items of the slot.
+func (s *Slot) allowModification() bool {
+	return s.mayPickup() && s.mayPlace(s.Item())
+}
+
+// remove takes at most n items out of the slot.
+func (s *Slot) remove(n int32) item.ItemStack {
+	if r, ok := s.Container.(Remover); ok {
+		return r.RemoveItem(s.Index, n)
+	}
+	stack := s.Item()
+	split := stack.Split(n)
+	s.Set(stack)
+	return split
+}
+
+// tryRemove takes count items out of the slot, or at most limit if the player can't put them back.
+func (s *Slot) tryRemove(count, limit int32) (item.ItemStack, bool) {
+	if !s.mayPickup() {
+		return item.ItemStack{}, false
+	}
+	if !s.allowModification() && limit < s.Item().Count {
+		return item.ItemStack{}, false
+	}
+	taken := s.remove(min(count, limit))
+	return taken, !taken.IsEmpty()
+}
+
+// safeTake takes the items like tryRemove and calls OnTake.
+func (s *Slot) safeTake(p Player, count, limit int32) item.ItemStack {
+	taken, _ := s.tryRemove(count, limit)
+	if !taken.IsEmpty() {
+		s.onTake(p, taken)
+	}
+	return taken
+}
+
+// safeInsert puts at most n items of the stack in the slot, and returns the remaining ones.
+func (s *Slot) safeInsert(stack item.ItemStack, n int32) item.ItemStack {
+	if stack.IsEmpty() || !s.mayPlace(stack) {
+		return stack
+	}
+	current := s.Item()
+	n = min(n, stack.Count, s.maxStackSize(stack)-current.Count)
+	if n <= 0 {
+		return stack
+	}
+	if current.IsEmpty() {
+		s.Set(stack.Split(n))
+	} else if item.SameItemSameComponents(current, stack) {
+		stack.Count -= n
+		current.Count += n
+		s.Set(current)
+		if stack.Count == 0 {
+			stack = item.ItemStack{}
+		}
+	}
+	return stack
+}
+
+// canItemQuickReplace reports whether the stack can be put in the slot by a drag or a double click.
+func canItemQuickReplace(s *Slot, stack item.ItemStack, stackSizeMatters bool) bool {
+	if s == nil || !s.HasItem() {
+		return true
+	}
+	current := s.Item()
+	if !item.SameItemSameComponents(stack, current) {
+		return false
+	}
+	n := current.Count
+	if !stackSizeMatters {
+		n += stack.Count
+	}
+	return n <= stack.MaxStackSize()
+}
@@ -0,0 +1,424 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/registry"
+	"github.com/mrhaoxx/go-mc/world/container"
+	"github.com/mrhaoxx/go-mc/world/entity"
+)
+
+// The damage types of the damage dealt by the world, keys of the minecraft:damage_type registry.
+const (
+	DamageGeneric    = "minecraft:generic"
+	DamageFall       = "minecraft:fall"
+	DamageOutOfWorld = "minecraft:out_of_world"
+	DamageDrown      = "minecraft:drown"
+	DamageInFire     = "minecraft:in_fire"
+	DamageOnFire     = "minecraft:on_fire"
+	DamageLava       = "minecraft:lava"
+)
+
+// DamageSource is what hurts a player: the key of its type in the damage type registry,
+// and the entity attacking the player, if any.
+type DamageSource struct {
+	Type     string
+	Attacker *Entity
+}
+
+// The damage type tags of vanilla deciding how the damage is reduced, which aren't part of the registry.
+var (
+	bypassesArmor = setOf(DamageGeneric, DamageFall, DamageOutOfWorld, DamageDrown, DamageOnFire,
+		"minecraft:in_wall", "minecraft:cramming", "minecraft:fly_into_wall", "minecraft:wither",
+		"minecraft:dragon_breath", "minecraft:starve", "minecraft:freeze", "minecraft:stalagmite",
+		"minecraft:magic", "minecraft:indirect_magic", "minecraft:generic_kill", "minecraft:sonic_boom",
+		"minecraft:outside_border")
+	// bypassesInvulnerability are also the types not reduced by the effects and the enchantments.
+	bypassesInvulnerability = setOf(DamageOutOfWorld, "minecraft:generic_kill")
+	isFire                  = setOf(DamageInFire, DamageOnFire, DamageLava, "minecraft:hot_floor",
+		"minecraft:unattributed_fireball", "minecraft:fireball", "minecraft:campfire")
+	isFall      = setOf(DamageFall, "minecraft:ender_pearl", "minecraft:stalagmite")
+	isExplosion = setOf("minecraft:explosion", "minecraft:player_explosion", "minecraft:fireworks",
+		"minecraft:bad_respawn_point")
+	isProjectile = setOf("minecraft:arrow", "minecraft:trident", "minecraft:mob_projectile", "minecraft:spit",
+		"minecraft:fireball", "minecraft:unattributed_fireball", "minecraft:wither_skull", "minecraft:thrown",
+		"minecraft:wind_charge")
+)
+
+func setOf(keys ...string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set
+}
+
+const (
+	maxHealth = 20
+	maxAir    = 300
+	// invulnerableTicks is the time after a hit during which the player is invulnerable,
+	// though in its later half the damage above the one of the hit is still dealt.
+	invulnerableTicks = 20
+	// regenInterval is the number of ticks between two healings of the fed players.
+	regenInterval = 80
+	// voidDepth is how far below the bottom of the world the players are hurt by the void, voidDamage every tick.
+	voidDepth  = 64
+	voidDamage = 4
+	// safeFall is the height the players fall without being hurt.
+	safeFall = 3
+	// burnInFire and burnInLava are the number of ticks the players burn after touching fire and lava.
+	burnInFire = 160
+	burnInLava = 300
+)
+
+// health is the state of the damage the player takes.
+type health struct {
+	// invulnerable is the number of ticks the player is still invulnerable, since it took the lastDamage.
+	invulnerable int32
+	lastDamage   float32
+	// regen is the number of ticks since the player was last healed.
+	regen int32
+	// sent is the health the client knows.
+	sent sentHealth
+}
+
+type sentHealth struct {
+	health     float32
+	food       int32
+	saturation float32
+}
+
+// Dead reports whether the player died and hasn't respawned yet.
+func (p *Player) Dead() bool { return p.Health <= 0 }
+
+// invulnerable reports whether the player is in creative or spectator mode, where it's hurt only by the void.
+func (p *Player) invulnerable() bool { return p.Gamemode == 1 || p.Gamemode == 3 }
+
+// Respawn restores the health, the hunger and the air of the dead player, and puts out its fire.
+// It must be called while the player is in no world, before it's spawned again.
+func (p *Player) Respawn() {
+	p.Health, p.FoodLevel, p.Saturation, p.Air = maxHealth, 20, 5, maxAir
+	p.FireTicks, p.FallDistance = 0, 0
+	p.health = health{}
+}
+
+// IsDead reports whether the player of the client is dead in the world, waiting to respawn.
+func (w *World) IsDead(c Client) bool {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p, ok := w.players[c]
+	return ok && p.Dead()
+}
+
+// Damage hurts the player of the client, and reports whether it took the damage.
+func (w *World) Damage(c Client, source DamageSource, amount float32) bool {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p, ok := w.players[c]
+	if !ok {
+		return false
+	}
+	return w.hurt(c, p, source, amount)
+}
+
+// hurt deals the damage to the player and reports whether it took it, like vanilla's LivingEntity.hurt.
+// While the player is invulnerable after a hit, only the damage above the one of that hit is dealt,
+// and none in the first half of the invulnerability. The damage is then reduced by the armor,
+// the resistance effect and the protection enchantments, and the player dies when it has no health left.
+func (w *World) hurt(c Client, p *Player, source DamageSource, amount float32) bool {
+	typeID, typ := NetworkCodec.DamageType.Get(source.Type)
+	if typ == nil || p.Dead() || amount <= 0 ||
+		p.invulnerable() && !bypassesInvulnerability[source.Type] ||
+		isFire[source.Type] && p.effectLevel("minecraft:fire_resistance") > 0 {
+		return false
+	}
+	h := &p.health
+	hit := h.invulnerable <= invulnerableTicks/2
+	if hit {
+		h.invulnerable, h.lastDamage = invulnerableTicks, amount
+	} else if amount <= h.lastDamage {
+		return false
+	} else {
+		amount, h.lastDamage = amount-h.lastDamage, amount
+	}
+	p.Health = max(p.Health-p.reduceDamage(source.Type, amount), 0)
+	if hit {
+		attacker := int32(-1)
+		if source.Attacker != nil {
+			attacker = source.Attacker.EntityID
+		}
+		c.ViewDamageEvent(p.EntityID, typeID, attacker)
+		for _, v := range p.viewers {
+			v.ViewDamageEvent(p.EntityID, typeID, attacker)
+		}
+	}
+	if p.Dead() {
+		w.die(c, p, source, typ)
+	}
+	return true
+}
+
+// reduceDamage returns the damage of the type left after the armor, the resistance effect and the protection enchantments.
+func (p *Player) reduceDamage(typ string, amount float32) float32 {
+	var armor, toughness float64
+	var protection int32
+	p.ContainerLock.Lock()
+	for _, s := range p.Inventory[container.ArmorSlot : container.ArmorSlot+4] {
+		a, t := s.Armor()
+		armor, toughness = armor+a, toughness+t
+		protection += s.EnchantmentLevel("minecraft:protection")
+		switch {
+		case isFire[typ]:
+			protection += 2 * s.EnchantmentLevel("minecraft:fire_protection")
+		case isFall[typ]:
+			protection += 3 * s.EnchantmentLevel("minecraft:feather_falling")
+		case isExplosion[typ]:
+			protection += 2 * s.EnchantmentLevel("minecraft:blast_protection")
+		case isProjectile[typ]:
+			protection += 2 * s.EnchantmentLevel("minecraft:projectile_protection")
+		}
+	}
+	p.ContainerLock.Unlock()
+
+	damage := float64(amount)
+	if !bypassesArmor[typ] {
+		damage *= 1 - min(20, max(armor/5, armor-4*damage/(toughness+8)))/25
+	}
+	if !bypassesInvulnerability[typ] {
+		damage *= max(0, 1-float64(p.effectLevel("minecraft:resistance"))/5)
+		damage *= 1 - float64(min(protection, 20))/25
+	}
+	return float32(damage)
+}
+
+// die kills the player hurt by the source. The death message is sent to it with the respawn screen,
+// and given to Config.OnDeath. The player loses its effects, its experience and the items of its inventory,
+// which are dropped around it.
+func (w *World) die(c Client, p *Player, source DamageSource, typ *registry.DamageType) {
+	msg := deathMessage(p, source, typ)
+	c.SendPlayerCombatKill(p.EntityID, msg)
+	if w.config.OnDeath != nil {
+		w.config.OnDeath(c, p, msg)
+	}
+	p.FireTicks, p.FallDistance = 0, 0
+	p.setSharedFlag(entity.FlagOnFire, false)
+	clear(p.Effects)
+	p.ExperienceLevel, p.ExperienceProgress, p.TotalExperience = 0, 0, 0
+	c.SendSetExperience(0, 0, 0)
+
+	p.ContainerLock.Lock()
+	defer p.ContainerLock.Unlock()
+	for i, s := range p.Inventory {
+		if !s.IsEmpty() {
+			w.throwItem(p, s, true)
+			p.Inventory[i] = item.ItemStack{}
+		}
+	}
+}
+
+// deathMessage returns the message telling the player was killed by the source,
+// one of the death translations chosen by the death message type of its damage type.
+func deathMessage(p *Player, source DamageSource, typ *registry.DamageType) chat.Message {
+	name := chat.Text(p.Name)
+	switch typ.DeathMessageType {
+	case "fall_variants":
+		return chat.TranslateMsg("death.fell.accident.generic", name)
+	case "intentional_game_design":
+		return chat.TranslateMsg("death.attack."+typ.MessageID+".message", name,
+			chat.TranslateMsg("chat.square_brackets", chat.TranslateMsg("death.attack."+typ.MessageID+".link")))
+	}
+	key := "death.attack." + typ.MessageID
+	if source.Attacker != nil {
+		return chat.TranslateMsg(key+".player", name, entityName(source.Attacker))
+	}
+	return chat.TranslateMsg(key, name)
+}
+
+// entityName returns the name of the player, or the name of the type of the other entities.
+func entityName(e *Entity) chat.Message {
+	if p, ok := e.Data.(*Player); ok {
+		return chat.Text(p.Name)
+	}
+	return chat.TranslateMsg("entity." + strings.ReplaceAll(e.Type.Name, ":", "."))
+}
+
+// fall hurts the player landing after falling more than the safe height, jump boost adding to it.
+func (w *World) fall(c Client, p *Player) {
+	damage := math.Ceil(p.FallDistance - safeFall - float64(p.effectLevel("minecraft:jump_boost")))
+	if damage > 0 {
+		w.hurt(c, p, DamageSource{Type: DamageFall}, float32(damage))
+	}
+}
+
+// subtickPlayerHealth hurts the players in the void, drowning and burning, heals the fed ones,
+// and sends the health of the players to them when it changes.
+func (w *World) subtickPlayerHealth() {
+	for c, p := range w.players {
+		if !p.Dead() {
+			w.tickHealth(c, p)
+		}
+		w.syncHealth(c, p)
+	}
+}
+
+func (w *World) tickHealth(c Client, p *Player) {
+	h := &p.health
+	if h.invulnerable > 0 {
+		h.invulnerable--
+	}
+	if p.pos0[1] < worldMinY-voidDepth {
+		w.hurt(c, p, DamageSource{Type: DamageOutOfWorld}, voidDamage)
+	}
+	w.tickAir(c, p)
+	w.tickFire(c, p)
+	if p.Dead() || p.Health >= maxHealth || p.FoodLevel < 18 {
+		h.regen = 0
+	} else if h.regen++; h.regen >= regenInterval {
+		h.regen = 0
+		p.Health = min(p.Health+1, maxHealth)
+	}
+}
+
+// tickAir consumes the air of the player underwater, which drowns when it has none left, and refills it out of water.
+// The respiration enchantment of the helmet gives a chance not to consume the air.
+func (w *World) tickAir(c Client, p *Player) {
+	if !w.eyeInWater(p) {
+		p.Air = min(p.Air+4, maxAir)
+		return
+	}
+	if p.invulnerable() || p.effectLevel("minecraft:water_breathing") > 0 || p.effectLevel("minecraft:conduit_power") > 0 {
+		return
+	}
+	p.ContainerLock.Lock()
+	respiration := p.Inventory[container.ArmorSlot+3].EnchantmentLevel("minecraft:respiration")
+	p.ContainerLock.Unlock()
+	if respiration > 0 && w.rand.Int31n(respiration+1) > 0 {
+		return
+	}
+	if p.Air--; p.Air <= -20 {
+		p.Air = 0
+		w.hurt(c, p, DamageSource{Type: DamageDrown}, 2)
+	}
+}
+
+// tickFire sets the player touching fire or lava on fire, and hurts it every second while it burns.
+// Water puts the fire out.
+func (w *World) tickFire(c Client, p *Player) {
+	if p.invulnerable() {
+		p.FireTicks = 0
+	}
+	switch w.touchedHeat(shrink(boundingBox(p.pos0, PlayerWidth, PlayerHeight), 1e-3)) {
+	case DamageLava:
+		w.hurt(c, p, DamageSource{Type: DamageLava}, 4)
+		p.FireTicks = max(p.FireTicks, burnInLava)
+	case DamageInFire:
+		w.hurt(c, p, DamageSource{Type: DamageInFire}, 1)
+		p.FireTicks = max(p.FireTicks, burnInFire)
+	case "water":
+		p.FireTicks = 0
+	}
+	if p.FireTicks > 0 {
+		if p.FireTicks%20 == 0 {
+			w.hurt(c, p, DamageSource{Type: DamageOnFire}, 1)
+		}
+		p.FireTicks--
+	}
+	p.setSharedFlag(entity.FlagOnFire, p.FireTicks > 0)
+}
+
+// touchedHeat returns the damage type of the fire or the lava in the box, or "water" if it's in water,
+// which puts out the fire.
+func (w *World) touchedHeat(box aabb3d) string {
+	var heat string
+	for x := int(math.Floor(box.Lower[0])); x <= int(math.Floor(box.Upper[0])); x++ {
+		for y := int(math.Floor(box.Lower[1])); y <= int(math.Floor(box.Upper[1])); y++ {
+			for z := int(math.Floor(box.Lower[2])); z <= int(math.Floor(box.Upper[2])); z++ {
+				s, ok := w.getBlock(x, y, z)
+				if !ok {
+					continue
+				}
+				switch b := block.StateList[s]; {
+				case block.FluidOf(block.StateID(s)).Type == block.FluidWater:
+					return "water"
+				case block.FluidOf(block.StateID(s)).Type == block.FluidLava:
+					heat = DamageLava
+				case heat == "" && isFireBlock(b):
+					heat = DamageInFire
+				}
+			}
+		}
+	}
+	return heat
+}
+
+// isFireBlock reports whether the block burns the entities in it.
+func isFireBlock(b block.Block) bool {
+	switch b := b.(type) {
+	case block.Fire, block.SoulFire:
+		return true
+	case block.Campfire:
+		return bool(b.Lit)
+	case block.SoulCampfire:
+		return bool(b.Lit)
+	}
+	return false
+}
+
+// eyeInWater reports whether the eyes of the player are in water.
+func (w *World) eyeInWater(p *Player) bool {
+	s, ok := w.getBlock(int(math.Floor(p.pos0[0])), int(math.Floor(p.pos0[1]+PlayerEyeHeight)), int(math.Floor(p.pos0[2])))
+	return ok && block.FluidOf(block.StateID(s)).Type == block.FluidWater
+}
+
+// setSharedFlag sets the bit of the DataSharedFlags metadata field of the player, and reports whether it changed.
+func (p *Player) setSharedFlag(flag pk.Byte, on bool) bool {
+	var flags pk.Byte
+	if v, ok := p.Metadata.Get(entity.DataSharedFlags); ok {
+		flags = v.(*entity.Byte).Byte
+	}
+	if flags&flag != 0 == on {
+		return false
+	}
+	p.SetMetadata(entity.DataSharedFlags, &entity.Byte{Byte: flags ^ flag})
+	return true
+}
+
+// syncHealth sends the health, the food and the saturation of the player to it when they change,
+// and shows its health and its air to the viewers.
+func (w *World) syncHealth(c Client, p *Player) {
+	if (sentHealth{p.Health, p.FoodLevel, p.Saturation}) != p.health.sent {
+		sendHealth(c, p)
+	}
+	if v, ok := p.Metadata.Get(entity.DataAirSupply); ok && v.(*entity.VarInt).VarInt != pk.VarInt(p.Air) ||
+		!ok && p.Air != maxAir {
+		p.SetMetadata(entity.DataAirSupply, &entity.VarInt{VarInt: pk.VarInt(p.Air)})
+	}
+}
+
+// sendHealth sends the health, the food and the saturation of the player to it, and its health to the viewers.
+func sendHealth(c Client, p *Player) {
+	p.health.sent = sentHealth{p.Health, p.FoodLevel, p.Saturation}
+	c.SendSetHealth(p.Health, p.FoodLevel, p.Saturation)
+	p.SetMetadata(entity.DataHealth, &entity.Float{Float: pk.Float(p.Health)})
+}
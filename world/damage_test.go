@@ -0,0 +1,125 @@
+package world
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/world/container"
+)
+
+// damageClient records the damage and the deaths sent to the client.
+type damageClient struct {
+	Client
+	health float32
+	hits   int
+	deaths []chat.Message
+}
+
+func (c *damageClient) SendSetHealth(health float32, _ int32, _ float32) { c.health = health }
+func (c *damageClient) SendSetExperience(float32, int32, int32)          {}
+func (c *damageClient) ViewDamageEvent(int32, int32, int32)              { c.hits++ }
+func (c *damageClient) SendPlayerCombatKill(_ int32, msg chat.Message) {
+	c.deaths = append(c.deaths, msg)
+}
+
+func damagedPlayer(pos Position) *Player {
+	p := &Player{Entity: Entity{EntityID: NewEntityID(), Position: pos, Type: PlayerType}, Name: "Steve"}
+	p.Data, p.pos0 = p, pos
+	p.Respawn()
+	return p
+}
+
+func TestWorld_hurt(t *testing.T) {
+	w, _, _ := physicsWorld(t)
+	c := new(damageClient)
+	p := damagedPlayer(Position{8.5, 65, 8.5})
+	var chestplate item.ID
+	if err := chestplate.UnmarshalText([]byte("minecraft:diamond_chestplate")); err != nil {
+		t.Fatal(err)
+	}
+	p.Inventory[container.ArmorSlot+2] = item.New(chestplate, 1)
+	attack := DamageSource{Type: "minecraft:mob_attack"}
+	near := func(want float32) bool { return math.Abs(float64(p.Health-want)) < 1e-3 }
+
+	// 8 armor points and 2 toughness block 4 of the 25 parts of 10 damage.
+	if !w.hurt(c, p, attack, 10) || !near(11.6) || c.hits != 1 {
+		t.Fatalf("health after 10 damage = %v, %d hits, want 11.6", p.Health, c.hits)
+	}
+	// Right after the hit, the player is invulnerable to the damage not above it.
+	if w.hurt(c, p, attack, 10) || !near(11.6) {
+		t.Fatalf("health after 10 more damage = %v, want 11.6", p.Health)
+	}
+	// The damage above it is dealt, without another hit.
+	if !w.hurt(c, p, attack, 12) || !near(11.6-2*(1-7.2/25)) || c.hits != 1 {
+		t.Fatalf("health after 12 damage = %v, %d hits", p.Health, c.hits)
+	}
+	// Fall damage goes through the armor, and the creative players aren't hurt.
+	p.health.invulnerable = 0
+	health := p.Health
+	if !w.hurt(c, p, DamageSource{Type: DamageFall}, 3) || !near(health-3) {
+		t.Fatalf("health after 3 fall damage = %v, want %v", p.Health, health-3)
+	}
+	p.health.invulnerable, p.Gamemode = 0, 1
+	if w.hurt(c, p, attack, 3) {
+		t.Error("the creative player is hurt")
+	}
+}
+
+func TestWorld_fall(t *testing.T) {
+	w, _, _ := physicsWorld(t)
+	c := new(damageClient)
+	p := damagedPlayer(Position{8.5, 75, 8.5})
+	var in Inputs
+	for y := 74.0; y >= 65; y-- {
+		in.Position = Position{8.5, y, 8.5}
+		if !w.checkMove(c, p, &in) {
+			t.Fatalf("the fall to y %v is rejected", y)
+		}
+		p.pos0 = in.Position
+	}
+	// The fall distance is 9 blocks before the landing move, 3 of which are safe.
+	if p.Health != 14 || p.FallDistance != 0 {
+		t.Errorf("health after falling from y 75 = %v, fall distance %v, want 14 and 0", p.Health, p.FallDistance)
+	}
+}
+
+func TestWorld_tickHealth_void(t *testing.T) {
+	w, _, _ := physicsWorld(t)
+	w.rand = rand.New(rand.NewSource(1))
+	var deaths []chat.Message
+	w.config.OnDeath = func(_ Client, _ *Player, msg chat.Message) { deaths = append(deaths, msg) }
+	c := new(damageClient)
+	p := damagedPlayer(Position{8.5, worldMinY - voidDepth - 10, 8.5})
+	p.Gamemode = 1
+	p.Inventory[0] = item.New(1, 5)
+	entities := len(w.entities)
+
+	// The void deals 4 damage every 10 ticks, through the invulnerability of the creative mode.
+	for range 41 {
+		w.tickHealth(c, p)
+		w.syncHealth(c, p)
+	}
+	if !p.Dead() || c.health != 0 {
+		t.Fatalf("health after 41 ticks in the void = %v, the client has %v", p.Health, c.health)
+	}
+	w.players = map[Client]*Player{c: p}
+	if !w.IsDead(c) || w.IsDead(new(damageClient)) {
+		t.Error("IsDead doesn't report the dead player of the client")
+	}
+	if len(c.deaths) != 1 || len(deaths) != 1 || c.deaths[0].Translate != "death.attack.outOfWorld" {
+		t.Fatalf("death messages = %v, %v", c.deaths, deaths)
+	}
+	if !p.Inventory[0].IsEmpty() || len(w.entities) != entities+1 {
+		t.Errorf("the inventory wasn't dropped: %v, %d entities", p.Inventory[0], len(w.entities))
+	}
+
+	p.Respawn()
+	p.pos0 = Position{8.5, 65, 8.5}
+	w.syncHealth(c, p)
+	if p.Dead() || c.health != maxHealth {
+		t.Errorf("health after respawning = %v, the client has %v", p.Health, c.health)
+	}
+}
@@ -0,0 +1,284 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/loot"
+	"github.com/mrhaoxx/go-mc/world/container"
+	"github.com/mrhaoxx/go-mc/world/internal/bvh"
+)
+
+// DigAction is what a player does to a block, the status of the ServerboundPlayerAction packet.
+type DigAction int32
+
+const (
+	DigStart DigAction = iota
+	DigAbort
+	DigFinish
+)
+
+// The block interaction range of the players, from their eyes to the nearest point of a block, like vanilla.
+const (
+	blockInteractionRange         = 4.5
+	creativeBlockInteractionRange = 5
+)
+
+// The margins vanilla adds to the block interaction range, for using the blocks,
+// and for keeping the windows opened from them.
+const (
+	reachMargin = 1
+	menuMargin  = 4
+)
+
+// minFinishProgress is the part of a block a player must have broken when it reports the block broken.
+// Like vanilla, a bit of latency is tolerated, and the block is broken later if the player was too fast.
+const minFinishProgress = 0.7
+
+// digging is the state of a player breaking blocks, like vanilla's ServerPlayerGameMode.
+type digging struct {
+	active bool
+	pos    [3]int
+	// start is the tick the player started breaking the block.
+	start uint
+	// stage is the last stage of the destruction sent to the other players.
+	stage int
+	// delayed is set for a block the player finished breaking too fast,
+	// which breaks when enough ticks have passed.
+	delayed      bool
+	delayedPos   [3]int
+	delayedStart uint
+}
+
+// DigBlock handles the action of the player of the client on the block at the position.
+// Players in creative mode break the blocks at once, while the others must wait for the time the block takes to break,
+// which depends on its hardness, on the tool and on the player. The blocks broken too fast, out of reach,
+// or broken by spectators are sent again to the client.
+func (w *World) DigBlock(c Client, pos [3]int, action DigAction) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p, ok := w.players[c]
+	if !ok {
+		return
+	}
+	d := &p.digging
+	state, loaded := w.getBlock(pos[0], pos[1], pos[2])
+	if !loaded {
+		return
+	}
+	s := block.StateID(state)
+	if action == DigAbort {
+		if d.active {
+			d.active = false
+			w.broadcastDestruction(p, d.pos, -1)
+		}
+		return
+	}
+	if !w.mayDig(p, pos) {
+		c.ViewBlockUpdate([3]int32{int32(pos[0]), int32(pos[1]), int32(pos[2])}, state)
+		return
+	}
+	switch action {
+	case DigStart:
+		if p.HasInfiniteMaterials() {
+			w.destroyBlock(p, pos)
+			return
+		}
+		if d.active {
+			w.broadcastDestruction(p, d.pos, -1)
+		}
+		d.active = false
+		progress := w.miner(p).DestroyProgress(s)
+		if block.IsAirBlock(block.StateList[s]) {
+			return
+		}
+		if progress >= 1 {
+			w.destroyBlock(p, pos)
+			return
+		}
+		*d = digging{active: true, pos: pos, start: w.tickCount, stage: int(progress * 10),
+			delayed: d.delayed, delayedPos: d.delayedPos, delayedStart: d.delayedStart}
+		w.broadcastDestruction(p, pos, d.stage)
+	case DigFinish:
+		if !d.active || d.pos != pos {
+			c.ViewBlockUpdate([3]int32{int32(pos[0]), int32(pos[1]), int32(pos[2])}, state)
+			return
+		}
+		d.active = false
+		progress := w.miner(p).DestroyProgress(s) * float32(w.tickCount-d.start+1)
+		if progress >= minFinishProgress {
+			w.broadcastDestruction(p, pos, -1)
+			w.destroyBlock(p, pos)
+			return
+		}
+		if d.delayed {
+			c.ViewBlockUpdate([3]int32{int32(pos[0]), int32(pos[1]), int32(pos[2])}, state)
+			return
+		}
+		d.delayed, d.delayedPos, d.delayedStart = true, pos, d.start
+	}
+}
+
+// mayDig reports whether the block is in reach of the player, unless it's a spectator.
+func (w *World) mayDig(p *Player, pos [3]int) bool {
+	return p.Gamemode != 3 && p.inReach(pos)
+}
+
+// inReach reports whether the player can use the block, within its block interaction range.
+func (p *Player) inReach(pos [3]int) bool {
+	return p.withinRange(pos, reachMargin)
+}
+
+// withinRange reports whether the block is within the block interaction range of the player plus the margin, from its eyes.
+func (p *Player) withinRange(pos [3]int, margin float64) bool {
+	eye := [3]float64{p.Position[0], p.Position[1] + PlayerEyeHeight, p.Position[2]}
+	var dist2 float64
+	for i := range eye {
+		nearest := math.Max(float64(pos[i]), math.Min(eye[i], float64(pos[i]+1)))
+		dist2 += (eye[i] - nearest) * (eye[i] - nearest)
+	}
+	reach := blockInteractionRange + margin
+	if p.HasInfiniteMaterials() {
+		reach = creativeBlockInteractionRange + margin
+	}
+	return dist2 <= reach*reach
+}
+
+// InReach reports whether the player of the client can use the block, within its block interaction range.
+func (w *World) InReach(c Client, pos [3]int) bool {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p, ok := w.players[c]
+	return ok && p.inReach(pos)
+}
+
+// heldItem returns the item in the main hand of the player.
+func (p *Player) heldItem() item.ItemStack {
+	if p.CarriedSlot < 0 || p.CarriedSlot >= container.HotbarSize {
+		return item.ItemStack{}
+	}
+	p.ContainerLock.Lock()
+	defer p.ContainerLock.Unlock()
+	return p.Inventory[p.CarriedSlot]
+}
+
+// miner returns how fast the player mines blocks with the item held,
+// which also depends on its effects, on whether it's underwater and whether it's standing on the ground.
+func (w *World) miner(p *Player) block.Miner {
+	held := p.heldItem()
+	m := block.Miner{
+		Haste:         max(p.effectLevel("minecraft:haste"), p.effectLevel("minecraft:conduit_power")),
+		MiningFatigue: p.effectLevel("minecraft:mining_fatigue"),
+		OnGround:      bool(p.OnGround),
+	}
+	if tool, ok := held.Tool(); ok {
+		m.Tool = tool
+		m.Efficiency = held.EnchantmentLevel("minecraft:efficiency")
+	}
+	if w.eyeInWater(p) {
+		p.ContainerLock.Lock()
+		helmet := p.Inventory[container.ArmorSlot+3]
+		p.ContainerLock.Unlock()
+		m.Underwater = helmet.EnchantmentLevel("minecraft:aqua_affinity") == 0
+	}
+	return m
+}
+
+// effectLevel returns the amplifier of the effect on the player plus one, 0 if it hasn't the effect.
+func (p *Player) effectLevel(name string) int32 {
+	if amplifier, ok := p.Effects[name]; ok {
+		return amplifier + 1
+	}
+	return 0
+}
+
+// subtickDigging breaks the blocks the players finished breaking too fast once enough ticks have passed,
+// and sends the destruction stages of the blocks being broken to the other players.
+func (w *World) subtickDigging() {
+	for _, p := range w.players {
+		d := &p.digging
+		if d.delayed {
+			s, ok := w.getBlock(d.delayedPos[0], d.delayedPos[1], d.delayedPos[2])
+			if !ok || block.IsAirBlock(block.StateList[s]) {
+				d.delayed = false
+			} else if w.digProgress(p, d.delayedPos, d.delayedStart, block.StateID(s)) >= 1 {
+				d.delayed = false
+				w.broadcastDestruction(p, d.delayedPos, -1)
+				w.destroyBlock(p, d.delayedPos)
+			}
+		} else if d.active {
+			s, ok := w.getBlock(d.pos[0], d.pos[1], d.pos[2])
+			if !ok || block.IsAirBlock(block.StateList[s]) {
+				d.active = false
+				w.broadcastDestruction(p, d.pos, -1)
+			} else {
+				w.digProgress(p, d.pos, d.start, block.StateID(s))
+			}
+		}
+	}
+}
+
+// digProgress returns the part of the block the player has broken since the tick started,
+// and sends the destruction stage to the other players if it has changed.
+func (w *World) digProgress(p *Player, pos [3]int, start uint, s block.StateID) float32 {
+	progress := w.miner(p).DestroyProgress(s) * float32(w.tickCount-start+1)
+	if stage := int(progress * 10); stage != p.digging.stage {
+		p.digging.stage = stage
+		w.broadcastDestruction(p, pos, stage)
+	}
+	return progress
+}
+
+// broadcastDestruction sends the destruction stage of the block the player is breaking to the other players around,
+// from 0 to 9, or -1 to remove it.
+func (w *World) broadcastDestruction(p *Player, pos [3]int, stage int) {
+	center := vec3d{float64(pos[0]) + 0.5, float64(pos[1]) + 0.5, float64(pos[2]) + 0.5}
+	w.playerViews.Find(bvh.TouchPoint[vec3d, aabb3d](center), func(n *playerViewNode) bool {
+		if n.Value.Player != p {
+			n.Value.ViewBlockDestruction(p.EntityID, [3]int32{int32(pos[0]), int32(pos[1]), int32(pos[2])}, int8(stage))
+		}
+		return true
+	})
+}
+
+// destroyBlock breaks the block, leaving its water if it was waterlogged.
+// Unless the player is in creative mode, the items of the block are dropped if it's mined with a correct tool.
+func (w *World) destroyBlock(p *Player, pos [3]int) {
+	state, ok := w.getBlock(pos[0], pos[1], pos[2])
+	s := block.StateID(state)
+	if !ok || block.IsAirBlock(block.StateList[s]) {
+		return
+	}
+	left := block.FluidOf(s).State()
+	lc := w.chunks[[2]int32{int32(pos[0] >> 4), int32(pos[2] >> 4)}]
+	lc.SetBlock(pos[0], pos[1], pos[2], level.BlocksState(left))
+	if p.HasInfiniteMaterials() || w.config.LootTables == nil || !w.miner(p).HasCorrectTool(s) {
+		return
+	}
+	drops := w.config.LootTables.BlockDrops(loot.Context{Block: s, Tool: p.heldItem(), Rand: w.rand})
+	for _, stack := range drops {
+		w.spawnItem(Position{
+			float64(pos[0]) + 0.25 + w.rand.Float64()*0.5,
+			float64(pos[1]) + 0.125 + w.rand.Float64()*0.5,
+			float64(pos[2]) + 0.25 + w.rand.Float64()*0.5,
+		}, stack)
+	}
+}
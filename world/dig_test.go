@@ -0,0 +1,139 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/loot"
+	"github.com/mrhaoxx/go-mc/world/container"
+)
+
+// digClient counts the blocks sent again to the client.
+type digClient struct {
+	Client
+	updates int
+}
+
+func (c *digClient) ViewBlockUpdate([3]int32, level.BlocksState) { c.updates++ }
+
+func TestWorld_DigBlock(t *testing.T) {
+	c := hpcworld.Alloc()
+	defer c.Free()
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{0, 0}, heightMaps: c.HeightMaps()}
+	tables, err := loot.Load(loot.Builtin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := new(digClient)
+	p := &Player{Entity: Entity{Position: Position{8.5, 65, 8.5}, OnGround: true}}
+	w := &World{
+		chunks:   map[[2]int32]*LoadedChunk{{0, 0}: lc},
+		players:  map[Client]*Player{cl: p},
+		entities: make(map[int32]*Entity),
+		rand:     rand.New(rand.NewSource(1)),
+		config:   Config{LootTables: tables},
+	}
+	stone := level.BlocksState(block.ToStateID[block.Stone{}])
+	pos := [3]int{8, 64, 8}
+	isStone := func() bool { return lc.GetBlock(pos[0], pos[1], pos[2]) == stone }
+	drops := func() (items []*ItemEntity) {
+		for _, e := range w.entities {
+			if e.Type == ItemType {
+				items = append(items, e.Data.(*ItemEntity))
+			}
+		}
+		return
+	}
+	tick := func(n int) {
+		for range n {
+			w.tickCount++
+			w.subtickDigging()
+		}
+	}
+
+	// Stone takes 150 ticks to break by hand, and a player finishing too soon must wait.
+	lc.SetBlock(pos[0], pos[1], pos[2], stone)
+	w.DigBlock(cl, pos, DigStart)
+	tick(10)
+	w.DigBlock(cl, pos, DigFinish)
+	if !isStone() {
+		t.Fatal("the stone is broken by hand in 10 ticks")
+	}
+	tick(138)
+	if !isStone() {
+		t.Fatal("the stone is broken by hand in 148 ticks")
+	}
+	tick(2)
+	if isStone() {
+		t.Fatal("the stone isn't broken by hand in 150 ticks")
+	}
+	if len(drops()) != 0 {
+		t.Errorf("the stone broken by hand dropped %d items", len(drops()))
+	}
+
+	// With an iron pickaxe, it takes 8 ticks and drops cobblestone.
+	var pickaxe item.ID
+	if err := pickaxe.UnmarshalText([]byte("minecraft:iron_pickaxe")); err != nil {
+		t.Fatal(err)
+	}
+	p.Inventory[0] = item.New(pickaxe, 1)
+	lc.SetBlock(pos[0], pos[1], pos[2], stone)
+	w.DigBlock(cl, pos, DigStart)
+	tick(7)
+	w.DigBlock(cl, pos, DigFinish)
+	if isStone() {
+		t.Fatal("the stone isn't broken with an iron pickaxe in 8 ticks")
+	}
+	if d := drops(); len(d) != 1 || d[0].Stack.ItemID.Name() != "minecraft:cobblestone" {
+		t.Errorf("the stone broken with an iron pickaxe dropped %v", d)
+	}
+
+	// Out of reach, the block is sent again.
+	far := [3]int{8, 64, 15}
+	lc.SetBlock(far[0], far[1], far[2], stone)
+	w.DigBlock(cl, far, DigStart)
+	w.DigBlock(cl, far, DigFinish)
+	if lc.GetBlock(far[0], far[1], far[2]) != stone || cl.updates != 2 {
+		t.Errorf("the stone out of reach is broken, or sent %d times", cl.updates)
+	}
+
+	// In creative mode, blocks break at once without drops.
+	p.Gamemode = 1
+	lc.SetBlock(pos[0], pos[1], pos[2], stone)
+	w.DigBlock(cl, pos, DigStart)
+	if isStone() || len(drops()) != 1 {
+		t.Error("the stone isn't broken at once in creative mode, or dropped items")
+	}
+}
+
+// menuClient counts the windows closed on the client.
+type menuClient struct {
+	Client
+	closed int
+}
+
+func (c *menuClient) CloseMenu() { c.closed++ }
+
+func TestWorld_subtickUpdatePlayers_leaveMenu(t *testing.T) {
+	w, _, _ := physicsWorld(t)
+	c := new(menuClient)
+	p := damagedPlayer(Position{8.5, 65, 8.5})
+	p.Inputs.Position, p.Inputs.OnGround = p.pos0, true
+	p.ContainerMenu = &container.Menu{Block: &[3]int{8, 65, 14}}
+	w.players = map[Client]*Player{c: p}
+
+	// The crafting table 5 blocks away stays open, and is closed once the player is 9.5 blocks away.
+	w.subtickUpdatePlayers()
+	if c.closed != 0 {
+		t.Fatal("the window of the block 5 blocks away is closed")
+	}
+	p.Position = Position{8.5, 65, 4.5}
+	w.subtickUpdatePlayers()
+	if c.closed != 1 {
+		t.Error("the window of the block 9.5 blocks away isn't closed")
+	}
+}
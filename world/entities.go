@@ -0,0 +1,287 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+
+	dataentity "github.com/mrhaoxx/go-mc/data/entity"
+	"github.com/mrhaoxx/go-mc/world/internal/bvh"
+)
+
+// EntityType is a kind of entities, with their size and behaviour.
+type EntityType struct {
+	// Name is the name of the type in the entity type registry, like minecraft:pig.
+	Name string
+	// Width and Height are the size of the bounding box of the entities.
+	Width, Height float64
+	// BlocksBuilding reports whether the entities stop blocks from being placed where they stand.
+	BlocksBuilding bool
+	// Gravity is the vertical velocity the entities lose every tick, and Drag the part of their velocity.
+	// The entities of the types with neither, like the players, aren't moved by the world.
+	Gravity, Drag float64
+	// StepHeight is the height of the blocks the entities walk up.
+	StepHeight float64
+	// Extrapolated reports whether the clients simulate the motion of the entities between the updates,
+	// which are then sent only when the entities don't move as the clients expect.
+	Extrapolated bool
+
+	// Tick updates an entity every tick, before its changes are sent to its viewers.
+	// It may be nil. It runs with the world locked, so it must not call the methods of the World.
+	Tick func(w *World, e *Entity)
+	// Spawn sends a new entity to a viewer, which is ViewAddEntity if it's nil.
+	Spawn func(v EntityViewer, e *Entity)
+}
+
+// The types of the entities known by the world.
+var (
+	PlayerType = sized(dataentity.Player, EntityType{
+		BlocksBuilding: true,
+		Spawn:          func(v EntityViewer, e *Entity) { v.ViewAddPlayer(e.Data.(*Player)) },
+	})
+	ItemType = sized(dataentity.Item, EntityType{
+		Gravity: 0.04, Drag: 0.02, Extrapolated: true,
+		Tick: (*World).tickItem,
+	})
+	ArmorStandType = sized(dataentity.ArmorStand, EntityType{BlocksBuilding: true, Gravity: 0.08, Drag: 0.02})
+	PigType        = sized(dataentity.Pig, EntityType{BlocksBuilding: true, Gravity: 0.08, Drag: 0.02, StepHeight: 0.6})
+)
+
+// sized returns the type with the name and the size of the entity in the entity type registry.
+func sized(e dataentity.Entity, t EntityType) *EntityType {
+	t.Name, t.Width, t.Height = "minecraft:"+e.Name, e.Width, e.Height
+	return &t
+}
+
+type (
+	entityNode = bvh.Node[float64, aabb3d, *Entity]
+	entityTree = bvh.Tree[float64, aabb3d, *Entity]
+)
+
+// SpawnEntity adds a new entity of the type to the world, which is sent to the players around on the next tick.
+// The data is the state specific to the type, see Entity.Data.
+func (w *World) SpawnEntity(typ *EntityType, pos Position, rot Rotation, data any) *Entity {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	return w.spawnEntity(typ, pos, rot, data)
+}
+
+func (w *World) spawnEntity(typ *EntityType, pos Position, rot Rotation, data any) *Entity {
+	e := &Entity{EntityID: NewEntityID(), Position: pos, Rotation: rot, UUID: uuid.New(), Type: typ, Data: data}
+	w.addEntity(e)
+	return e
+}
+
+// RemoveEntity removes the entity from the world and from the view of the players.
+// The players are removed with RemovePlayer instead.
+func (w *World) RemoveEntity(e *Entity) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	if e.Type != PlayerType {
+		w.removeEntity(e)
+	}
+}
+
+// Entity returns the entity of the world with the ID.
+func (w *World) Entity(id int32) (*Entity, bool) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	e, ok := w.entities[id]
+	return e, ok
+}
+
+func (w *World) addEntity(e *Entity) {
+	e.pos0, e.rot0, e.vel0 = e.Position, e.Rotation, e.Velocity
+	e.viewers = make(map[*Player]EntityViewer)
+	e.node = w.entityIndex.Insert(e.boundingBox(), e)
+	w.entities[e.EntityID] = e
+}
+
+func (w *World) removeEntity(e *Entity) {
+	if _, ok := w.entities[e.EntityID]; !ok {
+		return
+	}
+	delete(w.entities, e.EntityID)
+	w.entityIndex.Delete(e.node)
+	for p, v := range e.viewers {
+		w.hideEntity(p, v, e)
+	}
+}
+
+// entitiesTouching calls f with the entities whose bounding box touches the box, until it returns false.
+func (w *World) entitiesTouching(box aabb3d, f func(e *Entity) bool) {
+	w.entityIndex.Find(bvh.TouchBound(box), func(n *entityNode) bool {
+		return f(n.Value)
+	})
+}
+
+// subtickUpdateEntities ticks the entities, and sends them and their moves to the players in range.
+func (w *World) subtickUpdateEntities() {
+	for id, e := range w.entities {
+		e.Age++
+		if e.hasPhysics() {
+			w.tickPhysics(e)
+		}
+		if e.Type.Tick != nil {
+			e.Type.Tick(w, e)
+			if _, ok := w.entities[id]; !ok {
+				continue
+			}
+		}
+		w.trackEntity(e)
+	}
+}
+
+// trackEntity sends the move of the entity to its viewers, and updates the set of the players seeing it,
+// which are the players whose view contains it, apart from itself.
+// The extrapolated entities are sent when their position or velocity diverges from the one the viewers simulate.
+func (w *World) trackEntity(e *Entity) {
+	from := e.Position
+	var moved, accelerated bool
+	if e.Type.Extrapolated {
+		e.extrapolate()
+		moved = diverged(e.Position, e.pos0, positionEps) || diverged(e.Velocity, e.vel0, velocityEps)
+		accelerated = moved
+	} else {
+		moved = e.Position != e.pos0
+		accelerated = diverged(e.Velocity, e.vel0, velocityEps)
+	}
+	if moved || e.Rotation != e.rot0 {
+		send := entityMove(e)
+		for _, v := range e.viewers {
+			send(v)
+		}
+		e.Position, e.Rotation = e.pos0, e.rot0
+	}
+	if accelerated {
+		for _, v := range e.viewers {
+			v.ViewSetEntityMotion(e.EntityID, e.vel0)
+		}
+		e.Velocity = e.vel0
+	}
+	self, _ := e.Data.(*Player)
+	if dirty := e.Metadata.PackDirty(); len(dirty) > 0 {
+		for _, v := range e.viewers {
+			v.ViewSetEntityData(e.EntityID, dirty)
+		}
+		// the players see their own fire and air
+		if self != nil && self.view != nil {
+			self.view.Value.EntityViewer.ViewSetEntityData(e.EntityID, dirty)
+		}
+	}
+	if e.Position != from {
+		e.node = w.entityIndex.Insert(e.boundingBox(), w.entityIndex.Delete(e.node))
+		if self != nil {
+			self.view = w.playerViews.Insert(self.getView(), w.playerViews.Delete(self.view))
+		}
+	}
+
+	for p, v := range e.viewers {
+		if !p.view.Box.WithIn(vec3d(e.Position)) {
+			w.hideEntity(p, v, e)
+		}
+	}
+	w.playerViews.Find(bvh.TouchPoint[vec3d, aabb3d](vec3d(e.Position)), func(n *playerViewNode) bool {
+		if _, ok := e.viewers[n.Value.Player]; !ok && n.Value.Player != self {
+			w.showEntity(n.Value.Player, n.Value.EntityViewer, e)
+		}
+		return true
+	})
+}
+
+// positionEps and velocityEps are the differences between the state of the entities and the one known by their viewers
+// below which it isn't sent.
+const (
+	positionEps = 1.0 / 64
+	velocityEps = 1e-3
+)
+
+func diverged(a, b [3]float64, eps float64) bool {
+	return math.Abs(a[0]-b[0]) > eps || math.Abs(a[1]-b[1]) > eps || math.Abs(a[2]-b[2]) > eps
+}
+
+// extrapolate advances the position and velocity of the entity known by its viewers by a tick,
+// as the clients do between the updates with its gravity and drag, apart from the collisions.
+// The entities resting on the ground stay there.
+func (e *Entity) extrapolate() {
+	if (!bool(e.OnGround) || e.Velocity[1] != 0) && !e.noGravity() {
+		e.Velocity[1] -= e.Type.Gravity
+	}
+	for i := range e.Position {
+		e.Position[i] += e.Velocity[i]
+		e.Velocity[i] *= 1 - e.Type.Drag
+	}
+}
+
+// entityMove returns the function sending the move of the entity in this tick to a viewer.
+// The entity is teleported if it moved 8 blocks or more, which the relative moves can't encode.
+func entityMove(e *Entity) func(v EntityViewer) {
+	rot := [2]int8{int8(e.rot0[0] * 256 / 360), int8(e.rot0[1] * 256 / 360)}
+	var delta [3]int16
+	for i := range delta {
+		d := (e.pos0[i] - e.Position[i]) * 32 * 128
+		if d < math.MinInt16 || d > math.MaxInt16 {
+			return func(v EntityViewer) {
+				v.ViewTeleportEntity(e.EntityID, e.pos0, rot, bool(e.OnGround))
+				v.ViewRotateHead(e.EntityID, rot[0])
+			}
+		}
+		delta[i] = int16(d)
+	}
+	switch {
+	case e.Position != e.pos0 && e.Rotation != e.rot0:
+		return func(v EntityViewer) {
+			v.ViewMoveEntityPosAndRot(e.EntityID, delta, rot, bool(e.OnGround))
+			v.ViewRotateHead(e.EntityID, rot[0])
+		}
+	case e.Position != e.pos0:
+		return func(v EntityViewer) {
+			v.ViewMoveEntityPos(e.EntityID, delta, bool(e.OnGround))
+		}
+	default:
+		return func(v EntityViewer) {
+			v.ViewMoveEntityRot(e.EntityID, rot, bool(e.OnGround))
+			v.ViewRotateHead(e.EntityID, rot[0])
+		}
+	}
+}
+
+// showEntity sends the entity to the player.
+func (w *World) showEntity(p *Player, v EntityViewer, e *Entity) {
+	if e.Type.Spawn != nil {
+		e.Type.Spawn(v, e)
+	} else {
+		v.ViewAddEntity(e, e.Type.Name)
+	}
+	if len(e.Metadata) > 0 {
+		v.ViewSetEntityData(e.EntityID, e.Metadata)
+	}
+	if e.Velocity != [3]float64{} {
+		v.ViewSetEntityMotion(e.EntityID, e.Velocity)
+	}
+	e.viewers[p] = v
+	p.EntitiesInView[e.EntityID] = e
+}
+
+// hideEntity removes the entity from the view of the player.
+func (w *World) hideEntity(p *Player, v EntityViewer, e *Entity) {
+	v.ViewRemoveEntities([]int32{e.EntityID})
+	delete(e.viewers, p)
+	delete(p.EntitiesInView, e.EntityID)
+}
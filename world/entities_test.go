@@ -0,0 +1,166 @@
+package world
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/world/entity"
+)
+
+// entityRecorder records the entities sent to a player.
+type entityRecorder struct {
+	EntityViewer
+	seen     []int32
+	moves    int
+	motions  int
+	metadata []entity.MetadataSet
+}
+
+func (r *entityRecorder) ViewSetEntityMotion(int32, [3]float64) { r.motions++ }
+
+func (r *entityRecorder) ViewSetEntityData(_ int32, m entity.MetadataSet) {
+	r.metadata = append(r.metadata, m)
+}
+
+func (r *entityRecorder) ViewAddPlayer(p *Player)                 { r.seen = append(r.seen, p.EntityID) }
+func (r *entityRecorder) ViewAddEntity(e *Entity, _ string)       { r.seen = append(r.seen, e.EntityID) }
+func (r *entityRecorder) ViewMoveEntityPos(int32, [3]int16, bool) { r.moves++ }
+func (r *entityRecorder) ViewTeleportEntity(int32, [3]float64, [2]int8, bool) {
+	r.moves++
+}
+func (r *entityRecorder) ViewRotateHead(int32, int8) {}
+func (r *entityRecorder) ViewRemoveEntities(ids []int32) {
+	r.seen = slices.DeleteFunc(r.seen, func(id int32) bool { return slices.Contains(ids, id) })
+}
+
+func TestWorld_trackEntity(t *testing.T) {
+	w := &World{entities: make(map[int32]*Entity)}
+	join := func(pos Position) (*Player, *entityRecorder) {
+		r := new(entityRecorder)
+		p := &Player{Entity: Entity{EntityID: NewEntityID(), Position: pos}, ViewDistance: 2, EntitiesInView: make(map[int32]*Entity)}
+		p.view = w.playerViews.Insert(p.getView(), playerView{r, p})
+		p.Type, p.Data = PlayerType, p
+		w.addEntity(&p.Entity)
+		return p, r
+	}
+	a, ra := join(Position{0, 64, 0})
+	b, rb := join(Position{20, 64, 0})
+	pig := w.spawnEntity(PigType, Position{10, 64, 0}, Rotation{}, nil)
+	w.subtickUpdateEntities()
+	if !slices.Contains(ra.seen, b.EntityID) || !slices.Contains(ra.seen, pig.EntityID) || slices.Contains(ra.seen, a.EntityID) {
+		t.Errorf("the first player sees %v", ra.seen)
+	}
+	if !slices.Contains(rb.seen, a.EntityID) || !slices.Contains(rb.seen, pig.EntityID) {
+		t.Errorf("the second player sees %v", rb.seen)
+	}
+
+	// The moves are sent to the viewers, and the entities out of view are removed.
+	pig.MoveTo(Position{-20, 64, 0}, Rotation{})
+	w.subtickUpdateEntities()
+	if ra.moves != 1 || rb.moves != 1 {
+		t.Errorf("the move of the pig is sent %d and %d times", ra.moves, rb.moves)
+	}
+	if !slices.Contains(ra.seen, pig.EntityID) || slices.Contains(rb.seen, pig.EntityID) {
+		t.Errorf("the pig out of view of the second player is seen by %v and %v", ra.seen, rb.seen)
+	}
+	if _, ok := b.EntitiesInView[pig.EntityID]; ok {
+		t.Error("the pig out of view is still in the entities in view")
+	}
+
+	// Players moving update their view.
+	b.MoveTo(Position{-10, 64, 0}, Rotation{})
+	w.subtickUpdateEntities()
+	w.subtickUpdateEntities()
+	if !slices.Contains(rb.seen, pig.EntityID) {
+		t.Errorf("the pig isn't seen by the player moving next to it, which sees %v", rb.seen)
+	}
+
+	w.removeEntity(pig)
+	if slices.Contains(ra.seen, pig.EntityID) || slices.Contains(rb.seen, pig.EntityID) || len(w.entities) != 2 {
+		t.Error("the pig removed is still seen")
+	}
+}
+
+func TestWorld_obstructed_entities(t *testing.T) {
+	stone := block.ToStateID[block.Stone{}]
+	w := &World{entities: make(map[int32]*Entity)}
+	w.spawnItem(Position{10.5, 64, 10.5}, item.New(1, 1))
+	if w.obstructed([3]int{10, 64, 10}, stone) {
+		t.Error("an item obstructs placing blocks")
+	}
+	w.spawnEntity(PigType, Position{10.5, 64, 10.5}, Rotation{}, nil)
+	if !w.obstructed([3]int{10, 64, 10}, stone) {
+		t.Error("a pig doesn't obstruct placing blocks")
+	}
+}
+
+func TestWorld_tickItem(t *testing.T) {
+	w := &World{entities: make(map[int32]*Entity)}
+	p := &Player{Entity: Entity{Position: Position{0.5, 64, 0.5}, Type: PlayerType}}
+	p.Data = p
+	w.addEntity(&p.Entity)
+	e := w.spawnItem(Position{1.5, 64.2, 0.5}, item.New(1, 5))
+	for range itemPickupDelay {
+		w.subtickUpdateEntities()
+	}
+	if _, ok := w.entities[e.EntityID]; !ok || !p.Inventory[0].IsEmpty() {
+		t.Fatal("the item is picked up before the pickup delay")
+	}
+	w.subtickUpdateEntities()
+	if _, ok := w.entities[e.EntityID]; ok || p.Inventory[0].Count != 5 {
+		t.Errorf("the item isn't picked up, the player has %v", p.Inventory[0])
+	}
+}
+
+func TestWorld_throwDrops(t *testing.T) {
+	w := &World{entities: make(map[int32]*Entity), rand: rand.New(rand.NewSource(1))}
+	p := &Player{Entity: Entity{Position: Position{0.5, 64, 0.5}, Type: PlayerType}}
+	p.Data, p.pos0 = p, p.Position
+	p.Drop(item.New(1, 2))
+	p.Drop(item.ItemStack{})
+	w.throwDrops(p)
+	if len(w.entities) != 1 || len(p.drops) != 0 {
+		t.Fatalf("%d items are thrown, %d left", len(w.entities), len(p.drops))
+	}
+	for _, e := range w.entities {
+		// Looking south, the item is thrown from the eyes toward +z.
+		if it := e.Data.(*ItemEntity); it.Stack.Count != 2 || it.PickupDelay != throwPickupDelay ||
+			e.pos0[1] != 64+PlayerEyeHeight-0.3 || e.vel0[2] < 0.25 {
+			t.Errorf("the item thrown is %v at %v, moving %v", it, e.pos0, e.vel0)
+		}
+	}
+}
+
+func TestWorld_metadata(t *testing.T) {
+	w := &World{entities: make(map[int32]*Entity)}
+	r := new(entityRecorder)
+	p := &Player{Entity: Entity{EntityID: NewEntityID(), Type: PlayerType}, ViewDistance: 2, EntitiesInView: make(map[int32]*Entity)}
+	p.Data = p
+	p.view = w.playerViews.Insert(p.getView(), playerView{r, p})
+	w.addEntity(&p.Entity)
+
+	drop := w.spawnItem(Position{1, 0, 1}, item.New(1, 3))
+	w.subtickUpdateEntities()
+	if len(r.metadata) != 1 || len(r.metadata[0]) != 1 || r.metadata[0][0].Index != entity.DataItem {
+		t.Fatalf("the metadata of the item sent are %v", r.metadata)
+	}
+	w.removeEntity(drop)
+
+	// The fields set are sent once to the viewers.
+	pig := w.spawnEntity(PigType, Position{2, 0, 2}, Rotation{}, nil)
+	w.subtickUpdateEntities()
+	if !pig.SetMetadata(entity.DataPigSaddle, &entity.Boolean{Boolean: true}) {
+		t.Fatal("the saddle of the pig can't be set")
+	}
+	if pig.SetMetadata(entity.DataArmorStandFlags+5, &entity.Byte{}) {
+		t.Error("a field the pig doesn't have is set")
+	}
+	w.subtickUpdateEntities()
+	w.subtickUpdateEntities()
+	if len(r.metadata) != 2 || len(r.metadata[1]) != 1 || r.metadata[1][0].Index != entity.DataPigSaddle {
+		t.Errorf("the metadata sent are %v", r.metadata)
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"sync/atomic"
 
 	"github.com/google/uuid"
+
+	"github.com/mrhaoxx/go-mc/world/entity"
 )
 
 var entityCounter atomic.Int32
@@ -37,6 +39,23 @@ type Entity struct {
 	pos0 Position
 	rot0 Rotation
 	UUID uuid.UUID
+	// Type is the type of the entity, set when it's added to a world.
+	Type *EntityType
+	// Velocity is the motion of the entity known by its viewers, in blocks per tick.
+	Velocity [3]float64
+	vel0     [3]float64
+	// Data is the state specific to the type of the entity,
+	// which is the *Player of the players and the *ItemEntity of the items.
+	Data any
+	// Age is the number of ticks since the entity was added to its world.
+	Age uint
+	// Metadata are the fields specific to the class of the entity, sent to its viewers when they change.
+	Metadata entity.MetadataSet
+
+	// viewers are the players the entity is sent to, the visibility set of the entity.
+	viewers map[*Player]EntityViewer
+	// node is the bounding box of the entity in the spatial index of its world.
+	node *entityNode
 }
 
 type (
@@ -49,6 +68,31 @@ func (e *Entity) getPoint() [2]float64 {
 	return [2]float64{e.Position[0], e.Position[2]}
 }
 
+// MoveTo moves the entity at the end of the tick, when the move is sent to its viewers.
+func (e *Entity) MoveTo(pos Position, rot Rotation) {
+	e.pos0, e.rot0 = pos, rot
+}
+
+// SetVelocity changes the motion of the entity at the end of the tick, when it's sent to its viewers.
+func (e *Entity) SetVelocity(v [3]float64) {
+	e.vel0 = v
+}
+
+// SetMetadata sets the metadata field at the index, which is sent to the viewers at the end of the tick.
+// It reports false if the class of the type of the entity has no such field, whose indexes are in package entity.
+func (e *Entity) SetMetadata(index byte, v entity.MetadataValue) bool {
+	if !entity.ClassOf(e.Type.Name).Accepts(index, v) {
+		return false
+	}
+	e.Metadata.Set(index, v)
+	return true
+}
+
+// boundingBox returns the box of the entity at its position.
+func (e *Entity) boundingBox() aabb3d {
+	return boundingBox(e.Position, e.Type.Width, e.Type.Height)
+}
+
 func (p *Position) IsValid() bool {
 	return !math.IsNaN((*p)[0]) && !math.IsNaN((*p)[1]) && !math.IsNaN((*p)[2]) &&
 		!math.IsInf((*p)[0], 0) && !math.IsInf((*p)[1], 0) && !math.IsInf((*p)[2], 0)
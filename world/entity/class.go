@@ -0,0 +1,196 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entity
+
+import (
+	"slices"
+	"strings"
+
+	dataentity "github.com/mrhaoxx/go-mc/data/entity"
+)
+
+// Class is a class of the vanilla entity hierarchy, which adds metadata fields after the ones of its parent.
+type Class struct {
+	Name   string
+	Parent *Class
+	// Fields are the types of the fields added by the class.
+	Fields []int32
+}
+
+// Some classes of the vanilla entity hierarchy, whose fields are indexed below.
+var (
+	EntityClass       = classes["Entity"]
+	ItemEntityClass   = classes["ItemEntity"]
+	LivingEntityClass = classes["LivingEntity"]
+	PlayerClass       = classes["Player"]
+	ArmorStandClass   = classes["ArmorStand"]
+	MobClass          = classes["Mob"]
+	AgeableMobClass   = classes["AgeableMob"]
+	PigClass          = classes["Pig"]
+)
+
+// The indexes of the metadata fields, after the ones of the parent class.
+const (
+	// Entity
+	DataSharedFlags byte = iota
+	DataAirSupply
+	DataCustomName
+	DataCustomNameVisible
+	DataSilent
+	DataNoGravity
+	DataPose
+	DataTicksFrozen
+)
+
+const (
+	// ItemEntity
+	DataItem byte = 8 + iota
+)
+
+const (
+	// LivingEntity
+	DataLivingEntityFlags byte = 8 + iota
+	DataHealth
+	DataEffectParticles
+	DataEffectAmbience
+	DataArrowCount
+	DataStingerCount
+	DataSleepingPos
+)
+
+const (
+	// Player
+	DataPlayerAbsorption byte = 15 + iota
+	DataScore
+	DataPlayerModeCustomisation
+	DataPlayerMainHand
+	DataShoulderLeft
+	DataShoulderRight
+)
+
+const (
+	// ArmorStand
+	DataArmorStandFlags byte = 15 + iota
+	DataHeadPose
+	DataBodyPose
+	DataLeftArmPose
+	DataRightArmPose
+	DataLeftLegPose
+	DataRightLegPose
+)
+
+const (
+	// Mob
+	DataMobFlags byte = 15 + iota
+	// AgeableMob
+	DataBaby
+	// Pig
+	DataPigSaddle
+	DataPigBoostTime
+)
+
+// The bits of the DataSharedFlags field.
+const (
+	FlagOnFire     = 0x01
+	FlagCrouching  = 0x02
+	FlagSprinting  = 0x08
+	FlagSwimming   = 0x10
+	FlagInvisible  = 0x20
+	FlagGlowing    = 0x40
+	FlagFallFlying = 0x80
+)
+
+// serializers are the names of the serializers of the metadata types in data/entity, indexed by the type id.
+var serializers = [...]string{
+	ByteType:               "byte",
+	VarIntType:             "int",
+	VarLongType:            "long",
+	FloatType:              "float",
+	StringType:             "string",
+	ChatType:               "component",
+	OptionalChatType:       "optional_component",
+	SlotType:               "item_stack",
+	BooleanType:            "boolean",
+	RotationsType:          "rotations",
+	BlockPosType:           "block_pos",
+	OptionalBlockPosType:   "optional_block_pos",
+	DirectionType:          "direction",
+	OptionalUUIDType:       "optional_uuid",
+	BlockStateType:         "block_state",
+	OptionalBlockStateType: "optional_block_state",
+	NBTType:                "compound_tag",
+	ParticleType:           "particle",
+	ParticlesType:          "particles",
+	VillagerDataType:       "villager_data",
+	OptionalVarIntType:     "optional_unsigned_int",
+	PoseType:               "pose",
+	CatVariantType:         "cat_variant",
+	WolfVariantType:        "wolf_variant",
+	FrogVariantType:        "frog_variant",
+	OptionalGlobalPosType:  "optional_global_pos",
+	PaintingVariantType:    "painting_variant",
+	SnifferStateType:       "sniffer_state",
+	ArmadilloStateType:     "armadillo_state",
+	Vector3Type:            "vector3",
+	QuaternionType:         "quaternion",
+}
+
+// classes are the classes of the entity hierarchy generated in data/entity, keyed by name.
+var classes = loadClasses()
+
+func loadClasses() map[string]*Class {
+	classes := make(map[string]*Class, len(dataentity.Classes))
+	for _, dc := range dataentity.Classes {
+		c := &Class{Name: dc.Name, Parent: classes[dc.Parent], Fields: make([]int32, len(dc.Fields))}
+		for i, f := range dc.Fields {
+			c.Fields[i] = int32(slices.Index(serializers[:], f.Serializer))
+		}
+		classes[dc.Name] = c
+	}
+	return classes
+}
+
+// ClassOf returns the class of the type of entities, like minecraft:pig.
+// The unknown types have the metadata fields of all entities.
+func ClassOf(typeName string) *Class {
+	if c, ok := classes[dataentity.ClassOf[strings.TrimPrefix(typeName, "minecraft:")]]; ok {
+		return c
+	}
+	return EntityClass
+}
+
+// Types returns the types of all the metadata fields of the class, indexed by their index.
+func (c *Class) Types() []int32 {
+	if c.Parent == nil {
+		return slices.Clone(c.Fields)
+	}
+	return append(c.Parent.Types(), c.Fields...)
+}
+
+// Index returns the index of the first field added by the class.
+func (c *Class) Index() byte {
+	if c.Parent == nil {
+		return 0
+	}
+	return c.Parent.Index() + byte(len(c.Parent.Fields))
+}
+
+// Accepts reports whether the class has a field at the index of the type of the value.
+func (c *Class) Accepts(index byte, v MetadataValue) bool {
+	types := c.Types()
+	return int(index) < len(types) && types[index] == v.TypeID()
+}
@@ -18,15 +18,52 @@ package entity
 
 import (
 	"io"
+	"slices"
 
+	"github.com/mrhaoxx/go-mc/chat"
+	"github.com/mrhaoxx/go-mc/level/item"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
 )
 
+// MetadataSet is the metadata of an entity, sorted by index.
+// It remembers the fields set since they were last packed, which are the ones sent to the viewers of the entity.
 type MetadataSet []MetadataField
 
 type MetadataField struct {
 	Index byte
 	MetadataValue
+
+	dirty bool
+}
+
+// Set sets the field at the index, and marks it to be sent.
+func (m *MetadataSet) Set(index byte, v MetadataValue) {
+	i, ok := slices.BinarySearchFunc(*m, index, func(f MetadataField, index byte) int { return int(f.Index) - int(index) })
+	if ok {
+		(*m)[i].MetadataValue, (*m)[i].dirty = v, true
+		return
+	}
+	*m = slices.Insert(*m, i, MetadataField{Index: index, MetadataValue: v, dirty: true})
+}
+
+// Get returns the field at the index.
+func (m MetadataSet) Get(index byte) (MetadataValue, bool) {
+	i, ok := slices.BinarySearchFunc(m, index, func(f MetadataField, index byte) int { return int(f.Index) - int(index) })
+	if !ok {
+		return nil, false
+	}
+	return m[i].MetadataValue, true
+}
+
+// PackDirty returns the fields set since it was last called, and marks them sent.
+func (m MetadataSet) PackDirty() (dirty MetadataSet) {
+	for i := range m {
+		if m[i].dirty {
+			m[i].dirty = false
+			dirty = append(dirty, MetadataField{Index: m[i].Index, MetadataValue: m[i].MetadataValue})
+		}
+	}
+	return
 }
 
 func (m MetadataSet) WriteTo(w io.Writer) (n int64, err error) {
@@ -38,6 +75,7 @@ func (m MetadataSet) WriteTo(w io.Writer) (n int64, err error) {
 			return
 		}
 		tmpN, err = v.WriteTo(w)
+		n += tmpN
 		if err != nil {
 			return
 		}
@@ -60,26 +98,232 @@ type MetadataValue interface {
 	pk.Field
 }
 
+// The IDs of the types of the metadata values, the entity data serializers of vanilla.
+const (
+	ByteType int32 = iota
+	VarIntType
+	VarLongType
+	FloatType
+	StringType
+	ChatType
+	OptionalChatType
+	SlotType
+	BooleanType
+	RotationsType
+	BlockPosType
+	OptionalBlockPosType
+	DirectionType
+	OptionalUUIDType
+	BlockStateType
+	OptionalBlockStateType
+	NBTType
+	ParticleType
+	ParticlesType
+	VillagerDataType
+	OptionalVarIntType
+	PoseType
+	CatVariantType
+	WolfVariantType
+	FrogVariantType
+	OptionalGlobalPosType
+	PaintingVariantType
+	SnifferStateType
+	ArmadilloStateType
+	Vector3Type
+	QuaternionType
+)
+
 type (
-	Byte struct{ pk.Byte }
-	// VarInt       struct{ pk.VarInt }
-	// Float        struct{ pk.Float }
-	// String       struct{ pk.String }
-	// Chat         struct{ chat.Message }
-	// OptionalChat struct {
-	// 	Exist   bool
-	// 	Message chat.Message
-	// }
-	// Slot     struct{}
-	// Boolean  struct{ pk.Boolean }
-	// Rotation [3]pk.Float
-	// Position struct{ pk.Position }
+	Byte         struct{ pk.Byte }
+	VarInt       struct{ pk.VarInt }
+	VarLong      struct{ pk.VarLong }
+	Float        struct{ pk.Float }
+	String       struct{ pk.String }
+	Chat         struct{ chat.Message }
+	OptionalChat struct {
+		pk.Option[chat.Message, *chat.Message]
+	}
+	Slot     struct{ item.ItemStack }
+	Boolean  struct{ pk.Boolean }
+	BlockPos struct{ pk.Position }
+	// OptionalBlockPos is the position of the bed of sleeping entities.
+	OptionalBlockPos struct {
+		pk.Option[pk.Position, *pk.Position]
+	}
+	// Direction is the direction of a shulker's attachment, from 0 to 5 like block.Direction.
+	Direction    struct{ pk.VarInt }
+	OptionalUUID struct {
+		pk.Option[pk.UUID, *pk.UUID]
+	}
+	// BlockState is the ID of a block state.
+	BlockState struct{ pk.VarInt }
+	// OptionalBlockState is the ID of a block state, or 0 for none.
+	OptionalBlockState struct{ pk.VarInt }
+	// NBT is a value encoded as an NBT tag.
+	NBT struct{ V any }
+	// OptionalVarInt is an optional non-negative number.
+	OptionalVarInt struct {
+		Has bool
+		Val int32
+	}
+	// CatVariant, WolfVariant, FrogVariant and PaintingVariant are the IDs of the variants in their registries.
+	// The wolf and painting variants are written as holders, their ID plus 1.
+	CatVariant      struct{ pk.VarInt }
+	WolfVariant     struct{ pk.VarInt }
+	FrogVariant     struct{ pk.VarInt }
+	PaintingVariant struct{ pk.VarInt }
+	SnifferState    struct{ pk.VarInt }
+	ArmadilloState  struct{ pk.VarInt }
 
 	Pose int32
 )
 
-func (b *Byte) TypeID() int32 { return 0 }
-func (p *Pose) TypeID() int32 { return 18 }
+func (*Byte) TypeID() int32               { return ByteType }
+func (*VarInt) TypeID() int32             { return VarIntType }
+func (*VarLong) TypeID() int32            { return VarLongType }
+func (*Float) TypeID() int32              { return FloatType }
+func (*String) TypeID() int32             { return StringType }
+func (*Chat) TypeID() int32               { return ChatType }
+func (*OptionalChat) TypeID() int32       { return OptionalChatType }
+func (*Slot) TypeID() int32               { return SlotType }
+func (*Boolean) TypeID() int32            { return BooleanType }
+func (*Rotations) TypeID() int32          { return RotationsType }
+func (*BlockPos) TypeID() int32           { return BlockPosType }
+func (*OptionalBlockPos) TypeID() int32   { return OptionalBlockPosType }
+func (*Direction) TypeID() int32          { return DirectionType }
+func (*OptionalUUID) TypeID() int32       { return OptionalUUIDType }
+func (*BlockState) TypeID() int32         { return BlockStateType }
+func (*OptionalBlockState) TypeID() int32 { return OptionalBlockStateType }
+func (*NBT) TypeID() int32                { return NBTType }
+func (*Particle) TypeID() int32           { return ParticleType }
+func (*Particles) TypeID() int32          { return ParticlesType }
+func (*VillagerData) TypeID() int32       { return VillagerDataType }
+func (*OptionalVarInt) TypeID() int32     { return OptionalVarIntType }
+func (*Pose) TypeID() int32               { return PoseType }
+func (*CatVariant) TypeID() int32         { return CatVariantType }
+func (*WolfVariant) TypeID() int32        { return WolfVariantType }
+func (*FrogVariant) TypeID() int32        { return FrogVariantType }
+func (*OptionalGlobalPos) TypeID() int32  { return OptionalGlobalPosType }
+func (*PaintingVariant) TypeID() int32    { return PaintingVariantType }
+func (*SnifferState) TypeID() int32       { return SnifferStateType }
+func (*ArmadilloState) TypeID() int32     { return ArmadilloStateType }
+func (*Vector3) TypeID() int32            { return Vector3Type }
+func (*Quaternion) TypeID() int32         { return QuaternionType }
+
+func (n NBT) WriteTo(w io.Writer) (int64, error) { return pk.NBT(n.V).WriteTo(w) }
+func (n *NBT) ReadFrom(r io.Reader) (int64, error) {
+	return pk.NBTField{V: &n.V, AllowUnknownFields: true}.ReadFrom(r)
+}
+func (o OptionalVarInt) WriteTo(w io.Writer) (int64, error) {
+	if !o.Has {
+		return pk.VarInt(0).WriteTo(w)
+	}
+	return pk.VarInt(o.Val + 1).WriteTo(w)
+}
+
+func (o *OptionalVarInt) ReadFrom(r io.Reader) (int64, error) {
+	var v pk.VarInt
+	n, err := v.ReadFrom(r)
+	o.Has, o.Val = v != 0, int32(v)-1
+	return n, err
+}
+
+// Rotations are the rotations of the parts of an armor stand, in degrees.
+type Rotations struct{ X, Y, Z float32 }
+
+func (v Rotations) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.Float(v.X), pk.Float(v.Y), pk.Float(v.Z)}.WriteTo(w)
+}
+
+func (v *Rotations) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{(*pk.Float)(&v.X), (*pk.Float)(&v.Y), (*pk.Float)(&v.Z)}.ReadFrom(r)
+}
+
+// Vector3 is the translation or the scale of a display entity.
+type Vector3 struct{ X, Y, Z float32 }
+
+func (v Vector3) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.Float(v.X), pk.Float(v.Y), pk.Float(v.Z)}.WriteTo(w)
+}
+
+func (v *Vector3) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{(*pk.Float)(&v.X), (*pk.Float)(&v.Y), (*pk.Float)(&v.Z)}.ReadFrom(r)
+}
+
+// Quaternion is a rotation of a display entity.
+type Quaternion struct{ X, Y, Z, W float32 }
+
+func (q Quaternion) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.Float(q.X), pk.Float(q.Y), pk.Float(q.Z), pk.Float(q.W)}.WriteTo(w)
+}
+
+func (q *Quaternion) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{(*pk.Float)(&q.X), (*pk.Float)(&q.Y), (*pk.Float)(&q.Z), (*pk.Float)(&q.W)}.ReadFrom(r)
+}
+
+// VillagerData is the type, the profession and the level of a villager.
+type VillagerData struct {
+	Type, Profession, Level int32
+}
+
+func (v VillagerData) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{pk.VarInt(v.Type), pk.VarInt(v.Profession), pk.VarInt(v.Level)}.WriteTo(w)
+}
+
+func (v *VillagerData) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{(*pk.VarInt)(&v.Type), (*pk.VarInt)(&v.Profession), (*pk.VarInt)(&v.Level)}.ReadFrom(r)
+}
+
+// GlobalPos is a position in a dimension.
+type GlobalPos struct {
+	Dimension pk.Identifier
+	Pos       pk.Position
+}
+
+func (p GlobalPos) WriteTo(w io.Writer) (int64, error) {
+	return pk.Tuple{p.Dimension, p.Pos}.WriteTo(w)
+}
+
+func (p *GlobalPos) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Tuple{&p.Dimension, &p.Pos}.ReadFrom(r)
+}
+
+// OptionalGlobalPos is the position of the lodestone a compass points to, or of the last death of a player.
+type OptionalGlobalPos struct {
+	pk.Option[GlobalPos, *GlobalPos]
+}
+
+// Particle is a particle, whose options depend on its type.
+// The options are written as they are, and aren't read, since their size depends on the type.
+type Particle struct {
+	Type    int32
+	Options []byte
+}
+
+func (p Particle) WriteTo(w io.Writer) (int64, error) {
+	n, err := pk.VarInt(p.Type).WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	n2, err := w.Write(p.Options)
+	return n + int64(n2), err
+}
+
+func (p *Particle) ReadFrom(r io.Reader) (int64, error) {
+	p.Options = nil
+	return (*pk.VarInt)(&p.Type).ReadFrom(r)
+}
+
+// Particles are the particles of the effects on a living entity.
+type Particles []Particle
+
+func (p Particles) WriteTo(w io.Writer) (int64, error) {
+	return pk.Array(p).WriteTo(w)
+}
+
+func (p *Particles) ReadFrom(r io.Reader) (int64, error) {
+	return pk.Array((*[]Particle)(p)).ReadFrom(r)
+}
 
 const (
 	Standing Pose = iota
@@ -92,10 +336,14 @@ const (
 	Dying
 	Croaking
 	UsingTongue
+	Sitting
 	Roaring
 	Sniffing
 	Emerging
 	Digging
+	Sliding
+	Shooting
+	Inhaling
 )
 
 func (p Pose) WriteTo(w io.Writer) (n int64, err error)   { return pk.VarInt(p).WriteTo(w) }
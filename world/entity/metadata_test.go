@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"bytes"
+	"testing"
+
+	pk "github.com/mrhaoxx/go-mc/net/packet"
+)
+
+func TestClass_Index(t *testing.T) {
+	for _, c := range []struct {
+		class *Class
+		first byte
+	}{
+		{ItemEntityClass, DataItem},
+		{LivingEntityClass, DataLivingEntityFlags},
+		{PlayerClass, DataPlayerAbsorption},
+		{ArmorStandClass, DataArmorStandFlags},
+		{MobClass, DataMobFlags},
+		{AgeableMobClass, DataBaby},
+		{PigClass, DataPigSaddle},
+	} {
+		if got := c.class.Index(); got != c.first {
+			t.Errorf("the first field of %s is %d, want %d", c.class.Name, got, c.first)
+		}
+	}
+	if len(ArmorStandClass.Types()) != int(DataRightLegPose)+1 {
+		t.Errorf("the armor stand has %d fields", len(ArmorStandClass.Types()))
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	for name, want := range map[string]*Class{
+		"minecraft:pig":    PigClass,
+		"cow":              AgeableMobClass,
+		"minecraft:zombie": classes["Zombie"],
+		"husk":             classes["Zombie"],
+		"minecraft:arrow":  classes["Arrow"],
+		"minecraft:marker": EntityClass,
+		"minecraft:player": PlayerClass,
+		"unknown:thing":    EntityClass,
+	} {
+		if got := ClassOf(name); got != want {
+			t.Errorf("the class of %s is %s, want %s", name, got.Name, want.Name)
+		}
+	}
+	if !PigClass.Accepts(DataPigSaddle, &Boolean{true}) || PigClass.Accepts(DataPigSaddle, &Byte{1}) || PigClass.Accepts(30, &Byte{}) {
+		t.Error("the pig accepts the wrong fields")
+	}
+}
+
+func TestClasses(t *testing.T) {
+	for _, c := range classes {
+		for i, typ := range c.Fields {
+			if typ < 0 {
+				t.Errorf("the field %d of %s has an unknown serializer", c.Index()+byte(i), c.Name)
+			}
+		}
+	}
+	// The type-specific fields, at their index in the protocol.
+	for _, f := range []struct {
+		entity string
+		index  byte
+		typ    int32
+	}{
+		{"zombie", 16, BooleanType},
+		{"zombie_villager", 20, VillagerDataType},
+		{"creeper", 16, VarIntType},
+		{"slime", 16, VarIntType},
+		{"magma_cube", 16, VarIntType},
+		{"wolf", 22, WolfVariantType},
+		{"cat", 19, CatVariantType},
+		{"llama", 21, VarIntType},
+		{"text_display", 23, ChatType},
+		{"item_display", 24, ByteType},
+		{"oak_boat", 13, VarIntType},
+		{"command_block_minecart", 14, ChatType},
+		{"trident", 12, BooleanType},
+	} {
+		types := ClassOf(f.entity).Types()
+		if int(f.index) >= len(types) || types[f.index] != f.typ {
+			t.Errorf("the field %d of %s isn't of type %d: %v", f.index, f.entity, f.typ, types)
+		}
+	}
+}
+
+func TestMetadataSet(t *testing.T) {
+	var m MetadataSet
+	m.Set(DataPose, ptr(Crouching))
+	m.Set(DataSharedFlags, &Byte{FlagCrouching})
+	if dirty := m.PackDirty(); len(dirty) != 2 || dirty[0].Index != DataSharedFlags {
+		t.Fatalf("the fields set are packed as %v", dirty)
+	}
+	if dirty := m.PackDirty(); len(dirty) != 0 {
+		t.Errorf("the fields packed are packed again as %v", dirty)
+	}
+	m.Set(DataSharedFlags, &Byte{FlagGlowing})
+	if dirty := m.PackDirty(); len(dirty) != 1 || dirty[0].MetadataValue.(*Byte).Byte != FlagGlowing {
+		t.Errorf("the field changed is packed as %v", dirty)
+	}
+	if v, ok := m.Get(DataPose); !ok || *v.(*Pose) != Crouching {
+		t.Errorf("the pose is %v", v)
+	}
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		DataSharedFlags, byte(ByteType), FlagGlowing,
+		DataPose, byte(PoseType), byte(Crouching),
+		0xFF,
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("the metadata is written as % x, want % x", buf.Bytes(), want)
+	}
+}
+
+func TestOptionalVarInt(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := (OptionalVarInt{Has: true, Val: 0}).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var v OptionalVarInt
+	if _, err := v.ReadFrom(&buf); err != nil || !v.Has || v.Val != 0 {
+		t.Errorf("the optional 0 is read as %v, %v", v, err)
+	}
+	buf.Reset()
+	_, _ = (OptionalVarInt{}).WriteTo(&buf)
+	var n pk.VarInt
+	_, _ = n.ReadFrom(&buf)
+	if n != 0 {
+		t.Errorf("the empty optional is written as %d", n)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,36 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+)
+
+// Generator creates the chunks which the ChunkProvider doesn't have.
+// The same position and seed must always produce the same chunk.
+type Generator interface {
+	Generate(pos level.ChunkPos, seed int64) *hpcworld.Chunk
+}
+
+// LevelGenerator is a Generator producing level.Chunk,
+// the result is converted to the C-allocated hpcworld.Chunk.
+type LevelGenerator func(pos level.ChunkPos, seed int64) *level.Chunk
+
+func (f LevelGenerator) Generate(pos level.ChunkPos, seed int64) *hpcworld.Chunk {
+	return hpcworld.ChunkFromLevel(f(pos, seed))
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// DefaultFlatLayers is the classic superflat preset.
+const DefaultFlatLayers = "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains"
+
+// Flat generates superflat chunks.
+// Layers are listed from the bottom of the world.
+type Flat struct {
+	Layers []level.BlocksState
+	Biome  level.BiomesState
+}
+
+// ParseFlat parses a superflat layer string like "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains".
+// The biome part is optional and defaults to minecraft:plains.
+func ParseFlat(settings string) (*Flat, error) {
+	layers, biomeName, hasBiome := strings.Cut(settings, ";")
+	if !hasBiome || biomeName == "" {
+		biomeName = "minecraft:plains"
+	}
+	var f Flat
+	if err := f.Biome.UnmarshalText([]byte(biomeName)); err != nil {
+		return nil, fmt.Errorf("unknown biome %q", biomeName)
+	}
+	if strings.TrimSpace(layers) == "" {
+		return &f, nil
+	}
+	for _, layer := range strings.Split(layers, ",") {
+		layer = strings.TrimSpace(layer)
+		count := 1
+		if n, name, ok := strings.Cut(layer, "*"); ok {
+			var err error
+			if count, err = strconv.Atoi(n); err != nil || count < 0 {
+				return nil, fmt.Errorf("invalid layer height %q", n)
+			}
+			layer = name
+		}
+		if !strings.Contains(layer, ":") {
+			layer = "minecraft:" + layer
+		}
+		b, ok := block.FromID[layer]
+		if !ok {
+			return nil, block.UnknownBlockErr{Name: layer}
+		}
+		state, ok := block.ToStateID[b]
+		if !ok {
+			return nil, fmt.Errorf("block %q has no default state", layer)
+		}
+		for i := 0; i < count; i++ {
+			f.Layers = append(f.Layers, state)
+		}
+	}
+	if len(f.Layers) > len(hpcworld.Chunk{}.Sections)*16 {
+		return nil, errors.New("too many layers")
+	}
+	return &f, nil
+}
+
+func (f *Flat) Generate(level.ChunkPos, int64) *hpcworld.Chunk {
+	c := hpcworld.Alloc()
+	fillBiome(c, f.Biome)
+	for y, state := range f.Layers {
+		for x := 0; x < 16; x++ {
+			for z := 0; z < 16; z++ {
+				setBlock(c, x, MinY+y, z, int32(state))
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestParseFlat(t *testing.T) {
+	f, err := ParseFlat(DefaultFlatLayers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []block.Block{block.Bedrock{}, block.Dirt{}, block.Dirt{}, block.GrassBlock{}}
+	if len(f.Layers) != len(want) {
+		t.Fatalf("got %d layers, want %d", len(f.Layers), len(want))
+	}
+	for i, b := range want {
+		if f.Layers[i] != block.ToStateID[b] {
+			t.Errorf("layer %d: got %v, want %v", i, block.StateList[f.Layers[i]], b)
+		}
+	}
+	if b, _ := f.Biome.MarshalText(); string(b) != "minecraft:plains" {
+		t.Errorf("got biome %s", b)
+	}
+
+	for _, invalid := range []string{"minecraft:not_a_block", "x*minecraft:dirt", "minecraft:dirt;minecraft:nowhere"} {
+		if _, err := ParseFlat(invalid); err == nil {
+			t.Errorf("ParseFlat(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestNoise_Deterministic(t *testing.T) {
+	n := NewNoise()
+	a := n.Generate([2]int32{5, -7}, 42)
+	defer a.Free()
+	b := n.Generate([2]int32{5, -7}, 42)
+	defer b.Free()
+	if a.Sections != b.Sections {
+		t.Error("the same seed and position generate different chunks")
+	}
+}
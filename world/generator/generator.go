@@ -0,0 +1,35 @@
+// Package generator provides the built-in world generators.
+package generator
+
+import (
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// MinY is the lowest block Y of the generated chunks.
+const MinY = -64
+
+var (
+	air     = int32(block.ToStateID[block.Air{}])
+	bedrock = int32(block.ToStateID[block.Bedrock{}])
+	stone   = int32(block.ToStateID[block.Stone{}])
+	dirt    = int32(block.ToStateID[block.Dirt{}])
+	grass   = int32(block.ToStateID[block.GrassBlock{}])
+	sand    = int32(block.ToStateID[block.Sand{}])
+	water   = int32(block.ToStateID[block.Water{}])
+)
+
+// setBlock sets the block at the chunk relative x, z and the absolute y.
+func setBlock(c *hpcworld.Chunk, x, y, z int, state int32) {
+	y -= MinY
+	c.Sections[y>>4].SetBlock((y&15)<<8|z<<4|x, state)
+}
+
+func fillBiome(c *hpcworld.Chunk, b level.BiomesState) {
+	for i := range c.Sections {
+		for j := range c.Sections[i].Biomes {
+			c.Sections[i].Biomes[j] = int32(b)
+		}
+	}
+}
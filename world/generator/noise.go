@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+)
+
+// Noise generates rolling terrain from a seeded fractal Perlin noise heightmap.
+// It is much simpler than the vanilla generator, there are no caves, ores or structures.
+type Noise struct {
+	SeaLevel  int
+	BaseY     int
+	Amplitude float64
+	Scale     float64 // horizontal size of the biggest features in blocks
+	Octaves   int
+	Biome     level.BiomesState
+}
+
+// NewNoise returns a Noise generator with the default parameters.
+func NewNoise() *Noise {
+	n := &Noise{
+		SeaLevel:  63,
+		BaseY:     68,
+		Amplitude: 24,
+		Scale:     192,
+		Octaves:   4,
+	}
+	_ = n.Biome.UnmarshalText([]byte("minecraft:plains"))
+	return n
+}
+
+func (n *Noise) Generate(pos level.ChunkPos, seed int64) *hpcworld.Chunk {
+	c := hpcworld.Alloc()
+	fillBiome(c, n.Biome)
+	p := newPerlin(seed)
+	maxY := MinY + len(c.Sections)*16 - 1
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			wx := float64(int(pos[0])*16 + x)
+			wz := float64(int(pos[1])*16 + z)
+			height := n.BaseY + int(math.Round(n.Amplitude*p.fractal(wx/n.Scale, wz/n.Scale, n.Octaves)))
+			height = min(max(height, MinY+1), maxY)
+
+			setBlock(c, x, MinY, z, bedrock)
+			for y := MinY + 1; y <= height; y++ {
+				state := stone
+				switch {
+				case y == height && height >= n.SeaLevel:
+					state = grass
+				case y == height || y > height-4 && height < n.SeaLevel+2:
+					state = sand
+				case y > height-4:
+					state = dirt
+				}
+				setBlock(c, x, y, z, state)
+			}
+			for y := height + 1; y < n.SeaLevel; y++ {
+				setBlock(c, x, y, z, water)
+			}
+		}
+	}
+	return c
+}
+
+// perlin is a classic 2D gradient noise with a permutation table shuffled by the seed.
+type perlin struct {
+	perm [512]uint8
+}
+
+func newPerlin(seed int64) *perlin {
+	var p perlin
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < 256; i++ {
+		p.perm[i] = uint8(i)
+	}
+	r.Shuffle(256, func(i, j int) { p.perm[i], p.perm[j] = p.perm[j], p.perm[i] })
+	copy(p.perm[256:], p.perm[:256])
+	return &p
+}
+
+// fractal sums octaves of noise, the result is roughly in [-1, 1].
+func (p *perlin) fractal(x, z float64, octaves int) (v float64) {
+	amp, total := 1.0, 0.0
+	for i := 0; i < octaves; i++ {
+		v += amp * p.noise(x, z)
+		total += amp
+		x, z, amp = x*2, z*2, amp/2
+	}
+	return v / total
+}
+
+func (p *perlin) noise(x, z float64) float64 {
+	fx, fz := math.Floor(x), math.Floor(z)
+	xi, zi := int(fx)&255, int(fz)&255
+	x, z = x-fx, z-fz
+	u, v := fade(x), fade(z)
+	a, b := int(p.perm[xi])+zi, int(p.perm[xi+1])+zi
+	return lerp(v,
+		lerp(u, grad(p.perm[a], x, z), grad(p.perm[b], x-1, z)),
+		lerp(u, grad(p.perm[a+1], x, z-1), grad(p.perm[b+1], x-1, z-1)),
+	)
+}
+
+func fade(t float64) float64       { return t * t * t * (t*(t*6-15) + 10) }
+func lerp(t, a, b float64) float64 { return a + t*(b-a) }
+
+func grad(hash uint8, x, z float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + z
+	case 1:
+		return x - z
+	case 2:
+		return -x + z
+	case 3:
+		return -x - z
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return z
+	default:
+		return -z
+	}
+}
@@ -0,0 +1,18 @@
+package generator
+
+import (
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/biome"
+)
+
+// Void generates empty chunks with the minecraft:the_void biome.
+type Void struct{}
+
+func (Void) Generate(level.ChunkPos, int64) *hpcworld.Chunk {
+	c := hpcworld.Alloc()
+	var b biome.Type
+	_ = b.UnmarshalText([]byte("minecraft:the_void"))
+	fillBiome(c, b)
+	return c
+}
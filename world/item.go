@@ -0,0 +1,123 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/world/entity"
+)
+
+// ItemEntity is the state of an item entity, its Data.
+type ItemEntity struct {
+	Stack item.ItemStack
+	// PickupDelay is the number of ticks before the item can be picked up.
+	PickupDelay int
+}
+
+// itemPickupDelay is the number of ticks before an item dropped by a block can be picked up,
+// and itemLifetime the number of ticks before an item despawns, like vanilla.
+const (
+	itemPickupDelay = 10
+	itemLifetime    = 6000
+)
+
+// spawnItem drops the stack at the position as an item entity.
+func (w *World) spawnItem(pos Position, s item.ItemStack) *Entity {
+	e := w.spawnEntity(ItemType, pos, Rotation{}, &ItemEntity{Stack: s, PickupDelay: itemPickupDelay})
+	e.SetMetadata(entity.DataItem, &entity.Slot{ItemStack: s})
+	return e
+}
+
+// throwPickupDelay is the number of ticks before an item thrown by a player can be picked up.
+const throwPickupDelay = 40
+
+// throwItem spawns the stack thrown by the player from its eyes, like vanilla's Player.drop.
+// It's thrown toward where the player looks, or in a random direction if randomly, like the items of the dead players.
+func (w *World) throwItem(p *Player, s item.ItemStack, randomly bool) {
+	pos := p.pos0
+	pos[1] += PlayerEyeHeight - 0.3
+	var v [3]float64
+	if randomly {
+		speed, angle := w.rand.Float64()*0.5, w.rand.Float64()*2*math.Pi
+		v = [3]float64{-math.Sin(angle) * speed, 0.2, math.Cos(angle) * speed}
+	} else {
+		yaw, pitch := float64(p.rot0[0])*math.Pi/180, float64(p.rot0[1])*math.Pi/180
+		angle, spread := w.rand.Float64()*2*math.Pi, w.rand.Float64()*0.02
+		v = [3]float64{
+			-math.Sin(yaw)*math.Cos(pitch)*0.3 + math.Cos(angle)*spread,
+			-math.Sin(pitch)*0.3 + 0.1 + (w.rand.Float64()-w.rand.Float64())*0.1,
+			math.Cos(yaw)*math.Cos(pitch)*0.3 + math.Sin(angle)*spread,
+		}
+	}
+	e := w.spawnItem(pos, s)
+	e.SetVelocity(v)
+	e.Data.(*ItemEntity).PickupDelay = throwPickupDelay
+}
+
+// throwDrops spawns the stacks the player dropped since the last tick, with its ContainerLock held.
+func (w *World) throwDrops(p *Player) {
+	for _, s := range p.drops {
+		w.throwItem(p, s, false)
+	}
+	p.drops = nil
+}
+
+// tickItem lets the players next to the item pick it up, and despawns it when it's old.
+func (w *World) tickItem(e *Entity) {
+	it := e.Data.(*ItemEntity)
+	if it.PickupDelay > 0 {
+		it.PickupDelay--
+	} else {
+		w.pickUp(e, it)
+	}
+	if it.Stack.IsEmpty() || e.Age >= itemLifetime {
+		w.removeEntity(e)
+	}
+}
+
+// pickUp puts the item in the inventory of the players touching it, like vanilla,
+// whose pickup box is the bounding box of the player inflated by 1 horizontally and 0.5 vertically.
+func (w *World) pickUp(e *Entity, it *ItemEntity) {
+	box := e.boundingBox()
+	box.Lower = vec3d{box.Lower[0] - 1, box.Lower[1] - 0.5, box.Lower[2] - 1}
+	box.Upper = vec3d{box.Upper[0] + 1, box.Upper[1] + 0.5, box.Upper[2] + 1}
+	var players []*Player
+	w.entitiesTouching(box, func(other *Entity) bool {
+		if p, ok := other.Data.(*Player); ok && p.Gamemode != 3 {
+			players = append(players, p)
+		}
+		return true
+	})
+	count := it.Stack.Count
+	for _, p := range players {
+		p.ContainerLock.Lock()
+		p.Inventory.Add(&it.Stack)
+		if p.ContainerMenu != nil {
+			p.ContainerMenu.BroadcastChanges()
+		}
+		p.ContainerLock.Unlock()
+		if it.Stack.IsEmpty() {
+			return
+		}
+	}
+	// The players picking up a part of the stack see the rest.
+	if it.Stack.Count != count {
+		e.SetMetadata(entity.DataItem, &entity.Slot{ItemStack: it.Stack})
+	}
+}
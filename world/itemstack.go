@@ -1,6 +0,0 @@
-package world
-
-type ItemStack struct {
-	ItemID int32
-	Count  byte
-}
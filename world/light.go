@@ -0,0 +1,370 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import "github.com/mrhaoxx/go-mc/level/block"
+
+const (
+	worldMinY   = minSectionY * 16
+	worldHeight = 24 * 16
+	worldMaxY   = worldMinY + worldHeight - 1
+)
+
+type lightKind uint8
+
+const (
+	skyLight lightKind = iota
+	blockLight
+)
+
+type lightNode struct {
+	pos   [3]int32
+	level int
+}
+
+// lightDirections are the six neighbours of a block, the first one is below.
+var lightDirections = [6][3]int32{{0, -1, 0}, {0, 1, 0}, {-1, 0, 0}, {1, 0, 0}, {0, 0, -1}, {0, 0, 1}}
+
+// lighter floods one kind of light through the loaded chunks.
+// Every chunk it touches stays locked until done is called.
+//
+// Light is increased with a breadth-first search from the sources and
+// decreased the same way before the remaining light flows back in,
+// so both work across section and chunk borders.
+// Unloaded chunks stop the search, their borders are exchanged when they load.
+type lighter struct {
+	w      *World
+	kind   lightKind
+	locked []*LoadedChunk
+
+	lastPos [2]int32
+	last    *LoadedChunk
+
+	increase, decrease []lightNode
+}
+
+func newLighter(w *World, kind lightKind) *lighter {
+	return &lighter{w: w, kind: kind}
+}
+
+// at returns the chunk, the section index and the block index of the world coordinates,
+// or nil if the block is outside the world or in an unloaded chunk.
+func (l *lighter) at(x, y, z int32) (*LoadedChunk, int, int) {
+	if y < worldMinY || y > worldMaxY {
+		return nil, 0, 0
+	}
+	pos := [2]int32{x >> 4, z >> 4}
+	if l.last == nil || l.lastPos != pos {
+		lc, ok := l.w.chunks[pos]
+		if !ok || lc.Chunk == nil {
+			return nil, 0, 0
+		}
+		if !l.isLocked(lc) {
+			lc.Lock()
+			l.locked = append(l.locked, lc)
+		}
+		l.last, l.lastPos = lc, pos
+	}
+	y -= worldMinY
+	return l.last, int(y >> 4), int((y&15)<<8 | (z&15)<<4 | x&15)
+}
+
+func (l *lighter) isLocked(lc *LoadedChunk) bool {
+	for _, v := range l.locked {
+		if v == lc {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lighter) get(lc *LoadedChunk, sec, i int) int {
+	if l.kind == skyLight {
+		return lc.Sections[sec].GetSkyLight(i)
+	}
+	return lc.Sections[sec].GetBlockLight(i)
+}
+
+func (l *lighter) set(lc *LoadedChunk, sec, i, v int) {
+	if l.kind == skyLight {
+		lc.Sections[sec].SetSkyLight(i, v)
+	} else {
+		lc.Sections[sec].SetBlockLight(i, v)
+	}
+	lc.lightChanged |= 1 << sec
+	lc.dirty = true
+}
+
+func (l *lighter) state(lc *LoadedChunk, sec, i int) block.StateID {
+	return block.StateID(lc.Sections[sec].BlocksState[i])
+}
+
+// attenuate returns the light level reaching a block of the opacity from a neighbour of level lv.
+// Like vanilla, full sky light going down isn't reduced by transparent blocks.
+func (l *lighter) attenuate(lv, opacity int, down bool) int {
+	if l.kind == skyLight && down && lv == 15 && opacity == 0 {
+		return 15
+	}
+	return lv - max(1, opacity)
+}
+
+// source returns the light level a block has on its own:
+// the emission for block light, or the light from above the world for sky light.
+func (l *lighter) source(lc *LoadedChunk, sec, i int, y int32) int {
+	if l.kind == blockLight {
+		return block.LightEmission(l.state(lc, sec, i))
+	}
+	if y == worldMaxY {
+		return max(0, l.attenuate(15, block.LightOpacity(l.state(lc, sec, i)), true))
+	}
+	return 0
+}
+
+// update queues the block for relighting after it has been changed.
+func (l *lighter) update(pos [3]int32) {
+	lc, sec, i := l.at(pos[0], pos[1], pos[2])
+	if lc == nil {
+		return
+	}
+	l.decrease = append(l.decrease, lightNode{pos, l.get(lc, sec, i)})
+	l.set(lc, sec, i, 0)
+	if lv := l.source(lc, sec, i, pos[1]); lv > 0 {
+		l.set(lc, sec, i, lv)
+		l.increase = append(l.increase, lightNode{pos, lv})
+	}
+}
+
+// unpropagate removes the light depending on the queued decrease nodes.
+// The light of neighbours not depending on them is queued to flow back.
+func (l *lighter) unpropagate() {
+	for head := 0; head < len(l.decrease); head++ {
+		n := l.decrease[head]
+		for d, dir := range lightDirections {
+			p := [3]int32{n.pos[0] + dir[0], n.pos[1] + dir[1], n.pos[2] + dir[2]}
+			lc, sec, i := l.at(p[0], p[1], p[2])
+			if lc == nil {
+				continue
+			}
+			lv := l.get(lc, sec, i)
+			if lv == 0 {
+				continue
+			}
+			if lv < n.level || l.kind == skyLight && d == 0 && n.level == 15 && lv == 15 {
+				l.set(lc, sec, i, 0)
+				l.decrease = append(l.decrease, lightNode{p, lv})
+				if src := l.source(lc, sec, i, p[1]); src > 0 {
+					l.set(lc, sec, i, src)
+					l.increase = append(l.increase, lightNode{p, src})
+				}
+			} else {
+				l.increase = append(l.increase, lightNode{p, lv})
+			}
+		}
+	}
+	l.decrease = l.decrease[:0]
+}
+
+// propagate spreads the light from the queued increase nodes.
+func (l *lighter) propagate() {
+	for head := 0; head < len(l.increase); head++ {
+		n := l.increase[head]
+		if lc, sec, i := l.at(n.pos[0], n.pos[1], n.pos[2]); lc == nil || l.get(lc, sec, i) != n.level {
+			continue // the block has been changed by a later node
+		}
+		for d, dir := range lightDirections {
+			p := [3]int32{n.pos[0] + dir[0], n.pos[1] + dir[1], n.pos[2] + dir[2]}
+			lc, sec, i := l.at(p[0], p[1], p[2])
+			if lc == nil {
+				continue
+			}
+			lv := l.attenuate(n.level, block.LightOpacity(l.state(lc, sec, i)), d == 0)
+			if lv > l.get(lc, sec, i) {
+				l.set(lc, sec, i, lv)
+				l.increase = append(l.increase, lightNode{p, lv})
+			}
+		}
+	}
+	l.increase = l.increase[:0]
+}
+
+// done unlocks the chunks touched by the lighter.
+func (l *lighter) done() {
+	for _, lc := range l.locked {
+		lc.Unlock()
+	}
+	l.locked, l.last = l.locked[:0], nil
+}
+
+// seedChunk lights the chunk from scratch: sky light falls down every column,
+// and every light emitting block becomes a source.
+// The blocks that may light their horizontal neighbours are queued.
+func (l *lighter) seedChunk(lc *LoadedChunk) {
+	x0, z0 := lc.Pos[0]<<4, lc.Pos[1]<<4
+	if l.kind == skyLight {
+		for x := int32(0); x < 16; x++ {
+			for z := int32(0); z < 16; z++ {
+				lv := 15
+				for y := int32(worldMaxY); y >= worldMinY; y-- {
+					_, sec, i := l.at(x0+x, y, z0+z)
+					lv = max(0, l.attenuate(lv, block.LightOpacity(l.state(lc, sec, i)), true))
+					lc.Sections[sec].SetSkyLight(i, lv)
+				}
+			}
+		}
+	} else {
+		for sec := range lc.Sections {
+			s := &lc.Sections[sec]
+			clear(s.BlockLight[:])
+			if s.Blockcount == 0 {
+				continue
+			}
+			for i, state := range s.BlocksState {
+				if lv := block.LightEmission(block.StateID(state)); lv > 0 {
+					s.SetBlockLight(i, lv)
+				}
+			}
+		}
+	}
+	for sec := range lc.Sections {
+		for i := 0; i < 16*16*16; i++ {
+			lv := l.get(lc, sec, i)
+			if lv <= 1 {
+				continue
+			}
+			x, y, z := int32(i&15), int32(sec<<4|i>>8), int32(i>>4&15)
+			pos := [3]int32{x0 + x, y + worldMinY, z0 + z}
+			if l.kind == blockLight || l.darkerNeighbour(lc, sec, i, lv) {
+				l.increase = append(l.increase, lightNode{pos, lv})
+			}
+		}
+	}
+}
+
+// darkerNeighbour reports whether any horizontal neighbour of the block in the same chunk
+// has a light level low enough to be lit by the block.
+func (l *lighter) darkerNeighbour(lc *LoadedChunk, sec, i, lv int) bool {
+	x, z := i&15, i>>4&15
+	return x > 0 && l.get(lc, sec, i-1) < lv-1 ||
+		x < 15 && l.get(lc, sec, i+1) < lv-1 ||
+		z > 0 && l.get(lc, sec, i-16) < lv-1 ||
+		z < 15 && l.get(lc, sec, i+16) < lv-1
+}
+
+// seedBorders queues the blocks on both sides of the borders between the chunk and its loaded neighbours
+// that can light the block on the other side.
+func (l *lighter) seedBorders(lc *LoadedChunk) {
+	x0, z0 := lc.Pos[0]<<4, lc.Pos[1]<<4
+	for _, side := range [4][4]int32{
+		// the first block inside the chunk, the step along the border, and the direction out of the chunk.
+		{x0, z0, 0, -1},
+		{x0, z0 + 15, 0, 1},
+		{x0, z0, -1, 0},
+		{x0 + 15, z0, 1, 0},
+	} {
+		dx, dz := side[2], side[3]
+		for j := int32(0); j < 16; j++ {
+			x, z := side[0]+j*abs32(dz), side[1]+j*abs32(dx)
+			for y := int32(worldMinY); y <= worldMaxY; y++ {
+				in, inSec, inI := l.at(x, y, z)
+				out, outSec, outI := l.at(x+dx, y, z+dz)
+				if in == nil || out == nil {
+					break
+				}
+				inLv, outLv := l.get(in, inSec, inI), l.get(out, outSec, outI)
+				if inLv > outLv+1 {
+					l.increase = append(l.increase, lightNode{[3]int32{x, y, z}, inLv})
+				} else if outLv > inLv+1 {
+					l.increase = append(l.increase, lightNode{[3]int32{x + dx, y, z + dz}, outLv})
+				}
+			}
+		}
+	}
+}
+
+func abs32(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// lightChunk computes the light of a newly loaded chunk.
+// Generated chunks are lit from scratch, chunks read from the provider keep their stored light.
+// In both cases the light is exchanged with the loaded neighbours.
+func (w *World) lightChunk(lc *LoadedChunk, generated bool) {
+	for _, kind := range [...]lightKind{skyLight, blockLight} {
+		l := newLighter(w, kind)
+		l.at(lc.Pos[0]<<4, worldMinY, lc.Pos[1]<<4) // lock the chunk first
+		if generated {
+			l.seedChunk(lc)
+		}
+		l.seedBorders(lc)
+		l.propagate()
+		l.done()
+	}
+	// the whole chunk is sent to the viewers, there is no need to send an update.
+	lc.Lock()
+	lc.lightChanged = 0
+	lc.Unlock()
+}
+
+// relightChanges updates the light around the blocks changed since the last flush,
+// whose light emission or opacity is different from the block they replaced.
+func (w *World) relightChanges() {
+	var sky, blk [][3]int32
+	for _, lc := range w.chunks {
+		lc.Lock()
+		for pos, old := range lc.changes {
+			state := lc.getBlock(int(pos[0]), int(pos[1]), int(pos[2]))
+			if block.LightOpacity(old) != block.LightOpacity(state) {
+				sky = append(sky, pos)
+				blk = append(blk, pos)
+			} else if block.LightEmission(old) != block.LightEmission(state) {
+				blk = append(blk, pos)
+			}
+		}
+		lc.Unlock()
+	}
+	for kind, positions := range [...][][3]int32{skyLight: sky, blockLight: blk} {
+		if len(positions) == 0 {
+			continue
+		}
+		l := newLighter(w, lightKind(kind))
+		for _, pos := range positions {
+			l.update(pos)
+		}
+		l.unpropagate()
+		l.propagate()
+		l.done()
+	}
+}
+
+// GetSkyLight returns the sky light level at the world coordinates, which must be in this chunk.
+func (lc *LoadedChunk) GetSkyLight(x, y, z int) int {
+	lc.Lock()
+	defer lc.Unlock()
+	y -= worldMinY
+	return lc.Sections[y>>4].GetSkyLight((y&15)<<8 | (z&15)<<4 | x&15)
+}
+
+// GetBlockLight returns the block light level at the world coordinates, which must be in this chunk.
+func (lc *LoadedChunk) GetBlockLight(x, y, z int) int {
+	lc.Lock()
+	defer lc.Unlock()
+	y -= worldMinY
+	return lc.Sections[y>>4].GetBlockLight((y&15)<<8 | (z&15)<<4 | x&15)
+}
@@ -0,0 +1,92 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/world/generator"
+)
+
+func newLightTestWorld(t *testing.T) *World {
+	g, err := generator.ParseFlat(generator.DefaultFlatLayers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &World{chunks: make(map[[2]int32]*LoadedChunk)}
+	for x := int32(-1); x <= 1; x++ {
+		for z := int32(-1); z <= 1; z++ {
+			c := g.Generate(level.ChunkPos{x, z}, 0)
+			lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{x, z}, heightMaps: c.HeightMaps()}
+			w.chunks[lc.Pos] = lc
+			w.lightChunk(lc, true)
+		}
+	}
+	return w
+}
+
+func TestLighter_incremental(t *testing.T) {
+	w := newLightTestWorld(t)
+	states := []level.BlocksState{
+		block.ToStateID[block.Torch{}],
+		block.ToStateID[block.Stone{}],
+		block.ToStateID[block.Air{}],
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, z := r.Intn(48)-16, r.Intn(16)-61, r.Intn(48)-16
+		lc := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+		lc.SetBlock(x, y, z, states[r.Intn(len(states))])
+		w.relightChanges()
+		clear(lc.changes)
+	}
+
+	// light the same blocks from scratch
+	w2 := &World{chunks: make(map[[2]int32]*LoadedChunk)}
+	for pos, lc := range w.chunks {
+		c := *lc.Chunk
+		for i := range c.Sections {
+			clear(c.Sections[i].SkyLight[:])
+			clear(c.Sections[i].BlockLight[:])
+		}
+		w2.chunks[pos] = &LoadedChunk{Chunk: &c, Pos: lc.Pos}
+	}
+	for _, lc := range w2.chunks {
+		w2.lightChunk(lc, true)
+	}
+
+	for pos, lc := range w.chunks {
+		for i := range lc.Sections {
+			want := &w2.chunks[pos].Sections[i]
+			if lc.Sections[i].SkyLight != want.SkyLight {
+				t.Errorf("sky light of chunk %v section %d mismatch", pos, i)
+			}
+			if lc.Sections[i].BlockLight != want.BlockLight {
+				t.Errorf("block light of chunk %v section %d mismatch", pos, i)
+			}
+		}
+	}
+}
+
+func TestLighter_sky(t *testing.T) {
+	w := newLightTestWorld(t)
+	lc := w.chunks[[2]int32{0, 0}]
+	// the flat world is 4 blocks high, from y=-64 to y=-61.
+	if got := lc.GetSkyLight(0, -60, 0); got != 15 {
+		t.Errorf("sky light above the ground: want 15, got %d", got)
+	}
+	if got := lc.GetSkyLight(0, -61, 0); got != 0 {
+		t.Errorf("sky light in the ground: want 0, got %d", got)
+	}
+	// a roof makes a shadow lit from the sides
+	for x := -3; x <= 3; x++ {
+		for z := -3; z <= 3; z++ {
+			w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}].SetBlock(x, -58, z, block.ToStateID[block.Stone{}])
+		}
+	}
+	w.relightChanges()
+	if got := lc.GetSkyLight(0, -60, 0); got != 11 {
+		t.Errorf("sky light under the roof: want 11, got %d", got)
+	}
+}
@@ -0,0 +1,262 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// Violation is a kind of illegal move of a player, counted in Player.Violations and reported to Config.OnViolation.
+type Violation int
+
+const (
+	// ViolationSpeed is a move faster than the player walks, sprints, flies or glides.
+	ViolationSpeed Violation = iota
+	// ViolationNoclip is a move into the blocks.
+	ViolationNoclip
+	// ViolationFlight is flying, or staying in the air, without being allowed to fly.
+	ViolationFlight
+	// ViolationGround is claiming to stand on the ground in the air, which cancels the fall damage.
+	ViolationGround
+	// violationKinds is the number of the kinds of violations.
+	violationKinds
+)
+
+var strViolation = [...]string{"speed", "noclip", "flight", "ground"}
+
+func (v Violation) String() string {
+	if v >= 0 && int(v) < len(strViolation) {
+		return strViolation[v]
+	}
+	return "invalid Violation"
+}
+
+// The horizontal distances the players may move in a tick, with some room over vanilla's speeds,
+// which are 0.216 walking, 0.36 sprint jumping, 0.55 flying in creative and 3.9 diving with an elytra.
+// The players moving on slippery blocks go faster.
+const (
+	walkSpeed     = 0.35
+	sprintSpeed   = 0.5
+	flySpeed      = 0.7
+	glideSpeed    = 4.0
+	slipperyBoost = 2.5
+	// moveBurst is the number of ticks of unused moves a player may catch up with at once,
+	// as the moves of the clients lagging arrive together.
+	moveBurst = 5
+	// riseLimit is the height a player may rise in a tick without flying, which is the height of a jump.
+	riseLimit = 1.3
+	// hoverLimit is the number of ticks a player may stay in the air without falling, a jump taking about 8.
+	hoverLimit = 20
+	// groundMargin is the distance under the feet of a player in which a block supports it.
+	groundMargin = 0.03
+)
+
+// movement is the state of the checks of the moves of a player.
+type movement struct {
+	// budget is the horizontal distance the player may still move.
+	budget float64
+	// hoverTicks is the number of ticks the player has spent in the air without falling.
+	hoverTicks int
+}
+
+// climbable are the blocks the players climb, which they don't fall from.
+var climbable = map[string]bool{
+	"minecraft:ladder": true, "minecraft:vine": true, "minecraft:scaffolding": true,
+	"minecraft:twisting_vines": true, "minecraft:twisting_vines_plant": true,
+	"minecraft:weeping_vines": true, "minecraft:weeping_vines_plant": true,
+	"minecraft:cave_vines": true, "minecraft:cave_vines_plant": true,
+	"minecraft:cobweb": true, "minecraft:powder_snow": true,
+}
+
+// checkMove reports whether the move of the player to the position of its inputs is legal,
+// and updates its ground state and fall distance, hurting it when it lands. The illegal moves are reported,
+// and the player is sent back to its last position unless it only claimed to stand on the ground.
+// The blocks of the chunks not loaded aren't checked, and the spectators move freely.
+func (w *World) checkMove(c Client, p *Player, in *Inputs) bool {
+	from, to := p.pos0, in.Position
+	if p.Gamemode == 3 {
+		p.OnGround = in.OnGround
+		return true
+	}
+	m := &p.movement
+	box := boundingBox(to, PlayerWidth, PlayerHeight)
+	loaded := w.loaded(box)
+	supported := !loaded || w.collides(under(box, groundMargin))
+	if supported || in.Flying {
+		in.FallFlying = false
+	}
+
+	limit := p.speedLimit(in)
+	if below, ok := w.getBlock(int(math.Floor(from[0])), int(math.Floor(from[1]-0.5)), int(math.Floor(from[2]))); ok &&
+		block.Slipperiness(block.StateID(below)) > 0.6 {
+		limit *= slipperyBoost
+	}
+	m.budget = min(m.budget+limit, limit*moveBurst)
+	horizontal := math.Hypot(to[0]-from[0], to[2]-from[2])
+	if horizontal > m.budget || !in.Flying && !in.FallFlying && to[1]-from[1] > riseLimit && !w.bouncing(from) {
+		return w.rejectMove(c, p, ViolationSpeed)
+	}
+	m.budget -= horizontal
+
+	if !loaded {
+		p.OnGround = in.OnGround
+		return true
+	}
+	// The players stuck in blocks, like the ones placed where they stand, may move out of them.
+	if w.collides(shrink(box, 1e-3)) && !w.collides(shrink(boundingBox(from, PlayerWidth, PlayerHeight), 1e-3)) {
+		return w.rejectMove(c, p, ViolationNoclip)
+	}
+
+	held := w.heldInAir(box)
+	if !p.MayFly() {
+		switch {
+		case in.Flying:
+			return w.rejectMove(c, p, ViolationFlight)
+		case supported, held, in.FallFlying, to[1] < from[1]:
+			m.hoverTicks = 0
+		default:
+			m.hoverTicks++
+			if m.hoverTicks > hoverLimit+5*int(p.effectLevel("minecraft:jump_boost")) {
+				m.hoverTicks = 0
+				return w.rejectMove(c, p, ViolationFlight)
+			}
+		}
+	}
+
+	if bool(in.OnGround) && !supported {
+		w.reportViolation(c, p, ViolationGround)
+	}
+	switch {
+	case supported, held, in.Flying:
+		// the slime blocks bounce the players falling on them, unless they sneak
+		if supported && !held && !in.Flying && (in.Sneaking || !w.bouncing(to)) {
+			w.fall(c, p)
+		}
+		p.FallDistance = 0
+	case to[1] < from[1]:
+		p.FallDistance += from[1] - to[1]
+	}
+	p.OnGround = OnGround(supported)
+	return true
+}
+
+// speedLimit returns the horizontal distance the player may move in a tick, faster with the speed effect.
+func (p *Player) speedLimit(in *Inputs) float64 {
+	var limit float64
+	switch {
+	case in.FallFlying:
+		limit = glideSpeed
+	case in.Flying && in.Sprinting:
+		limit = 2 * flySpeed
+	case in.Flying:
+		limit = flySpeed
+	case in.Sprinting:
+		limit = sprintSpeed
+	default:
+		limit = walkSpeed
+	}
+	return limit * (1 + 0.2*float64(p.effectLevel("minecraft:speed")))
+}
+
+// rejectMove reports the violation and sends the player back to its last position.
+// The moves of the client are ignored until it accepts the teleportation.
+func (w *World) rejectMove(c Client, p *Player, v Violation) bool {
+	w.reportViolation(c, p, v)
+	p.movement.budget = 0
+	w.teleport(c, p, p.Position)
+	return false
+}
+
+// reportViolation counts the violation of the player and calls Config.OnViolation.
+func (w *World) reportViolation(c Client, p *Player, v Violation) {
+	p.Violations[v]++
+	if w.config.OnViolation != nil {
+		w.config.OnViolation(c, p, v)
+	}
+}
+
+// teleport moves the player of the client to the position, keeping the rotation.
+func (w *World) teleport(c Client, p *Player, pos Position) {
+	p.teleport = &TeleportRequest{
+		ID:       c.SendPlayerPosition(pos, p.Rotation),
+		Position: pos,
+		Rotation: p.Rotation,
+	}
+}
+
+// under returns the layer of the height under the box.
+func under(box aabb3d, height float64) aabb3d {
+	box.Upper[1] = box.Lower[1]
+	box.Lower[1] -= height
+	return box
+}
+
+// shrink returns the box with its faces moved in by d, so that it doesn't collide with the blocks it touches.
+func shrink(box aabb3d, d float64) aabb3d {
+	for i := range 3 {
+		box.Lower[i] += d
+		box.Upper[i] -= d
+	}
+	return box
+}
+
+// collides reports whether the box overlaps the collision boxes of the blocks.
+func (w *World) collides(box aabb3d) bool {
+	for _, b := range w.collisionBoxes(box) {
+		if b.Min[0] < box.Upper[0] && b.Max[0] > box.Lower[0] &&
+			b.Min[1] < box.Upper[1] && b.Max[1] > box.Lower[1] &&
+			b.Min[2] < box.Upper[2] && b.Max[2] > box.Lower[2] {
+			return true
+		}
+	}
+	return false
+}
+
+// loaded reports whether the chunks of the box are loaded.
+func (w *World) loaded(box aabb3d) bool {
+	for _, x := range [2]float64{box.Lower[0], box.Upper[0]} {
+		for _, z := range [2]float64{box.Lower[2], box.Upper[2]} {
+			if _, ok := w.chunks[[2]int32{int32(math.Floor(x)) >> 4, int32(math.Floor(z)) >> 4}]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// heldInAir reports whether the box is in a fluid or a block the players climb, where they don't fall freely.
+func (w *World) heldInAir(box aabb3d) bool {
+	for x := int(math.Floor(box.Lower[0])); x <= int(math.Floor(box.Upper[0])); x++ {
+		for y := int(math.Floor(box.Lower[1])); y <= int(math.Floor(box.Upper[1])); y++ {
+			for z := int(math.Floor(box.Lower[2])); z <= int(math.Floor(box.Upper[2])); z++ {
+				s, ok := w.getBlock(x, y, z)
+				if ok && (!block.FluidOf(block.StateID(s)).IsEmpty() || climbable[block.StateList[s].ID()]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// bouncing reports whether the player at the position stands on a slime block, which throws it up.
+func (w *World) bouncing(pos Position) bool {
+	s, ok := w.getBlock(int(math.Floor(pos[0])), int(math.Floor(pos[1]-0.5)), int(math.Floor(pos[2])))
+	return ok && block.StateList[s].ID() == "minecraft:slime_block"
+}
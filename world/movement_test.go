@@ -0,0 +1,96 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// teleportClient counts the teleportations sent to the client.
+type teleportClient struct {
+	Client
+	teleports int
+}
+
+func (c *teleportClient) SendPlayerPosition([3]float64, [2]float32) int32 {
+	c.teleports++
+	return int32(c.teleports)
+}
+
+func TestWorld_checkMove(t *testing.T) {
+	w, lc, _ := physicsWorld(t)
+	lc.SetBlock(12, 65, 9, level.BlocksState(block.ToStateID[block.Stone{}]))
+	var reported []Violation
+	w.config.OnViolation = func(_ Client, _ *Player, v Violation) { reported = append(reported, v) }
+	c := new(teleportClient)
+	p := &Player{Entity: Entity{Position: Position{8.5, 65, 8.5}}}
+	p.pos0 = p.Position
+	var in Inputs
+	move := func(pos Position, onGround bool) bool {
+		in.Position, in.OnGround = pos, OnGround(onGround)
+		if !w.checkMove(c, p, &in) {
+			p.teleport = nil
+			return false
+		}
+		p.pos0, p.Position = pos, pos
+		return true
+	}
+
+	for i := range 5 {
+		if !move(Position{8.5, 65, 8.5 + 0.2*float64(i+1)}, true) || !bool(p.OnGround) {
+			t.Fatalf("the player can't walk, violations %v", reported)
+		}
+	}
+	if move(Position{11, 65, 9.5}, true) || c.teleports != 1 || p.Violations[ViolationSpeed] != 1 {
+		t.Errorf("the player moves 2.5 blocks in a tick, violations %v", reported)
+	}
+	for range 3 {
+		move(Position{8.5 + 0.3, 65, 9.5}, true)
+		move(Position{8.5, 65, 9.5}, true)
+	}
+	if p.Violations[ViolationSpeed] != 1 {
+		t.Errorf("the player is too fast walking back and forth, violations %v", reported)
+	}
+
+	// The player can't walk into the stone block at x 12, but can leave the blocks it's stuck in.
+	for x := 8.7; x < 11.7; x += 0.2 {
+		move(Position{x, 65, 9.5}, true)
+	}
+	if p.Violations[ViolationNoclip] != 0 || !move(Position{11.6, 65, 9.5}, true) {
+		t.Fatalf("the player can't walk to the stone block, violations %v", reported)
+	}
+	if move(Position{11.8, 65, 9.5}, true) || p.Violations[ViolationNoclip] != 1 {
+		t.Errorf("the player walks into the stone block, violations %v", reported)
+	}
+	p.pos0, p.Position = Position{12.5, 65.5, 9.5}, Position{12.5, 65.5, 9.5}
+	if !move(Position{12.5, 65.6, 9.5}, false) {
+		t.Errorf("the player can't leave the block it's stuck in, violations %v", reported)
+	}
+
+	// Falling players can't claim to stand on the ground, and players can't hover without flying.
+	p.pos0, p.Position = Position{4.5, 70, 4.5}, Position{4.5, 70, 4.5}
+	move(Position{4.5, 69, 4.5}, true)
+	if p.Violations[ViolationGround] != 1 || bool(p.OnGround) || p.FallDistance != 1 {
+		t.Errorf("the player falling on the ground: %v, fall distance %v", p.OnGround, p.FallDistance)
+	}
+	for range hoverLimit + 1 {
+		move(Position{4.5, 69, 4.5}, false)
+	}
+	if p.Violations[ViolationFlight] != 1 {
+		t.Errorf("the player hovers, violations %v", reported)
+	}
+	p.Gamemode = 1
+	in.Flying = true
+	for range hoverLimit + 1 {
+		move(Position{4.5, 69, 4.5}, false)
+	}
+	if p.Violations[ViolationFlight] != 1 || len(reported) != 4 {
+		t.Errorf("the player in creative can't fly, violations %v", reported)
+	}
+	in.Flying = false
+	move(Position{4.5, 65, 4.5}, true)
+	if !bool(p.OnGround) || p.FallDistance != 0 {
+		t.Errorf("the player landing on the ground: %v, fall distance %v", p.OnGround, p.FallDistance)
+	}
+}
@@ -0,0 +1,165 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/world/entity"
+)
+
+// minVelocity is the speed below which a component of the velocity of an entity stops, like vanilla.
+const minVelocity = 0.003
+
+// hasPhysics reports whether the entity is moved by the world, which the players, moved by their clients, aren't.
+func (e *Entity) hasPhysics() bool {
+	return e.Type.Gravity != 0 || e.Type.Drag != 0
+}
+
+// noGravity reports whether the NoGravity field of the metadata of the entity is set.
+func (e *Entity) noGravity() bool {
+	v, ok := e.Metadata.Get(entity.DataNoGravity)
+	b, _ := v.(*entity.Boolean)
+	return ok && b != nil && bool(b.Boolean)
+}
+
+// tickPhysics moves the entity by its velocity until it hits the blocks, stepping up the ones lower than its step height,
+// then applies its gravity and drag, and the friction of the block it stands on.
+// The entities in the chunks not loaded don't move.
+func (w *World) tickPhysics(e *Entity) {
+	if _, ok := w.chunks[[2]int32{int32(math.Floor(e.pos0[0])) >> 4, int32(math.Floor(e.pos0[2])) >> 4}]; !ok {
+		return
+	}
+	t := e.Type
+	v := e.vel0
+	if !e.noGravity() {
+		v[1] -= t.Gravity
+	}
+	d, onGround := w.collide(boundingBox(e.pos0, t.Width, t.Height), v, t.StepHeight, bool(e.OnGround))
+	for i := range d {
+		e.pos0[i] += d[i]
+	}
+	if d[0] != v[0] {
+		v[0] = 0
+	}
+	if d[2] != v[2] {
+		v[2] = 0
+	}
+	if onGround || v[1] > 0 && d[1] < v[1] {
+		v[1] = 0
+	}
+
+	friction := 1.0
+	if onGround {
+		below, _ := w.getBlock(int(math.Floor(e.pos0[0])), int(math.Floor(e.pos0[1]-0.5)), int(math.Floor(e.pos0[2])))
+		friction = block.Slipperiness(block.StateID(below))
+	}
+	for i := range v {
+		v[i] *= 1 - t.Drag
+		if i != 1 {
+			v[i] *= friction
+		}
+		if math.Abs(v[i]) < minVelocity {
+			v[i] = 0
+		}
+	}
+	e.vel0, e.OnGround = v, OnGround(onGround)
+}
+
+// collide returns the part of the move the box makes before hitting the blocks, and whether it lands on one.
+// A box on the ground blocked horizontally steps up the blocks up to stepHeight high, like vanilla.
+func (w *World) collide(box aabb3d, move [3]float64, stepHeight float64, onGround bool) ([3]float64, bool) {
+	swept := box
+	for i := range move {
+		swept.Lower[i] += min(move[i], 0)
+		swept.Upper[i] += max(move[i], 0)
+	}
+	swept.Upper[1] += stepHeight
+	boxes := w.collisionBoxes(swept)
+
+	d := sweep(box, move, boxes)
+	landed := move[1] < 0 && d[1] != move[1]
+	if stepHeight <= 0 || !onGround && !landed || d[0] == move[0] && d[2] == move[2] {
+		return d, landed
+	}
+	step := sweep(box, [3]float64{move[0], stepHeight, move[2]}, boxes)
+	raised := box
+	for i := range step {
+		raised.Lower[i] += step[i]
+		raised.Upper[i] += step[i]
+	}
+	step[1] += sweep(raised, [3]float64{0, -step[1], 0}, boxes)[1]
+	if step[0]*step[0]+step[2]*step[2] > d[0]*d[0]+d[2]*d[2] {
+		return step, true
+	}
+	return d, landed
+}
+
+// sweep moves the box along Y, then X and Z, and returns the distance it moves along each axis before hitting the boxes.
+func sweep(box aabb3d, move [3]float64, boxes []block.Box) [3]float64 {
+	for _, axis := range [3]int{1, 0, 2} {
+		for _, b := range boxes {
+			move[axis] = clip(box, b, axis, move[axis])
+		}
+		box.Lower[axis] += move[axis]
+		box.Upper[axis] += move[axis]
+	}
+	return move
+}
+
+// clip returns the part of the move d along the axis the box makes before hitting b.
+// The boxes overlapping already don't stop it, so that the entities stuck in blocks can leave them.
+func clip(box aabb3d, b block.Box, axis int, d float64) float64 {
+	const eps = 1e-7
+	for i := range 3 {
+		if i != axis && (b.Max[i] <= box.Lower[i]+eps || b.Min[i] >= box.Upper[i]-eps) {
+			return d
+		}
+	}
+	switch {
+	case d > 0 && b.Min[axis] >= box.Upper[axis]-eps:
+		return min(d, max(b.Min[axis]-box.Upper[axis], 0))
+	case d < 0 && b.Max[axis] <= box.Lower[axis]+eps:
+		return max(d, min(b.Max[axis]-box.Lower[axis], 0))
+	}
+	return d
+}
+
+// collisionBoxes returns the collision boxes of the blocks touching the box,
+// and the ones below it as fences and walls are higher than a block.
+// The blocks of the chunks not loaded are full cubes, and the ones out of the world empty.
+func (w *World) collisionBoxes(box aabb3d) []block.Box {
+	var boxes []block.Box
+	x0, y0, z0 := int(math.Floor(box.Lower[0])), int(math.Floor(box.Lower[1]))-1, int(math.Floor(box.Lower[2]))
+	x1, y1, z1 := int(math.Floor(box.Upper[0])), int(math.Floor(box.Upper[1])), int(math.Floor(box.Upper[2]))
+	for x := x0; x <= x1; x++ {
+		for z := z0; z <= z1; z++ {
+			lc, ok := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+			for y := max(y0, worldMinY); y <= min(y1, worldMaxY); y++ {
+				if !ok {
+					boxes = append(boxes, block.Box{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 1, 1}}.Offset(x, y, z))
+					continue
+				}
+				for _, b := range block.CollisionBoxes(block.StateID(lc.GetBlock(x, y, z))) {
+					boxes = append(boxes, b.Offset(x, y, z))
+				}
+			}
+		}
+	}
+	return boxes
+}
@@ -0,0 +1,74 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+)
+
+// physicsWorld returns a world of a chunk with a stone floor at y 64, and a viewer at its center.
+func physicsWorld(t *testing.T) (*World, *LoadedChunk, *entityRecorder) {
+	c := hpcworld.Alloc()
+	t.Cleanup(c.Free)
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{0, 0}, heightMaps: c.HeightMaps()}
+	stone := level.BlocksState(block.ToStateID[block.Stone{}])
+	for x := range 16 {
+		for z := range 16 {
+			lc.SetBlock(x, 64, z, stone)
+		}
+	}
+	w := &World{chunks: map[[2]int32]*LoadedChunk{{0, 0}: lc}, entities: make(map[int32]*Entity)}
+	r := new(entityRecorder)
+	p := &Player{Entity: Entity{EntityID: NewEntityID(), Position: Position{8, 65, 8}, Type: PlayerType}, ViewDistance: 2, EntitiesInView: make(map[int32]*Entity)}
+	p.Data = p
+	p.view = w.playerViews.Insert(p.getView(), playerView{r, p})
+	w.addEntity(&p.Entity)
+	return w, lc, r
+}
+
+func TestWorld_tickPhysics_fall(t *testing.T) {
+	w, _, r := physicsWorld(t)
+	e := w.spawnItem(Position{4.5, 70, 4.5}, item.New(1, 1))
+	w.subtickUpdateEntities()
+	r.moves, r.motions = 0, 0
+	for range 40 {
+		w.subtickUpdateEntities()
+	}
+	if !e.OnGround || e.pos0 != (Position{4.5, 65, 4.5}) || e.vel0 != [3]float64{} {
+		t.Errorf("the item falls to %v, at %v, on the ground: %v", e.pos0, e.vel0, e.OnGround)
+	}
+	// The clients simulate the fall, so only the landing is sent.
+	if r.moves != 1 || r.motions != 1 {
+		t.Errorf("%d moves and %d velocities are sent for the fall", r.moves, r.motions)
+	}
+	for range 20 {
+		w.subtickUpdateEntities()
+	}
+	if e.pos0 != (Position{4.5, 65, 4.5}) || r.moves != 1 || r.motions != 1 {
+		t.Errorf("the item resting on the ground moves to %v", e.pos0)
+	}
+}
+
+func TestWorld_tickPhysics_step(t *testing.T) {
+	w, lc, _ := physicsWorld(t)
+	slab := level.BlocksState(block.ToStateID[block.StoneSlab{Type: block.SlabTypeBottom}])
+	stone := level.BlocksState(block.ToStateID[block.Stone{}])
+	lc.SetBlock(6, 65, 4, slab)
+	lc.SetBlock(7, 65, 4, slab)
+	lc.SetBlock(8, 65, 4, stone)
+	lc.SetBlock(8, 66, 4, stone)
+	pig := w.spawnEntity(PigType, Position{4.5, 65, 4.5}, Rotation{}, nil)
+	pig.OnGround = true
+	for range 20 {
+		pig.SetVelocity([3]float64{0.2, pig.vel0[1], 0})
+		w.subtickUpdateEntities()
+	}
+	// The pig walks up the slabs, and stops at the stone blocks, which are too high.
+	if math.Abs(pig.pos0[1]-65.5) > 1e-9 || math.Abs(pig.pos0[0]-(8-PigType.Width/2)) > 1e-9 || !pig.OnGround {
+		t.Errorf("the pig walks to %v, on the ground: %v", pig.pos0, pig.OnGround)
+	}
+}
@@ -0,0 +1,88 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// PlayerWidth and PlayerHeight are the size of the bounding box of a standing player.
+const (
+	PlayerWidth  = 0.6
+	PlayerHeight = 1.8
+)
+
+// PlaceBlock places the block item of the context for the player of the client and returns the blocks set.
+// The block states are decided by block.Place with the blocks of this world, and it reports false
+// if the clicked block is out of the reach of the player, they can't be placed, or a player or an entity is in the way.
+func (w *World) PlaceBlock(c Client, ctx block.PlaceContext) ([]block.Placed, bool) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	if p, ok := w.players[c]; !ok || !p.inReach(ctx.Clicked) {
+		return nil, false
+	}
+	ctx.Get = func(x, y, z int) (block.StateID, bool) {
+		s, ok := w.getBlock(x, y, z)
+		return block.StateID(s), ok
+	}
+	placed, ok := block.Place(ctx)
+	if !ok {
+		return nil, false
+	}
+	for _, b := range placed {
+		if w.obstructed(b.Pos, b.State) {
+			return nil, false
+		}
+	}
+	for _, b := range placed {
+		lc := w.chunks[[2]int32{int32(b.Pos[0] >> 4), int32(b.Pos[2] >> 4)}]
+		lc.SetBlock(b.Pos[0], b.Pos[1], b.Pos[2], level.BlocksState(b.State))
+	}
+	return placed, true
+}
+
+// obstructed reports whether the bounding box of a player or an entity intersects the collision boxes of the block state at the position.
+func (w *World) obstructed(pos [3]int, state block.StateID) bool {
+	for _, b := range block.CollisionBoxes(state) {
+		b = b.Offset(pos[0], pos[1], pos[2])
+		box := aabb3d{Lower: vec3d(b.Min), Upper: vec3d(b.Max)}
+		for _, p := range w.players {
+			// Spectators go through blocks.
+			if p.Gamemode != 3 && boundingBox(p.Position, PlayerWidth, PlayerHeight).Touch(box) {
+				return true
+			}
+		}
+		obstructed := false
+		w.entitiesTouching(box, func(e *Entity) bool {
+			obstructed = e.Type.BlocksBuilding && e.Type != PlayerType
+			return !obstructed
+		})
+		if obstructed {
+			return true
+		}
+	}
+	return false
+}
+
+// boundingBox returns the box of an entity of the size standing at the position.
+func boundingBox(pos Position, width, height float64) aabb3d {
+	return aabb3d{
+		Lower: vec3d{pos[0] - width/2, pos[1], pos[2] - width/2},
+		Upper: vec3d{pos[0] + width/2, pos[1] + height, pos[2] + width/2},
+	}
+}
@@ -0,0 +1,67 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestWorld_obstructed(t *testing.T) {
+	stone := block.ToStateID[block.Stone{}]
+	p := &Player{Entity: Entity{Position: Position{10.5, 64, 10.5}}}
+	w := &World{players: map[Client]*Player{nil: p}}
+	for pos, want := range map[[3]int]bool{
+		{10, 64, 10}: true,
+		{10, 65, 10}: true,
+		{10, 66, 10}: false,
+		// The block the player stands on.
+		{10, 63, 10}: false,
+		{11, 64, 10}: false,
+	} {
+		if got := w.obstructed(pos, stone); got != want {
+			t.Errorf("obstructed(%v) = %v, want %v", pos, got, want)
+		}
+	}
+	// The player standing on a slab reaches into the lower half of the block above its head.
+	p.Position[1] = 64.5
+	for state, want := range map[block.Block]bool{
+		block.OakSlab{Type: block.SlabTypeBottom}: true,
+		block.OakSlab{Type: block.SlabTypeTop}:    false,
+		block.Poppy{}:                             false,
+	} {
+		if got := w.obstructed([3]int{10, 66, 10}, block.ToStateID[state]); got != want {
+			t.Errorf("obstructed by %v above the head = %v, want %v", state, got, want)
+		}
+	}
+	p.Gamemode = 3
+	if w.obstructed([3]int{10, 64, 10}, stone) {
+		t.Error("a spectator obstructs placing blocks")
+	}
+}
+
+func TestWorld_PlaceBlock_reach(t *testing.T) {
+	w, _, _ := physicsWorld(t)
+	c := new(damageClient)
+	p := damagedPlayer(Position{1.5, 65, 1.5})
+	w.players = map[Client]*Player{c: p}
+	place := func(x, z int) bool {
+		_, ok := w.PlaceBlock(c, block.PlaceContext{
+			Item: "minecraft:stone", Clicked: [3]int{x, 64, z}, Face: block.Up, Cursor: [3]float64{0.5, 1, 0.5},
+		})
+		return ok
+	}
+
+	if !place(5, 1) {
+		t.Error("the block 3 blocks away isn't placed")
+	}
+	if place(7, 1) {
+		t.Error("the block 5.7 blocks away is placed in survival mode")
+	}
+	p.Gamemode = 1
+	if !place(7, 1) {
+		t.Error("the block 5.7 blocks away isn't placed in creative mode")
+	}
+	if place(14, 14) {
+		t.Error("the block out of reach is placed in creative mode")
+	}
+}
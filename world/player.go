@@ -23,7 +23,9 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/mrhaoxx/go-mc/level/item"
 	pk "github.com/mrhaoxx/go-mc/net/packet"
+	"github.com/mrhaoxx/go-mc/world/container"
 	"github.com/mrhaoxx/go-mc/yggdrasil/user"
 )
 
@@ -54,18 +56,78 @@ type Player struct {
 	ChunkPos     [3]int32
 	ViewDistance int32
 
-	Gamemode       int32
-	EntitiesInView map[int32]*Entity
-	view           *playerViewNode
-	teleport       *TeleportRequest
+	Gamemode int32
+	// PermissionLevel is the operator level of the player, from 0 to 4.
+	PermissionLevel int
+	EntitiesInView  map[int32]*Entity
+	view            *playerViewNode
+	teleport        *TeleportRequest
+	// ackedSequence is the last block interaction sequence acknowledged to the client.
+	ackedSequence int32
+	// digging is the block the player is breaking.
+	digging digging
+	// Effects are the amplifiers of the mob effects on the player, keyed by name like minecraft:haste.
+	Effects map[string]int32
+	// AllowFlight lets the player fly outside creative and spectator mode.
+	AllowFlight bool
+	// Health is the health of the player, from 20 down to 0 when it's dead.
+	Health float32
+	// FoodLevel and Saturation are the hunger of the player, from 20 down to 0.
+	FoodLevel  int32
+	Saturation float32
+	// Air is the number of ticks the player can still breathe underwater, up to 300.
+	Air int32
+	// FireTicks is the number of ticks the player still burns.
+	FireTicks int32
+	// ExperienceLevel, ExperienceProgress toward the next level and TotalExperience are the experience of the player.
+	ExperienceLevel    int32
+	ExperienceProgress float32
+	TotalExperience    int32
+	// health is the state of the damage the player takes.
+	health health
+	// Dimension is the name of the world the player is in, or was in when it was saved.
+	Dimension string
+	// portal is the state of the player standing in portals.
+	portal portalState
+	// FallDistance is the height the player has fallen since it last stood on the ground.
+	FallDistance float64
+	// Violations are the numbers of illegal moves of the player, by kind.
+	Violations [violationKinds]int
+	// movement is the state of the checks of the moves of the player.
+	movement movement
 	// Currently selected hotbar slot (0-8)
 	CarriedSlot int32
 	// Player inventory: slots 0-8 are hotbar, 9-35 are main inventory, 36-39 are armor, 40 is offhand
-	Inventory [36]*ItemStack
+	Inventory container.PlayerInventory
+	// InventoryMenu is the window of the inventory, the client has it open when no other window is.
+	InventoryMenu *container.Menu
+	// ContainerMenu is the window open on the client.
+	ContainerMenu *container.Menu
+	// ContainerLock guards the Inventory and the menus, which are used by both the packet handlers and the world.
+	ContainerLock sync.Mutex
+	// drops are the stacks thrown by the player since the last tick, guarded by the ContainerLock.
+	drops []item.ItemStack
 
 	Inputs Inputs
 }
 
+// HasInfiniteMaterials reports whether the player is in creative mode.
+func (p *Player) HasInfiniteMaterials() bool { return p.Gamemode == 1 }
+
+// MayFly reports whether the player is allowed to fly.
+func (p *Player) MayFly() bool { return p.Gamemode == 1 || p.Gamemode == 3 || p.AllowFlight }
+
+// MayBuild reports whether the player can change the blocks, which adventure and spectator players can't.
+func (p *Player) MayBuild() bool { return p.Gamemode != 2 && p.Gamemode != 3 }
+
+// Drop throws the items out of the inventory of the player, toward where it looks.
+// It's called with the ContainerLock held, so the item entity is spawned by the world in its next tick.
+func (p *Player) Drop(s item.ItemStack) {
+	if !s.IsEmpty() {
+		p.drops = append(p.drops, s)
+	}
+}
+
 func (p *Player) chunkPosition() [2]int32 { return [2]int32{p.ChunkPos[0], p.ChunkPos[2]} }
 func (p *Player) chunkRadius() int32      { return p.ViewDistance }
 
@@ -92,6 +154,12 @@ type Inputs struct {
 	OnGround
 	Latency    time.Duration
 	TeleportID int32
+	// BlockSequence is the latest sequence number of the player's block interactions.
+	BlockSequence int32
+	// Sneaking reports whether the player holds the sneak key.
+	Sneaking bool
+	// Sprinting, Flying and FallFlying report whether the player sprints, flies and glides with an elytra.
+	Sprinting, Flying, FallFlying bool
 }
 
 type ClientInfo struct {
@@ -0,0 +1,214 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// Portal is a kind of portal the players travel through to another dimension.
+type Portal int
+
+const (
+	NoPortal Portal = iota
+	NetherPortal
+	EndPortal
+)
+
+const (
+	// netherPortalDelay is the number of ticks the players stand in a nether portal before traveling,
+	// the creative players travel right away.
+	netherPortalDelay = 80
+	// portalCooldown is the number of ticks after leaving the portals before the players can travel again,
+	// so that they don't travel back when they arrive in a portal.
+	portalCooldown = 20
+	// portalSearchRadius is the horizontal distance of the portals the players traveling through a nether portal
+	// arrive in, before one is built.
+	portalSearchRadius = 16
+)
+
+// portalState is the state of the player standing in portals.
+type portalState struct {
+	// ticks is the number of ticks the player has stood in a nether portal.
+	ticks    int32
+	cooldown int32
+	// pending is the portal the player travels through, which it entered at the position from.
+	pending Portal
+	from    Position
+}
+
+// subtickPortals makes the players who stood long enough in a portal travel through it,
+// which the game does when it takes the travel with TakePortal.
+func (w *World) subtickPortals() {
+	for _, p := range w.players {
+		s := &p.portal
+		kind := NoPortal
+		if !p.Dead() {
+			kind = w.touchedPortal(shrink(boundingBox(p.pos0, PlayerWidth, PlayerHeight), 1e-3))
+		}
+		switch {
+		case kind == NoPortal:
+			s.ticks = 0
+			s.cooldown = max(s.cooldown-1, 0)
+		case s.pending != NoPortal:
+		case s.cooldown > 0:
+			s.cooldown = portalCooldown
+		case kind == NetherPortal && !p.HasInfiniteMaterials() && s.ticks < netherPortalDelay:
+			s.ticks++
+		default:
+			s.pending, s.from = kind, p.pos0
+			s.ticks, s.cooldown = 0, portalCooldown
+		}
+	}
+}
+
+// TakePortal returns the portal the player of the client travels through and the position where it entered it,
+// NoPortal if it isn't traveling. The travel is forgotten, the caller moves the player to the other dimension.
+func (w *World) TakePortal(c Client) (Portal, Position) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p, ok := w.players[c]
+	if !ok {
+		return NoPortal, Position{}
+	}
+	kind, from := p.portal.pending, p.portal.from
+	p.portal.pending = NoPortal
+	return kind, from
+}
+
+// touchedPortal returns the kind of the portal blocks in the box.
+func (w *World) touchedPortal(box aabb3d) Portal {
+	for x := int(math.Floor(box.Lower[0])); x <= int(math.Floor(box.Upper[0])); x++ {
+		for y := int(math.Floor(box.Lower[1])); y <= int(math.Floor(box.Upper[1])); y++ {
+			for z := int(math.Floor(box.Lower[2])); z <= int(math.Floor(box.Upper[2])); z++ {
+				s, ok := w.getBlock(x, y, z)
+				if !ok {
+					continue
+				}
+				switch block.StateList[s].(type) {
+				case block.NetherPortal:
+					return NetherPortal
+				case block.EndPortal:
+					return EndPortal
+				}
+			}
+		}
+	}
+	return NoPortal
+}
+
+// NetherPortalExit returns where a player traveling through a nether portal arrives near the position:
+// in the nearest nether portal within portalSearchRadius blocks, or in a portal built there like vanilla.
+func (w *World) NetherPortalExit(pos Position) Position {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	x, z := int(math.Floor(pos[0])), int(math.Floor(pos[2]))
+	w.loadArea(x, z, portalSearchRadius)
+	if exit, ok := w.findNetherPortal(x, int(pos[1]), z); ok {
+		return exit
+	}
+	return w.buildNetherPortal(x, int(pos[1]), z)
+}
+
+// EndPlatform builds the obsidian platform under the spawn of the world, where the players traveling through an end portal
+// arrive, and returns the position on it.
+func (w *World) EndPlatform() Position {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	x, y, z := int(w.config.SpawnPosition[0]), int(w.config.SpawnPosition[1]), int(w.config.SpawnPosition[2])
+	w.loadArea(x, z, 2)
+	obsidian := level.BlocksState(block.ToStateID[block.Obsidian{}])
+	air := level.BlocksState(block.ToStateID[block.Air{}])
+	for dx := -2; dx <= 2; dx++ {
+		for dz := -2; dz <= 2; dz++ {
+			w.setBlock(x+dx, y-1, z+dz, obsidian)
+			for dy := range 3 {
+				w.setBlock(x+dx, y+dy, z+dz, air)
+			}
+		}
+	}
+	return Position{float64(x) + 0.5, float64(y), float64(z) + 0.5}
+}
+
+// loadArea loads the chunks of the blocks within the horizontal distance of the column, when the chunk provider allows it.
+func (w *World) loadArea(x, z, radius int) {
+	for cx := (x - radius) >> 4; cx <= (x+radius)>>4; cx++ {
+		for cz := (z - radius) >> 4; cz <= (z+radius)>>4; cz++ {
+			if pos := [2]int32{int32(cx), int32(cz)}; w.chunks[pos] == nil {
+				w.loadChunk(pos)
+			}
+		}
+	}
+}
+
+// findNetherPortal returns the bottom of the nether portal nearest to the block within portalSearchRadius.
+func (w *World) findNetherPortal(x, y, z int) (Position, bool) {
+	var exit Position
+	best := math.MaxInt
+	for bx := x - portalSearchRadius; bx <= x+portalSearchRadius; bx++ {
+		for bz := z - portalSearchRadius; bz <= z+portalSearchRadius; bz++ {
+			below := false
+			for by := worldMinY; by <= worldMaxY; by++ {
+				s, ok := w.getBlock(bx, by, bz)
+				if !ok {
+					break
+				}
+				_, portal := block.StateList[s].(block.NetherPortal)
+				if d := (bx-x)*(bx-x) + (by-y)*(by-y) + (bz-z)*(bz-z); portal && !below && d < best {
+					best, exit = d, Position{float64(bx) + 0.5, float64(by), float64(bz) + 0.5}
+				}
+				below = portal
+			}
+		}
+	}
+	return exit, best != math.MaxInt
+}
+
+// buildNetherPortal builds a nether portal along the x-axis on the ground of the column,
+// with an obsidian frame and ledges on both sides, and returns the position in it.
+// The player is sent to the position as is if the chunk isn't loaded.
+func (w *World) buildNetherPortal(x, y, z int) Position {
+	exit := Position{float64(x) + 1, float64(y), float64(z) + 0.5}
+	ground, ok := w.heightAt(x, z, level.MotionBlocking)
+	if !ok {
+		return exit
+	}
+	y = min(max(ground, worldMinY+1), worldMaxY-3)
+	obsidian := level.BlocksState(block.ToStateID[block.Obsidian{}])
+	portal := level.BlocksState(block.ToStateID[block.NetherPortal{Axis: block.X}])
+	air := level.BlocksState(block.ToStateID[block.Air{}])
+	for dx := -1; dx <= 2; dx++ {
+		for dy := -1; dy <= 3; dy++ {
+			if dx == -1 || dx == 2 || dy == -1 || dy == 3 {
+				w.setBlock(x+dx, y+dy, z, obsidian)
+				continue
+			}
+			w.setBlock(x+dx, y+dy, z, portal)
+			for _, dz := range [2]int{-1, 1} {
+				w.setBlock(x+dx, y+dy, z+dz, air)
+				if dy == 0 {
+					w.setBlock(x+dx, y-1, z+dz, obsidian)
+				}
+			}
+		}
+	}
+	exit[1] = float64(y)
+	return exit
+}
@@ -0,0 +1,84 @@
+package world
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/world/generator"
+)
+
+func TestWorld_subtickPortals(t *testing.T) {
+	w, lc, _ := physicsWorld(t)
+	portal := level.BlocksState(block.ToStateID[block.NetherPortal{Axis: block.X}])
+	lc.SetBlock(8, 65, 8, portal)
+	lc.SetBlock(8, 66, 8, portal)
+	c := new(damageClient)
+	p := damagedPlayer(Position{8.5, 65, 8.5})
+	w.players = map[Client]*Player{c: p}
+
+	for range netherPortalDelay {
+		w.subtickPortals()
+	}
+	if kind, _ := w.TakePortal(c); kind != NoPortal {
+		t.Fatalf("the player travels through %v before the delay", kind)
+	}
+	w.subtickPortals()
+	if kind, from := w.TakePortal(c); kind != NetherPortal || from != p.pos0 {
+		t.Fatalf("TakePortal after the delay = %v, %v, want the nether portal at %v", kind, from, p.pos0)
+	}
+	if kind, _ := w.TakePortal(c); kind != NoPortal {
+		t.Fatal("the travel is taken twice")
+	}
+	// The player arriving in a portal doesn't travel back until it leaves it.
+	for range 2 * netherPortalDelay {
+		w.subtickPortals()
+	}
+	if kind, _ := w.TakePortal(c); kind != NoPortal {
+		t.Fatal("the player travels back without leaving the portal")
+	}
+}
+
+func TestWorld_NetherPortalExit(t *testing.T) {
+	gen, err := generator.ParseFlat(generator.DefaultFlatLayers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &World{
+		log:           zap.NewNop(),
+		chunkProvider: NewProvider(t.TempDir(), rate.NewLimiter(rate.Inf, 1)),
+		generator:     gen,
+		chunks:        make(map[[2]int32]*LoadedChunk),
+	}
+
+	// Without a portal nearby, one is built on the ground.
+	exit := w.NetherPortalExit(Position{100.5, 70, -20.5})
+	ground, ok := w.heightAt(110, -30, level.MotionBlocking)
+	if !ok {
+		t.Fatal("the chunks around the exit aren't loaded")
+	}
+	if want := (Position{101, float64(ground), -20.5}); exit != want {
+		t.Fatalf("exit of the built portal = %v, want %v", exit, want)
+	}
+	for _, pos := range [][3]int{{100, ground, -21}, {101, ground + 2, -21}} {
+		if s, _ := w.getBlock(pos[0], pos[1], pos[2]); !isNetherPortal(s) {
+			t.Errorf("block %v of the built portal is %v", pos, block.StateList[s])
+		}
+	}
+	if s, _ := w.getBlock(99, ground, -21); block.StateList[s] != (block.Obsidian{}) {
+		t.Errorf("frame of the built portal is %v", block.StateList[s])
+	}
+
+	// The players traveling later arrive in the same portal.
+	if again := w.NetherPortalExit(Position{110, 90, -10}); again != (Position{101.5, exit[1], -20.5}) {
+		t.Errorf("exit near the portal = %v, want the portal at %v", again, exit)
+	}
+}
+
+func isNetherPortal(s level.BlocksState) bool {
+	_, ok := block.StateList[s].(block.NetherPortal)
+	return ok
+}
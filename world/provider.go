@@ -18,8 +18,10 @@ package world
 
 import (
 	"compress/gzip"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -28,7 +30,10 @@ import (
 
 	"github.com/mrhaoxx/go-mc/hpcworld"
 	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
 	"github.com/mrhaoxx/go-mc/save"
+	"github.com/mrhaoxx/go-mc/save/region"
+	"github.com/mrhaoxx/go-mc/world/container"
 	"github.com/mrhaoxx/go-mc/yggdrasil/user"
 )
 
@@ -44,75 +49,131 @@ func NewProvider(dir string, limiter *rate.Limiter) ChunkProvider {
 
 var ErrReachRateLimit = errors.New("reach rate limit")
 
-func (p *ChunkProvider) GetChunk(pos [2]int32) (c *level.Chunk, errRet error) {
-
-	hpcworld.LoadChunk(pos[0], pos[1])
-
-	// fmt.Println("GetChunk", pos)
-	// if !p.limiter.Allow() {
-	// 	return nil, ErrReachRateLimit
-	// }
-	// r, err := p.getRegion(region.At(int(pos[0]), int(pos[1])))
-	// if err != nil {
-	// 	return nil, fmt.Errorf("open region fail: %w", err)
-	// }
-	// defer func(r *region.Region) {
-	// 	err2 := r.Close()
-	// 	if errRet == nil && err2 != nil {
-	// 		errRet = fmt.Errorf("close region fail: %w", err2)
-	// 	}
-	// }(r)
-
-	// x, z := region.In(int(pos[0]), int(pos[1]))
-	// if !r.ExistSector(x, z) {
-	// 	return nil, errChunkNotExist
-	// }
-
-	// data, err := r.ReadSector(x, z)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("read sector fail: %w", err)
-	// }
-
-	// var chunk save.Chunk
-	// if err := chunk.Load(data); err != nil {
-	// 	return nil, fmt.Errorf("parse chunk data fail: %w", err)
-	// }
-
-	// c, err = level.EmptyChunk(24)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("load chunk data fail: %w", err)
-	// }
-	return nil, errChunkNotExist
+// dataVersion is the DataVersion of Minecraft 1.21.4, written to every saved chunk.
+const dataVersion = 4189
+
+// minSectionY is the section Y of the lowest section in the world.
+const minSectionY = -4
+
+func (p *ChunkProvider) getRegion(rx, rz int, create bool) (*region.Region, error) {
+	filename := filepath.Join(p.dir, fmt.Sprintf("r.%d.%d.mca", rx, rz))
+	r, err := region.Open(filename)
+	if errors.Is(err, os.ErrNotExist) && create {
+		if err := os.MkdirAll(p.dir, 0o755); err != nil {
+			return nil, err
+		}
+		return region.Create(filename)
+	}
+	return r, err
+}
+
+// ChunkData is what's stored with the blocks of a chunk.
+type ChunkData struct {
+	// BlockEntities are keyed by the world coordinates.
+	BlockEntities map[[3]int32]block.Entity
+	// BlockTicks and FluidTicks are the scheduled ticks, whose delays count from when the chunk is stored.
+	BlockTicks, FluidTicks []save.ScheduledTick
+}
+
+// GetChunk reads the chunk and the data stored with it.
+func (p *ChunkProvider) GetChunk(pos [2]int32) (c *hpcworld.Chunk, data ChunkData, errRet error) {
+	if !p.limiter.Allow() {
+		return nil, data, ErrReachRateLimit
+	}
+	rx, rz := region.At(int(pos[0]), int(pos[1]))
+	r, err := p.getRegion(rx, rz, false)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, data, errChunkNotExist
+	} else if err != nil {
+		return nil, data, fmt.Errorf("open region fail: %w", err)
+	}
+	defer func(r *region.Region) {
+		err2 := r.Close()
+		if errRet == nil && err2 != nil {
+			errRet = fmt.Errorf("close region fail: %w", err2)
+		}
+	}(r)
+
+	x, z := region.In(int(pos[0]), int(pos[1]))
+	if !r.ExistSector(x, z) {
+		return nil, data, errChunkNotExist
+	}
+
+	sector, err := r.ReadSector(x, z)
+	if err != nil {
+		return nil, data, fmt.Errorf("read sector fail: %w", err)
+	}
+
+	var chunk save.Chunk
+	if err := chunk.Load(sector); err != nil {
+		return nil, data, fmt.Errorf("parse chunk data fail: %w", err)
+	}
+
+	// Vanilla also stores light-only sections just below and above the build height.
+	sections := chunk.Sections[:0]
+	for _, s := range chunk.Sections {
+		if i := int32(s.Y) - chunk.YPos; i >= 0 && i < int32(len(hpcworld.Chunk{}.Sections)) {
+			sections = append(sections, s)
+		}
+	}
+	chunk.Sections = sections
+
+	lc, err := level.ChunkFromSave(&chunk)
+	if err != nil {
+		return nil, data, fmt.Errorf("load chunk data fail: %w", err)
+	}
+	data.BlockEntities, err = blockEntitiesFromSave(lc.BlockEntity)
+	if err != nil {
+		return nil, data, fmt.Errorf("load block entities fail: %w", err)
+	}
+	data.BlockTicks, data.FluidTicks = chunk.BlockTicks, chunk.FluidTicks
+	return hpcworld.ChunkFromLevel(lc), data, nil
 }
 
-func (p *ChunkProvider) PutChunk(pos [2]int32, c *level.Chunk) (err error) {
-	//var chunk save.Chunk
-	//err = level.ChunkToSave(c, &chunk)
-	//if err != nil {
-	//	return fmt.Errorf("encode chunk data fail: %w", err)
-	//}
-	//
-	//data, err := chunk.Data(1)
-	//if err != nil {
-	//	return fmt.Errorf("record chunk data fail: %w", err)
-	//}
-	//
-	//r, err := p.getRegion(region.At(int(pos[0]), int(pos[1])))
-	//if err != nil {
-	//	return fmt.Errorf("open region fail: %w", err)
-	//}
-	//defer func(r *region.Region) {
-	//	err2 := r.Close()
-	//	if err == nil && err2 != nil {
-	//		err = fmt.Errorf("open region fail: %w", err)
-	//	}
-	//}(r)
-	//
-	//x, z := region.In(int(pos[0]), int(pos[1]))
-	//err = r.WriteSector(x, z, data)
-	//if err != nil {
-	//	return fmt.Errorf("write sector fail: %w", err)
-	//}
+// PutChunk stores the chunk and the data stored with it.
+func (p *ChunkProvider) PutChunk(pos [2]int32, c *hpcworld.Chunk, data ChunkData) (err error) {
+	lc := hpcworld.LevelChunkFromHPC(c)
+	lc.Status = level.StatusFull
+	lc.BlockEntity, err = blockEntitiesToSave(data.BlockEntities)
+	if err != nil {
+		return fmt.Errorf("encode block entities fail: %w", err)
+	}
+
+	chunk := save.Chunk{
+		DataVersion: dataVersion,
+		XPos:        pos[0],
+		YPos:        minSectionY,
+		ZPos:        pos[1],
+		BlockTicks:  data.BlockTicks,
+		FluidTicks:  data.FluidTicks,
+	}
+	err = level.ChunkToSave(lc, &chunk)
+	if err != nil {
+		return fmt.Errorf("encode chunk data fail: %w", err)
+	}
+
+	sector, err := chunk.Data(2)
+	if err != nil {
+		return fmt.Errorf("record chunk data fail: %w", err)
+	}
+
+	rx, rz := region.At(int(pos[0]), int(pos[1]))
+	r, err := p.getRegion(rx, rz, true)
+	if err != nil {
+		return fmt.Errorf("open region fail: %w", err)
+	}
+	defer func(r *region.Region) {
+		err2 := r.Close()
+		if err == nil && err2 != nil {
+			err = fmt.Errorf("close region fail: %w", err2)
+		}
+	}(r)
+
+	x, z := region.In(int(pos[0]), int(pos[1]))
+	err = r.WriteSector(x, z, sector)
+	if err != nil {
+		return fmt.Errorf("write sector fail: %w", err)
+	}
 
 	return nil
 }
@@ -167,6 +228,109 @@ func (p *PlayerProvider) GetPlayer(name string, id uuid.UUID, pubKey *user.Publi
 		Gamemode:       data.PlayerGameType,
 		EntitiesInView: make(map[int32]*Entity),
 		ViewDistance:   10,
+		CarriedSlot:    data.SelectedItemSlot,
+		Dimension:      data.Dimension,
+
+		Health:             data.Health,
+		FoodLevel:          data.FoodLevel,
+		Saturation:         data.FoodSaturationLevel,
+		Air:                int32(data.Air),
+		FireTicks:          max(int32(data.Fire), 0),
+		FallDistance:       float64(data.FallDistance),
+		ExperienceLevel:    data.XpLevel,
+		ExperienceProgress: data.XpP,
+		TotalExperience:    data.XpTotal,
+	}
+	for _, item := range data.Inventory {
+		// In the player data, the armor is stored at 100-103 and the offhand at -106.
+		switch slot := int8(item.Slot); {
+		case slot >= 0 && slot < container.InventorySize:
+			player.Inventory[slot] = item.ItemStack
+		case slot >= 100 && slot < 104:
+			player.Inventory[container.ArmorSlot+int(slot)-100] = item.ItemStack
+		case slot == -106:
+			player.Inventory[container.OffhandSlot] = item.ItemStack
+		}
 	}
 	return
 }
+
+// PutPlayer stores the player in the <uuid>.dat file of the player data, replacing the previous one once it's written.
+// It must not be called while the player is in a world unless the world is locked, like by SavePlayers.
+func (p *PlayerProvider) PutPlayer(player *Player) (err error) {
+	data := save.PlayerData{
+		DataVersion:         dataVersion,
+		Dimension:           player.Dimension,
+		Pos:                 player.Position,
+		Rotation:            player.Rotation,
+		FallDistance:        float32(player.FallDistance),
+		PlayerGameType:      player.Gamemode,
+		Air:                 int16(player.Air),
+		Fire:                int16(min(player.FireTicks, math.MaxInt16)),
+		Health:              player.Health,
+		SelectedItemSlot:    player.CarriedSlot,
+		XpLevel:             player.ExperienceLevel,
+		XpP:                 player.ExperienceProgress,
+		XpTotal:             player.TotalExperience,
+		FoodLevel:           player.FoodLevel,
+		FoodSaturationLevel: player.Saturation,
+	}
+	if player.OnGround {
+		data.OnGround = 1
+	}
+	for i := range data.UUID {
+		data.UUID[i] = int32(binary.BigEndian.Uint32(player.UUID[i*4:]))
+	}
+	data.Abilities.WalkSpeed, data.Abilities.FlySpeed = 0.1, 0.05
+	data.Abilities.MayBuild = boolByte(player.MayBuild())
+	data.Abilities.InstantBuild = boolByte(player.HasInfiniteMaterials())
+	data.Abilities.MayFly = boolByte(player.Gamemode == 1 || player.Gamemode == 3)
+	data.Abilities.Invulnerable = data.Abilities.MayFly
+	player.ContainerLock.Lock()
+	for i, stack := range player.Inventory {
+		if stack.IsEmpty() {
+			continue
+		}
+		// In the player data, the armor is stored at 100-103 and the offhand at -106.
+		var slot int8
+		switch {
+		case i < container.InventorySize:
+			slot = int8(i)
+		case i < container.ArmorSlot+4:
+			slot = int8(100 + i - container.ArmorSlot)
+		default:
+			slot = -106
+		}
+		data.Inventory = append(data.Inventory, save.Item{Slot: byte(slot), ItemStack: stack.Copy()})
+	}
+	player.ContainerLock.Unlock()
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(p.dir, player.UUID.String()+"-*.dat")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+	w := gzip.NewWriter(f)
+	if err := save.WritePlayerData(w, &data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write player data fail: %w", err)
+	}
+	if err := errors.Join(w.Close(), f.Close()); err != nil {
+		return fmt.Errorf("close player data fail: %w", err)
+	}
+	return os.Rename(f.Name(), filepath.Join(p.dir, player.UUID.String()+".dat"))
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/mrhaoxx/go-mc/level/component"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/world/container"
+)
+
+func TestPlayerProvider_PutPlayer(t *testing.T) {
+	provider := NewPlayerProvider(t.TempDir())
+	id := uuid.New()
+	p := damagedPlayer(Position{1.5, 70, -3.25})
+	p.UUID, p.Rotation = id, Rotation{90, -10}
+	p.Dimension, p.Gamemode, p.CarriedSlot = "minecraft:the_nether", 0, 4
+	p.Health, p.FoodLevel, p.ExperienceLevel, p.TotalExperience = 12.5, 17, 3, 40
+	var sword, helmet item.ID
+	if err := sword.UnmarshalText([]byte("minecraft:iron_sword")); err != nil {
+		t.Fatal(err)
+	}
+	if err := helmet.UnmarshalText([]byte("minecraft:iron_helmet")); err != nil {
+		t.Fatal(err)
+	}
+	damaged := item.New(sword, 1)
+	damaged.Set(&component.Damage{VarInt: 7})
+	p.Inventory[4] = damaged
+	p.Inventory[container.ArmorSlot+3] = item.New(helmet, 1)
+	p.Inventory[container.OffhandSlot] = item.New(1, 32)
+
+	if err := provider.PutPlayer(p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := provider.GetPlayer(p.Name, id, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Position != p.Position || got.Rotation != p.Rotation || got.Dimension != p.Dimension ||
+		got.Gamemode != 0 || got.CarriedSlot != 4 {
+		t.Errorf("the player is read at %v %v in %s, gamemode %d, slot %d",
+			got.Position, got.Rotation, got.Dimension, got.Gamemode, got.CarriedSlot)
+	}
+	if got.Health != 12.5 || got.FoodLevel != 17 || got.ExperienceLevel != 3 || got.TotalExperience != 40 {
+		t.Errorf("the player is read with %v health, %d food, level %d and %d experience",
+			got.Health, got.FoodLevel, got.ExperienceLevel, got.TotalExperience)
+	}
+	for i := range p.Inventory {
+		if !item.Equal(got.Inventory[i], p.Inventory[i]) {
+			t.Errorf("the slot %d is read as %v, want %v", i, got.Inventory[i], p.Inventory[i])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"math"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+// PlayerEyeHeight is the height of the eyes of a standing player above its position.
+const PlayerEyeHeight = 1.62
+
+// LookVector returns the unit vector of the direction an entity with the rotation is facing.
+func LookVector(rot Rotation) [3]float64 {
+	const rad = math.Pi / 180
+	yaw, pitch := float64(rot[0])*rad, float64(rot[1])*rad
+	return [3]float64{
+		-math.Sin(yaw) * math.Cos(pitch),
+		-math.Sin(pitch),
+		math.Cos(yaw) * math.Cos(pitch),
+	}
+}
+
+// Raycast returns the first block crossed by the ray from the position in the direction within the distance,
+// and the point where the ray enters it. Air and fluids are passed through,
+// and the ray stops at unloaded chunks or the limits of the world.
+func (w *World) Raycast(from, dir [3]float64, distance float64) (hit [3]float64, pos [3]int, ok bool) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+
+	length := math.Sqrt(dir[0]*dir[0] + dir[1]*dir[1] + dir[2]*dir[2])
+	if length == 0 {
+		return hit, pos, false
+	}
+	// Amanatides & Woo voxel traversal.
+	var step [3]int
+	var tMax, tDelta [3]float64
+	for i := range dir {
+		dir[i] /= length
+		pos[i] = int(math.Floor(from[i]))
+		switch {
+		case dir[i] > 0:
+			step[i] = 1
+			tMax[i] = (float64(pos[i]) + 1 - from[i]) / dir[i]
+			tDelta[i] = 1 / dir[i]
+		case dir[i] < 0:
+			step[i] = -1
+			tMax[i] = (from[i] - float64(pos[i])) / -dir[i]
+			tDelta[i] = -1 / dir[i]
+		default:
+			tMax[i], tDelta[i] = math.Inf(1), math.Inf(1)
+		}
+	}
+	for t := 0.0; t <= distance; {
+		lc, loaded := w.chunks[[2]int32{int32(pos[0] >> 4), int32(pos[2] >> 4)}]
+		if !loaded || pos[1] < worldMinY || pos[1] > worldMaxY {
+			return hit, pos, false
+		}
+		if s := lc.GetBlock(pos[0], pos[1], pos[2]); isSolidTarget(s) {
+			for i := range hit {
+				hit[i] = from[i] + dir[i]*t
+			}
+			return hit, pos, true
+		}
+		axis := 0
+		if tMax[1] < tMax[axis] {
+			axis = 1
+		}
+		if tMax[2] < tMax[axis] {
+			axis = 2
+		}
+		t = tMax[axis]
+		pos[axis] += step[axis]
+		tMax[axis] += tDelta[axis]
+	}
+	return hit, pos, false
+}
+
+// isSolidTarget reports whether the ray of Raycast stops at the block.
+func isSolidTarget(s block.StateID) bool {
+	switch block.StateList[s].(type) {
+	case block.Air, block.CaveAir, block.VoidAir, block.Water, block.Lava, block.BubbleColumn:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,26 @@
+package world
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWorld_Raycast(t *testing.T) {
+	w := newLightTestWorld(t)
+	// Looking down at 45 degrees towards +z from 3 blocks above the ground at y=-61.
+	from := [3]float64{0.5, -57, 0.5}
+	hit, pos, ok := w.Raycast(from, LookVector(Rotation{0, 45}), 10)
+	if !ok {
+		t.Fatal("the ray should hit the ground")
+	}
+	if pos != [3]int{0, -61, 3} {
+		t.Errorf("hit block %v, want [0 -61 3]", pos)
+	}
+	if math.Abs(hit[1]-(-60)) > 1e-9 || math.Abs(hit[2]-3.5) > 1e-9 {
+		t.Errorf("hit point %v, want the top face at z=3.5", hit)
+	}
+
+	if _, _, ok := w.Raycast(from, LookVector(Rotation{0, -90}), 10); ok {
+		t.Error("the ray to the sky shouldn't hit any block")
+	}
+}
@@ -0,0 +1,267 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"container/heap"
+	"slices"
+
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/save"
+)
+
+const (
+	// maxScheduledTicks is the number of block ticks, and of fluid ticks, run in a game tick, like vanilla.
+	// The remaining ones are run in the next game ticks.
+	maxScheduledTicks = 65536
+	// defaultRandomTickSpeed is the vanilla default of the randomTickSpeed gamerule.
+	defaultRandomTickSpeed = 3
+)
+
+// scheduledTick is a tick of a block or a fluid at a position.
+type scheduledTick struct {
+	pos [3]int
+	id  string
+	// due is the game tick when it runs.
+	due      uint
+	priority block.TickPriority
+	// seq is the order it's scheduled in, which orders the ticks of the same game tick and priority.
+	seq uint64
+}
+
+func (t scheduledTick) less(o scheduledTick) bool {
+	if t.due != o.due {
+		return t.due < o.due
+	}
+	if t.priority != o.priority {
+		return t.priority < o.priority
+	}
+	return t.seq < o.seq
+}
+
+type tickKey struct {
+	pos [3]int
+	id  string
+}
+
+// tickHeap implements heap.Interface for the scheduled ticks, the next one first.
+type tickHeap []scheduledTick
+
+func (h tickHeap) Len() int           { return len(h) }
+func (h tickHeap) Less(i, j int) bool { return h[i].less(h[j]) }
+func (h tickHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *tickHeap) Push(x any)        { *h = append(*h, x.(scheduledTick)) }
+func (h *tickHeap) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return t
+}
+
+// tickQueue is the scheduled block or fluid ticks of a chunk.
+type tickQueue struct {
+	ticks tickHeap
+	// scheduled are the positions and ids of the ticks, which are scheduled only once.
+	scheduled map[tickKey]struct{}
+}
+
+// schedule adds the tick unless one of the same id is already scheduled at the position,
+// and reports whether it's added.
+func (q *tickQueue) schedule(t scheduledTick) bool {
+	key := tickKey{t.pos, t.id}
+	if _, ok := q.scheduled[key]; ok {
+		return false
+	}
+	if q.scheduled == nil {
+		q.scheduled = make(map[tickKey]struct{})
+	}
+	q.scheduled[key] = struct{}{}
+	heap.Push(&q.ticks, t)
+	return true
+}
+
+// popDue removes and returns the ticks due by the game tick now.
+func (q *tickQueue) popDue(now uint, due []scheduledTick) []scheduledTick {
+	for len(q.ticks) > 0 && q.ticks[0].due <= now {
+		t := heap.Pop(&q.ticks).(scheduledTick)
+		delete(q.scheduled, tickKey{t.pos, t.id})
+		due = append(due, t)
+	}
+	return due
+}
+
+// toSave returns the ticks in the order they run, with their delays from the game tick now.
+func (q *tickQueue) toSave(now uint) []save.ScheduledTick {
+	ticks := slices.Clone(q.ticks)
+	slices.SortFunc(ticks, func(a, b scheduledTick) int {
+		if a.less(b) {
+			return -1
+		}
+		return 1
+	})
+	saved := make([]save.ScheduledTick, len(ticks))
+	for i, t := range ticks {
+		saved[i] = save.ScheduledTick{
+			ID:       t.id,
+			X:        int32(t.pos[0]),
+			Y:        int32(t.pos[1]),
+			Z:        int32(t.pos[2]),
+			Delay:    int32(int(t.due) - int(now)),
+			Priority: int32(t.priority),
+		}
+	}
+	return saved
+}
+
+// scheduleTick schedules a block or fluid tick in the chunk at the position, if it's loaded.
+func (w *World) scheduleTick(fluid bool, pos [3]int, id string, delay int, priority block.TickPriority) {
+	lc, ok := w.chunks[[2]int32{int32(pos[0] >> 4), int32(pos[2] >> 4)}]
+	if !ok || pos[1] < worldMinY || pos[1] > worldMaxY {
+		return
+	}
+	w.tickSeq++
+	t := scheduledTick{pos: pos, id: id, due: w.tickCount + uint(max(delay, 0)), priority: priority, seq: w.tickSeq}
+	q := &lc.blockTicks
+	if fluid {
+		q = &lc.fluidTicks
+	}
+	lc.Lock()
+	// the ticks are saved with the chunk, like vanilla marks it unsaved
+	if q.schedule(t) {
+		lc.dirty = true
+	}
+	lc.Unlock()
+}
+
+// loadTicks schedules the ticks read from the save files in the chunk, in their order.
+func (w *World) loadTicks(q *tickQueue, ticks []save.ScheduledTick) {
+	for _, t := range ticks {
+		w.tickSeq++
+		q.schedule(scheduledTick{
+			pos:      [3]int{int(t.X), int(t.Y), int(t.Z)},
+			id:       t.ID,
+			due:      w.tickCount + uint(max(t.Delay, 0)),
+			priority: block.TickPriority(t.Priority),
+			seq:      w.tickSeq,
+		})
+	}
+}
+
+// subtickScheduledTicks runs the block ticks due, then the fluid ticks due, in the order of their time,
+// priority and scheduling. A block tick is dropped if the block has changed to another one.
+func (w *World) subtickScheduledTicks() {
+	l := blockUpdater{w: w}
+	w.runDueTicks(func(lc *LoadedChunk) *tickQueue { return &lc.blockTicks }, func(t scheduledTick) {
+		if s, ok := l.GetBlock(t.pos[0], t.pos[1], t.pos[2]); ok && block.StateList[s].ID() == t.id {
+			block.Tick(l, t.pos, s, w.rand)
+		}
+	})
+	w.runDueTicks(func(lc *LoadedChunk) *tickQueue { return &lc.fluidTicks }, func(t scheduledTick) {
+		if s, ok := l.GetBlock(t.pos[0], t.pos[1], t.pos[2]); ok {
+			block.FluidTick(l, t.pos, t.id, s, w.rand)
+		}
+	})
+}
+
+// runDueTicks runs up to maxScheduledTicks of the due ticks of the queues of the chunks,
+// and puts the other ones back.
+func (w *World) runDueTicks(queue func(lc *LoadedChunk) *tickQueue, run func(t scheduledTick)) {
+	var due []scheduledTick
+	for _, lc := range w.chunks {
+		lc.Lock()
+		n := len(due)
+		if due = queue(lc).popDue(w.tickCount, due); len(due) > n {
+			lc.dirty = true
+		}
+		lc.Unlock()
+	}
+	slices.SortFunc(due, func(a, b scheduledTick) int {
+		if a.less(b) {
+			return -1
+		}
+		return 1
+	})
+	if len(due) > maxScheduledTicks {
+		for _, t := range due[maxScheduledTicks:] {
+			lc := w.chunks[[2]int32{int32(t.pos[0] >> 4), int32(t.pos[2] >> 4)}]
+			lc.Lock()
+			queue(lc).schedule(t)
+			lc.Unlock()
+		}
+		due = due[:maxScheduledTicks]
+	}
+	for _, t := range due {
+		run(t)
+	}
+}
+
+// subtickRandomTicks ticks randomTickSpeed random blocks in every non-empty section of the chunks viewed by players.
+// Like vanilla, only the blocks with a random tick handler are ticked.
+func (w *World) subtickRandomTicks() {
+	if w.randomTickSpeed <= 0 {
+		return
+	}
+	type randomTick struct {
+		pos   [3]int
+		state block.StateID
+	}
+	var ticks []randomTick
+	for _, lc := range w.chunks {
+		if len(lc.viewers) == 0 {
+			continue
+		}
+		lc.Lock()
+		for i := range lc.Chunk.Sections {
+			if lc.Chunk.Sections[i].Blockcount == 0 {
+				continue
+			}
+			for range w.randomTickSpeed {
+				pos := [3]int{
+					int(lc.Pos[0])<<4 | w.rand.Intn(16),
+					worldMinY + i<<4 + w.rand.Intn(16),
+					int(lc.Pos[1])<<4 | w.rand.Intn(16),
+				}
+				if s := block.StateID(lc.getBlock(pos[0], pos[1], pos[2])); block.IsRandomlyTicking(s) {
+					ticks = append(ticks, randomTick{pos, s})
+				}
+			}
+		}
+		lc.Unlock()
+	}
+	l := blockUpdater{w: w}
+	for _, t := range ticks {
+		// The block may have been changed by a previous tick.
+		if s, ok := l.GetBlock(t.pos[0], t.pos[1], t.pos[2]); ok && s == t.state {
+			block.RandomTick(l, t.pos, s, w.rand)
+		}
+	}
+}
+
+// RandomTickSpeed returns the randomTickSpeed gamerule,
+// the number of blocks randomly ticked per section in a game tick.
+func (w *World) RandomTickSpeed() int {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	return w.randomTickSpeed
+}
+
+// SetRandomTickSpeed sets the randomTickSpeed gamerule. Random ticks are disabled when it isn't positive.
+func (w *World) SetRandomTickSpeed(speed int) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	w.randomTickSpeed = speed
+}
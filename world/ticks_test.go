@@ -0,0 +1,106 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/save"
+)
+
+func TestTickQueue(t *testing.T) {
+	var q tickQueue
+	w := &World{tickCount: 100}
+	w.loadTicks(&q, []save.ScheduledTick{
+		{ID: "minecraft:sand", X: 1, Y: 64, Z: 1, Delay: 3},
+		{ID: "minecraft:water", X: 2, Y: 64, Z: 1, Delay: 1},
+		{ID: "minecraft:sand", X: 3, Y: 64, Z: 1, Delay: 1, Priority: int32(block.TickPriorityHigh)},
+		{ID: "minecraft:gravel", X: 4, Y: 64, Z: 1, Delay: 1},
+		// Ticks are scheduled once.
+		{ID: "minecraft:water", X: 2, Y: 64, Z: 1, Delay: 2},
+	})
+	if due := q.popDue(100, nil); len(due) != 0 {
+		t.Errorf("the ticks due by 100 are %v", due)
+	}
+	due := q.popDue(101, nil)
+	if len(due) != 3 || due[0].pos[0] != 3 || due[1].pos[0] != 2 || due[2].pos[0] != 4 {
+		t.Errorf("the ticks due by 101 are %v, want the high priority one first, then in the order they're scheduled", due)
+	}
+	saved := q.toSave(101)
+	if len(saved) != 1 || saved[0].ID != "minecraft:sand" || saved[0].X != 1 || saved[0].Delay != 2 {
+		t.Errorf("the ticks are saved as %v", saved)
+	}
+}
+
+func TestWorld_fluids(t *testing.T) {
+	c := hpcworld.Alloc()
+	defer c.Free()
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{0, 0}, heightMaps: c.HeightMaps()}
+	w := &World{chunks: map[[2]int32]*LoadedChunk{{0, 0}: lc}, rand: rand.New(rand.NewSource(1))}
+	stone := level.BlocksState(block.ToStateID[block.Stone{}])
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			lc.SetBlock(x, 63, z, stone)
+		}
+	}
+	lc.SetBlock(8, 64, 8, level.BlocksState(block.ToStateID[block.Water{}]))
+	for range 100 {
+		w.tickCount++
+		w.subtickScheduledTicks()
+		w.subtickBlockUpdates()
+	}
+	for pos, want := range map[[3]int]block.Block{
+		{9, 64, 8}:  block.Water{Level: 1},
+		{8, 64, 15}: block.Water{Level: 7},
+		{8, 65, 8}:  block.Air{},
+	} {
+		if got := block.StateList[lc.GetBlock(pos[0], pos[1], pos[2])]; got != want {
+			t.Errorf("the block at %v is %#v, want %#v", pos, got, want)
+		}
+	}
+}
+
+func TestWorld_scheduleTick_save(t *testing.T) {
+	c := hpcworld.Alloc()
+	provider := NewProvider(t.TempDir(), rate.NewLimiter(rate.Inf, 1))
+	if err := provider.PutChunk([2]int32{0, 0}, c, ChunkData{}); err != nil {
+		t.Fatal(err)
+	}
+	c.Free()
+	load := func() *World {
+		w := &World{log: zap.NewNop(), chunkProvider: provider, chunks: make(map[[2]int32]*LoadedChunk)}
+		if !w.loadChunk([2]int32{0, 0}) {
+			t.Fatal("the chunk isn't loaded")
+		}
+		t.Cleanup(w.chunks[[2]int32{0, 0}].Free)
+		return w
+	}
+
+	// The chunk whose only change is a scheduled tick is saved with it.
+	w := load()
+	w.scheduleTick(false, [3]int{3, 70, 5}, "minecraft:sand", 2, block.TickPriorityNormal)
+	if err := w.saveChunks(); err != nil {
+		t.Fatal(err)
+	}
+	w = load()
+	lc := w.chunks[[2]int32{0, 0}]
+	if saved := lc.blockTicks.toSave(w.tickCount); len(saved) != 1 || saved[0].ID != "minecraft:sand" || saved[0].Delay != 2 {
+		t.Fatalf("the ticks reloaded are %v", saved)
+	}
+
+	// The tick running without changing a block isn't saved again.
+	w.tickCount += 2
+	w.subtickScheduledTicks()
+	if err := w.saveChunks(); err != nil {
+		t.Fatal(err)
+	}
+	w = load()
+	if saved := w.chunks[[2]int32{0, 0}].blockTicks.toSave(w.tickCount); len(saved) != 0 {
+		t.Errorf("the ticks run are reloaded: %v", saved)
+	}
+}
@@ -0,0 +1,122 @@
+// This file is part of go-mc/server project.
+// Copyright (C) 2023.  Tnze
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package world
+
+import (
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+const (
+	// maxUpdateDepth is how far a chain of updates goes, like the update limit of vanilla.
+	// The blocks set by deeper updates don't update their neighbours.
+	maxUpdateDepth = 512
+	// maxUpdatesPerTick is the number of block updates processed in a tick.
+	// The remaining ones are processed in the next ticks.
+	maxUpdatesPerTick = 1 << 16
+)
+
+// blockUpdate is a changed block whose neighbours are to be updated.
+// depth is the number of updates which lead to the change.
+type blockUpdate struct {
+	pos   [3]int
+	depth int
+}
+
+// blockUpdater is the block.Level the neighbour handlers of an update at the depth change the world in.
+type blockUpdater struct {
+	w     *World
+	depth int
+}
+
+func (u blockUpdater) GetBlock(x, y, z int) (block.StateID, bool) {
+	s, ok := u.w.getBlock(x, y, z)
+	return block.StateID(s), ok
+}
+
+func (u blockUpdater) SetBlock(x, y, z int, s block.StateID) {
+	lc, ok := u.w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+	if !ok || y < worldMinY || y > worldMaxY {
+		return
+	}
+	lc.Lock()
+	changed := lc.setBlock(x, y, z, level.BlocksState(s))
+	lc.Unlock()
+	if changed && u.depth < maxUpdateDepth {
+		u.w.blockUpdates = append(u.w.blockUpdates, blockUpdate{pos: [3]int{x, y, z}, depth: u.depth})
+	}
+}
+
+func (u blockUpdater) ScheduleTick(x, y, z int, id string, delay int, priority block.TickPriority) {
+	u.w.scheduleTick(false, [3]int{x, y, z}, id, delay, priority)
+}
+
+func (u blockUpdater) ScheduleFluidTick(x, y, z int, id string, delay int, priority block.TickPriority) {
+	u.w.scheduleTick(true, [3]int{x, y, z}, id, delay, priority)
+}
+
+func (u blockUpdater) Ultrawarm() bool {
+	_, dim := NetworkCodec.DimensionType.Get(u.w.config.Dimension)
+	return dim != nil && dim.Ultrawarm
+}
+
+// subtickBlockUpdates updates the neighbours of the blocks set since the last tick,
+// and then the neighbours of the blocks those updates change, until nothing changes any more.
+// The updates are processed in the order of the changes, up to maxUpdatesPerTick.
+func (w *World) subtickBlockUpdates() {
+	for _, lc := range w.chunks {
+		lc.Lock()
+		for _, pos := range lc.updates {
+			w.blockUpdates = append(w.blockUpdates, blockUpdate{pos: pos})
+		}
+		lc.updates = lc.updates[:0]
+		lc.Unlock()
+	}
+	for n := 0; n < maxUpdatesPerTick && len(w.blockUpdates) > 0; n++ {
+		u := w.blockUpdates[0]
+		w.blockUpdates = w.blockUpdates[1:]
+		w.updateNeighbors(u)
+	}
+	if len(w.blockUpdates) == 0 {
+		w.blockUpdates = nil
+	}
+}
+
+// updateNeighbors calls the neighbour handler of the changed block,
+// then updates the shape of its six neighbours and calls their neighbour handlers.
+func (w *World) updateNeighbors(u blockUpdate) {
+	l := blockUpdater{w: w, depth: u.depth + 1}
+	s, ok := l.GetBlock(u.pos[0], u.pos[1], u.pos[2])
+	if !ok {
+		return
+	}
+	block.NeighborChanged(l, u.pos, s)
+	// The handler may have moved the block.
+	s, _ = l.GetBlock(u.pos[0], u.pos[1], u.pos[2])
+	for d := block.Down; d <= block.East; d++ {
+		pos := d.Relative(u.pos)
+		old, ok := l.GetBlock(pos[0], pos[1], pos[2])
+		if !ok {
+			continue
+		}
+		state := block.UpdateShape(old, pos, d.Opposite(), s, l.GetBlock)
+		if state != old {
+			l.SetBlock(pos[0], pos[1], pos[2], state)
+		}
+		block.NeighborChanged(l, pos, state)
+	}
+}
@@ -0,0 +1,58 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestWorld_subtickBlockUpdates(t *testing.T) {
+	c := hpcworld.Alloc()
+	defer c.Free()
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{0, 0}, heightMaps: c.HeightMaps()}
+	w := &World{chunks: map[[2]int32]*LoadedChunk{{0, 0}: lc}}
+	state := func(b block.Block) level.BlocksState { return level.BlocksState(block.ToStateID[b]) }
+
+	lc.SetBlock(1, 60, 2, state(block.Stone{}))
+	lc.SetBlock(1, 70, 2, state(block.Sand{}))
+	lc.SetBlock(5, 64, 5, state(block.Stone{}))
+	lc.SetBlock(5, 65, 5, state(block.Torch{}))
+	w.subtickBlockUpdates()
+	// The sand falls on its scheduled tick.
+	w.tickCount += 2
+	w.subtickScheduledTicks()
+	if got := lc.GetBlock(1, 61, 2); got != state(block.Sand{}) {
+		t.Errorf("the sand has fallen to %v", block.StateList[got])
+	}
+	if got := lc.GetBlock(5, 65, 5); got != state(block.Torch{}) {
+		t.Errorf("the torch is updated to %v", block.StateList[got])
+	}
+
+	lc.SetBlock(5, 64, 5, state(block.Air{}))
+	lc.SetBlock(5, 64, 5, state(block.Air{}))
+	if len(lc.updates) != 1 {
+		t.Errorf("%d updates are queued for one change", len(lc.updates))
+	}
+	w.subtickBlockUpdates()
+	if got := lc.GetBlock(5, 65, 5); got != state(block.Air{}) {
+		t.Errorf("the torch without support is updated to %v", block.StateList[got])
+	}
+	if len(w.blockUpdates) != 0 {
+		t.Errorf("%d updates are left", len(w.blockUpdates))
+	}
+}
+
+func TestBlockUpdater_maxUpdateDepth(t *testing.T) {
+	c := hpcworld.Alloc()
+	defer c.Free()
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{0, 0}, heightMaps: c.HeightMaps()}
+	w := &World{chunks: map[[2]int32]*LoadedChunk{{0, 0}: lc}}
+
+	blockUpdater{w: w, depth: maxUpdateDepth - 1}.SetBlock(0, 64, 0, block.ToStateID[block.Stone{}])
+	blockUpdater{w: w, depth: maxUpdateDepth}.SetBlock(1, 64, 0, block.ToStateID[block.Stone{}])
+	if len(w.blockUpdates) != 1 || w.blockUpdates[0].pos != [3]int{0, 64, 0} {
+		t.Errorf("the queued updates are %v", w.blockUpdates)
+	}
+}
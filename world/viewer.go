@@ -20,6 +20,10 @@ import (
 	"github.com/mrhaoxx/go-mc/chat"
 	"github.com/mrhaoxx/go-mc/hpcworld"
 	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/nbt"
+	"github.com/mrhaoxx/go-mc/world/entity"
 )
 
 type Client interface {
@@ -28,12 +32,33 @@ type Client interface {
 	SendDisconnect(reason chat.Message)
 	SendPlayerPosition(pos [3]float64, rot [2]float32) (teleportID int32)
 	SendSetChunkCacheCenter(chunkPos [2]int32)
-	SendSetPlayerInventorySlot(slot int32, stack *ItemStack)
+	SendSetPlayerInventorySlot(slot int32, stack item.ItemStack)
+	SendBlockChangedAck(sequence int32)
+	// SendSetHealth sends the health, the food level and the saturation of the player.
+	SendSetHealth(health float32, food int32, saturation float32)
+	// SendSetExperience sends the experience of the player, its progress toward the next level from 0 to 1.
+	SendSetExperience(progress float32, level, total int32)
+	// SendPlayerCombatKill tells the client its player died with the message, which shows the respawn screen.
+	SendPlayerCombatKill(id int32, message chat.Message)
+	// CloseMenu closes the window open on the client, if it's not the inventory. It takes the ContainerLock of the player.
+	CloseMenu()
 }
 
 type ChunkViewer interface {
-	ViewChunkLoad(pos level.ChunkPos, c *hpcworld.Chunk)
+	ViewChunkLoad(pos level.ChunkPos, c *hpcworld.Chunk, blockEntities []level.BlockEntity)
 	ViewChunkUnload(pos level.ChunkPos)
+	ViewBlockUpdate(pos [3]int32, state level.BlocksState)
+	ViewSectionBlocksUpdate(section [3]int32, changes []BlockChange)
+	// ViewLightUpdate sends the light of the chunk sections, which is a bit mask of section indexes from the bottom.
+	ViewLightUpdate(pos level.ChunkPos, c *hpcworld.Chunk, sections uint32)
+	// ViewBlockEntityData sends the data of the block entity at the world coordinates.
+	ViewBlockEntityData(pos [3]int32, typ block.EntityType, data nbt.RawMessage)
+}
+
+// BlockChange is a block set to a new state, the Pos is in world coordinates.
+type BlockChange struct {
+	Pos   [3]int32
+	State level.BlocksState
 }
 
 type EntityViewer interface {
@@ -47,4 +72,10 @@ type EntityViewer interface {
 	ViewTeleportEntity(id int32, pos [3]float64, rot [2]int8, onGround bool)
 	ViewSetEntityMotion(id int32, velocity [3]float64)
 	ViewAnimate(id int32, animation byte)
+	// ViewSetEntityData sends the metadata of the entity, the fields specific to its type.
+	ViewSetEntityData(id int32, metadata entity.MetadataSet)
+	// ViewBlockDestruction shows the block the entity is breaking cracked, from stage 0 to 9, or -1 to remove the cracks.
+	ViewBlockDestruction(id int32, pos [3]int32, stage int8)
+	// ViewDamageEvent shows the entity hurt by the damage type of the registry, attacked by the entity of the ID, -1 if none.
+	ViewDamageEvent(id int32, damageType int32, attackerID int32)
 }
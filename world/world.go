@@ -17,21 +17,30 @@
 package world
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/rand"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 
-	"github.com/google/uuid"
+	"github.com/mrhaoxx/go-mc/chat"
 	"github.com/mrhaoxx/go-mc/hpcworld"
 	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+	"github.com/mrhaoxx/go-mc/level/item"
+	"github.com/mrhaoxx/go-mc/level/loot"
 	"github.com/mrhaoxx/go-mc/world/internal/bvh"
 )
 
 type World struct {
-	log    *zap.Logger
-	config Config
-	// chunkProvider ChunkProvider
+	log           *zap.Logger
+	config        Config
+	chunkProvider ChunkProvider
+	generator     Generator
 
 	chunks    map[[2]int32]*LoadedChunk
 	loaders   map[ChunkViewer]*loader
@@ -43,14 +52,41 @@ type World struct {
 	playerViews playerViewTree
 	players     map[Client]*Player
 
-	// staticEntities are simple demo entities broadcast to clients for visibility testing.
-	staticEntities []simpleEntity
+	// entities are all the entities of the world, including the players, keyed by ID.
+	entities map[int32]*Entity
+	// entityIndex is a BVH tree of the bounding boxes of the entities, to find the entities in an area.
+	entityIndex entityTree
+
+	// blockUpdates are the changed blocks whose neighbours are to be updated, in order.
+	blockUpdates []blockUpdate
+	// tickSeq is the number of ticks scheduled, which orders them.
+	tickSeq uint64
+	// randomTickSpeed is the randomTickSpeed gamerule.
+	randomTickSpeed int
+	// rand is the random source of the block ticks.
+	rand *rand.Rand
+	// closed is set by Close, which stops the ticks by closing done.
+	closed bool
+	done   chan struct{}
 }
 
 type Config struct {
+	// Dimension is the name of the world, which is also the key of its type in the dimension type registry.
+	// It defaults to minecraft:overworld.
+	Dimension     string
 	ViewDistance  int32
 	SpawnAngle    float32
 	SpawnPosition [3]int32
+	Seed          int64
+	// LootTables decide the items dropped by the blocks broken, nothing is dropped if it's nil.
+	LootTables *loot.Tables
+	// OnViolation is called when a player moves illegally, after the violation is counted in Player.Violations.
+	// It runs with the world locked, so it must not call the methods of the World, but may disconnect the client.
+	OnViolation func(c Client, p *Player, v Violation)
+	// OnDeath is called with the death message when a player dies, with the world locked like OnViolation.
+	OnDeath func(c Client, p *Player, message chat.Message)
+	// ImmediateRespawn makes the dead players respawn without the respawn screen, like the doImmediateRespawn gamerule.
+	ImmediateRespawn bool
 }
 
 type playerView struct {
@@ -65,55 +101,97 @@ type (
 	playerViewTree = bvh.Tree[float64, aabb3d, playerView]
 )
 
-func New(logger *zap.Logger, config Config) (w *World) {
+func New(logger *zap.Logger, provider ChunkProvider, generator Generator, config Config) (w *World) {
+	if config.Dimension == "" {
+		config.Dimension = "minecraft:overworld"
+	}
 	w = &World{
-		log:     logger,
-		config:  config,
-		chunks:  make(map[[2]int32]*LoadedChunk),
-		loaders: make(map[ChunkViewer]*loader),
-		players: make(map[Client]*Player),
-		// chunkProvider: provider,
+		log:           logger,
+		config:        config,
+		chunks:        make(map[[2]int32]*LoadedChunk),
+		loaders:       make(map[ChunkViewer]*loader),
+		players:       make(map[Client]*Player),
+		entities:      make(map[int32]*Entity),
+		chunkProvider: provider,
+		generator:     generator,
+
+		randomTickSpeed: defaultRandomTickSpeed,
+		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		done:            make(chan struct{}),
 	}
 	// Add a few sample entities near spawn for testing visibility in clients.
-	base := [3]float64{float64(config.SpawnPosition[0]) + 2, float64(config.SpawnPosition[1]) + 1, float64(config.SpawnPosition[2]) + 2}
-	zap.S().Infof("default base %f %f %f", base[0], base[1], base[2])
-	w.staticEntities = []simpleEntity{
-		{Entity: Entity{EntityID: NewEntityID(), Position: base, Rotation: [2]float32{0, 0}, OnGround: true, UUID: uuid.New()}, TypeName: "minecraft:armor_stand"},
-		{Entity: Entity{EntityID: NewEntityID(), Position: [3]float64{base[0] + 2, base[1], base[2]}, Rotation: [2]float32{0, 0}, OnGround: true, UUID: uuid.New()}, TypeName: "minecraft:pig"},
-	}
+	base := Position{float64(config.SpawnPosition[0]) + 2, float64(config.SpawnPosition[1]) + 1, float64(config.SpawnPosition[2]) + 2}
+	w.spawnEntity(ArmorStandType, base, Rotation{}, nil)
+	w.spawnEntity(PigType, Position{base[0] + 2, base[1], base[2]}, Rotation{}, nil)
 	go w.tickLoop()
 	return
 }
 
-type simpleEntity struct {
-	Entity
-	TypeName    string
-	OrbitCenter *Player
-	OrbitRadius float64
-	OrbitSpeed  float64 // radians per tick
-	Angle       float64 // current angle
-	Velocity    [3]float64
-	vel0        [3]float64
+func (w *World) Name() string {
+	return w.config.Dimension
 }
 
-func (w *World) Name() string {
-	return "minecraft:overworld"
+// DimensionType returns the id of the world's type in the dimension type registry.
+func (w *World) DimensionType() int32 {
+	id, _ := NetworkCodec.DimensionType.Get(w.config.Dimension)
+	return id
+}
+
+// ImmediateRespawn reports whether the dead players respawn without the respawn screen.
+func (w *World) ImmediateRespawn() bool {
+	return w.config.ImmediateRespawn
 }
 
 func (w *World) SpawnPositionAndAngle() ([3]int32, float32) {
 	return w.config.SpawnPosition, w.config.SpawnAngle
 }
 
-func (w *World) HashedSeed() [8]byte {
-	return [8]byte{}
+// HashedSeed returns the first 8 bytes of the SHA-256 of the seed, which the client uses for biome noise.
+// Like vanilla, the seed is hashed in little-endian and the result is read as a little-endian long,
+// the returned bytes are the big-endian representation of that long.
+func (w *World) HashedSeed() (hashed [8]byte) {
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], uint64(w.config.Seed))
+	sum := sha256.Sum256(seed[:])
+	binary.BigEndian.PutUint64(hashed[:], binary.LittleEndian.Uint64(sum[:8]))
+	return
 }
 
+// AddPlayer adds the player joining the game to the world, and sends the client to the position of the player
+// with its health and experience.
 func (w *World) AddPlayer(c Client, p *Player, limiter *rate.Limiter) {
 	w.tickLock.Lock()
 	defer w.tickLock.Unlock()
+	w.teleport(c, p, p.Position)
+	w.loaders[c] = newLoader(p, limiter)
+	w.players[c] = p
+	p.view = w.playerViews.Insert(p.getView(), playerView{c, p})
+	p.Type, p.Data = PlayerType, p
+	p.Dimension = w.config.Dimension
+	w.addEntity(&p.Entity)
+	sendHealth(c, p)
+	c.SendSetExperience(p.ExperienceProgress, p.ExperienceLevel, p.TotalExperience)
+}
+
+// SpawnPlayer adds the player to the world at the position, where the client is teleported.
+// It's used when the player comes from another world, after the client is told to respawn in this one.
+func (w *World) SpawnPlayer(c Client, p *Player, pos Position, limiter *rate.Limiter) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p.Position, p.pos0 = pos, pos
+	p.rot0 = p.Rotation
+	p.ChunkPos = [3]int32{int32(pos[0]) >> 4, int32(pos[1]) >> 4, int32(pos[2]) >> 4}
+	// the client forgets the chunk cache center when it respawns
+	c.SendSetChunkCacheCenter(p.chunkPosition())
+	w.teleport(c, p, pos)
 	w.loaders[c] = newLoader(p, limiter)
 	w.players[c] = p
 	p.view = w.playerViews.Insert(p.getView(), playerView{c, p})
+	p.Type, p.Data = PlayerType, p
+	p.Dimension = w.config.Dimension
+	w.addEntity(&p.Entity)
+	sendHealth(c, p)
+	c.SendSetExperience(p.ExperienceProgress, p.ExperienceLevel, p.TotalExperience)
 }
 
 func (w *World) RemovePlayer(c Client, p *Player) {
@@ -131,91 +209,208 @@ func (w *World) RemovePlayer(c Client, p *Player) {
 	}
 	delete(w.loaders, c)
 	delete(w.players, c)
+	// the items dropped when the player leaves, like the carried one, fall in the world it leaves
+	p.ContainerLock.Lock()
+	w.throwDrops(p)
+	p.ContainerLock.Unlock()
 	// delete the player from entity system.
 	w.playerViews.Delete(p.view)
-	w.playerViews.Find(
-		bvh.TouchPoint[vec3d, aabb3d](bvh.Vec3[float64](p.Position)),
-		func(n *playerViewNode) bool {
-			n.Value.ViewRemoveEntities([]int32{p.EntityID})
-			delete(n.Value.EntitiesInView, p.EntityID)
-			return true
-		},
-	)
+	w.removeEntity(&p.Entity)
+	for id := range p.EntitiesInView {
+		if e, ok := w.entities[id]; ok {
+			delete(e.viewers, p)
+		}
+	}
+	clear(p.EntitiesInView)
+}
+
+// Teleport moves the player of the client to the position, keeping the rotation.
+// The movements of the client are ignored until it accepts the teleportation.
+func (w *World) Teleport(c Client, pos Position) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	p, ok := w.players[c]
+	if !ok {
+		return
+	}
+	w.teleport(c, p, pos)
 }
 
 func (w *World) loadChunk(pos [2]int32) bool {
 	logger := w.log.With(zap.Int32("x", pos[0]), zap.Int32("z", pos[1]))
 	logger.Debug("Loading chunk")
-	// c, err := w.chunkProvider.GetChunk(pos)
-	c := hpcworld.LoadChunk(pos[0], pos[1])
-
-	// if err != nil {
-	// 	if errors.Is(err, errChunkNotExist) {
-	// 		logger.Debug("Generate chunk")
-	// 		// TODO: because there is no chunk generator，generate an empty chunk and mark it as generated
-	// 		c = level.EmptyChunk(24)
-
-	// 		var t level.BiomesState
-	// 		t.UnmarshalText([]byte("minecraft:plains"))
-
-	// 		for s := range c.Sections {
-	// 			for b := 0; b < 4*4*4; b++ {
-	// 				c.Sections[s].Biomes.Set(b, t)
-	// 			}
-	// 			for i := 0; i < 16*16*16; i++ {
-	// 				rnd := rand.Intn(16)
-
-	// 				c.Sections[s].SetSkyLight(i, rnd)
-	// 				c.Sections[s].SetBlockLight(i, rnd)
-	// 			}
-
-	// 			if s != 10 {
-	// 				continue
-	// 			}
-
-	// 			for i := 0; i < 16*16; i++ {
-	// 				// if i == 10 {
-	// 				stone := block.GrassBlock{}
-
-	// 				c.Sections[s].SetBlock(i, block.ToStateID[stone])
-	// 				c.Sections[s].SetSkyLight(i, i%16)
-	// 				c.Sections[s].SetBlockLight(i, i%16)
-	// 				// }
-	// 			}
-	// 		}
-	// 		c.Status = level.StatusFull
-	// 	} else if !errors.Is(err, ErrReachRateLimit) {
-	// 		logger.Error("GetChunk error", zap.Error(err))
-	// 		return false
-	// 	}
-	// }
-	w.chunks[pos] = &LoadedChunk{Chunk: c, Pos: level.ChunkPos{pos[0], pos[1]}}
+	c, data, err := w.chunkProvider.GetChunk(pos)
+	dirty := false
+	if err != nil {
+		if errors.Is(err, ErrReachRateLimit) {
+			return false
+		} else if !errors.Is(err, errChunkNotExist) {
+			logger.Error("GetChunk error", zap.Error(err))
+			return false
+		}
+		logger.Debug("Generate chunk")
+		c = w.generator.Generate(level.ChunkPos(pos), w.config.Seed)
+		dirty = true
+	}
+	lc := &LoadedChunk{
+		Chunk:         c,
+		Pos:           level.ChunkPos{pos[0], pos[1]},
+		dirty:         dirty,
+		lastViewed:    w.tickCount,
+		heightMaps:    c.HeightMaps(),
+		blockEntities: data.BlockEntities,
+	}
+	w.loadTicks(&lc.blockTicks, data.BlockTicks)
+	w.loadTicks(&lc.fluidTicks, data.FluidTicks)
+	w.chunks[pos] = lc
+	w.lightChunk(lc, dirty)
 	return true
 }
 
-// func (w *World) unloadChunk(pos [2]int32) {
-// 	logger := w.log.With(zap.Int32("x", pos[0]), zap.Int32("z", pos[1]))
-// 	logger.Debug("Unloading chunk")
-// 	c, ok := w.chunks[pos]
-// 	if !ok {
-// 		logger.Panic("Unloading an non-exist chunk")
-// 	}
-// 	// notify all viewers who are watching the chunk to unload the chunk
-// 	for _, viewer := range c.viewers {
-// 		viewer.ViewChunkUnload(pos)
-// 	}
-// 	// move the chunk to provider and save
-// 	err := w.chunkProvider.PutChunk(pos, c.Chunk)
-// 	if err != nil {
-// 		logger.Error("Store chunk data error", zap.Error(err))
-// 	}
-// 	delete(w.chunks, pos)
-// }
+func (w *World) unloadChunk(pos [2]int32) {
+	logger := w.log.With(zap.Int32("x", pos[0]), zap.Int32("z", pos[1]))
+	logger.Debug("Unloading chunk")
+	c, ok := w.chunks[pos]
+	if !ok {
+		logger.Panic("Unloading an non-exist chunk")
+	}
+	// notify all viewers who are watching the chunk to unload the chunk
+	for _, viewer := range c.viewers {
+		viewer.ViewChunkUnload(level.ChunkPos(pos))
+	}
+	// move the chunk to provider and save.
+	// keep the chunk in memory if it cannot be stored, we will try again later.
+	if err := w.saveChunk(pos, c); err != nil {
+		return
+	}
+	delete(w.chunks, pos)
+	c.Lock()
+	c.Chunk.Free()
+	c.Chunk = nil
+	c.blockEntities = nil
+	c.blockTicks, c.fluidTicks = tickQueue{}, tickQueue{}
+	c.Unlock()
+}
+
+// saveChunk stores the chunk through the provider if it has been modified since last save.
+func (w *World) saveChunk(pos [2]int32, c *LoadedChunk) error {
+	c.Lock()
+	defer c.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	data := ChunkData{
+		BlockEntities: c.blockEntities,
+		BlockTicks:    c.blockTicks.toSave(w.tickCount),
+		FluidTicks:    c.fluidTicks.toSave(w.tickCount),
+	}
+	if err := w.chunkProvider.PutChunk(pos, c.Chunk, data); err != nil {
+		w.log.Error("Store chunk data error", zap.Int32("x", pos[0]), zap.Int32("z", pos[1]), zap.Error(err))
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// saveChunks stores every modified chunk, called by the autosave in tick.
+func (w *World) saveChunks() error {
+	var errs []error
+	for pos, c := range w.chunks {
+		if err := w.saveChunk(pos, c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SavePlayers stores each player in the world with the function, like PlayerProvider.PutPlayer, and joins the errors.
+// The world is locked meanwhile, so the players don't change.
+func (w *World) SavePlayers(put func(p *Player) error) error {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	var errs []error
+	for _, p := range w.players {
+		errs = append(errs, put(p))
+	}
+	return errors.Join(errs...)
+}
+
+// Save stores every chunk modified since it was last saved, with its block entities and scheduled ticks.
+func (w *World) Save() error {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	return w.saveChunks()
+}
+
+// Close stops ticking the world and saves the modified chunks, to be called when the server shuts down.
+func (w *World) Close() error {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	return w.saveChunks()
+}
 
 func (w *World) GetChunk(pos [2]int32) *LoadedChunk {
 	return w.chunks[pos]
 }
 
+// SetBlock sets the block at the world coordinates.
+// It reports false if the chunk isn't loaded or y is out of the world.
+func (w *World) SetBlock(x, y, z int, state level.BlocksState) bool {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	return w.setBlock(x, y, z, state)
+}
+
+// IsOutsideBuildHeight reports whether the height is below or above the blocks of the world.
+func (w *World) IsOutsideBuildHeight(y int) bool {
+	return y < worldMinY || y > worldMaxY
+}
+
+func (w *World) setBlock(x, y, z int, state level.BlocksState) bool {
+	lc, ok := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+	if !ok || w.IsOutsideBuildHeight(y) {
+		return false
+	}
+	lc.SetBlock(x, y, z, state)
+	return true
+}
+
+// GetBlock returns the block at the world coordinates.
+// The ok is false if the chunk isn't loaded or y is out of the world.
+func (w *World) GetBlock(x, y, z int) (state level.BlocksState, ok bool) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	return w.getBlock(x, y, z)
+}
+
+func (w *World) getBlock(x, y, z int) (state level.BlocksState, ok bool) {
+	lc, ok := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+	if !ok || w.IsOutsideBuildHeight(y) {
+		return 0, false
+	}
+	return lc.GetBlock(x, y, z), true
+}
+
+// HeightAt returns the y above the highest block counted by the heightmap in the column at the world coordinates.
+// The ok is false if the chunk isn't loaded.
+func (w *World) HeightAt(x, z int, kind level.HeightMapKind) (y int, ok bool) {
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
+	return w.heightAt(x, z, kind)
+}
+
+func (w *World) heightAt(x, z int, kind level.HeightMapKind) (y int, ok bool) {
+	lc, ok := w.chunks[[2]int32{int32(x >> 4), int32(z >> 4)}]
+	if !ok {
+		return 0, false
+	}
+	return lc.HeightAt(x, z, kind), true
+}
+
 // BroadcastSwing sends an animation for the given player entity to all viewers in range.
 func (w *World) BroadcastSwing(p *Player, animation byte) {
 	cond := bvh.TouchPoint[vec3d, aabb3d](vec3d(p.Position))
@@ -234,6 +429,24 @@ type LoadedChunk struct {
 	// *level.Chunk
 	*hpcworld.Chunk
 	Pos level.ChunkPos
+	// dirty reports whether the chunk has been changed since it was last stored.
+	dirty bool
+	// lastViewed is the last tick the chunk had at least one viewer.
+	lastViewed uint
+	// changes are the blocks changed since the last flushChanges, with the state before the first change.
+	changes map[[3]int32]level.BlocksState
+	// heightMaps are updated with every SetBlock.
+	heightMaps level.HeightMaps
+	// lightChanged is a bit mask of the sections whose light has changed since the last flushChanges.
+	lightChanged uint32
+	// blockEntities are keyed by the world coordinates.
+	blockEntities map[[3]int32]block.Entity
+	// entityChanges are the block entities changed since the last flushChanges.
+	entityChanges map[[3]int32]struct{}
+	// updates are the blocks changed by SetBlock whose neighbours haven't been updated yet.
+	updates [][3]int
+	// blockTicks and fluidTicks are the scheduled ticks in the chunk.
+	blockTicks, fluidTicks tickQueue
 }
 
 func (lc *LoadedChunk) AddViewer(v ChunkViewer) {
@@ -261,9 +474,28 @@ func (lc *LoadedChunk) RemoveViewer(v ChunkViewer) bool {
 	return false
 }
 
+// SetBlock sets the block state at the world coordinates, which must be in this chunk.
+// The neighbours of a changed block are updated within the tick.
 func (lc *LoadedChunk) SetBlock(x, y, z int, block level.BlocksState) {
 	lc.Lock()
 	defer lc.Unlock()
+	if lc.setBlock(x, y, z, block) {
+		lc.updates = append(lc.updates, [3]int{x, y, z})
+	}
+}
+
+// setBlock sets the block state and reports whether it has changed.
+func (lc *LoadedChunk) setBlock(x, y, z int, block level.BlocksState) bool {
+	if lc.getBlock(x, y, z) == block {
+		return false
+	}
+	if lc.changes == nil {
+		lc.changes = make(map[[3]int32]level.BlocksState)
+	}
+	pos := [3]int32{int32(x), int32(y), int32(z)}
+	if _, ok := lc.changes[pos]; !ok {
+		lc.changes[pos] = lc.getBlock(x, y, z)
+	}
 	y += 64
 	// lc.Chunk.Sections[y/16].SetBlock((x%16)*16*16+(y%16)*16+z%16, block)
 
@@ -279,15 +511,80 @@ func (lc *LoadedChunk) SetBlock(x, y, z int, block level.BlocksState) {
 	}
 
 	lc.Chunk.Sections[y/16].SetBlock((y%16)*16*16+(tz%16)*16+tx%16, int32(block))
+	lc.heightMaps.Update(tx, y, tz, block, lc.Chunk.BlockAt)
+	lc.updateBlockEntity(pos, block)
+	lc.dirty = true
+	return true
+}
+
+// GetBlock returns the block state at the world coordinates, which must be in this chunk.
+func (lc *LoadedChunk) GetBlock(x, y, z int) level.BlocksState {
+	lc.Lock()
+	defer lc.Unlock()
+	return lc.getBlock(x, y, z)
+}
+
+func (lc *LoadedChunk) getBlock(x, y, z int) level.BlocksState {
+	y += 64
+	return level.BlocksState(lc.Chunk.Sections[y>>4].BlocksState[(y&15)<<8|(z&15)<<4|x&15])
+}
 
+// HeightAt returns the y above the highest block counted by the heightmap in the column,
+// which must be in this chunk.
+func (lc *LoadedChunk) HeightAt(x, z int, kind level.HeightMapKind) int {
+	lc.Lock()
+	defer lc.Unlock()
+	return lc.heightMaps.Get(kind).Get((z&15)<<4|x&15) + worldMinY
 }
 
+// flushChanges sends the blocks changed since the last call to the viewers,
+// followed by the changed block entities and light.
+// Like vanilla, a section with only one changed block is sent as a block update,
+// otherwise all changed blocks in the section are sent together.
+func (lc *LoadedChunk) flushChanges() {
+	lc.Lock()
+	defer lc.Unlock()
+	defer lc.flushLight()
+	defer lc.flushBlockEntities()
+	if len(lc.changes) == 0 {
+		return
+	}
+	sections := make(map[[3]int32][]BlockChange)
+	for pos := range lc.changes {
+		section := [3]int32{pos[0] >> 4, pos[1] >> 4, pos[2] >> 4}
+		state := lc.getBlock(int(pos[0]), int(pos[1]), int(pos[2]))
+		sections[section] = append(sections[section], BlockChange{Pos: pos, State: state})
+	}
+	clear(lc.changes)
+	for _, v := range lc.viewers {
+		for section, changes := range sections {
+			if len(changes) == 1 {
+				v.ViewBlockUpdate(changes[0].Pos, changes[0].State)
+			} else {
+				v.ViewSectionBlocksUpdate(section, changes)
+			}
+		}
+	}
+}
+
+// flushLight sends the light of the sections changed since the last call to the viewers.
+func (lc *LoadedChunk) flushLight() {
+	if lc.lightChanged == 0 {
+		return
+	}
+	for _, v := range lc.viewers {
+		v.ViewLightUpdate(lc.Pos, lc.Chunk, lc.lightChanged)
+	}
+	lc.lightChanged = 0
+}
+
+// UpdateToViewers resends the whole chunk to all viewers.
 func (lc *LoadedChunk) UpdateToViewers() {
 	lc.Lock()
 	defer lc.Unlock()
 	for _, v := range lc.viewers {
 		// fmt.Println("update chunk to viewers", lc.Pos)
-		v.ViewChunkLoad(lc.Pos, lc.Chunk)
+		v.ViewChunkLoad(lc.Pos, lc.Chunk, lc.blockEntityList())
 	}
 }
 
@@ -312,15 +609,13 @@ func (w *World) updateRainbowInventory() {
 		224, // Black Wool
 	}
 
-	for c, p := range w.players {
-		// Rotate colors for slots 9-17 (top inventory row)
+	for _, p := range w.players {
+		p.ContainerLock.Lock()
+		// Rotate colors for slots 9-17 (top inventory row), the open window sends them
 		for i := 9; i < 10; i++ {
 			colorIdx := (i - 9 + int(w.tickCount)) % len(rainbowColors)
-			p.Inventory[i] = &ItemStack{
-				ItemID: rainbowColors[colorIdx],
-				Count:  1,
-			}
-			c.SendSetPlayerInventorySlot(int32(i), p.Inventory[i])
+			p.Inventory[i] = item.New(item.ID(rainbowColors[colorIdx]), 1)
 		}
+		p.ContainerLock.Unlock()
 	}
 }
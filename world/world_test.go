@@ -0,0 +1,62 @@
+package world
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/mrhaoxx/go-mc/hpcworld"
+	"github.com/mrhaoxx/go-mc/level"
+	"github.com/mrhaoxx/go-mc/level/block"
+)
+
+func TestWorld_Close(t *testing.T) {
+	c := hpcworld.Alloc()
+	defer c.Free()
+	lc := &LoadedChunk{Chunk: c, Pos: level.ChunkPos{1, 2}, heightMaps: c.HeightMaps()}
+	stone := level.BlocksState(block.ToStateID[block.Stone{}])
+	lc.SetBlock(3, 70, 5, stone)
+	lc.dirty = true
+	provider := NewProvider(t.TempDir(), rate.NewLimiter(rate.Inf, 1))
+	w := &World{
+		log:           zap.NewNop(),
+		chunkProvider: provider,
+		chunks:        map[[2]int32]*LoadedChunk{{1, 2}: lc},
+		done:          make(chan struct{}),
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if lc.dirty {
+		t.Error("the chunk is still dirty after Close")
+	}
+	saved, _, err := provider.GetChunk([2]int32{1, 2})
+	if err != nil {
+		t.Fatalf("the chunk isn't saved: %v", err)
+	}
+	defer saved.Free()
+	if got := saved.BlockAt(3, 70-worldMinY, 5); got != stone {
+		t.Errorf("the saved block is %v, want stone", got)
+	}
+	// The ticks after Close don't change the world.
+	w.tick(0)
+	if w.tickCount != 0 {
+		t.Error("the world ticked after Close")
+	}
+}
+
+func TestWorld_HeightAt(t *testing.T) {
+	w, lc, _ := physicsWorld(t)
+	lc.SetBlock(3, 70, 5, level.BlocksState(block.ToStateID[block.Stone{}]))
+	if y, ok := w.HeightAt(3, 5, level.MotionBlocking); !ok || y != 71 {
+		t.Errorf("height of the column with a block at y 70 = %d, %v, want 71", y, ok)
+	}
+	if y, ok := w.HeightAt(4, 5, level.MotionBlocking); !ok || y != 65 {
+		t.Errorf("height of the floor at y 64 = %d, %v, want 65", y, ok)
+	}
+	if _, ok := w.HeightAt(20, 5, level.MotionBlocking); ok {
+		t.Error("the height of a column of a chunk not loaded is known")
+	}
+}